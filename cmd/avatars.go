@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"uiren/internal/app/avatars"
+	"uiren/internal/app/users"
+	"uiren/internal/infrastracture/storage"
+	"uiren/pkg/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const avatarsUsage = `usage:
+  main -config=<name> avatars backfill`
+
+// runAvatarsCommand handles "avatars backfill" without starting the http server,
+// it moves avatars uploaded before user_avatars into the configured storage
+func runAvatarsCommand(ctx context.Context, postgresDB *pgxpool.Pool, args []string) error {
+	if len(args) != 1 || args[0] != "backfill" {
+		return errors.New(avatarsUsage)
+	}
+
+	objectStorage, err := objectStorageFromConfig(config.GetValue(appPortKey).String())
+	if err != nil {
+		return err
+	}
+
+	// only the user list is needed, progress is never read
+	userService := users.NewUserService(users.NewUserRepository(postgresDB), nil)
+	avatarService := avatars.NewAvatarService(avatars.NewAvatarRepository(postgresDB), objectStorage, userService)
+	avatarService.WithLegacyStorage(storage.NewFileSystemStorage(fileSystemStorageRoot, ""))
+
+	report, err := avatarService.BackfillLegacyAvatars(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("converted: %d\nstale removed: %d\nfailed: %d\n", report.Converted, report.Stale, len(report.Failed))
+	for _, userID := range report.Failed {
+		fmt.Printf("  %s\n", userID)
+	}
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d avatars were not converted, run the backfill again", len(report.Failed))
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"uiren/internal/app/bundles"
+	"uiren/internal/app/data"
+	"uiren/internal/app/events"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/lessons"
+	"uiren/internal/app/media"
+	"uiren/internal/app/modules"
+	"uiren/internal/app/revisions"
+	"uiren/internal/infrastracture/database"
+	"uiren/pkg/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const bundleUsage = `usage:
+  main -config=<name> bundle export -module <code> [-format json|yaml] [-out file]
+  main -config=<name> bundle import -file <path> [-format json|yaml] [-dry-run]`
+
+// runBundleCommand handles "bundle export|import" without starting the http server,
+// imported content is evicted from the data cache the same way as when it is changed through the api
+func runBundleCommand(ctx context.Context, mongoDB *mongo.Database, postgresDB *pgxpool.Pool, redisDB *database.RedisDB, args []string) error {
+	if len(args) == 0 {
+		return errors.New(bundleUsage)
+	}
+
+	objectStorage, err := objectStorageFromConfig(config.GetValue(appPortKey).String())
+	if err != nil {
+		return err
+	}
+	mediaService := media.NewMediaService(media.NewMediaRepository(postgresDB), objectStorage)
+
+	eventBus := events.NewBus()
+
+	revisionService := revisions.NewRevisionService(revisions.NewRevisionRepository(mongoDB))
+	exerciseService := exercises.NewExerciseService(exercises.NewExercisesRepository(mongoDB))
+	exerciseService.WithRevisionService(revisionService)
+	exerciseService.WithMediaService(mediaService)
+	exerciseService.WithPublisher(eventBus)
+	lessonService := lessons.NewLessonsService(lessons.NewLessonRepository(mongoDB), exerciseService)
+	lessonService.WithRevisionService(revisionService)
+	lessonService.WithPublisher(eventBus)
+	modulesService := modules.NewModulesService(modules.NewModulesRepository(mongoDB), lessonService)
+	modulesService.WithRevisionService(revisionService)
+	modulesService.WithPublisher(eventBus)
+
+	// the data service is used only to evict the cache, so it needs no user service
+	dataService := data.NewDataService(redisDB, nil, modulesService, config.GetValue(dbRedisDataTTLKey).Duration())
+	dataService.WithLessonService(lessonService)
+	eventBus.Subscribe(dataService.HandleEvent)
+	bundleService := bundles.NewBundleService(modulesService, lessonService, exerciseService)
+	bundleService.WithMediaService(mediaService)
+
+	switch args[0] {
+	case "export":
+		return runBundleExport(ctx, bundleService, args[1:])
+	case "import":
+		return runBundleImport(ctx, bundleService, args[1:])
+	default:
+		return errors.New(bundleUsage)
+	}
+}
+
+func runBundleExport(ctx context.Context, bundleService *bundles.BundleService, args []string) error {
+	var (
+		flags      = flag.NewFlagSet("bundle export", flag.ContinueOnError)
+		moduleCode = flags.String("module", "", "module code")
+		format     = flags.String("format", bundles.FormatJSON, "json or yaml")
+		out        = flags.String("out", "", "output file, stdout by default")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *moduleCode == "" {
+		return errors.New(bundleUsage)
+	}
+
+	bundle, err := bundleService.ExportModule(ctx, *moduleCode)
+	if err != nil {
+		return err
+	}
+
+	data, err := bundles.Encode(bundle, *format)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0o644)
+}
+
+func runBundleImport(ctx context.Context, bundleService *bundles.BundleService, args []string) error {
+	var (
+		flags  = flag.NewFlagSet("bundle import", flag.ContinueOnError)
+		file   = flags.String("file", "", "bundle file")
+		format = flags.String("format", "", "json or yaml, detected by file extension by default")
+		dryRun = flags.Bool("dry-run", false, "only report changes")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New(bundleUsage)
+	}
+
+	if *format == "" {
+		*format = bundles.FormatJSON
+		if ext := strings.ToLower(filepath.Ext(*file)); ext == ".yaml" || ext == ".yml" {
+			*format = bundles.FormatYAML
+		}
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := bundles.Decode(data, *format)
+	if err != nil {
+		return err
+	}
+
+	report, err := bundleService.ImportBundle(ctx, bundle, *dryRun)
+	fmt.Print(formatImportReport(report))
+
+	return err
+}
+
+func formatImportReport(report bundles.ImportReport) string {
+	var b strings.Builder
+
+	if report.DryRun {
+		b.WriteString("dry run, nothing was written\n")
+	}
+	sections := []struct {
+		title string
+		items []bundles.ImportItem
+	}{
+		{"created", report.Created},
+		{"updated", report.Updated},
+		{"unchanged", report.Unchanged},
+		{"conflicts", report.Conflicts},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&b, "%s: %d\n", section.title, len(section.items))
+		for _, item := range section.items {
+			fmt.Fprintf(&b, "  %s %s", item.Type, item.Code)
+			if item.Reason != "" {
+				fmt.Fprintf(&b, " (%s)", item.Reason)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
@@ -2,25 +2,38 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 	"uiren/internal/app/achievements"
+	"uiren/internal/app/activity"
 	"uiren/internal/app/admin"
 	"uiren/internal/app/auth"
 	"uiren/internal/app/avatars"
+	"uiren/internal/app/bundles"
 	"uiren/internal/app/data"
+	"uiren/internal/app/events"
 	"uiren/internal/app/exercises"
 	"uiren/internal/app/friendship"
+	"uiren/internal/app/hearts"
+	"uiren/internal/app/integrity"
 	"uiren/internal/app/lessons"
+	"uiren/internal/app/mailing"
+	"uiren/internal/app/media"
 	"uiren/internal/app/modules"
+	"uiren/internal/app/notifications"
 	"uiren/internal/app/progress"
+	"uiren/internal/app/revisions"
+	"uiren/internal/app/search"
+	"uiren/internal/app/sessions"
 	"uiren/internal/app/users"
 	"uiren/internal/infrastracture/database"
 	jwt_maker "uiren/internal/infrastracture/jwt"
-	yandex_sender "uiren/internal/infrastracture/mail/yandex"
+	"uiren/internal/infrastracture/mail"
+	"uiren/internal/infrastracture/storage"
 	"uiren/pkg/config"
 	"uiren/pkg/logger"
 
@@ -30,6 +43,10 @@ import (
 	_ "github.com/lib/pq"
 )
 
+const (
+	defaultSMTPAddress = "smtp.yandex.kz:587"
+)
+
 var (
 	//app
 	appPortKey         = "app_port"
@@ -54,8 +71,28 @@ var (
 	emailSenderNameKey     = "email_sender_name"
 	fromEmailAddressKey    = "from_email_address"
 	verificationCodeTTLKey = "verification_code_TTL"
+	smtpAddressKey         = "smtp_address"
+	verificationLinkKey    = "verification_link_base"
+	verificationResendKey  = "verification_resend_cooldown"
+	//email outbox
+	emailOutboxPollIntervalKey = "email_outbox_poll_interval"
+	emailOutboxMaxAttemptsKey  = "email_outbox_max_attempts"
+	emailOutboxBaseBackoffKey  = "email_outbox_base_backoff"
+	emailOutboxMaxBackoffKey   = "email_outbox_max_backoff"
 	//data
 	xpLeaderboardLimitKey = "xp_leaderboard_limit"
+	//notifications
+	notificationsOvertakenLimitKey = "notifications_overtaken_limit"
+	//friends
+	friendRequestsMaxPendingKey       = "friend_requests_max_pending"
+	friendRequestsDeclinedCooldownKey = "friend_requests_declined_cooldown"
+	//exercises
+	answerFoldDiacriticsKey = "answer_fold_diacritics"
+	answerMaxTyposKey       = "answer_max_typos"
+	exerciseXPKey           = "exercise_xp"
+	//hearts
+	heartsMaxKey            = "hearts_max"
+	heartsRefillIntervalKey = "hearts_refill_interval"
 )
 
 func main() {
@@ -107,6 +144,14 @@ func main() {
 	}()
 	logger.Info("connected to MongoDB: ", mongoDB.Name())
 
+	if args := flag.Args(); len(args) > 0 && args[0] == "avatars" {
+		if err := runAvatarsCommand(ctx, postgresDB, args[1:]); err != nil {
+			logger.Error("avatars command: ", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	redisDB, err := database.GetRedisDatabase(ctx, database.RedisConfig{
 		Address:  config.GetValue(dbRedisAddressKey).String(),
 		Password: os.Getenv("REDIS_PASSWORD"),
@@ -123,41 +168,118 @@ func main() {
 		}
 	}()
 
-	yandex_sender.Init(
-		config.GetValue(emailSenderNameKey).String(),
-		config.GetValue(fromEmailAddressKey).String(),
-		os.Getenv("YANDEX_EMAIL_PASSWORD"),
-	)
+	if args := flag.Args(); len(args) > 0 && args[0] == "bundle" {
+		if err := runBundleCommand(ctx, mongoDB, postgresDB, redisDB, args[1:]); err != nil {
+			logger.Error("bundle command: ", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	smtpAddress, ok := config.GetValue(smtpAddressKey).LookupString()
+	if !ok {
+		smtpAddress = defaultSMTPAddress
+	}
+	mailer := mail.NewSMTPMailer(mail.SMTPConfig{
+		Address:     smtpAddress,
+		Username:    config.GetValue(fromEmailAddressKey).String(),
+		Password:    os.Getenv("YANDEX_EMAIL_PASSWORD"),
+		FromName:    config.GetValue(emailSenderNameKey).String(),
+		FromAddress: config.GetValue(fromEmailAddressKey).String(),
+	})
+
+	mailingRepo := mailing.NewOutboxRepository(postgresDB)
+	mailingService := mailing.NewMailingService(mailingRepo, mailer)
+	if interval, ok := config.GetValue(emailOutboxPollIntervalKey).LookupDuration(); ok {
+		mailingService.SetPollInterval(interval)
+	}
+	if maxAttempts, ok := config.GetValue(emailOutboxMaxAttemptsKey).LookupInt(); ok {
+		mailingService.SetMaxAttempts(maxAttempts)
+	}
+	if base, ok := config.GetValue(emailOutboxBaseBackoffKey).LookupDuration(); ok {
+		mailingService.SetBaseBackoff(base)
+	}
+	if max, ok := config.GetValue(emailOutboxMaxBackoffKey).LookupDuration(); ok {
+		mailingService.SetMaxBackoff(max)
+	}
+	go mailingService.Run(ctx)
 
 	jwtMaker := jwt_maker.NewJWTMaker(config.GetValue(jwtDurationKey).Duration())
 
+	eventBus := events.NewBus()
+
+	revisionRepo := revisions.NewRevisionRepository(mongoDB)
+	revisionService := revisions.NewRevisionService(revisionRepo)
+
 	exerciseRepo := exercises.NewExercisesRepository(mongoDB)
 	exerciseService := exercises.NewExerciseService(exerciseRepo)
+	exerciseService.WithRevisionService(revisionService)
+	exerciseService.WithPublisher(eventBus)
+	matcherConfig := exercises.DefaultMatcherConfig
+	if fold, ok := config.GetValue(answerFoldDiacriticsKey).LookupBoolean(); ok {
+		matcherConfig.FoldDiacritics = fold
+	}
+	if maxTypos, ok := config.GetValue(answerMaxTyposKey).LookupInt(); ok {
+		matcherConfig.MaxTypos = maxTypos
+	}
+	exerciseService.SetMatcherConfig(matcherConfig)
+	if exerciseXP, ok := config.GetValue(exerciseXPKey).LookupInt(); ok {
+		exerciseService.SetExerciseXP(exerciseXP)
+	}
 
 	lessonRepo := lessons.NewLessonRepository(mongoDB)
 	lessonService := lessons.NewLessonsService(lessonRepo, exerciseService)
+	lessonService.WithRevisionService(revisionService)
+	lessonService.WithPublisher(eventBus)
 
 	moduleRepo := modules.NewModulesRepository(mongoDB)
 	modulesService := modules.NewModulesService(moduleRepo, lessonService)
+	modulesService.WithRevisionService(revisionService)
+	modulesService.WithPublisher(eventBus)
+
+	exerciseService.WithReferenceTracker(lessonService)
+	lessonService.WithReferenceTracker(modulesService)
+	integrityService := integrity.NewIntegrityService(modulesService, lessonService, exerciseService)
+
+	searchRepo := search.NewSearchRepository(mongoDB)
+	searchService := search.NewSearchService(searchRepo)
+
+	bundleService := bundles.NewBundleService(modulesService, lessonService, exerciseService)
 
 	achievementRepo := achievements.NewAchievementRepository(postgresDB)
 	achievementService := achievements.NewAchievementService(achievementRepo)
+	achievementService.WithPublisher(eventBus)
 
 	progressReceiverRepo := progress.NewProgressReceiverRepository(postgresDB)
 	progressUpdaterRepo := progress.NewProgressUpdaterRepository(postgresDB)
 	progressService := progress.NewProgressService(progressReceiverRepo, progressUpdaterRepo, achievementService)
+	progressService.WithPublisher(eventBus)
 
 	userRepo := users.NewUserRepository(postgresDB)
 	userService := users.NewUserService(userRepo, progressService)
 
 	friendshipRepo := friendship.NewFriendshipRepository(postgresDB)
 	friendshipService := friendship.NewFriendshipService(friendshipRepo, userService)
+	friendshipService.WithPublisher(eventBus)
+	if maxPending, ok := config.GetValue(friendRequestsMaxPendingKey).LookupInt(); ok {
+		friendshipService.SetMaxPendingRequests(maxPending)
+	}
+	if cooldown, ok := config.GetValue(friendRequestsDeclinedCooldownKey).LookupDuration(); ok {
+		friendshipService.SetDeclinedRequestCooldown(cooldown)
+	}
 
 	verifRepo := auth.NewVerificationRepository(postgresDB)
 	authService := auth.NewAuthService(userService, jwtMaker, verifRepo)
 	authService.SetVerificationCodeTTL(config.GetValue(verificationCodeTTLKey).Duration())
 	authService.WithRedisClient(redisDB)
 	authService.SetRefreshTokenTTL(config.GetValue(refreshTokenDuration).Duration())
+	authService.WithEmailOutbox(mailingService)
+	if base, ok := config.GetValue(verificationLinkKey).LookupString(); ok {
+		authService.SetVerificationBase(base)
+	}
+	if cooldown, ok := config.GetValue(verificationResendKey).LookupDuration(); ok {
+		authService.SetResendCooldown(cooldown)
+	}
 
 	dataService := data.NewDataService(
 		redisDB,
@@ -169,9 +291,58 @@ func main() {
 	dataService.WithLessonService(lessonService)
 	dataService.WithExerciseService(exerciseService)
 	dataService.WithAchievementService(achievementService)
+	dataService.WithFriendshipService(friendshipService)
+	for _, family := range data.CacheFamilies {
+		if policy, ok := cachePolicyFromConfig(family); ok {
+			dataService.WithCachePolicy(family, policy)
+		}
+	}
+	eventBus.Subscribe(dataService.HandleEvent)
 
-	avatarRepo := avatars.NewAvatarRepository("/avatars")
-	avatarService := avatars.NewAvatarService(avatarRepo)
+	activityRepo := activity.NewActivityRepository(postgresDB)
+	activityService := activity.NewActivityService(activityRepo, friendshipService)
+	eventBus.Subscribe(activityService.HandleEvent)
+
+	notificationRepo := notifications.NewNotificationRepository(postgresDB)
+	notificationService := notifications.NewNotificationService(notificationRepo)
+	notificationService.WithBroker(redisDB)
+	if limit, ok := config.GetValue(notificationsOvertakenLimitKey).LookupInt(); ok {
+		notificationService.SetOvertakenLimit(limit)
+	}
+	eventBus.Subscribe(notificationService.HandleEvent)
+	go notificationService.Run(ctx)
+
+	objectStorage, err := objectStorageFromConfig(config.GetValue(appPortKey).String())
+	if err != nil {
+		logger.Fatal("object storage: ", err)
+		return
+	}
+
+	avatarRepo := avatars.NewAvatarRepository(postgresDB)
+	avatarService := avatars.NewAvatarService(avatarRepo, objectStorage, userService)
+	// avatars uploaded before user_avatars are files in ./storage whatever the backend is
+	avatarService.WithLegacyStorage(storage.NewFileSystemStorage(fileSystemStorageRoot, ""))
+
+	mediaRepo := media.NewMediaRepository(postgresDB)
+	mediaService := media.NewMediaService(mediaRepo, objectStorage)
+	exerciseService.WithMediaService(mediaService)
+	bundleService.WithMediaService(mediaService)
+	dataService.WithMediaService(mediaService)
+
+	sessionRepo := sessions.NewSessionRepository(postgresDB)
+	sessionService := sessions.NewSessionService(sessionRepo, dataService, exerciseService)
+	sessionService.WithProgressService(progressService)
+
+	heartsRepo := hearts.NewHeartsRepository(postgresDB)
+	heartsService := hearts.NewHeartsService(heartsRepo)
+	if maxHearts, ok := config.GetValue(heartsMaxKey).LookupInt(); ok {
+		heartsService.SetMaxHearts(maxHearts)
+	}
+	if refillInterval, ok := config.GetValue(heartsRefillIntervalKey).LookupDuration(); ok {
+		heartsService.SetRefillInterval(refillInterval)
+	}
+	sessionService.WithHeartsService(heartsService)
+	dataService.WithHeartsService(heartsService)
 
 	appService := admin.NewApp(app)
 	appService.WithUserService(userService)
@@ -179,11 +350,19 @@ func main() {
 	appService.WithModulesSerivce(modulesService)
 	appService.WithLessonService(lessonService)
 	appService.WithExerciseService(exerciseService)
+	appService.WithRevisionService(revisionService)
+	appService.WithBundleService(bundleService)
+	appService.WithIntegrityService(integrityService)
+	appService.WithSearchService(searchService)
 	appService.WithAchievementService(achievementService)
 	appService.WithFriendshipService(friendshipService)
+	appService.WithActivityService(activityService)
+	appService.WithNotificationService(notificationService)
 	appService.WithDataService(dataService)
 	appService.WithProgressService(progressService)
 	appService.WithAvatarService(avatarService)
+	appService.WithMediaService(mediaService)
+	appService.WithSessionService(sessionService)
 	appService.SetHandlers()
 
 	port := config.GetValue(appPortKey).String()
@@ -205,8 +384,28 @@ func main() {
 		}
 	}
 
+	// open notification streams would hold the shutdown until the timeout
+	notificationService.Close()
+
 	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	_ = app.ShutdownWithContext(shutdownCtx)
 	logger.Info("application closed")
 }
+
+// cachePolicyFromConfig reads data_cache_<family>_TTL, data_cache_<family>_stale_TTL
+// and data_cache_<family>_negative_TTL, the family keeps default policy if TTL is not set
+func cachePolicyFromConfig(family string) (data.CachePolicy, bool) {
+	prefix := "data_cache_" + family
+
+	ttl, ok := config.GetValue(prefix + "_TTL").LookupDuration()
+	if !ok {
+		return data.CachePolicy{}, false
+	}
+
+	return data.CachePolicy{
+		TTL:         ttl,
+		StaleTTL:    config.GetValue(prefix + "_stale_TTL").Duration(),
+		NegativeTTL: config.GetValue(prefix + "_negative_TTL").Duration(),
+	}, true
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"uiren/internal/infrastracture/storage"
+	"uiren/pkg/config"
+)
+
+var (
+	storageBackendKey   = "storage_backend"
+	storagePublicURLKey = "storage_public_url"
+	s3EndpointKey       = "s3_endpoint"
+	s3RegionKey         = "s3_region"
+	s3BucketKey         = "s3_bucket"
+	s3PathStyleKey      = "s3_path_style"
+	s3PublicURLKey      = "s3_public_url"
+	s3URLTTLKey         = "s3_url_TTL"
+)
+
+const (
+	storageBackendFileSystem = "filesystem"
+	storageBackendS3         = "s3"
+	// served by the app as /api/storage
+	fileSystemStorageRoot = "./storage"
+)
+
+// objectStorageFromConfig returns the storage chosen by storage_backend, filesystem by default,
+// s3 keys are read from S3_ACCESS_KEY and S3_SECRET_KEY
+func objectStorageFromConfig(port string) (storage.Storage, error) {
+	backend, ok := config.GetValue(storageBackendKey).LookupString()
+	if !ok {
+		backend = storageBackendFileSystem
+	}
+
+	switch backend {
+	case storageBackendFileSystem:
+		publicURL, ok := config.GetValue(storagePublicURLKey).LookupString()
+		if !ok {
+			publicURL = fmt.Sprintf("http://localhost:%s/api/storage", port)
+		}
+		return storage.NewFileSystemStorage(fileSystemStorageRoot, publicURL), nil
+	case storageBackendS3:
+		pathStyle, _ := config.GetValue(s3PathStyleKey).LookupBoolean()
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  config.GetValue(s3EndpointKey).String(),
+			Region:    config.GetValue(s3RegionKey).String(),
+			Bucket:    config.GetValue(s3BucketKey).String(),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PathStyle: pathStyle,
+			PublicURL: config.GetValue(s3PublicURLKey).String(),
+			URLTTL:    config.GetValue(s3URLTTLKey).Duration(),
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
@@ -47,6 +47,15 @@ type LevelData struct {
 	Threshold int
 }
 
+// SortFields lists fields the achievements list can be sorted by, the first one is the default
+var SortFields = []string{"created_at", "name", "id"}
+
+// ListFilter narrows the achievements list, nil fields are not applied
+type ListFilter struct {
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
 //for repo
 
 type UpdateAchievementDTO struct {
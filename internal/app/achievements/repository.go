@@ -3,6 +3,10 @@ package achievements
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"uiren/pkg/pagination"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -72,6 +76,80 @@ func (r *achievementRepository) getAllAchievements(ctx context.Context) ([]achie
 	return response, nil
 }
 
+func (r *achievementRepository) listAchievements(ctx context.Context, filter ListFilter, params pagination.Params) ([]achievement, error) {
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []interface{}
+		response   []achievement
+	)
+
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	if params.After != nil {
+		id, err := strconv.Atoi(params.After.Key)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+
+		var value interface{} = params.After.Value
+		if params.SortBy == "created_at" {
+			createdAt, err := params.After.TimeValue()
+			if err != nil {
+				return nil, err
+			}
+			value = createdAt
+		}
+		condition, afterArgs := pagination.PostgresAfter(params, params.SortBy, value, "id", id, len(args)+1)
+		args = append(args, afterArgs...)
+		conditions = append(conditions, condition)
+	}
+
+	args = append(args, params.Fetch())
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			name,
+			created_at,
+			updated_at
+		FROM
+			achievements
+		WHERE
+			%s
+		ORDER BY
+			%s
+		LIMIT $%d;
+		`, strings.Join(conditions, " AND "), pagination.PostgresOrderBy(params, params.SortBy, "id"), len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var achievement achievement
+		if err := rows.Scan(
+			&achievement.id,
+			&achievement.name,
+			&achievement.createdAt,
+			&achievement.updatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		response = append(response, achievement)
+	}
+
+	return response, rows.Err()
+}
+
 func (r *achievementRepository) getAchievement(ctx context.Context, id int) (achievement, error) {
 	var (
 		query = `
@@ -2,7 +2,10 @@ package achievements
 
 import (
 	"context"
+	"strconv"
+	"uiren/internal/app/events"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -11,6 +14,7 @@ import (
 
 type achievementRepo interface {
 	getAllAchievements(ctx context.Context) ([]achievement, error)
+	listAchievements(ctx context.Context, filter ListFilter, params pagination.Params) ([]achievement, error)
 	getAchievement(ctx context.Context, id int) (achievement, error)
 	createAchievement(ctx context.Context, name string) (achievement, error)
 	updateAchievement(ctx context.Context, dto UpdateAchievementDTO) (string, error)
@@ -33,8 +37,13 @@ type transaction interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (commandTag pgconn.CommandTag, err error)
 }
 
+type publisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
 type AchievementService struct {
 	achievementRepo achievementRepo
+	publisher       publisher
 }
 
 func NewAchievementService(achievementRepo achievementRepo) *AchievementService {
@@ -43,6 +52,10 @@ func NewAchievementService(achievementRepo achievementRepo) *AchievementService
 	}
 }
 
+func (s *AchievementService) WithPublisher(publisher publisher) {
+	s.publisher = publisher
+}
+
 func (s AchievementService) CreateAchievement(ctx context.Context, name string) (AchievementDTO, error) {
 	logger.Info("AchievementService.CreateAchievement new request")
 
@@ -52,6 +65,8 @@ func (s AchievementService) CreateAchievement(ctx context.Context, name string)
 		return AchievementDTO{}, err
 	}
 
+	s.publish(ctx, achievement.id)
+
 	emptyLevelsList := []AchievementLevel{}
 	return achievement.toDTO(emptyLevelsList), nil
 }
@@ -64,6 +79,7 @@ func (s AchievementService) UpdateAchievement(ctx context.Context, dto UpdateAch
 		logger.Error("AchievementService.UpdateAchievement achievementRepo.updateAchievement: ", err)
 		return "", err
 	}
+	s.publish(ctx, dto.ID)
 
 	return newName, nil
 }
@@ -79,6 +95,8 @@ func (s AchievementService) DeleteAchievement(ctx context.Context, id int) error
 	if err := s.achievementRepo.deleteAchievementLevelsByID(ctx, id); err != nil {
 		logger.Error("AchievementService.DeleteAchievement achievementRepo.deleteAchievementLevelsByID: ", err)
 	}
+	s.publish(ctx, id)
+
 	return nil
 }
 
@@ -121,6 +139,8 @@ func (s AchievementService) AddAchievementLevel(ctx context.Context, dto AddAchi
 		logger.Error("AchievementService.AddAchievementLevel achievementRepo.addLevel: ", err)
 		return err
 	}
+	s.publish(ctx, dto.AchID)
+
 	return nil
 }
 
@@ -155,6 +175,8 @@ func (s AchievementService) DeleteAchievementLevel(ctx context.Context, dto Dele
 	}
 
 	commited = true
+	s.publish(ctx, dto.AchID)
+
 	return nil
 }
 
@@ -192,3 +214,50 @@ func (s AchievementService) GetAllAchievements(ctx context.Context) ([]Achieveme
 
 	return result, nil
 }
+
+// ListAchievements returns one page of achievements with their levels
+func (s AchievementService) ListAchievements(ctx context.Context, filter ListFilter, params pagination.Params) (pagination.Page[AchievementDTO], error) {
+	logger.Info("AchievementService.ListAchievements new request")
+
+	achievements, err := s.achievementRepo.listAchievements(ctx, filter, params)
+	if err != nil {
+		logger.Error("AchievementService.ListAchievements achievementRepo.listAchievements: ", err)
+		return pagination.Page[AchievementDTO]{}, err
+	}
+
+	page := pagination.NewPage(achievements, params, achievementCursor)
+
+	result := make([]AchievementDTO, 0, len(page.Items))
+	for _, achievement := range page.Items {
+		levels, err := s.achievementRepo.getLevelsByAchievementID(ctx, achievement.id)
+		if err != nil {
+			logger.Error("AchievementService.ListAchievements achievementRepo.getLevelsByAchievementID: ", err)
+			return pagination.Page[AchievementDTO]{}, err
+		}
+
+		result = append(result, achievement.toDTO(levels))
+	}
+
+	return pagination.Page[AchievementDTO]{Items: result, NextCursor: page.NextCursor}, nil
+}
+
+func achievementCursor(achievement achievement, sortBy string) (string, string) {
+	id := strconv.Itoa(achievement.id)
+	switch sortBy {
+	case "name":
+		return achievement.name, id
+	case "id":
+		return id, id
+	default:
+		return pagination.FormatTime(achievement.createdAt), id
+	}
+}
+
+// publish notifies subscribers that the achievement or its levels changed
+func (s AchievementService) publish(ctx context.Context, id int) {
+	if s.publisher == nil {
+		return
+	}
+
+	s.publisher.Publish(ctx, events.Event{Type: events.AchievementChanged, Code: strconv.Itoa(id)})
+}
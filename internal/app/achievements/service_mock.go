@@ -7,6 +7,8 @@ package achievements
 import (
 	context "context"
 	reflect "reflect"
+	events "uiren/internal/app/events"
+	pagination "uiren/pkg/pagination"
 
 	gomock "github.com/golang/mock/gomock"
 	pgconn "github.com/jackc/pgx/v5/pgconn"
@@ -210,6 +212,21 @@ func (mr *MockachievementRepoMockRecorder) getLevelsByAchievementID(ctx, achID i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getLevelsByAchievementID", reflect.TypeOf((*MockachievementRepo)(nil).getLevelsByAchievementID), ctx, achID)
 }
 
+// listAchievements mocks base method.
+func (m *MockachievementRepo) listAchievements(ctx context.Context, filter ListFilter, params pagination.Params) ([]achievement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "listAchievements", ctx, filter, params)
+	ret0, _ := ret[0].([]achievement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// listAchievements indicates an expected call of listAchievements.
+func (mr *MockachievementRepoMockRecorder) listAchievements(ctx, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "listAchievements", reflect.TypeOf((*MockachievementRepo)(nil).listAchievements), ctx, filter, params)
+}
+
 // updateAchievement mocks base method.
 func (m *MockachievementRepo) updateAchievement(ctx context.Context, dto UpdateAchievementDTO) (string, error) {
 	m.ctrl.T.Helper()
@@ -295,3 +312,38 @@ func (mr *MocktransactionMockRecorder) Rollback(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*Mocktransaction)(nil).Rollback), ctx)
 }
+
+// Mockpublisher is a mock of publisher interface.
+type Mockpublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockpublisherMockRecorder
+}
+
+// MockpublisherMockRecorder is the mock recorder for Mockpublisher.
+type MockpublisherMockRecorder struct {
+	mock *Mockpublisher
+}
+
+// NewMockpublisher creates a new mock instance.
+func NewMockpublisher(ctrl *gomock.Controller) *Mockpublisher {
+	mock := &Mockpublisher{ctrl: ctrl}
+	mock.recorder = &MockpublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockpublisher) EXPECT() *MockpublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *Mockpublisher) Publish(ctx context.Context, event events.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", ctx, event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockpublisherMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*Mockpublisher)(nil).Publish), ctx, event)
+}
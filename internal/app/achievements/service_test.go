@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -744,3 +745,39 @@ func Test_achievementService_GetAllAchievements(t *testing.T) {
 		assert.Nil(t, result)
 	})
 }
+
+func Test_achievementService_ListAchievements(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		ctrl         = gomock.NewController(t)
+		repo         = NewMockachievementRepo(ctrl)
+		service      = &AchievementService{achievementRepo: repo}
+		params       = pagination.Params{Limit: 1, SortBy: "id", Order: pagination.OrderAsc}
+		achievements = []achievement{
+			{1, "Login Streak", time.Now(), time.Now(), nil},
+			{2, "Words Learned", time.Now(), time.Now(), nil},
+		}
+		levels  = []AchievementLevel{{AchID: 1, Level: 1, Threshold: 3}}
+		repoErr = errors.New("error")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().listAchievements(ctx, ListFilter{}, params).Return(achievements, nil)
+		repo.EXPECT().getLevelsByAchievementID(ctx, 1).Return(levels, nil)
+
+		page, err := service.ListAchievements(ctx, ListFilter{}, params)
+		assert.NoError(t, err)
+		assert.Equal(t, []AchievementDTO{achievements[0].toDTO(levels)}, page.Items)
+
+		cursor, err := pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", cursor.Key)
+	})
+
+	t.Run("repo failed", func(t *testing.T) {
+		repo.EXPECT().listAchievements(ctx, ListFilter{}, params).Return(nil, repoErr)
+
+		_, err := service.ListAchievements(ctx, ListFilter{}, params)
+		assert.Equal(t, repoErr, err)
+	})
+}
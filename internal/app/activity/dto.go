@@ -0,0 +1,45 @@
+package activity
+
+import (
+	"time"
+	"uiren/internal/app/events"
+)
+
+const (
+	ReactionCongratulate = "congratulate"
+	ReactionLike         = "like"
+	ReactionFire         = "fire"
+)
+
+var reactions = []string{ReactionCongratulate, ReactionLike, ReactionFire}
+
+// feedTypes are the progress events recorded to the feed
+var feedTypes = []string{
+	events.LessonCompleted,
+	events.ModuleFinished,
+	events.BadgeEarned,
+	events.AchievementLevelUp,
+	events.StreakMilestone,
+}
+
+// SortFields feed is always shown from the newest activity
+var SortFields = []string{"created_at"}
+
+// Activity is one entry of the friends feed. Code is the lesson, module, badge
+// or achievement name, Value is the reached achievement level or the streak length.
+type Activity struct {
+	ID          int64          `json:"id"`
+	Username    string         `json:"username"`
+	Type        string         `json:"type"`
+	Code        string         `json:"code,omitempty"`
+	Value       int            `json:"value,omitempty"`
+	Reactions   map[string]int `json:"reactions"`
+	MyReactions []string       `json:"my_reactions"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type ReactionDTO struct {
+	ActivityID int64  `json:"activity_id"`
+	Username   string `json:"username"`
+	Reaction   string `json:"reaction"`
+}
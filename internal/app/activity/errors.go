@@ -0,0 +1,9 @@
+package activity
+
+import "errors"
+
+var (
+	ErrActivityNotFound = errors.New("activity not found")
+	ErrInvalidReaction  = errors.New("invalid reaction")
+	ErrReactionNotFound = errors.New("reaction not found")
+)
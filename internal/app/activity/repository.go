@@ -0,0 +1,172 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"uiren/internal/app/events"
+	"uiren/pkg/pagination"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewActivityRepository(db *pgxpool.Pool) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// recordActivity saves the event, the same event of the user is saved once a day,
+// so repeated progress reports don't flood the feed
+func (r *repository) recordActivity(ctx context.Context, event events.Event) error {
+	var (
+		query = `
+		INSERT INTO activities (user_id, type, code, value)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, type, code, value, (created_at::date)) DO NOTHING;
+		`
+	)
+
+	_, err := r.db.Exec(ctx, query, event.UserID, event.Type, event.Code, event.Value)
+	return err
+}
+
+// getFeed returns activities of the users with reactions counts and reactions of the viewer
+func (r *repository) getFeed(ctx context.Context, usernames []string, viewer string, params pagination.Params) ([]Activity, error) {
+	var (
+		conditions = "u.username = ANY($1)"
+		args       = []interface{}{usernames, viewer}
+	)
+
+	if params.After != nil {
+		createdAt, err := params.After.TimeValue()
+		if err != nil {
+			return nil, err
+		}
+		id, err := strconv.ParseInt(params.After.Key, 10, 64)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+
+		condition, afterArgs := pagination.PostgresAfter(params, "a.created_at", createdAt, "a.id", id, len(args)+1)
+		args = append(args, afterArgs...)
+		conditions += " AND " + condition
+	}
+
+	args = append(args, params.Fetch())
+	query := fmt.Sprintf(`
+		SELECT
+			a.id,
+			u.username,
+			a.type,
+			a.code,
+			a.value,
+			a.created_at,
+			COALESCE((
+				SELECT jsonb_object_agg(c.reaction, c.total) FROM (
+					SELECT reaction, COUNT(*) AS total FROM activity_reactions
+					WHERE activity_id = a.id
+					GROUP BY reaction
+				) c
+			), '{}'::jsonb),
+			COALESCE((
+				SELECT array_agg(reaction ORDER BY reaction) FROM activity_reactions
+				WHERE activity_id = a.id AND username = $2
+			), '{}')
+		FROM
+			activities a
+		JOIN users u ON u.id = a.user_id
+		WHERE
+			%s
+		ORDER BY
+			%s
+		LIMIT $%d;
+		`, conditions, pagination.PostgresOrderBy(params, "a.created_at", "a.id"), len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feed []Activity
+	for rows.Next() {
+		var activity Activity
+		if err := rows.Scan(
+			&activity.ID,
+			&activity.Username,
+			&activity.Type,
+			&activity.Code,
+			&activity.Value,
+			&activity.CreatedAt,
+			&activity.Reactions,
+			&activity.MyReactions,
+		); err != nil {
+			return nil, err
+		}
+		feed = append(feed, activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+func (r *repository) getActivityAuthor(ctx context.Context, id int64) (string, error) {
+	var (
+		query = `
+		SELECT u.username FROM activities a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.id = $1;
+		`
+		username string
+	)
+
+	if err := r.db.QueryRow(ctx, query, id).Scan(&username); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrActivityNotFound
+		}
+		return "", err
+	}
+
+	return username, nil
+}
+
+func (r *repository) addReaction(ctx context.Context, dto ReactionDTO) error {
+	var (
+		query = `
+		INSERT INTO activity_reactions (activity_id, username, reaction)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (activity_id, username, reaction) DO NOTHING;
+		`
+	)
+
+	_, err := r.db.Exec(ctx, query, dto.ActivityID, dto.Username, dto.Reaction)
+	return err
+}
+
+func (r *repository) deleteReaction(ctx context.Context, dto ReactionDTO) error {
+	var (
+		query = `
+		DELETE FROM activity_reactions
+		WHERE activity_id = $1 AND username = $2 AND reaction = $3;
+		`
+	)
+
+	commandTag, err := r.db.Exec(ctx, query, dto.ActivityID, dto.Username, dto.Reaction)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrReactionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package activity
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"uiren/internal/app/events"
+	"uiren/internal/app/friendship"
+	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package activity
+
+type activityRepository interface {
+	recordActivity(ctx context.Context, event events.Event) error
+	getFeed(ctx context.Context, usernames []string, viewer string, params pagination.Params) ([]Activity, error)
+	getActivityAuthor(ctx context.Context, id int64) (string, error)
+	addReaction(ctx context.Context, dto ReactionDTO) error
+	deleteReaction(ctx context.Context, dto ReactionDTO) error
+}
+
+type friendshipService interface {
+	GetFriendList(ctx context.Context, username string) (friendship.FriendList, error)
+}
+
+type ActivityService struct {
+	activityRepository activityRepository
+	friendshipService  friendshipService
+}
+
+func NewActivityService(activityRepository activityRepository, friendshipService friendshipService) *ActivityService {
+	return &ActivityService{
+		activityRepository: activityRepository,
+		friendshipService:  friendshipService,
+	}
+}
+
+// HandleEvent records progress events to the feed, it is subscribed to the events bus.
+// Progress is already saved when the event comes, so errors are only logged.
+func (s *ActivityService) HandleEvent(ctx context.Context, event events.Event) {
+	if !slices.Contains(feedTypes, event.Type) || event.UserID == "" {
+		return
+	}
+
+	if err := s.activityRepository.recordActivity(ctx, event); err != nil {
+		logger.Error("ActivityService.HandleEvent activityRepository.recordActivity: ", err)
+	}
+}
+
+// GetFeed returns activities of the user's accepted friends from the newest
+func (s *ActivityService) GetFeed(ctx context.Context, username string, params pagination.Params) (pagination.Page[Activity], error) {
+	logger.Info("ActivityService.GetFeed new request")
+
+	friends, err := s.friendshipService.GetFriendList(ctx, username)
+	if err != nil {
+		logger.Error("ActivityService.GetFeed friendshipService.GetFriendList: ", err)
+		return pagination.Page[Activity]{}, err
+	}
+	if len(friends.Friends) == 0 {
+		return pagination.NewPage[Activity](nil, params, activityCursor), nil
+	}
+
+	usernames := make([]string, 0, len(friends.Friends))
+	for _, friend := range friends.Friends {
+		usernames = append(usernames, friend.Username)
+	}
+
+	feed, err := s.activityRepository.getFeed(ctx, usernames, username, params)
+	if err != nil {
+		logger.Error("ActivityService.GetFeed activityRepository.getFeed: ", err)
+		return pagination.Page[Activity]{}, err
+	}
+
+	return pagination.NewPage(feed, params, activityCursor), nil
+}
+
+// React adds the reaction to the activity of the user's friend, reacting twice changes nothing
+func (s *ActivityService) React(ctx context.Context, dto ReactionDTO) error {
+	logger.Info("ActivityService.React new request")
+
+	if !slices.Contains(reactions, dto.Reaction) {
+		return ErrInvalidReaction
+	}
+
+	if err := s.checkVisible(ctx, dto.ActivityID, dto.Username); err != nil {
+		logger.Error("ActivityService.React checkVisible: ", err)
+		return err
+	}
+
+	if err := s.activityRepository.addReaction(ctx, dto); err != nil {
+		logger.Error("ActivityService.React activityRepository.addReaction: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *ActivityService) Unreact(ctx context.Context, dto ReactionDTO) error {
+	logger.Info("ActivityService.Unreact new request")
+
+	if !slices.Contains(reactions, dto.Reaction) {
+		return ErrInvalidReaction
+	}
+
+	if err := s.activityRepository.deleteReaction(ctx, dto); err != nil {
+		logger.Error("ActivityService.Unreact activityRepository.deleteReaction: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// checkVisible returns ErrActivityNotFound when the activity is not in the user's feed
+func (s *ActivityService) checkVisible(ctx context.Context, activityID int64, username string) error {
+	author, err := s.activityRepository.getActivityAuthor(ctx, activityID)
+	if err != nil {
+		return err
+	}
+
+	friends, err := s.friendshipService.GetFriendList(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	for _, friend := range friends.Friends {
+		if friend.Username == author {
+			return nil
+		}
+	}
+
+	return ErrActivityNotFound
+}
+
+func activityCursor(activity Activity, _ string) (string, string) {
+	return pagination.FormatTime(activity.CreatedAt), strconv.FormatInt(activity.ID, 10)
+}
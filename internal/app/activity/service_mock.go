@@ -0,0 +1,148 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package activity is a generated GoMock package.
+package activity
+
+import (
+	context "context"
+	reflect "reflect"
+	events "uiren/internal/app/events"
+	friendship "uiren/internal/app/friendship"
+	pagination "uiren/pkg/pagination"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockactivityRepository is a mock of activityRepository interface.
+type MockactivityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockactivityRepositoryMockRecorder
+}
+
+// MockactivityRepositoryMockRecorder is the mock recorder for MockactivityRepository.
+type MockactivityRepositoryMockRecorder struct {
+	mock *MockactivityRepository
+}
+
+// NewMockactivityRepository creates a new mock instance.
+func NewMockactivityRepository(ctrl *gomock.Controller) *MockactivityRepository {
+	mock := &MockactivityRepository{ctrl: ctrl}
+	mock.recorder = &MockactivityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockactivityRepository) EXPECT() *MockactivityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// addReaction mocks base method.
+func (m *MockactivityRepository) addReaction(ctx context.Context, dto ReactionDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "addReaction", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// addReaction indicates an expected call of addReaction.
+func (mr *MockactivityRepositoryMockRecorder) addReaction(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "addReaction", reflect.TypeOf((*MockactivityRepository)(nil).addReaction), ctx, dto)
+}
+
+// deleteReaction mocks base method.
+func (m *MockactivityRepository) deleteReaction(ctx context.Context, dto ReactionDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "deleteReaction", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// deleteReaction indicates an expected call of deleteReaction.
+func (mr *MockactivityRepositoryMockRecorder) deleteReaction(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "deleteReaction", reflect.TypeOf((*MockactivityRepository)(nil).deleteReaction), ctx, dto)
+}
+
+// getActivityAuthor mocks base method.
+func (m *MockactivityRepository) getActivityAuthor(ctx context.Context, id int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getActivityAuthor", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getActivityAuthor indicates an expected call of getActivityAuthor.
+func (mr *MockactivityRepositoryMockRecorder) getActivityAuthor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getActivityAuthor", reflect.TypeOf((*MockactivityRepository)(nil).getActivityAuthor), ctx, id)
+}
+
+// getFeed mocks base method.
+func (m *MockactivityRepository) getFeed(ctx context.Context, usernames []string, viewer string, params pagination.Params) ([]Activity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getFeed", ctx, usernames, viewer, params)
+	ret0, _ := ret[0].([]Activity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getFeed indicates an expected call of getFeed.
+func (mr *MockactivityRepositoryMockRecorder) getFeed(ctx, usernames, viewer, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getFeed", reflect.TypeOf((*MockactivityRepository)(nil).getFeed), ctx, usernames, viewer, params)
+}
+
+// recordActivity mocks base method.
+func (m *MockactivityRepository) recordActivity(ctx context.Context, event events.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "recordActivity", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// recordActivity indicates an expected call of recordActivity.
+func (mr *MockactivityRepositoryMockRecorder) recordActivity(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "recordActivity", reflect.TypeOf((*MockactivityRepository)(nil).recordActivity), ctx, event)
+}
+
+// MockfriendshipService is a mock of friendshipService interface.
+type MockfriendshipService struct {
+	ctrl     *gomock.Controller
+	recorder *MockfriendshipServiceMockRecorder
+}
+
+// MockfriendshipServiceMockRecorder is the mock recorder for MockfriendshipService.
+type MockfriendshipServiceMockRecorder struct {
+	mock *MockfriendshipService
+}
+
+// NewMockfriendshipService creates a new mock instance.
+func NewMockfriendshipService(ctrl *gomock.Controller) *MockfriendshipService {
+	mock := &MockfriendshipService{ctrl: ctrl}
+	mock.recorder = &MockfriendshipServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockfriendshipService) EXPECT() *MockfriendshipServiceMockRecorder {
+	return m.recorder
+}
+
+// GetFriendList mocks base method.
+func (m *MockfriendshipService) GetFriendList(ctx context.Context, username string) (friendship.FriendList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFriendList", ctx, username)
+	ret0, _ := ret[0].(friendship.FriendList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFriendList indicates an expected call of GetFriendList.
+func (mr *MockfriendshipServiceMockRecorder) GetFriendList(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFriendList", reflect.TypeOf((*MockfriendshipService)(nil).GetFriendList), ctx, username)
+}
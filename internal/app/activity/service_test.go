@@ -0,0 +1,176 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"uiren/internal/app/events"
+	"uiren/internal/app/friendship"
+	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+func Test_ActivityService_HandleEvent(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockactivityRepository(ctrl)
+		service = NewActivityService(repo, nil)
+	)
+
+	t.Run("progress event recorded", func(t *testing.T) {
+		event := events.Event{Type: events.BadgeEarned, Code: "badge1", UserID: "user-1"}
+		repo.EXPECT().recordActivity(ctx, event).Return(nil)
+
+		service.HandleEvent(ctx, event)
+	})
+
+	t.Run("content event ignored", func(t *testing.T) {
+		repo.EXPECT().recordActivity(gomock.Any(), gomock.Any()).Times(0)
+
+		service.HandleEvent(ctx, events.Event{Type: events.LessonChanged, Code: "lesson-1"})
+	})
+
+	t.Run("repo error is not propagated", func(t *testing.T) {
+		event := events.Event{Type: events.StreakMilestone, UserID: "user-1", Value: 7}
+		repo.EXPECT().recordActivity(ctx, event).Return(errors.New("db error"))
+
+		service.HandleEvent(ctx, event)
+	})
+}
+
+func Test_ActivityService_GetFeed(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx           = context.TODO()
+		ctrl          = gomock.NewController(t)
+		repo          = NewMockactivityRepository(ctrl)
+		friendService = NewMockfriendshipService(ctrl)
+		service       = NewActivityService(repo, friendService)
+		params        = pagination.Params{Limit: 2, SortBy: "created_at", Order: pagination.OrderDesc}
+		now           = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		friends       = friendship.FriendList{
+			Friends: []friendship.FriendListEntity{{Username: "asan2"}, {Username: "s4ab"}},
+			Total:   2,
+		}
+		feed = []Activity{
+			{ID: 3, Username: "asan2", Type: events.LessonCompleted, Code: "lesson-1", CreatedAt: now},
+			{ID: 2, Username: "s4ab", Type: events.BadgeEarned, Code: "badge1", CreatedAt: now.Add(-time.Hour)},
+			{ID: 1, Username: "s4ab", Type: events.StreakMilestone, Value: 7, CreatedAt: now.Add(-2 * time.Hour)},
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		friendService.EXPECT().GetFriendList(ctx, "seab").Return(friends, nil)
+		repo.EXPECT().getFeed(ctx, []string{"asan2", "s4ab"}, "seab", params).Return(feed, nil)
+
+		page, err := service.GetFeed(ctx, "seab", params)
+		assert.NoError(t, err)
+		assert.Equal(t, page.Items, feed[:2])
+
+		cursor, err := pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, cursor.Key, "2")
+		assert.Equal(t, cursor.Value, pagination.FormatTime(feed[1].CreatedAt))
+	})
+
+	t.Run("no friends", func(t *testing.T) {
+		friendService.EXPECT().GetFriendList(ctx, "seab").Return(friendship.FriendList{}, nil)
+		repo.EXPECT().getFeed(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		page, err := service.GetFeed(ctx, "seab", params)
+		assert.NoError(t, err)
+		assert.Equal(t, page.Items, []Activity{})
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("friendship service error", func(t *testing.T) {
+		errFriends := errors.New("friends error")
+		friendService.EXPECT().GetFriendList(ctx, "seab").Return(friendship.FriendList{}, errFriends)
+
+		_, err := service.GetFeed(ctx, "seab", params)
+		assert.Equal(t, err, errFriends)
+	})
+}
+
+func Test_ActivityService_React(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx           = context.TODO()
+		ctrl          = gomock.NewController(t)
+		repo          = NewMockactivityRepository(ctrl)
+		friendService = NewMockfriendshipService(ctrl)
+		service       = NewActivityService(repo, friendService)
+		friends       = friendship.FriendList{
+			Friends: []friendship.FriendListEntity{{Username: "asan2"}},
+			Total:   1,
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		dto := ReactionDTO{ActivityID: 1, Username: "seab", Reaction: ReactionCongratulate}
+		repo.EXPECT().getActivityAuthor(ctx, int64(1)).Return("asan2", nil)
+		friendService.EXPECT().GetFriendList(ctx, "seab").Return(friends, nil)
+		repo.EXPECT().addReaction(ctx, dto).Return(nil)
+
+		err := service.React(ctx, dto)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid reaction", func(t *testing.T) {
+		err := service.React(ctx, ReactionDTO{ActivityID: 1, Username: "seab", Reaction: "boo"})
+		assert.Equal(t, err, ErrInvalidReaction)
+	})
+
+	t.Run("not a friend", func(t *testing.T) {
+		dto := ReactionDTO{ActivityID: 2, Username: "seab", Reaction: ReactionLike}
+		repo.EXPECT().getActivityAuthor(ctx, int64(2)).Return("stranger", nil)
+		friendService.EXPECT().GetFriendList(ctx, "seab").Return(friends, nil)
+
+		err := service.React(ctx, dto)
+		assert.Equal(t, err, ErrActivityNotFound)
+	})
+
+	t.Run("activity not found", func(t *testing.T) {
+		dto := ReactionDTO{ActivityID: 3, Username: "seab", Reaction: ReactionLike}
+		repo.EXPECT().getActivityAuthor(ctx, int64(3)).Return("", ErrActivityNotFound)
+
+		err := service.React(ctx, dto)
+		assert.Equal(t, err, ErrActivityNotFound)
+	})
+}
+
+func Test_ActivityService_Unreact(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockactivityRepository(ctrl)
+		service = NewActivityService(repo, nil)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		dto := ReactionDTO{ActivityID: 1, Username: "seab", Reaction: ReactionCongratulate}
+		repo.EXPECT().deleteReaction(ctx, dto).Return(nil)
+
+		err := service.Unreact(ctx, dto)
+		assert.NoError(t, err)
+	})
+
+	t.Run("reaction not found", func(t *testing.T) {
+		dto := ReactionDTO{ActivityID: 1, Username: "seab", Reaction: ReactionLike}
+		repo.EXPECT().deleteReaction(ctx, dto).Return(ErrReactionNotFound)
+
+		err := service.Unreact(ctx, dto)
+		assert.Equal(t, err, ErrReactionNotFound)
+	})
+}
@@ -139,11 +139,27 @@ func (app *App) getAllAchievements(c *fiber.Ctx) error {
 	var (
 		ctx = c.Context()
 	)
+	logger.Info("app.getAllAchievements handler")
 
-	resp, err := app.achievementService.GetAllAchievements(ctx)
+	params, err := parsePagination(c, achievements.SortFields)
 	if err != nil {
-		logger.Error("app.getAllAchievements achievementService.GetAllAchievements: ", err)
-		return fiberInternalServerError(c)
+		return fiberPaginationError(c, err)
+	}
+
+	createdFrom, createdTo, err := parseCreatedRange(c)
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	filter := achievements.ListFilter{
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+	}
+
+	resp, err := app.achievementService.ListAchievements(ctx, filter, params)
+	if err != nil {
+		logger.Error("app.getAllAchievements achievementService.ListAchievements: ", err)
+		return fiberPaginationError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
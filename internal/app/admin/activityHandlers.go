@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+	"uiren/internal/app/activity"
+	"uiren/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (app *App) getFriendsFeed(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.getFriendsFeed handler")
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	params, err := parsePagination(c, activity.SortFields)
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	feed, err := app.activityService.GetFeed(ctx, username, params)
+	if err != nil {
+		logger.Error("app.getFriendsFeed activityService.GetFeed: ", err)
+		return fiberPaginationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(feed)
+}
+
+func (app *App) reactToActivity(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		req ReactionReq
+	)
+	logger.Info("app.reactToActivity handler")
+
+	dto, err := reactionFromRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.reactToActivity c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	dto.Reaction = req.Reaction
+
+	if err := app.activityService.React(ctx, dto); err != nil {
+		logger.Error("app.reactToActivity activityService.React: ", err)
+		return fiberActivityError(c, err)
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) deleteActivityReaction(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.deleteActivityReaction handler")
+
+	dto, err := reactionFromRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	dto.Reaction = c.Params("reaction")
+
+	if err := app.activityService.Unreact(ctx, dto); err != nil {
+		logger.Error("app.deleteActivityReaction activityService.Unreact: ", err)
+		return fiberActivityError(c, err)
+	}
+
+	return fiberOK(c)
+}
+
+// reactionFromRequest reads the activity id and the reacting user
+func reactionFromRequest(c *fiber.Ctx) (activity.ReactionDTO, error) {
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return activity.ReactionDTO{}, errors.New(ErrBadRequest + ", incorrect token payload(missing username)")
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return activity.ReactionDTO{}, errors.New(ErrBadRequest + ", invalid activity id")
+	}
+
+	return activity.ReactionDTO{ActivityID: id, Username: username}, nil
+}
+
+func fiberActivityError(c *fiber.Ctx, err error) error {
+	switch err {
+	case activity.ErrInvalidReaction:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case activity.ErrActivityNotFound, activity.ErrReactionNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return fiberInternalServerError(c)
+	}
+}
@@ -62,6 +62,7 @@ func (app *App) register(c *fiber.Ctx) error {
 			Email:    req.Email,
 			Password: req.Password,
 		},
+		Locale: req.Locale,
 	})
 	if err != nil {
 		return returnCreateUserError(c, err)
@@ -98,6 +99,41 @@ func (app *App) verification(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": fmt.Sprintf("%s verified", username)})
 }
 
+func (app *App) resendVerification(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		req ResendVerificationReq
+	)
+	logger.Info("app.resendVerification handler")
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.resendVerification BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest})
+	}
+	if req.Identificator == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", ident required"})
+	}
+
+	if err := app.authService.ResendVerification(ctx, auth.ResendVerificationParams{
+		Identificator: req.Identificator,
+		Locale:        req.Locale,
+	}); err != nil {
+		logger.Error("app.resendVerification error: ", err)
+		switch err {
+		case users.ErrUserNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": users.ErrUserNotFound.Error()})
+		case auth.ErrAlreadyVerified:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": auth.ErrAlreadyVerified.Error()})
+		case auth.ErrResendTooSoon:
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": auth.ErrResendTooSoon.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "verification email sent"})
+}
+
 func (app *App) refreshToken(c *fiber.Ctx) error {
 	var (
 		ctx = c.Context()
@@ -1,6 +1,8 @@
 package admin
 
 import (
+	"errors"
+	"strconv"
 	"uiren/internal/app/avatars"
 	"uiren/pkg/logger"
 
@@ -29,14 +31,86 @@ func (app *App) uploadAvatar(c *fiber.Ctx) error {
 		logger.Error("app.uploadAvatar file.Open: ", err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest})
 	}
+	defer reader.Close()
 
 	if err := app.avatarService.UploadAvatar(ctx, avatars.UploadAvatarRequest{
 		UserId: id,
 		File:   reader,
 	}); err != nil {
 		logger.Error("app.uploadAvatar avatarService.UploadAvatar: ", err)
-		return err
+		return fiberAvatarError(c, err)
 	}
 
 	return fiberOK(c)
 }
+
+// getAvatar redirects to the stored variant, so the avatar link doesn't expire
+func (app *App) getAvatar(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		id  = c.Params("id")
+	)
+
+	size, err := strconv.Atoi(c.Query("size", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": avatars.ErrInvalidSize.Error()})
+	}
+
+	url, err := app.avatarService.GetAvatarURL(ctx, id, size)
+	if errors.Is(err, avatars.ErrAvatarNotFound) {
+		return app.getIdenticon(c, id, size)
+	}
+	if err != nil {
+		logger.Error("app.getAvatar avatarService.GetAvatarURL: ", err)
+		return fiberAvatarError(c, err)
+	}
+
+	return c.Redirect(url, fiber.StatusFound)
+}
+
+// getIdenticon serves the generated avatar, it is cached briefly
+// because the user may upload an avatar any moment
+func (app *App) getIdenticon(c *fiber.Ctx, id string, size int) error {
+	identicon, err := app.avatarService.GetIdenticon(c.Context(), id, size)
+	if err != nil {
+		logger.Error("app.getIdenticon avatarService.GetIdenticon: ", err)
+		return fiberAvatarError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, identicon.ContentType)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).Send(identicon.Content)
+}
+
+func (app *App) deleteAvatar(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+
+	id, ok := c.Locals("id").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect id"})
+	}
+
+	if err := app.avatarService.DeleteAvatar(ctx, id); err != nil {
+		logger.Error("app.deleteAvatar avatarService.DeleteAvatar: ", err)
+		return fiberAvatarError(c, err)
+	}
+
+	return fiberOK(c)
+}
+
+func fiberAvatarError(c *fiber.Ctx, err error) error {
+	switch err {
+	case avatars.ErrUnsupportedType:
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"error": err.Error()})
+	case avatars.ErrAvatarTooLarge:
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": err.Error()})
+	case avatars.ErrTooManyPixels, avatars.ErrInvalidImage, avatars.ErrInvalidSize:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case avatars.ErrAvatarNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return fiberInternalServerError(c)
+	}
+}
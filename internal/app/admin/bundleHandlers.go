@@ -0,0 +1,89 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"uiren/internal/app/bundles"
+	"uiren/internal/app/modules"
+	"uiren/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (app *App) exportModuleBundle(c *fiber.Ctx) error {
+	var (
+		ctx    = c.Context()
+		code   = c.Params("code")
+		format = c.Query("format", bundles.FormatJSON)
+	)
+	logger.Info("app.exportModuleBundle handler")
+
+	bundle, err := app.bundleService.ExportModule(ctx, code)
+	if err != nil {
+		logger.Error("app.exportModuleBundle bundleService.ExportModule: ", err)
+		switch err {
+		case modules.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": modules.ErrNotFound.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	data, err := bundles.Encode(bundle, format)
+	if err != nil {
+		logger.Error("app.exportModuleBundle bundles.Encode: ", err)
+		switch err {
+		case bundles.ErrUnsupportedFormat:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": bundles.ErrUnsupportedFormat.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, bundleContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, code, format))
+	return c.Status(fiber.StatusOK).Send(data)
+}
+
+func (app *App) importBundle(c *fiber.Ctx) error {
+	var (
+		ctx    = c.Context()
+		dryRun = c.QueryBool("dry_run", false)
+		format = c.Query("format")
+	)
+	logger.Info("app.importBundle handler")
+
+	if format == "" {
+		format = bundles.FormatJSON
+		if strings.Contains(c.Get(fiber.HeaderContentType), "yaml") {
+			format = bundles.FormatYAML
+		}
+	}
+
+	bundle, err := bundles.Decode(c.Body(), format)
+	if err != nil {
+		logger.Error("app.importBundle bundles.Decode: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	report, err := app.bundleService.ImportBundle(ctx, bundle, dryRun)
+	if err != nil {
+		logger.Error("app.importBundle bundleService.ImportBundle: ", err)
+		switch {
+		case errors.Is(err, bundles.ErrImportConflicts):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error(), "report": report})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": ErrInternalServerError, "report": report})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+func bundleContentType(format string) string {
+	if format == bundles.FormatYAML {
+		return "application/yaml"
+	}
+	return fiber.MIMEApplicationJSONCharsetUTF8
+}
@@ -1,7 +1,9 @@
 package admin
 
 import (
+	"errors"
 	"strconv"
+	"uiren/internal/app/avatars"
 	"uiren/internal/app/data"
 	"uiren/internal/app/exercises"
 	"uiren/internal/app/lessons"
@@ -45,7 +47,17 @@ func (app *App) getLessonToPass(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.Status(fiber.StatusOK).JSON(lesson)
+	learner := learnerLesson{LessonDTO: lesson, Exercises: make([]exercises.LearnerExercise, 0, len(lesson.Exercises))}
+	for _, exercise := range lesson.Exercises {
+		learnerExercise, err := app.exerciseService.ToLearner(exercise)
+		if err != nil {
+			logger.Error("app.getLessonToPass exerciseService.ToLearner: ", err)
+			return fiberInternalServerError(c)
+		}
+		learner.Exercises = append(learner.Exercises, learnerExercise)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(learner)
 }
 
 func (app *App) getExerciseToPass(c *fiber.Ctx) error {
@@ -68,7 +80,79 @@ func (app *App) getExerciseToPass(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.Status(fiber.StatusOK).JSON(exercise)
+	learner, err := app.exerciseService.ToLearner(exercise)
+	if err != nil {
+		logger.Error("app.getExerciseToPass exerciseService.ToLearner: ", err)
+		return fiberInternalServerError(c)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(learner)
+}
+
+// gradeExercise checks the answer on the server, so clients don't need the correct answers
+func (app *App) gradeExercise(c *fiber.Ctx) error {
+	var (
+		ctx    = c.Context()
+		code   = c.Query("code")
+		answer exercises.Answer
+	)
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", code required"})
+	}
+
+	if err := c.BodyParser(&answer); err != nil {
+		logger.Error("app.gradeExercise c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := app.exerciseService.GradeAnswer(ctx, code, answer)
+	if err != nil {
+		logger.Error("app.gradeExercise exerciseService.GradeAnswer: ", err)
+		switch err {
+		case exercises.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": exercises.ErrNotFound.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// gradeLessonAnswers grades the whole lesson at once, XP is weighted by the score of every exercise
+func (app *App) gradeLessonAnswers(c *fiber.Ctx) error {
+	var (
+		ctx  = c.Context()
+		code = c.Query("code")
+		req  GradeLessonReq
+	)
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", code required"})
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.gradeLessonAnswers c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	lesson, err := app.dataService.GetPublicLesson(ctx, code)
+	if err != nil {
+		logger.Error("app.gradeLessonAnswers dataService.GetPublicLesson: ", err)
+		switch err {
+		case lessons.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": lessons.ErrNotFound.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	grade, err := app.exerciseService.GradeLesson(lesson.Exercises, req.Answers)
+	if err != nil {
+		logger.Error("app.gradeLessonAnswers exerciseService.GradeLesson: ", err)
+		return fiberInternalServerError(c)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(grade)
 }
 
 func (app *App) getUserInfo(c *fiber.Ctx) error {
@@ -102,7 +186,16 @@ func (app *App) getUserInfo(c *fiber.Ctx) error {
 		return getUserError(c, err)
 	}
 
-	userInfo.AvatarURL = c.BaseURL() + "/api/storage/avatars/avatar-" + userInfo.ID + ".png"
+	userInfo.AvatarURL, err = app.avatarService.GetAvatarURL(ctx, userInfo.ID, avatars.DefaultSize)
+	if err != nil {
+		if !errors.Is(err, avatars.ErrAvatarNotFound) {
+			logger.Error("app.getUserByUsername avatarService.GetAvatarURL: ", err)
+		}
+		// without the uploaded avatar the link serves the identicon
+		userInfo.AvatarURL = c.BaseURL() + "/api/avatar/" + userInfo.ID
+	} else {
+		userInfo.HasAvatar = true
+	}
 
 	return c.Status(fiber.StatusOK).JSON(userInfo)
 }
@@ -112,7 +205,12 @@ func (app *App) getXPLeaderboard(c *fiber.Ctx) error {
 		ctx = c.Context()
 	)
 
-	leaderboard, err := app.dataService.GetXPLeaderboard(ctx)
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	leaderboard, err := app.dataService.GetXPLeaderboard(ctx, username)
 	if err != nil {
 		logger.Error("app.getXPLeaderboard dataService.GetXPLeaderboard: ", err)
 		return fiberInternalServerError(c)
@@ -1,11 +1,17 @@
 package admin
 
+import (
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/lessons"
+)
+
 // users
 type (
 	CreateUserReq struct {
 		Username string `json:"username"`
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Locale   string `json:"locale"`
 	}
 
 	UpdateUserReq struct {
@@ -14,6 +20,21 @@ type (
 		Phone       string `json:"phone"`
 		PhoneRegion string `json:"phone_region"`
 	}
+
+	UpdatePrivacyReq struct {
+		IsSearchable *bool `json:"is_searchable"`
+	}
+)
+
+// friends
+type (
+	BlockUserReq struct {
+		Username string `json:"username"`
+	}
+
+	ReactionReq struct {
+		Reaction string `json:"reaction"`
+	}
 )
 
 // auth
@@ -23,6 +44,11 @@ type (
 		Password      string `json:"password"`
 	}
 
+	ResendVerificationReq struct {
+		Identificator string `json:"ident"`
+		Locale        string `json:"locale"`
+	}
+
 	RefreshTokenParams struct {
 		Token string `json:"refresh_token"`
 	}
@@ -48,3 +74,26 @@ type (
 		Level int `json:"level"`
 	}
 )
+
+// data
+type (
+	// learnerLesson replaces exercises of the lesson with their learner view
+	learnerLesson struct {
+		lessons.LessonDTO
+		Exercises []exercises.LearnerExercise `json:"exercises"`
+	}
+
+	// GradeLessonReq has answers by exercise codes
+	GradeLessonReq struct {
+		Answers map[string]exercises.Answer `json:"answers"`
+	}
+)
+
+// lesson sessions
+type (
+	StartSessionReq struct {
+		LessonCode string `json:"lesson_code"`
+		// Practice starts the lesson without costing hearts, it earns them back
+		Practice bool `json:"practice"`
+	}
+)
@@ -3,6 +3,9 @@ package admin
 import (
 	"errors"
 	"strings"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/lessons"
+	"uiren/internal/app/publication"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -112,6 +115,24 @@ func fiberFormFileError(c *fiber.Ctx, err error) error {
 		return fiberInternalServerError(c)
 	}
 }
+
+// fiberStatusError maps publication lifecycle errors, referenced content that is missing
+// or unpublished comes wrapped with the list of codes
+func fiberStatusError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, publication.ErrInvalidStatus):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, publication.ErrInvalidTransition), errors.Is(err, publication.ErrStatusChanged):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, publication.ErrNotPublished),
+		errors.Is(err, lessons.ErrNotFound),
+		errors.Is(err, exercises.ErrNotFound):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return fiberInternalServerError(c)
+	}
+}
+
 func invalidMultipartFileError(err error) error {
 	if strings.Contains(err.Error(), "bad boundary") ||
 		strings.Contains(err.Error(), "not multipart/form-data") {
@@ -2,7 +2,9 @@ package admin
 
 import (
 	"encoding/json"
+	"errors"
 	"uiren/internal/app/exercises"
+	"uiren/internal/app/publication"
 	"uiren/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,16 +14,61 @@ func (app *App) getAllExercises(c *fiber.Ctx) error {
 	var (
 		ctx = c.Context()
 	)
+	logger.Info("app.getAllExercises handler")
 
-	resp, err := app.exerciseService.GetAllExercises(ctx)
+	params, err := parsePagination(c, exercises.SortFields)
 	if err != nil {
-		logger.Error("app.getAllExercises exerciseService.GetAllExercises: ", err)
-		return fiberInternalServerError(c)
+		return fiberPaginationError(c, err)
+	}
+
+	createdFrom, createdTo, err := parseCreatedRange(c)
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	filter := exercises.ListFilter{
+		Type:        c.Query("type"),
+		Status:      c.Query("status"),
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+	}
+
+	resp, err := app.exerciseService.ListExercises(ctx, filter, params)
+	if err != nil {
+		logger.Error("app.getAllExercises exerciseService.ListExercises: ", err)
+		return fiberPaginationError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
 
+func (app *App) getExerciseTypes(c *fiber.Ctx) error {
+	logger.Info("app.getExerciseTypes handler")
+
+	return c.Status(fiber.StatusOK).JSON(app.exerciseService.GetTypeSchemas())
+}
+
+func (app *App) previewExercise(c *fiber.Ctx) error {
+	var (
+		ctx  = c.Context()
+		code = c.Params("code")
+	)
+	logger.Info("app.previewExercise handler")
+
+	preview, err := app.exerciseService.PreviewExercise(ctx, code)
+	if err != nil {
+		logger.Error("app.previewExercise exerciseService.PreviewExercise: ", err)
+		switch err {
+		case exercises.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": exercises.ErrNotFound.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(preview)
+}
+
 func (app *App) getExercise(c *fiber.Ctx) error {
 	var (
 		ctx = c.Context()
@@ -87,8 +134,11 @@ func (app *App) createExercise(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": exercises.ErrPairsRequired.Error()})
 		case exercises.ErrCorrectOrderRequired:
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": exercises.ErrCorrectOrderRequired.Error()})
+		case exercises.ErrOptionMediaMismatch, exercises.ErrBlanksRequired, exercises.ErrBlanksMismatch,
+			exercises.ErrCorrectAnswersRequired, exercises.ErrIsTrueRequired, exercises.ErrAudioRequired:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		default:
-			return fiberInternalServerError(c)
+			return fiberMediaError(c, err)
 		}
 	}
 
@@ -97,16 +147,20 @@ func (app *App) createExercise(c *fiber.Ctx) error {
 
 func (app *App) deleteExercise(c *fiber.Ctx) error {
 	var (
-		ctx = c.Context()
-		req = c.Params("code")
+		ctx           = c.Context()
+		req           = c.Params("code")
+		cascade       = c.QueryBool("cascade", false)
+		referencedErr *exercises.ReferencedError
 	)
 	logger.Info("app.deleteExercise handler")
 
-	if err := app.exerciseService.DeleteExercise(ctx, req); err != nil {
+	if err := app.exerciseService.DeleteExercise(ctx, req, cascade); err != nil {
 		logger.Error("app.deleteExercise exerciseService.DeleteExercise: ", err)
-		switch err {
-		case exercises.ErrNotFound:
+		switch {
+		case errors.Is(err, exercises.ErrNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": exercises.ErrNotFound.Error()})
+		case errors.As(err, &referencedErr):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": referencedErr.Error(), "parents": referencedErr.Parents})
 		default:
 			return fiberInternalServerError(c)
 		}
@@ -156,8 +210,37 @@ func (app *App) updateExercise(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": exercises.ErrPairsRequired.Error()})
 		case exercises.ErrCorrectOrderRequired:
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": exercises.ErrCorrectOrderRequired.Error()})
+		case exercises.ErrOptionMediaMismatch, exercises.ErrBlanksRequired, exercises.ErrBlanksMismatch,
+			exercises.ErrCorrectAnswersRequired, exercises.ErrIsTrueRequired, exercises.ErrAudioRequired:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		default:
-			return fiberInternalServerError(c)
+			return fiberMediaError(c, err)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) updateExerciseStatus(c *fiber.Ctx) error {
+	var (
+		ctx  = c.Context()
+		code = c.Params("code")
+		req  publication.UpdateStatusDTO
+	)
+	logger.Info("app.updateExerciseStatus handler")
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.updateExerciseStatus c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := app.exerciseService.SetExerciseStatus(ctx, code, req.Status); err != nil {
+		logger.Error("app.updateExerciseStatus exerciseService.SetExerciseStatus: ", err)
+		switch err {
+		case exercises.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": exercises.ErrNotFound.Error()})
+		default:
+			return fiberStatusError(c, err)
 		}
 	}
 
@@ -1,7 +1,9 @@
 package admin
 
 import (
+	"strconv"
 	"uiren/internal/app/friendship"
+	"uiren/internal/app/users"
 	"uiren/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
@@ -40,6 +42,12 @@ func (app *App) sendFriendRequest(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": friendship.ErrRequesterNotFound.Error()})
 		case friendship.ErrRecipientNotFound:
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": friendship.ErrRecipientNotFound.Error()})
+		case friendship.ErrBlocked:
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": friendship.ErrBlocked.Error()})
+		case friendship.ErrTooManyRequests:
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": friendship.ErrTooManyRequests.Error()})
+		case friendship.ErrRequestCooldown:
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": friendship.ErrRequestCooldown.Error()})
 		default:
 			return fiberInternalServerError(c)
 		}
@@ -174,3 +182,153 @@ func (app *App) deleteFriendshipInfo(c *fiber.Ctx) error {
 
 	return fiberOK(c)
 }
+
+func (app *App) searchUsers(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.searchUsers handler")
+
+	usernameVal := c.Locals("username")
+	username, ok := usernameVal.(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": users.ErrInvalidLimit.Error()})
+	}
+
+	found, err := app.userService.SearchUsers(ctx, users.SearchUsersDTO{
+		Query:     c.Query("q"),
+		Requester: username,
+		Limit:     limit,
+	})
+	if err != nil {
+		logger.Error("app.searchUsers userService.SearchUsers: ", err)
+		switch err {
+		case users.ErrEmptySearchQuery, users.ErrInvalidLimit:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(found)
+}
+
+func (app *App) getFriendSuggestions(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.getFriendSuggestions handler")
+
+	usernameVal := c.Locals("username")
+	username, ok := usernameVal.(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": friendship.ErrInvalidLimit.Error()})
+	}
+
+	suggestions, err := app.friendshipService.GetSuggestions(ctx, username, limit)
+	if err != nil {
+		logger.Error("app.getFriendSuggestions friendshipService.GetSuggestions: ", err)
+		switch err {
+		case friendship.ErrInvalidLimit:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(suggestions)
+}
+
+func (app *App) blockUser(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		req BlockUserReq
+	)
+	logger.Info("app.blockUser handler")
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.blockUser c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	if req.Username == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", username required"})
+	}
+
+	if err := app.friendshipService.BlockUser(ctx, username, req.Username); err != nil {
+		logger.Error("app.blockUser friendshipService.BlockUser: ", err)
+		switch err {
+		case friendship.ErrSameUser:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": friendship.ErrSameUser.Error()})
+		case friendship.ErrRecipientNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": friendship.ErrRecipientNotFound.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) unblockUser(c *fiber.Ctx) error {
+	var (
+		ctx     = c.Context()
+		blocked = c.Params("username")
+	)
+	logger.Info("app.unblockUser handler")
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	if blocked == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", username required"})
+	}
+
+	if err := app.friendshipService.UnblockUser(ctx, username, blocked); err != nil {
+		logger.Error("app.unblockUser friendshipService.UnblockUser: ", err)
+		switch err {
+		case friendship.ErrBlockNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": friendship.ErrBlockNotFound.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) getBlockList(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	blockList, err := app.friendshipService.GetBlockList(ctx, username)
+	if err != nil {
+		logger.Error("app.getBlockList friendshipService.GetBlockList: ", err)
+		return fiberInternalServerError(c)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(blockList)
+}
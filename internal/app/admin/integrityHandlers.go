@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"uiren/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (app *App) getContentHealth(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.getContentHealth handler")
+
+	resp, err := app.integrityService.GetContentHealth(ctx)
+	if err != nil {
+		logger.Error("app.getContentHealth integrityService.GetContentHealth: ", err)
+		return fiberInternalServerError(c)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -1,8 +1,10 @@
 package admin
 
 import (
+	"errors"
 	"uiren/internal/app/exercises"
 	"uiren/internal/app/lessons"
+	"uiren/internal/app/publication"
 	"uiren/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,11 +14,28 @@ func (app *App) getAllLessons(c *fiber.Ctx) error {
 	var (
 		ctx = c.Context()
 	)
+	logger.Info("app.getAllLessons handler")
 
-	resp, err := app.lessonService.GetAllLessonsWithExercises(ctx)
+	params, err := parsePagination(c, lessons.SortFields)
 	if err != nil {
-		logger.Error("app.getLessons lessonService.GetAllLessonsWithExercises: ", err)
-		return fiberInternalServerError(c)
+		return fiberPaginationError(c, err)
+	}
+
+	createdFrom, createdTo, err := parseCreatedRange(c)
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	filter := lessons.ListFilter{
+		Status:      c.Query("status"),
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+	}
+
+	resp, err := app.lessonService.ListLessons(ctx, filter, params)
+	if err != nil {
+		logger.Error("app.getAllLessons lessonService.ListLessons: ", err)
+		return fiberPaginationError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
@@ -102,16 +121,50 @@ func (app *App) updateLesson(c *fiber.Ctx) error {
 
 func (app *App) deleteLesson(c *fiber.Ctx) error {
 	var (
-		ctx = c.Context()
-		req = c.Params("code")
+		ctx           = c.Context()
+		req           = c.Params("code")
+		cascade       = c.QueryBool("cascade", false)
+		referencedErr *lessons.ReferencedError
 	)
 	logger.Info("app.deleteLesson handler")
 
-	if err := app.lessonService.DeleteLesson(ctx, req); err != nil {
+	if err := app.lessonService.DeleteLesson(ctx, req, cascade); err != nil {
 		logger.Error("app.deleteLesson lessonService.DeleteLesson: ", err)
+		switch {
+		case errors.Is(err, lessons.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": lessons.ErrNotFound.Error()})
+		case errors.As(err, &referencedErr):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": referencedErr.Error(), "parents": referencedErr.Parents})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) reorderExercises(c *fiber.Ctx) error {
+	var (
+		ctx        = c.Context()
+		lessonCode = c.Params("code")
+		req        lessons.ReorderExercisesDTO
+	)
+	logger.Info("app.reorderExercises handler")
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.reorderExercises c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := app.lessonService.ReorderExercises(ctx, lessonCode, req.Exercises); err != nil {
+		logger.Error("app.reorderExercises lessonService.ReorderExercises: ", err)
 		switch err {
 		case lessons.ErrNotFound:
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": lessons.ErrNotFound.Error()})
+		case lessons.ErrNotPermutation:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": lessons.ErrNotPermutation.Error()})
+		case lessons.ErrListChanged:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": lessons.ErrListChanged.Error()})
 		default:
 			return fiberInternalServerError(c)
 		}
@@ -137,7 +190,8 @@ func (app *App) addExerciseToList(c *fiber.Ctx) error {
 		case exercises.ErrNotFound:
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": exercises.ErrNotFound.Error()})
 		default:
-			return fiberInternalServerError(c)
+			// a draft exercise can't join a published lesson
+			return fiberStatusError(c, err)
 		}
 	}
 
@@ -165,3 +219,29 @@ func (app *App) deleteExerciseFromList(c *fiber.Ctx) error {
 
 	return fiberOK(c)
 }
+
+func (app *App) updateLessonStatus(c *fiber.Ctx) error {
+	var (
+		ctx  = c.Context()
+		code = c.Params("code")
+		req  publication.UpdateStatusDTO
+	)
+	logger.Info("app.updateLessonStatus handler")
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.updateLessonStatus c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := app.lessonService.SetLessonStatus(ctx, code, req.Status); err != nil {
+		logger.Error("app.updateLessonStatus lessonService.SetLessonStatus: ", err)
+		switch err {
+		case lessons.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": lessons.ErrNotFound.Error()})
+		default:
+			return fiberStatusError(c, err)
+		}
+	}
+
+	return fiberOK(c)
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"errors"
+	"uiren/internal/app/media"
+	"uiren/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (app *App) uploadMedia(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.uploadMedia handler")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		logger.Error("app.uploadMedia c.FormFile: ", err)
+		return fiberFormFileError(c, err)
+	}
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect username"})
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		logger.Error("app.uploadMedia file.Open: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest})
+	}
+	defer reader.Close()
+
+	asset, err := app.mediaService.UploadAsset(ctx, media.UploadAssetRequest{
+		File:      reader,
+		CreatedBy: username,
+	})
+	if err != nil {
+		logger.Error("app.uploadMedia mediaService.UploadAsset: ", err)
+		return fiberMediaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(asset)
+}
+
+func (app *App) getMedia(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		id  = c.Params("id")
+	)
+	logger.Info("app.getMedia handler")
+
+	asset, err := app.mediaService.GetAsset(ctx, id)
+	if err != nil {
+		logger.Error("app.getMedia mediaService.GetAsset: ", err)
+		return fiberMediaError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(asset)
+}
+
+// fiberMediaError maps media errors, missing assets come wrapped with the list of ids
+func fiberMediaError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, media.ErrUnsupportedType):
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, media.ErrFileTooLarge):
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, media.ErrTooManyPixels), errors.Is(err, media.ErrInvalidFile),
+		errors.Is(err, media.ErrAudioTooLong), errors.Is(err, media.ErrWrongKind):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, media.ErrAssetNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return fiberInternalServerError(c)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"uiren/internal/app/lessons"
 	"uiren/internal/app/modules"
+	"uiren/internal/app/publication"
 	"uiren/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,11 +14,29 @@ func (app *App) getAllModules(c *fiber.Ctx) error {
 	var (
 		ctx = c.Context()
 	)
+	logger.Info("app.getAllModules handler")
 
-	resp, err := app.modulesService.GetAllModulesWithLessons(ctx)
+	params, err := parsePagination(c, modules.SortFields)
 	if err != nil {
-		logger.Error("app.getAllModules modulesService.GetAllModulesWithLessons: ", err)
-		return fiberInternalServerError(c)
+		return fiberPaginationError(c, err)
+	}
+
+	createdFrom, createdTo, err := parseCreatedRange(c)
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	filter := modules.ListFilter{
+		Difficulty:  c.Query("difficulty"),
+		Status:      c.Query("status"),
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+	}
+
+	resp, err := app.modulesService.ListModules(ctx, filter, params)
+	if err != nil {
+		logger.Error("app.getAllModules modulesService.ListModules: ", err)
+		return fiberPaginationError(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
@@ -169,6 +188,37 @@ func (app *App) addLessonToList(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": modules.ErrLessonAlreadyInSet.Error()})
 		case lessons.ErrNotFound:
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": lessons.ErrNotFound.Error()})
+		default:
+			// a draft lesson can't join a published module
+			return fiberStatusError(c, err)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) reorderLessons(c *fiber.Ctx) error {
+	var (
+		ctx        = c.Context()
+		moduleCode = c.Params("code")
+		req        modules.ReorderLessonsDTO
+	)
+	logger.Info("app.reorderLessons handler")
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.reorderLessons c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := app.modulesService.ReorderLessons(ctx, moduleCode, req.Lessons); err != nil {
+		logger.Error("app.reorderLessons modulesService.ReorderLessons: ", err)
+		switch err {
+		case modules.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": modules.ErrNotFound.Error()})
+		case modules.ErrNotPermutation:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": modules.ErrNotPermutation.Error()})
+		case modules.ErrListChanged:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": modules.ErrListChanged.Error()})
 		default:
 			return fiberInternalServerError(c)
 		}
@@ -202,3 +252,29 @@ func (app *App) deleteLessonFromList(c *fiber.Ctx) error {
 
 	return fiberOK(c)
 }
+
+func (app *App) updateModuleStatus(c *fiber.Ctx) error {
+	var (
+		ctx  = c.Context()
+		code = c.Params("code")
+		req  publication.UpdateStatusDTO
+	)
+	logger.Info("app.updateModuleStatus handler")
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.updateModuleStatus c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := app.modulesService.SetModuleStatus(ctx, code, req.Status); err != nil {
+		logger.Error("app.updateModuleStatus modulesService.SetModuleStatus: ", err)
+		switch err {
+		case modules.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": modules.ErrNotFound.Error()})
+		default:
+			return fiberStatusError(c, err)
+		}
+	}
+
+	return fiberOK(c)
+}
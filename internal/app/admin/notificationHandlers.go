@@ -0,0 +1,175 @@
+package admin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"uiren/internal/app/notifications"
+	"uiren/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// sseKeepAlive is how often the stream sends a comment, so proxies don't close
+// an idle connection and a gone client is noticed
+const sseKeepAlive = 25 * time.Second
+
+func (app *App) getNotifications(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.getNotifications handler")
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	params, err := parsePagination(c, notifications.SortFields)
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	unread, err := queryBool(c, "unread")
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	var filter notifications.ListFilter
+	if unread != nil {
+		filter.UnreadOnly = *unread
+	}
+
+	list, err := app.notificationService.GetNotifications(ctx, username, filter, params)
+	if err != nil {
+		logger.Error("app.getNotifications notificationService.GetNotifications: ", err)
+		return fiberPaginationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(list)
+}
+
+func (app *App) getUnreadNotificationsCount(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	count, err := app.notificationService.CountUnread(ctx, username)
+	if err != nil {
+		logger.Error("app.getUnreadNotificationsCount notificationService.CountUnread: ", err)
+		return fiberInternalServerError(c)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"unread": count})
+}
+
+func (app *App) markNotificationRead(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.markNotificationRead handler")
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", invalid notification id"})
+	}
+
+	if err := app.notificationService.MarkRead(ctx, username, id); err != nil {
+		logger.Error("app.markNotificationRead notificationService.MarkRead: ", err)
+		switch err {
+		case notifications.ErrNotificationNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) markAllNotificationsRead(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+	logger.Info("app.markAllNotificationsRead handler")
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	updated, err := app.notificationService.MarkAllRead(ctx, username)
+	if err != nil {
+		logger.Error("app.markAllNotificationsRead notificationService.MarkAllRead: ", err)
+		return fiberInternalServerError(c)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"updated": updated})
+}
+
+// streamNotifications pushes new notifications as server-sent events
+// until the client disconnects or the server shuts down
+func (app *App) streamNotifications(c *fiber.Ctx) error {
+	logger.Info("app.streamNotifications handler")
+
+	username, ok := c.Locals("username").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing username)"})
+	}
+
+	stream, unsubscribe := app.notificationService.Subscribe(username)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		keepAlive := time.NewTicker(sseKeepAlive)
+		defer keepAlive.Stop()
+
+		// the first write sends the headers, so the client knows the stream is open
+		fmt.Fprint(w, ": connected\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case notification, ok := <-stream:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(notification)
+				if err != nil {
+					logger.Error("app.streamNotifications json.Marshal: ", err)
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", notification.ID, data)
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return nil
+}
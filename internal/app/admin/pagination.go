@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+	"time"
+	"uiren/pkg/pagination"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var errInvalidFilter = errors.New("invalid filter")
+
+// parsePagination reads limit, cursor, sort and order query params
+func parsePagination(c *fiber.Ctx, sortFields []string) (pagination.Params, error) {
+	limit, err := strconv.Atoi(c.Query("limit", "0"))
+	if err != nil {
+		return pagination.Params{}, pagination.ErrInvalidLimit
+	}
+
+	return pagination.NewParams(limit, c.Query("cursor"), c.Query("sort"), c.Query("order"), sortFields)
+}
+
+// parseCreatedRange reads created_from and created_to query params in RFC3339
+func parseCreatedRange(c *fiber.Ctx) (*time.Time, *time.Time, error) {
+	from, err := queryTime(c, "created_from")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	to, err := queryTime(c, "created_to")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return from, to, nil
+}
+
+func queryTime(c *fiber.Ctx, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errInvalidFilter
+	}
+	t = t.UTC()
+
+	return &t, nil
+}
+
+func queryBool(c *fiber.Ctx, key string) (*bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errInvalidFilter
+	}
+
+	return &b, nil
+}
+
+func fiberPaginationError(c *fiber.Ctx, err error) error {
+	switch err {
+	case pagination.ErrInvalidCursor, pagination.ErrInvalidLimit, pagination.ErrInvalidSort, pagination.ErrInvalidOrder, errInvalidFilter:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return fiberInternalServerError(c)
+	}
+}
@@ -57,3 +57,37 @@ func (app *App) updateProfile(c *fiber.Ctx) error {
 		"update_time": updatedUser.UpdatedAt,
 	})
 }
+
+func (app *App) updatePrivacy(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		req UpdatePrivacyReq
+	)
+	logger.Info("app.updatePrivacy handler")
+
+	userIDVal := c.Locals("id")
+	userID, ok := userIDVal.(string)
+	if !ok || userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid token claims"})
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.updatePrivacy BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest})
+	}
+	if req.IsSearchable == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", is_searchable required"})
+	}
+
+	if err := app.userService.SetSearchable(ctx, userID, *req.IsSearchable); err != nil {
+		logger.Error("app.updatePrivacy userService.SetSearchable: ", err)
+		switch err {
+		case users.ErrUserNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": ErrUserNotFound})
+		default:
+			return fiberInternalServerError(c)
+		}
+	}
+
+	return fiberOK(c)
+}
@@ -0,0 +1,176 @@
+package admin
+
+import (
+	"strconv"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/lessons"
+	"uiren/internal/app/modules"
+	"uiren/internal/app/revisions"
+	"uiren/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (app *App) getRevisions(entityType string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var (
+			ctx  = c.Context()
+			code = c.Params("code")
+		)
+		logger.Info("app.getRevisions handler")
+
+		resp, err := app.revisionService.GetRevisions(ctx, entityType, code)
+		if err != nil {
+			logger.Error("app.getRevisions revisionService.GetRevisions: ", err)
+			return fiberInternalServerError(c)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(resp)
+	}
+}
+
+func (app *App) getRevision(entityType string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var (
+			ctx  = c.Context()
+			code = c.Params("code")
+		)
+		logger.Info("app.getRevision handler")
+
+		version, err := strconv.Atoi(c.Params("version"))
+		if err != nil {
+			logger.Error("app.getRevision strconv.Atoi: ", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": revisions.ErrInvalidVersion.Error()})
+		}
+
+		resp, err := app.revisionService.GetRevision(ctx, entityType, code, version)
+		if err != nil {
+			logger.Error("app.getRevision revisionService.GetRevision: ", err)
+			return fiberRevisionError(c, err)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(resp)
+	}
+}
+
+func (app *App) diffRevisions(entityType string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var (
+			ctx  = c.Context()
+			code = c.Params("code")
+		)
+		logger.Info("app.diffRevisions handler")
+
+		fromVersion, err := strconv.Atoi(c.Query("from"))
+		if err != nil {
+			logger.Error("app.diffRevisions strconv.Atoi(from): ", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": revisions.ErrInvalidVersion.Error()})
+		}
+
+		toVersion, err := strconv.Atoi(c.Query("to"))
+		if err != nil {
+			logger.Error("app.diffRevisions strconv.Atoi(to): ", err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": revisions.ErrInvalidVersion.Error()})
+		}
+
+		resp, err := app.revisionService.DiffRevisions(ctx, entityType, code, fromVersion, toVersion)
+		if err != nil {
+			logger.Error("app.diffRevisions revisionService.DiffRevisions: ", err)
+			return fiberRevisionError(c, err)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(resp)
+	}
+}
+
+func (app *App) rollbackModule(c *fiber.Ctx) error {
+	var (
+		ctx  = c.Context()
+		code = c.Params("code")
+	)
+	logger.Info("app.rollbackModule handler")
+
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		logger.Error("app.rollbackModule strconv.Atoi: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": revisions.ErrInvalidVersion.Error()})
+	}
+
+	if err := app.modulesService.RollbackModule(ctx, code, version); err != nil {
+		logger.Error("app.rollbackModule modulesService.RollbackModule: ", err)
+		switch err {
+		case modules.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": modules.ErrNotFound.Error()})
+		default:
+			return fiberRevisionError(c, err)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) rollbackLesson(c *fiber.Ctx) error {
+	var (
+		ctx  = c.Context()
+		code = c.Params("code")
+	)
+	logger.Info("app.rollbackLesson handler")
+
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		logger.Error("app.rollbackLesson strconv.Atoi: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": revisions.ErrInvalidVersion.Error()})
+	}
+
+	if err := app.lessonService.RollbackLesson(ctx, code, version); err != nil {
+		logger.Error("app.rollbackLesson lessonService.RollbackLesson: ", err)
+		switch err {
+		case lessons.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": lessons.ErrNotFound.Error()})
+		default:
+			return fiberRevisionError(c, err)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func (app *App) rollbackExercise(c *fiber.Ctx) error {
+	var (
+		ctx  = c.Context()
+		code = c.Params("code")
+	)
+	logger.Info("app.rollbackExercise handler")
+
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		logger.Error("app.rollbackExercise strconv.Atoi: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": revisions.ErrInvalidVersion.Error()})
+	}
+
+	if err := app.exerciseService.RollbackExercise(ctx, code, version); err != nil {
+		logger.Error("app.rollbackExercise exerciseService.RollbackExercise: ", err)
+		switch err {
+		case exercises.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": exercises.ErrNotFound.Error()})
+		default:
+			return fiberRevisionError(c, err)
+		}
+	}
+
+	return fiberOK(c)
+}
+
+func fiberRevisionError(c *fiber.Ctx, err error) error {
+	switch err {
+	case revisions.ErrRevisionNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": revisions.ErrRevisionNotFound.Error()})
+	case revisions.ErrInvalidVersion, revisions.ErrInvalidEntityType:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case revisions.ErrHistoryNotAvailable:
+		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{"error": revisions.ErrHistoryNotAvailable.Error()})
+	default:
+		// restored lists are checked like the publication checks the children
+		return fiberStatusError(c, err)
+	}
+}
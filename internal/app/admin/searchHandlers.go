@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"strconv"
+	"uiren/internal/app/search"
+	"uiren/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// searchContent searches course content, learners get only published content
+func (app *App) searchContent(publishedOnly bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var (
+			ctx = c.Context()
+		)
+		logger.Info("app.searchContent handler")
+
+		limit, err := strconv.Atoi(c.Query("limit", "0"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": search.ErrInvalidLimit.Error()})
+		}
+
+		resp, err := app.searchService.Search(ctx, search.Query{
+			Text:          c.Query("q"),
+			Limit:         limit,
+			PublishedOnly: publishedOnly,
+		})
+		if err != nil {
+			logger.Error("app.searchContent searchService.Search: ", err)
+			switch err {
+			case search.ErrEmptyQuery, search.ErrQueryTooLong, search.ErrInvalidLimit:
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			default:
+				return fiberInternalServerError(c)
+			}
+		}
+
+		return c.Status(fiber.StatusOK).JSON(resp)
+	}
+}
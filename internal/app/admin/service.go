@@ -3,16 +3,25 @@ package admin
 import (
 	"context"
 	"uiren/internal/app/achievements"
+	"uiren/internal/app/activity"
 	"uiren/internal/app/auth"
 	"uiren/internal/app/avatars"
+	"uiren/internal/app/bundles"
 	"uiren/internal/app/data"
 	"uiren/internal/app/exercises"
 	"uiren/internal/app/friendship"
+	"uiren/internal/app/integrity"
 	"uiren/internal/app/lessons"
+	"uiren/internal/app/media"
 	"uiren/internal/app/modules"
+	"uiren/internal/app/notifications"
 	"uiren/internal/app/progress"
+	"uiren/internal/app/revisions"
+	"uiren/internal/app/search"
+	"uiren/internal/app/sessions"
 	"uiren/internal/app/users"
 	"uiren/internal/infrastracture/middleware"
+	"uiren/pkg/pagination"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -25,25 +34,57 @@ type modulesService interface {
 	UpdateModule(ctx context.Context, code string, dto modules.UpdateModuleDTO) error
 	AddLessonToList(ctx context.Context, code, lessonCode string) error
 	DeleteLessonFromList(ctx context.Context, code, lessonCode string) error
-	GetAllModulesWithLessons(ctx context.Context) ([]modules.ModuleWithLessons, error)
+	ReorderLessons(ctx context.Context, code string, lessonCodes []string) error
+	ListModules(ctx context.Context, filter modules.ListFilter, params pagination.Params) (pagination.Page[modules.ModuleWithLessons], error)
+	RollbackModule(ctx context.Context, code string, version int) error
+	SetModuleStatus(ctx context.Context, code, status string) error
 }
 
 type lessonService interface {
 	GetLesson(ctx context.Context, code string) (lessons.LessonDTO, error)
 	CreateLesson(ctx context.Context, dto lessons.CreateLessonDTO) (primitive.ObjectID, error)
 	UpdateLesson(ctx context.Context, code string, dto lessons.UpdateLessonDTO) error
-	DeleteLesson(ctx context.Context, code string) error
+	DeleteLesson(ctx context.Context, code string, cascade bool) error
 	AddExerciseToList(ctx context.Context, code, exerciseCode string) error
 	DeleteExerciseFromList(ctx context.Context, code, exerciseCode string) error
-	GetAllLessonsWithExercises(ctx context.Context) ([]lessons.LessonDTO, error)
+	ReorderExercises(ctx context.Context, code string, exerciseCodes []string) error
+	ListLessons(ctx context.Context, filter lessons.ListFilter, params pagination.Params) (pagination.Page[lessons.LessonDTO], error)
+	RollbackLesson(ctx context.Context, code string, version int) error
+	SetLessonStatus(ctx context.Context, code, status string) error
 }
 
 type exerciseService interface {
 	GetExercise(ctx context.Context, code string) (exercises.Exercise, error)
 	CreateExercise(ctx context.Context, dto exercises.CreateExerciseDTO) (primitive.ObjectID, error)
 	UpdateExercise(ctx context.Context, code string, dto exercises.UpdateExerciseDTO) error
-	DeleteExercise(ctx context.Context, code string) error
-	GetAllExercises(ctx context.Context) ([]exercises.Exercise, error)
+	DeleteExercise(ctx context.Context, code string, cascade bool) error
+	ListExercises(ctx context.Context, filter exercises.ListFilter, params pagination.Params) (pagination.Page[exercises.Exercise], error)
+	RollbackExercise(ctx context.Context, code string, version int) error
+	GradeAnswer(ctx context.Context, code string, answer exercises.Answer) (exercises.GradeResult, error)
+	GradeLesson(exercises []exercises.Exercise, answers map[string]exercises.Answer) (exercises.LessonGrade, error)
+	ToLearner(exercise exercises.Exercise) (exercises.LearnerExercise, error)
+	PreviewExercise(ctx context.Context, code string) (exercises.Preview, error)
+	GetTypeSchemas() []exercises.Schema
+	SetExerciseStatus(ctx context.Context, code, status string) error
+}
+
+type revisionService interface {
+	GetRevisions(ctx context.Context, entityType, code string) ([]revisions.Revision, error)
+	GetRevision(ctx context.Context, entityType, code string, version int) (revisions.Revision, error)
+	DiffRevisions(ctx context.Context, entityType, code string, fromVersion, toVersion int) (revisions.RevisionDiff, error)
+}
+
+type bundleService interface {
+	ExportModule(ctx context.Context, code string) (bundles.Bundle, error)
+	ImportBundle(ctx context.Context, bundle bundles.Bundle, dryRun bool) (bundles.ImportReport, error)
+}
+
+type integrityService interface {
+	GetContentHealth(ctx context.Context) (integrity.ContentHealth, error)
+}
+
+type searchService interface {
+	Search(ctx context.Context, query search.Query) (search.Results, error)
 }
 
 type achievementService interface {
@@ -51,7 +92,7 @@ type achievementService interface {
 	GetAchievement(ctx context.Context, id int) (achievements.AchievementDTO, error)
 	UpdateAchievement(ctx context.Context, dto achievements.UpdateAchievementDTO) (string, error)
 	DeleteAchievement(ctx context.Context, id int) error
-	GetAllAchievements(ctx context.Context) ([]achievements.AchievementDTO, error)
+	ListAchievements(ctx context.Context, filter achievements.ListFilter, params pagination.Params) (pagination.Page[achievements.AchievementDTO], error)
 
 	AddAchievementLevel(ctx context.Context, dto achievements.AddAchievementLevelDTO) error
 	DeleteAchievementLevel(ctx context.Context, dto achievements.DeleteAchievementLevelDTO) error
@@ -61,14 +102,17 @@ type userService interface {
 	CreateUser(ctx context.Context, params users.CreateUserDTO) (string, error)
 	GetUserForLogin(ctx context.Context, username string) (users.UserDTO, error)
 	UpdateUser(ctx context.Context, dto users.UpdateUserDTO) (users.UserDTO, error)
-	GetAllUsers(ctx context.Context) ([]users.UserDTO, error)
+	ListUsers(ctx context.Context, filter users.ListFilter, params pagination.Params) (pagination.Page[users.UserDTO], error)
 	GetUserByID(ctx context.Context, id string) (users.UserDTO, error)
+	SearchUsers(ctx context.Context, dto users.SearchUsersDTO) ([]users.PublicUser, error)
+	SetSearchable(ctx context.Context, id string, searchable bool) error
 }
 
 type authService interface {
 	SignIn(ctx context.Context, params auth.LoginParams) (string, string, error)
 	Register(ctx context.Context, params auth.RegisterParams) (string, error)
 	VerifyUser(ctx context.Context, username, code string) error
+	ResendVerification(ctx context.Context, params auth.ResendVerificationParams) error
 	RefreshToken(ctx context.Context, token string) (string, string, error)
 }
 
@@ -78,6 +122,11 @@ type friendshipService interface {
 	GetFriendList(ctx context.Context, username string) (friendship.FriendList, error)
 	GetRequestList(ctx context.Context, username string) (friendship.FriendList, error)
 	DeleteFriendship(ctx context.Context, req friendship.FriendshipRequestDTO) error
+	GetSuggestions(ctx context.Context, username string, limit int) ([]friendship.Suggestion, error)
+
+	BlockUser(ctx context.Context, blocker, blocked string) error
+	UnblockUser(ctx context.Context, blocker, blocked string) error
+	GetBlockList(ctx context.Context, username string) (friendship.FriendList, error)
 }
 
 type dataService interface {
@@ -88,7 +137,7 @@ type dataService interface {
 	GetPublicLesson(ctx context.Context, code string) (lessons.LessonDTO, error)
 	GetPublicExercise(ctx context.Context, code string) (exercises.Exercise, error)
 
-	GetXPLeaderboard(ctx context.Context) (data.XPLeaderboard, error)
+	GetXPLeaderboard(ctx context.Context, viewer string) (data.XPLeaderboard, error)
 
 	GetPublicAchievements(ctx context.Context) ([]achievements.AchievementDTO, error)
 }
@@ -99,22 +148,63 @@ type progressService interface {
 	GetAllBadges(ctx context.Context) ([]progress.Badge, error)
 }
 
+type activityService interface {
+	GetFeed(ctx context.Context, username string, params pagination.Params) (pagination.Page[activity.Activity], error)
+	React(ctx context.Context, dto activity.ReactionDTO) error
+	Unreact(ctx context.Context, dto activity.ReactionDTO) error
+}
+
+type notificationService interface {
+	Subscribe(username string) (<-chan notifications.Notification, func())
+	GetNotifications(ctx context.Context, username string, filter notifications.ListFilter, params pagination.Params) (pagination.Page[notifications.Notification], error)
+	CountUnread(ctx context.Context, username string) (int, error)
+	MarkRead(ctx context.Context, username string, id int64) error
+	MarkAllRead(ctx context.Context, username string) (int64, error)
+}
+
 type avatarService interface {
 	UploadAvatar(ctx context.Context, req avatars.UploadAvatarRequest) error
+	GetAvatarURL(ctx context.Context, userID string, size int) (string, error)
+	GetIdenticon(ctx context.Context, userID string, size int) (avatars.Avatar, error)
+	DeleteAvatar(ctx context.Context, userID string) error
+}
+
+type mediaService interface {
+	UploadAsset(ctx context.Context, req media.UploadAssetRequest) (media.Asset, error)
+	GetAsset(ctx context.Context, id string) (media.Asset, error)
+}
+
+type sessionService interface {
+	StartSession(ctx context.Context, userID, lessonCode string) (sessions.SessionState, error)
+	StartPractice(ctx context.Context, userID, lessonCode string) (sessions.SessionState, error)
+	GetActiveSession(ctx context.Context, userID string) (sessions.SessionState, error)
+	GetSession(ctx context.Context, userID, id string) (sessions.SessionState, error)
+	ResumeSession(ctx context.Context, userID, id string) (sessions.SessionState, error)
+	TakeHint(ctx context.Context, userID, id string) (sessions.SessionState, error)
+	SubmitAnswer(ctx context.Context, userID, id string, answer exercises.Answer) (sessions.AnswerResult, error)
+	FinishSession(ctx context.Context, userID, id string) (sessions.Summary, error)
 }
 
 type App struct {
-	appFiber           *fiber.App
-	userService        userService
-	authService        authService
-	modulesService     modulesService
-	lessonService      lessonService
-	exerciseService    exerciseService
-	achievementService achievementService
-	friendshipService  friendshipService
-	dataService        dataService
-	progressService    progressService
-	avatarService      avatarService
+	appFiber            *fiber.App
+	userService         userService
+	authService         authService
+	modulesService      modulesService
+	lessonService       lessonService
+	exerciseService     exerciseService
+	revisionService     revisionService
+	bundleService       bundleService
+	integrityService    integrityService
+	searchService       searchService
+	achievementService  achievementService
+	friendshipService   friendshipService
+	activityService     activityService
+	notificationService notificationService
+	dataService         dataService
+	progressService     progressService
+	avatarService       avatarService
+	mediaService        mediaService
+	sessionService      sessionService
 }
 
 func NewApp(appFiber *fiber.App) *App {
@@ -143,6 +233,22 @@ func (app *App) WithExerciseService(exerciseService exerciseService) {
 	app.exerciseService = exerciseService
 }
 
+func (app *App) WithRevisionService(revisionService revisionService) {
+	app.revisionService = revisionService
+}
+
+func (app *App) WithBundleService(bundleService bundleService) {
+	app.bundleService = bundleService
+}
+
+func (app *App) WithIntegrityService(integrityService integrityService) {
+	app.integrityService = integrityService
+}
+
+func (app *App) WithSearchService(searchService searchService) {
+	app.searchService = searchService
+}
+
 func (app *App) WithAchievementService(achievementService achievementService) {
 	app.achievementService = achievementService
 }
@@ -151,6 +257,14 @@ func (app *App) WithFriendshipService(friendshipService friendshipService) {
 	app.friendshipService = friendshipService
 }
 
+func (app *App) WithActivityService(activityService activityService) {
+	app.activityService = activityService
+}
+
+func (app *App) WithNotificationService(notificationService notificationService) {
+	app.notificationService = notificationService
+}
+
 func (app *App) WithDataService(dataService dataService) {
 	app.dataService = dataService
 }
@@ -163,6 +277,14 @@ func (app *App) WithAvatarService(avatarService avatarService) {
 	app.avatarService = avatarService
 }
 
+func (app *App) WithMediaService(mediaService mediaService) {
+	app.mediaService = mediaService
+}
+
+func (app *App) WithSessionService(sessionService sessionService) {
+	app.sessionService = sessionService
+}
+
 func (app *App) SetHandlers() {
 	api := app.appFiber.Group("/api")
 	api.Static("/storage", "./storage")
@@ -171,6 +293,7 @@ func (app *App) SetHandlers() {
 	api.Post("/sign-in", app.signIn)
 	api.Post("/register", app.register)
 	api.Get("/verify/:username/:code", app.verification)
+	api.Post("/verify/resend", app.resendVerification)
 	api.Post("/refresh-token", app.refreshToken)
 	//users
 	usersApi := api.Group("/users", middleware.JWTMiddleware(), middleware.AdminMiddleware())
@@ -185,24 +308,51 @@ func (app *App) SetHandlers() {
 	modulesApi.Post("/", app.createModule)
 	modulesApi.Delete("/:code", app.deleteModule)
 	modulesApi.Patch("/:code", app.updateModule)
+	modulesApi.Patch("/:code/status", app.updateModuleStatus)
+	modulesApi.Put("/:code/lessons-list", app.reorderLessons)
 	modulesApi.Post("/:code/lessons-list/:lessonCode", app.addLessonToList)
 	modulesApi.Delete("/:code/lessons-list/:lessonCode", app.deleteLessonFromList)
+	modulesApi.Get("/:code/revisions", app.getRevisions(revisions.EntityModule))
+	modulesApi.Get("/:code/revisions/diff", app.diffRevisions(revisions.EntityModule))
+	modulesApi.Get("/:code/revisions/:version", app.getRevision(revisions.EntityModule))
+	modulesApi.Post("/:code/revisions/:version/rollback", app.rollbackModule)
 	//lessons
 	lessonApi := api.Group("/lessons", middleware.JWTMiddleware(), middleware.AdminMiddleware())
 	lessonApi.Get("/", app.getAllLessons)
 	lessonApi.Get("/:code", app.getLesson)
 	lessonApi.Post("/", app.createLesson)
 	lessonApi.Patch("/:code", app.updateLesson)
+	lessonApi.Patch("/:code/status", app.updateLessonStatus)
 	lessonApi.Delete("/:code", app.deleteLesson)
+	lessonApi.Put("/:code/exercises-list", app.reorderExercises)
 	lessonApi.Post(":code/exercises-list/:exerciseCode", app.addExerciseToList)
 	lessonApi.Delete(":code/exercises-list/:exerciseCode", app.deleteExerciseFromList)
+	lessonApi.Get("/:code/revisions", app.getRevisions(revisions.EntityLesson))
+	lessonApi.Get("/:code/revisions/diff", app.diffRevisions(revisions.EntityLesson))
+	lessonApi.Get("/:code/revisions/:version", app.getRevision(revisions.EntityLesson))
+	lessonApi.Post("/:code/revisions/:version/rollback", app.rollbackLesson)
 	//exercises
 	exerciseApi := api.Group("/exercises", middleware.JWTMiddleware(), middleware.AdminMiddleware())
 	exerciseApi.Get("/", app.getAllExercises)
+	exerciseApi.Get("/types", app.getExerciseTypes)
 	exerciseApi.Get("/:code", app.getExercise)
+	exerciseApi.Get("/:code/preview", app.previewExercise)
 	exerciseApi.Post("/", app.createExercise)
 	exerciseApi.Patch("/:code", app.updateExercise)
+	exerciseApi.Patch("/:code/status", app.updateExerciseStatus)
 	exerciseApi.Delete("/:code", app.deleteExercise)
+	exerciseApi.Get("/:code/revisions", app.getRevisions(revisions.EntityExercise))
+	exerciseApi.Get("/:code/revisions/diff", app.diffRevisions(revisions.EntityExercise))
+	exerciseApi.Get("/:code/revisions/:version", app.getRevision(revisions.EntityExercise))
+	exerciseApi.Post("/:code/revisions/:version/rollback", app.rollbackExercise)
+	//bundles
+	bundlesApi := api.Group("/bundles", middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	bundlesApi.Get("/modules/:code", app.exportModuleBundle)
+	bundlesApi.Post("/import", app.importBundle)
+	//content
+	contentApi := api.Group("/content", middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	contentApi.Get("/health", app.getContentHealth)
+	contentApi.Get("/search", app.searchContent(false))
 	//achievements
 	achievementsApi := api.Group("/achievements", middleware.JWTMiddleware(), middleware.AdminMiddleware())
 	achievementsApi.Get("/", app.getAllAchievements)
@@ -218,15 +368,43 @@ func (app *App) SetHandlers() {
 	friendsApi.Post("/handle-request", app.handleFriendRequest)
 	friendsApi.Get("/friend-list", app.getFriendList)
 	friendsApi.Get("/request-list", app.getRequestList)
+	friendsApi.Get("/search", app.searchUsers)
+	friendsApi.Get("/suggestions", app.getFriendSuggestions)
+	friendsApi.Post("/block", app.blockUser)
+	friendsApi.Delete("/block/:username", app.unblockUser)
+	friendsApi.Get("/block-list", app.getBlockList)
+	friendsApi.Get("/feed", app.getFriendsFeed)
+	friendsApi.Post("/feed/:id/reactions", app.reactToActivity)
+	friendsApi.Delete("/feed/:id/reactions/:reaction", app.deleteActivityReaction)
 	friendsApi.Delete("/", app.deleteFriendshipInfo)
+	//notifications
+	notificationsApi := api.Group("/notifications", middleware.JWTMiddleware())
+	notificationsApi.Get("/", app.getNotifications)
+	notificationsApi.Get("/unread-count", app.getUnreadNotificationsCount)
+	notificationsApi.Get("/stream", app.streamNotifications)
+	notificationsApi.Post("/read-all", app.markAllNotificationsRead)
+	notificationsApi.Post("/:id/read", app.markNotificationRead)
 	//data
 	dataApi := api.Group("/data", middleware.JWTMiddleware())
 	dataApi.Get("/modules", app.mainPageModules)
-	dataApi.Get("lesson", app.getLessonToPass)
+	//learners pass lessons through sessions, which cost hearts, so whole lessons and grading are left to admins checking content
+	dataApi.Get("lesson", middleware.AdminMiddleware(), app.getLessonToPass)
+	dataApi.Post("/lesson/grade", middleware.AdminMiddleware(), app.gradeLessonAnswers)
 	dataApi.Get("/exercise", app.getExerciseToPass)
+	dataApi.Post("/exercise/grade", middleware.AdminMiddleware(), app.gradeExercise)
 	dataApi.Get("/users", app.getUserInfo)
 	dataApi.Get("/xp-leaderboard", app.getXPLeaderboard)
 	dataApi.Get("/achievements", app.getPublicAchievements)
+	dataApi.Get("/search", app.searchContent(true))
+	//lesson sessions
+	sessionsApi := api.Group("/sessions", middleware.JWTMiddleware())
+	sessionsApi.Post("/", app.startSession)
+	sessionsApi.Get("/active", app.getActiveSession)
+	sessionsApi.Get("/:id", app.getSession)
+	sessionsApi.Post("/:id/resume", app.resumeSession)
+	sessionsApi.Post("/:id/hint", app.takeHint)
+	sessionsApi.Post("/:id/answer", app.submitSessionAnswer)
+	sessionsApi.Post("/:id/finish", app.finishSession)
 	//progress
 	progressApi := api.Group("/progress", middleware.JWTMiddleware())
 	progressApi.Patch("/", app.updateProgress)
@@ -237,7 +415,15 @@ func (app *App) SetHandlers() {
 	//profile
 	profileAPI := api.Group("/profile", middleware.JWTMiddleware())
 	profileAPI.Patch("/", app.updateProfile)
-	//avatar
+	profileAPI.Patch("/privacy", app.updatePrivacy)
+	//avatar, the public route goes first, the group middleware matches every path under /avatar
+	api.Get("/avatar/:id", app.getAvatar)
 	avatarAPI := api.Group("/avatar", middleware.JWTMiddleware())
 	avatarAPI.Post("/", app.uploadAvatar)
+	avatarAPI.Delete("/", app.deleteAvatar)
+
+	//media
+	mediaAPI := api.Group("/media", middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	mediaAPI.Post("/", app.uploadMedia)
+	mediaAPI.Get("/:id", app.getMedia)
 }
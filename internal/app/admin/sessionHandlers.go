@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/lessons"
+	"uiren/internal/app/sessions"
+	"uiren/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (app *App) startSession(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		req StartSessionReq
+	)
+
+	userID, ok := c.Locals("id").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing id)"})
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		logger.Error("app.startSession c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", invalid request"})
+	}
+	if req.LessonCode == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", lesson_code required"})
+	}
+
+	start := app.sessionService.StartSession
+	if req.Practice {
+		start = app.sessionService.StartPractice
+	}
+
+	state, err := start(ctx, userID, req.LessonCode)
+	if err != nil {
+		logger.Error("app.startSession sessionService.StartSession: ", err)
+		return fiberSessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+func (app *App) getActiveSession(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+	)
+
+	userID, ok := c.Locals("id").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing id)"})
+	}
+
+	state, err := app.sessionService.GetActiveSession(ctx, userID)
+	if err != nil {
+		logger.Error("app.getActiveSession sessionService.GetActiveSession: ", err)
+		return fiberSessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+func (app *App) getSession(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		id  = c.Params("id")
+	)
+
+	userID, ok := c.Locals("id").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing id)"})
+	}
+
+	state, err := app.sessionService.GetSession(ctx, userID, id)
+	if err != nil {
+		logger.Error("app.getSession sessionService.GetSession: ", err)
+		return fiberSessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+func (app *App) resumeSession(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		id  = c.Params("id")
+	)
+
+	userID, ok := c.Locals("id").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing id)"})
+	}
+
+	state, err := app.sessionService.ResumeSession(ctx, userID, id)
+	if err != nil {
+		logger.Error("app.resumeSession sessionService.ResumeSession: ", err)
+		return fiberSessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+func (app *App) takeHint(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		id  = c.Params("id")
+	)
+
+	userID, ok := c.Locals("id").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing id)"})
+	}
+
+	state, err := app.sessionService.TakeHint(ctx, userID, id)
+	if err != nil {
+		logger.Error("app.takeHint sessionService.TakeHint: ", err)
+		return fiberSessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+func (app *App) submitSessionAnswer(c *fiber.Ctx) error {
+	var (
+		ctx    = c.Context()
+		id     = c.Params("id")
+		answer exercises.Answer
+	)
+
+	userID, ok := c.Locals("id").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing id)"})
+	}
+
+	if err := c.BodyParser(&answer); err != nil {
+		logger.Error("app.submitSessionAnswer c.BodyParser: ", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", invalid request"})
+	}
+
+	result, err := app.sessionService.SubmitAnswer(ctx, userID, id, answer)
+	if err != nil {
+		logger.Error("app.submitSessionAnswer sessionService.SubmitAnswer: ", err)
+		return fiberSessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (app *App) finishSession(c *fiber.Ctx) error {
+	var (
+		ctx = c.Context()
+		id  = c.Params("id")
+	)
+
+	userID, ok := c.Locals("id").(string)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrBadRequest + ", incorrect token payload(missing id)"})
+	}
+
+	summary, err := app.sessionService.FinishSession(ctx, userID, id)
+	if err != nil {
+		logger.Error("app.finishSession sessionService.FinishSession: ", err)
+		return fiberSessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
+
+func fiberSessionError(c *fiber.Ctx, err error) error {
+	switch err {
+	case sessions.ErrSessionNotFound, lessons.ErrNotFound, exercises.ErrNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case sessions.ErrSessionNotActive, sessions.ErrSessionChanged, sessions.ErrSessionCompleted,
+		sessions.ErrSessionNotCompleted, sessions.ErrNoMoreHints, sessions.ErrSessionOutdated:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case sessions.ErrEmptyLesson:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case sessions.ErrNoHearts:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return fiberInternalServerError(c)
+	}
+}
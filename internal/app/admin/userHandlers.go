@@ -104,13 +104,40 @@ func (app *App) getAllUsers(c *fiber.Ctx) error {
 	)
 	logger.Info("app.getAllUsers handler")
 
-	users, err := app.userService.GetAllUsers(ctx)
+	params, err := parsePagination(c, users.SortFields)
 	if err != nil {
-		logger.Error("app.getAllUsers GetAllUsers: ", err)
-		return fiberInternalServerError(c)
+		return fiberPaginationError(c, err)
+	}
+
+	createdFrom, createdTo, err := parseCreatedRange(c)
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	isActive, err := queryBool(c, "is_active")
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	isAdmin, err := queryBool(c, "is_admin")
+	if err != nil {
+		return fiberPaginationError(c, err)
+	}
+
+	filter := users.ListFilter{
+		IsActive:    isActive,
+		IsAdmin:     isAdmin,
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+	}
+
+	resp, err := app.userService.ListUsers(ctx, filter, params)
+	if err != nil {
+		logger.Error("app.getAllUsers userService.ListUsers: ", err)
+		return fiberPaginationError(c, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(users)
+	return c.Status(fiber.StatusOK).JSON(resp)
 }
 
 func returnCreateUserError(c *fiber.Ctx, err error) error {
@@ -1,12 +1,28 @@
 package auth
 
-import "uiren/internal/app/users"
+import (
+	"time"
+	"uiren/internal/app/users"
+)
 
 type LoginParams struct {
 	Identificator string `json:"ident"`
 	Password      string `json:"pass"`
 }
 
+const (
+	defaultVerificationBase = "http://localhost:8080"
+	defaultResendCooldown   = time.Minute
+)
+
 type RegisterParams struct {
 	DTO users.CreateUserDTO
+	// Locale of the verification email, the default locale is used when it is not supported
+	Locale string
+}
+
+type ResendVerificationParams struct {
+	// Identificator is the username or email of the user
+	Identificator string
+	Locale        string
 }
@@ -9,4 +9,6 @@ var (
 	ErrVerificationNotFound = errors.New("verification not found")
 	ErrInvalidToken         = errors.New("invalid token")
 	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrAlreadyVerified      = errors.New("user already verified")
+	ErrResendTooSoon        = errors.New("verification email was sent recently")
 )
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
+	"uiren/internal/app/mailing"
 	"uiren/internal/app/users"
 	"uiren/internal/infrastracture/hasher"
 	jwt_maker "uiren/internal/infrastracture/jwt"
-	yandex_sender "uiren/internal/infrastracture/mail/yandex"
 	"uiren/pkg/logger"
 
 	"github.com/redis/go-redis/v9"
@@ -38,20 +40,29 @@ type verificationCodeRepository interface {
 	getVerificationCode(ctx context.Context, username string) (Verification, error)
 }
 
+type emailOutbox interface {
+	Enqueue(ctx context.Context, email mailing.Email) error
+}
+
 type AuthService struct {
-	userService     userService
-	jwtMaker        jwtMaker
-	verifRepo       verificationCodeRepository
-	verifCodeTTL    time.Duration
-	refreshTokenTTL time.Duration
-	redisClient     redisClient
+	userService      userService
+	jwtMaker         jwtMaker
+	verifRepo        verificationCodeRepository
+	verifCodeTTL     time.Duration
+	refreshTokenTTL  time.Duration
+	redisClient      redisClient
+	emailOutbox      emailOutbox
+	verificationBase string
+	resendCooldown   time.Duration
 }
 
 func NewAuthService(userService userService, jwtMaker jwtMaker, verifRepo verificationCodeRepository) *AuthService {
 	return &AuthService{
-		userService: userService,
-		jwtMaker:    jwtMaker,
-		verifRepo:   verifRepo,
+		userService:      userService,
+		jwtMaker:         jwtMaker,
+		verifRepo:        verifRepo,
+		verificationBase: defaultVerificationBase,
+		resendCooldown:   defaultResendCooldown,
 	}
 }
 
@@ -63,10 +74,25 @@ func (s *AuthService) SetRefreshTokenTTL(refreshTokenTTL time.Duration) {
 	s.refreshTokenTTL = refreshTokenTTL
 }
 
+// SetResendCooldown sets how long a user waits before another verification email
+func (s *AuthService) SetResendCooldown(cooldown time.Duration) {
+	s.resendCooldown = cooldown
+}
+
 func (s *AuthService) WithRedisClient(redisClient redisClient) {
 	s.redisClient = redisClient
 }
 
+// WithEmailOutbox enables verification emails, they are sent by the outbox worker
+func (s *AuthService) WithEmailOutbox(emailOutbox emailOutbox) {
+	s.emailOutbox = emailOutbox
+}
+
+// SetVerificationBase sets the public address the verification link points to
+func (s *AuthService) SetVerificationBase(base string) {
+	s.verificationBase = strings.TrimSuffix(base, "/")
+}
+
 func (s *AuthService) SignIn(ctx context.Context, params LoginParams) (string, string, error) {
 	logger.Info("AuthService.SignIn new request")
 
@@ -98,6 +124,8 @@ func (s *AuthService) SignIn(ctx context.Context, params LoginParams) (string, s
 	return s.generateTokens(ctx, payload)
 }
 
+// Register creates the user and sends the verification email, the user exists once CreateUser succeeds
+// so a failed code or email is only logged, the user asks for a new one with ResendVerification
 func (s *AuthService) Register(ctx context.Context, params RegisterParams) (string, error) {
 	logger.Info("AuthService.Register new request")
 
@@ -106,36 +134,80 @@ func (s *AuthService) Register(ctx context.Context, params RegisterParams) (stri
 		return "", err
 	}
 
+	if err := s.sendVerification(ctx, params.DTO.Username, params.DTO.Email, params.Locale); err != nil {
+		logger.Error("AuthService.Register sendVerification: ", err)
+	}
+
+	return userID, nil
+}
+
+// ResendVerification issues a new verification code for a user that is not verified yet,
+// the previous codes stop working
+func (s *AuthService) ResendVerification(ctx context.Context, params ResendVerificationParams) error {
+	logger.Info("AuthService.ResendVerification new request")
+
+	user, err := s.userService.GetUserForLogin(ctx, params.Identificator)
+	if err != nil {
+		logger.Error("AuthService.ResendVerification getUser: ", err)
+		return err
+	}
+
+	if user.IsActive {
+		return ErrAlreadyVerified
+	}
+
+	last, err := s.verifRepo.getVerificationCode(ctx, user.Username)
+	if err != nil && !errors.Is(err, ErrVerificationNotFound) {
+		logger.Error("AuthService.ResendVerification verifRepo.getVerificationCode: ", err)
+		return err
+	}
+	if err == nil && time.Since(last.CreatedAt) < s.resendCooldown {
+		return ErrResendTooSoon
+	}
+
+	if err := s.sendVerification(ctx, user.Username, user.Email, params.Locale); err != nil {
+		logger.Error("AuthService.ResendVerification sendVerification: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AuthService) sendVerification(ctx context.Context, username, email, locale string) error {
 	verifReq := CreateVerificationCodeRequest{
-		Username: params.DTO.Username,
-		Email:    params.DTO.Email,
+		Username: username,
+		Email:    email,
 		Code:     generateAlphanumericCode(10),
 		Duration: s.verifCodeTTL,
 	}
 
 	if err := s.verifRepo.createVerificationCode(ctx, verifReq); err != nil {
-		logger.Error("UserService.CreateUser verifRepo.createVerificationCode error: ", err)
-		return "", err
+		logger.Error("AuthService.sendVerification verifRepo.createVerificationCode: ", err)
+		return err
 	}
 
-	errChan := make(chan error, 1)
-	go func() {
-		defer close(errChan)
-		err := yandex_sender.SendEmail(
-			"Uiren. Email Verification",
-			fmt.Sprintf("localhost:8080/api/verify/%s/%s", verifReq.Username, verifReq.Code),
-			[]string{verifReq.Email},
-			[]string{}, []string{}, []string{})
-
-		errChan <- err
-	}()
-	go func() {
-		if err := <-errChan; err != nil {
-			logger.Error("UserService.CreateUser emailSender.SendEmail error: ", err)
-		}
-	}()
+	if s.emailOutbox == nil {
+		return nil
+	}
 
-	return userID, nil
+	if err := s.emailOutbox.Enqueue(ctx, mailing.Email{
+		Type:   mailing.TypeEmailVerification,
+		Locale: locale,
+		To:     verifReq.Email,
+		Data: map[string]string{
+			"Username": verifReq.Username,
+			"Link":     s.verificationLink(verifReq.Username, verifReq.Code),
+		},
+	}); err != nil {
+		logger.Error("AuthService.sendVerification emailOutbox.Enqueue: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AuthService) verificationLink(username, code string) string {
+	return fmt.Sprintf("%s/api/verify/%s/%s", s.verificationBase, url.PathEscape(username), url.PathEscape(code))
 }
 
 func (s *AuthService) VerifyUser(ctx context.Context, username, code string) error {
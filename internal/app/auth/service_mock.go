@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 	time "time"
+	mailing "uiren/internal/app/mailing"
 	users "uiren/internal/app/users"
 	jwt "uiren/internal/infrastracture/jwt"
 
@@ -236,3 +237,40 @@ func (mr *MockverificationCodeRepositoryMockRecorder) getVerificationCode(ctx, u
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getVerificationCode", reflect.TypeOf((*MockverificationCodeRepository)(nil).getVerificationCode), ctx, username)
 }
+
+// MockemailOutbox is a mock of emailOutbox interface.
+type MockemailOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockemailOutboxMockRecorder
+}
+
+// MockemailOutboxMockRecorder is the mock recorder for MockemailOutbox.
+type MockemailOutboxMockRecorder struct {
+	mock *MockemailOutbox
+}
+
+// NewMockemailOutbox creates a new mock instance.
+func NewMockemailOutbox(ctrl *gomock.Controller) *MockemailOutbox {
+	mock := &MockemailOutbox{ctrl: ctrl}
+	mock.recorder = &MockemailOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockemailOutbox) EXPECT() *MockemailOutboxMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method.
+func (m *MockemailOutbox) Enqueue(ctx context.Context, email mailing.Email) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockemailOutboxMockRecorder) Enqueue(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockemailOutbox)(nil).Enqueue), ctx, email)
+}
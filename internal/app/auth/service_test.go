@@ -7,10 +7,10 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"uiren/internal/app/mailing"
 	"uiren/internal/app/users"
 	"uiren/internal/infrastracture/hasher"
 	jwt_maker "uiren/internal/infrastracture/jwt"
-	yandex_sender "uiren/internal/infrastracture/mail/yandex"
 	"uiren/pkg/logger"
 
 	"github.com/golang/mock/gomock"
@@ -20,7 +20,6 @@ import (
 
 func init() {
 	logger.InitLogger("info")
-	yandex_sender.Init("", "", "")
 }
 
 func Test_authService_SignIn_success(t *testing.T) {
@@ -309,19 +308,68 @@ func Test_authService_Register_success(t *testing.T) {
 		userService = NewMockuserService(ctrl)
 		jwtMaker    = NewMockjwtMaker(ctrl)
 		verifRepo   = NewMockverificationCodeRepository(ctrl)
+		emailOutbox = NewMockemailOutbox(ctrl)
 		authService = NewAuthService(userService, jwtMaker, verifRepo)
 
 		newID = "ruweioruweioruweororeurioewurweio"
+		code  string
 	)
+	paramsWithLogin.Locale = mailing.LocaleEN
+	authService.WithEmailOutbox(emailOutbox)
+	authService.SetVerificationBase("https://uiren.kz/")
 
 	userService.EXPECT().CreateUser(ctx, paramsWithLogin.DTO).Return(newID, nil)
-	verifRepo.EXPECT().createVerificationCode(ctx, gomock.Any()).Return(nil)
+	verifRepo.EXPECT().createVerificationCode(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req CreateVerificationCodeRequest) error {
+		code = req.Code
+		return nil
+	})
+	emailOutbox.EXPECT().Enqueue(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, email mailing.Email) error {
+		assert.Equal(t, mailing.TypeEmailVerification, email.Type)
+		assert.Equal(t, mailing.LocaleEN, email.Locale)
+		assert.Equal(t, "user@user.ru", email.To)
+		assert.Equal(t, "user", email.Data["Username"])
+		assert.Equal(t, "https://uiren.kz/api/verify/user/"+code, email.Data["Link"])
+		return nil
+	})
 
 	id, err := authService.Register(ctx, paramsWithLogin)
 	assert.NoError(t, err)
 	assert.Equal(t, id, newID)
 }
 
+func Test_authService_Register_enqueue_failed(t *testing.T) {
+	t.Parallel()
+	var (
+		ctrl            = gomock.NewController(t)
+		ctx             = context.TODO()
+		paramsWithLogin = RegisterParams{
+			DTO: users.CreateUserDTO{
+				Username: "user",
+				Email:    "user@user.ru",
+				Password: "Pass@123456",
+			},
+		}
+		userService = NewMockuserService(ctrl)
+		jwtMaker    = NewMockjwtMaker(ctrl)
+		verifRepo   = NewMockverificationCodeRepository(ctrl)
+		emailOutbox = NewMockemailOutbox(ctrl)
+		authService = NewAuthService(userService, jwtMaker, verifRepo)
+
+		errEnqueue = errors.New("outbox insert error")
+	)
+	authService.WithEmailOutbox(emailOutbox)
+
+	userService.EXPECT().CreateUser(ctx, paramsWithLogin.DTO).Return("id", nil)
+	verifRepo.EXPECT().createVerificationCode(ctx, gomock.Any()).Return(nil)
+	emailOutbox.EXPECT().Enqueue(ctx, gomock.Any()).Return(errEnqueue)
+
+	res, err := authService.Register(ctx, paramsWithLogin)
+
+	// the user is created, the email is requested again with ResendVerification
+	assert.NoError(t, err)
+	assert.Equal(t, "id", res)
+}
+
 func Test_authService_Register_createUser_failed(t *testing.T) {
 	t.Parallel()
 	var (
@@ -376,9 +424,101 @@ func Test_authService_Register_createVerificationCode_failed(t *testing.T) {
 
 	res, err := authService.Register(ctx, paramsWithLogin)
 
-	assert.Error(t, err)
-	assert.Equal(t, err, errCode)
-	assert.Equal(t, res, "")
+	assert.NoError(t, err)
+	assert.Equal(t, paramsWithLogin.DTO.Username, res)
+}
+
+func Test_authService_ResendVerification(t *testing.T) {
+	t.Parallel()
+	var (
+		params = ResendVerificationParams{Identificator: "user@user.ru", Locale: mailing.LocaleEN}
+		user   = users.UserDTO{Username: "user", Email: "user@user.ru"}
+		errDB  = errors.New("database error")
+	)
+
+	tests := []struct {
+		name    string
+		prepare func(userService *MockuserService, verifRepo *MockverificationCodeRepository, emailOutbox *MockemailOutbox)
+		wantErr error
+	}{
+		{
+			name: "sent",
+			prepare: func(userService *MockuserService, verifRepo *MockverificationCodeRepository, emailOutbox *MockemailOutbox) {
+				userService.EXPECT().GetUserForLogin(gomock.Any(), params.Identificator).Return(user, nil)
+				verifRepo.EXPECT().getVerificationCode(gomock.Any(), "user").Return(Verification{CreatedAt: time.Now().Add(-2 * time.Minute)}, nil)
+				verifRepo.EXPECT().createVerificationCode(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req CreateVerificationCodeRequest) error {
+					assert.Equal(t, "user", req.Username)
+					assert.Equal(t, "user@user.ru", req.Email)
+					return nil
+				})
+				emailOutbox.EXPECT().Enqueue(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, email mailing.Email) error {
+					assert.Equal(t, mailing.LocaleEN, email.Locale)
+					assert.Equal(t, "user@user.ru", email.To)
+					return nil
+				})
+			},
+		},
+		{
+			name: "no previous code",
+			prepare: func(userService *MockuserService, verifRepo *MockverificationCodeRepository, emailOutbox *MockemailOutbox) {
+				userService.EXPECT().GetUserForLogin(gomock.Any(), params.Identificator).Return(user, nil)
+				verifRepo.EXPECT().getVerificationCode(gomock.Any(), "user").Return(Verification{}, ErrVerificationNotFound)
+				verifRepo.EXPECT().createVerificationCode(gomock.Any(), gomock.Any()).Return(nil)
+				emailOutbox.EXPECT().Enqueue(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "user not found",
+			prepare: func(userService *MockuserService, verifRepo *MockverificationCodeRepository, emailOutbox *MockemailOutbox) {
+				userService.EXPECT().GetUserForLogin(gomock.Any(), params.Identificator).Return(users.UserDTO{}, users.ErrUserNotFound)
+			},
+			wantErr: users.ErrUserNotFound,
+		},
+		{
+			name: "already verified",
+			prepare: func(userService *MockuserService, verifRepo *MockverificationCodeRepository, emailOutbox *MockemailOutbox) {
+				verified := user
+				verified.IsActive = true
+				userService.EXPECT().GetUserForLogin(gomock.Any(), params.Identificator).Return(verified, nil)
+			},
+			wantErr: ErrAlreadyVerified,
+		},
+		{
+			name: "too soon",
+			prepare: func(userService *MockuserService, verifRepo *MockverificationCodeRepository, emailOutbox *MockemailOutbox) {
+				userService.EXPECT().GetUserForLogin(gomock.Any(), params.Identificator).Return(user, nil)
+				verifRepo.EXPECT().getVerificationCode(gomock.Any(), "user").Return(Verification{CreatedAt: time.Now().Add(-10 * time.Second)}, nil)
+			},
+			wantErr: ErrResendTooSoon,
+		},
+		{
+			name: "enqueue failed",
+			prepare: func(userService *MockuserService, verifRepo *MockverificationCodeRepository, emailOutbox *MockemailOutbox) {
+				userService.EXPECT().GetUserForLogin(gomock.Any(), params.Identificator).Return(user, nil)
+				verifRepo.EXPECT().getVerificationCode(gomock.Any(), "user").Return(Verification{}, ErrVerificationNotFound)
+				verifRepo.EXPECT().createVerificationCode(gomock.Any(), gomock.Any()).Return(nil)
+				emailOutbox.EXPECT().Enqueue(gomock.Any(), gomock.Any()).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				ctrl        = gomock.NewController(t)
+				userService = NewMockuserService(ctrl)
+				verifRepo   = NewMockverificationCodeRepository(ctrl)
+				emailOutbox = NewMockemailOutbox(ctrl)
+				authService = NewAuthService(userService, NewMockjwtMaker(ctrl), verifRepo)
+			)
+			authService.WithEmailOutbox(emailOutbox)
+			tt.prepare(userService, verifRepo, emailOutbox)
+
+			err := authService.ResendVerification(context.TODO(), params)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
 }
 
 func Test_authService_VerifyUser_success(t *testing.T) {
@@ -14,4 +14,5 @@ type Verification struct {
 	Email     string
 	Code      string
 	ExpiresAt time.Time
+	CreatedAt time.Time
 }
@@ -23,32 +23,37 @@ func (r *verificationRepository) createVerificationCode(ctx context.Context, req
 	var (
 		query = `
 		INSERT INTO
-			users_verification_codes(username, email, verification_code, expires_at)
+			users_verification_codes(username, email, verification_code, expires_at, created_at)
 		VALUES
-			($1, $2, $3, $4);
+			($1, $2, $3, $4, $5);
 		`
+		now = time.Now()
 	)
 
-	_, err := r.db.Exec(ctx, query, req.Username, req.Email, req.Code, time.Now().Add(req.Duration))
+	_, err := r.db.Exec(ctx, query, req.Username, req.Email, req.Code, now.Add(req.Duration), now)
 	return err
 }
 
+// getVerificationCode returns the last code of the user, a resent code replaces the previous ones
 func (r *verificationRepository) getVerificationCode(ctx context.Context, username string) (Verification, error) {
 	var (
 		query = `
 		SELECT 
-			username, email, verification_code, expires_at
+			username, email, verification_code, expires_at, created_at
 		FROM
 			users_verification_codes
 		WHERE
-			username = $1;
+			username = $1
+		ORDER BY
+			id DESC
+		LIMIT 1;
 		`
 		response Verification
 	)
 
 	row := r.db.QueryRow(ctx, query, username)
 
-	if err := row.Scan(&response.Username, &response.Email, &response.Code, &response.ExpiresAt); err != nil {
+	if err := row.Scan(&response.Username, &response.Email, &response.Code, &response.ExpiresAt, &response.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return Verification{}, ErrVerificationNotFound
 		}
@@ -2,11 +2,37 @@ package avatars
 
 import "io"
 
+const (
+	maxAvatarBytes  = 3 << 20
+	maxAvatarPixels = 25_000_000
+
+	DefaultSize   = 128
+	contentType   = "image/png"
+	storagePrefix = "avatars"
+)
+
+// Sizes are the square variants every avatar is stored in
+var Sizes = []int{64, 128, 512}
+
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
 type UploadAvatarRequest struct {
 	UserId string
 	File   io.Reader
 }
 
+// BackfillReport counts the users whose legacy files BackfillLegacyAvatars handled
+type BackfillReport struct {
+	Converted int
+	// Stale are legacy files of users who uploaded an avatar since, they are removed
+	Stale  int
+	Failed []string
+}
+
 type Avatar struct {
 	Content     []byte
 	ContentType string
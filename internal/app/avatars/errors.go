@@ -0,0 +1,12 @@
+package avatars
+
+import "errors"
+
+var (
+	ErrUnsupportedType = errors.New("unsupported avatar type, allowed jpeg, png and webp")
+	ErrAvatarTooLarge  = errors.New("avatar file is too large")
+	ErrTooManyPixels   = errors.New("avatar dimensions are too large")
+	ErrInvalidImage    = errors.New("avatar is not a valid image")
+	ErrInvalidSize     = errors.New("invalid avatar size")
+	ErrAvatarNotFound  = errors.New("avatar not found")
+)
@@ -0,0 +1,51 @@
+package avatars
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const identiconContentType = "image/svg+xml"
+
+// identicon draws the initials on a color derived from the user id,
+// the same user always gets the same picture
+func identicon(userID, initials string, size int) []byte {
+	h := fnv.New32a()
+	h.Write([]byte(userID))
+	hue := h.Sum32() % 360
+
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 100 100">`+
+		`<rect width="100" height="100" fill="hsl(%d, 55%%, 45%%)"/>`+
+		`<text x="50" y="50" dy="0.35em" text-anchor="middle" font-family="Arial, sans-serif" font-size="40" fill="#ffffff">%s</text>`+
+		`</svg>`, size, size, hue, html.EscapeString(initials)))
+}
+
+// initials returns the first letters of the first and the last name,
+// the first letter of the username when the names are empty
+func initials(firstname, lastname, username string) string {
+	var letters []rune
+	for _, name := range []string{firstname, lastname} {
+		if letter, ok := firstLetter(name); ok {
+			letters = append(letters, letter)
+		}
+	}
+	if len(letters) == 0 {
+		if letter, ok := firstLetter(username); ok {
+			letters = append(letters, letter)
+		}
+	}
+	return strings.ToUpper(string(letters))
+}
+
+func firstLetter(name string) (rune, bool) {
+	for _, r := range strings.TrimSpace(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r, true
+		}
+	}
+	return utf8.RuneError, false
+}
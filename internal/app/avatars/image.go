@@ -0,0 +1,63 @@
+package avatars
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// processAvatar decodes the upload, crops its center square and encodes it into every size,
+// the encoded variants have no metadata, so EXIF of the upload is dropped
+func processAvatar(content []byte, fileType string) (map[int][]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if config.Width*config.Height > maxAvatarPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	// phones save photos unrotated with the orientation in EXIF,
+	// it is applied before EXIF is dropped, the center square doesn't depend on it
+	orientation := orientationNormal
+	if fileType == "image/jpeg" {
+		orientation = jpegOrientation(content)
+	}
+
+	square := centerSquare(img)
+
+	variants := make(map[int][]byte, len(Sizes))
+	for _, size := range Sizes {
+		dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, square, draw.Src, nil)
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, applyOrientation(dst, orientation)); err != nil {
+			return nil, err
+		}
+		variants[size] = buf.Bytes()
+	}
+
+	return variants, nil
+}
+
+func centerSquare(img image.Image) image.Rectangle {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	return image.Rect(x, y, x+side, y+side)
+}
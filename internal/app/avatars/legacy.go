@@ -0,0 +1,115 @@
+package avatars
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"uiren/internal/infrastracture/storage"
+	"uiren/pkg/logger"
+)
+
+// legacyKeys are the files an avatar was saved in before user_avatars, the 512 variant
+// of the sized layout first, then the original upload of the first layout
+func legacyKeys(userID string) []string {
+	return []string{
+		storagePrefix + "/avatar-" + userID + "-512.png",
+		storagePrefix + "/avatar-" + userID + ".png",
+	}
+}
+
+// convertLegacyAvatar stores the legacy file of the user the way UploadAvatar does and returns
+// the key of the size, the file is removed then, so a deleted avatar doesn't come back
+func (s *AvatarService) convertLegacyAvatar(ctx context.Context, userID string, size int) (string, error) {
+	if s.legacyStorage == nil {
+		return "", ErrAvatarNotFound
+	}
+
+	for _, legacyKey := range legacyKeys(userID) {
+		content, err := s.legacyStorage.Get(ctx, legacyKey)
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		// legacy uploads were not validated, a file that is not an avatar is left as it is
+		err = s.storeAvatar(ctx, userID, content, http.DetectContentType(content))
+		if errors.Is(err, ErrInvalidImage) || errors.Is(err, ErrTooManyPixels) {
+			logger.Error("AvatarService.convertLegacyAvatar skip "+legacyKey+": ", err)
+			continue
+		}
+		if err != nil {
+			logger.Error("AvatarService.convertLegacyAvatar storeAvatar: ", err)
+			return "", err
+		}
+		s.deleteLegacyAvatar(ctx, userID)
+
+		return s.repo.getAvatarKey(ctx, userID, size)
+	}
+
+	return "", ErrAvatarNotFound
+}
+
+// deleteLegacyAvatar removes the legacy files of the user and reports whether there were any
+func (s *AvatarService) deleteLegacyAvatar(ctx context.Context, userID string) bool {
+	if s.legacyStorage == nil {
+		return false
+	}
+
+	found := false
+	for _, legacyKey := range legacyKeys(userID) {
+		if _, err := s.legacyStorage.Get(ctx, legacyKey); err != nil {
+			continue
+		}
+		found = true
+		if err := s.legacyStorage.Delete(ctx, legacyKey); err != nil {
+			logger.Error("AvatarService.deleteLegacyAvatar legacyStorage.Delete: ", err)
+		}
+	}
+
+	return found
+}
+
+// BackfillLegacyAvatars converts the legacy files of every user at once, GetAvatarURL converts
+// them on the first request anyway, the backfill lets the files be removed after one run
+func (s *AvatarService) BackfillLegacyAvatars(ctx context.Context) (BackfillReport, error) {
+	logger.Info("AvatarService.BackfillLegacyAvatars new request")
+
+	var report BackfillReport
+	if s.legacyStorage == nil {
+		return report, nil
+	}
+
+	allUsers, err := s.userService.GetAllUsers(ctx)
+	if err != nil {
+		logger.Error("AvatarService.BackfillLegacyAvatars userService.GetAllUsers: ", err)
+		return report, err
+	}
+
+	for _, user := range allUsers {
+		_, err := s.repo.getAvatarKey(ctx, user.ID, DefaultSize)
+		switch {
+		case err == nil:
+			if s.deleteLegacyAvatar(ctx, user.ID) {
+				report.Stale++
+			}
+			continue
+		case !errors.Is(err, ErrAvatarNotFound):
+			logger.Error("AvatarService.BackfillLegacyAvatars repo.getAvatarKey: ", err)
+			report.Failed = append(report.Failed, user.ID)
+			continue
+		}
+
+		_, err = s.convertLegacyAvatar(ctx, user.ID, DefaultSize)
+		switch {
+		case err == nil:
+			report.Converted++
+		case !errors.Is(err, ErrAvatarNotFound):
+			logger.Error("AvatarService.BackfillLegacyAvatars convertLegacyAvatar: ", err)
+			report.Failed = append(report.Failed, user.ID)
+		}
+	}
+
+	return report, nil
+}
@@ -2,34 +2,121 @@ package avatars
 
 import (
 	"context"
-	"io"
-	"os"
-	"path/filepath"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type repository struct {
-	folderPath string
+	db *pgxpool.Pool
 }
 
-func NewAvatarRepository(folderPath string) *repository {
-	folderPath = "./storage" + folderPath + "/"
+func NewAvatarRepository(db *pgxpool.Pool) *repository {
 	return &repository{
-		folderPath: folderPath,
+		db: db,
+	}
+}
+
+func (r *repository) getAvatarKey(ctx context.Context, userID string, size int) (string, error) {
+	var (
+		query = `
+		SELECT object_key FROM user_avatars WHERE user_id = $1 AND size = $2;
+		`
+		key string
+	)
+
+	err := r.db.QueryRow(ctx, query, userID, size).Scan(&key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrAvatarNotFound
 	}
+	return key, err
 }
 
-func (r *repository) uploadAvatar(ctx context.Context, req UploadAvatarRequest) error {
+// replaceAvatarKeys points every size of the avatar to the new objects and returns
+// the previous objects nobody references anymore
+func (r *repository) replaceAvatarKeys(ctx context.Context, userID string, keys map[int]string) ([]string, error) {
 	var (
-		filename = generateAvatarFilename(req.UserId)
-		fullPath = r.folderPath + filepath.Join(filename)
+		query = `
+		WITH previous AS (
+			SELECT object_key FROM user_avatars WHERE user_id = $1
+		),
+		upserted AS (
+			INSERT INTO user_avatars (user_id, size, object_key)
+			SELECT $1, size, object_key FROM unnest($2::int[], $3::text[]) AS v(size, object_key)
+			ON CONFLICT (user_id, size) DO UPDATE
+			SET object_key = EXCLUDED.object_key, updated_at = now()
+		)
+		SELECT DISTINCT p.object_key FROM previous p
+		WHERE p.object_key <> ALL($3::text[])
+			AND NOT EXISTS (
+				SELECT 1 FROM user_avatars a
+				WHERE a.object_key = p.object_key AND a.user_id <> $1
+			);
+		`
+		sizes   = make([]int, 0, len(keys))
+		objects = make([]string, 0, len(keys))
 	)
 
-	file, err := os.Create(fullPath)
+	for size, key := range keys {
+		sizes = append(sizes, size)
+		objects = append(objects, key)
+	}
+
+	rows, err := r.db.Query(ctx, query, userID, sizes, objects)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer file.Close()
 
-	_, err = io.Copy(file, req.File)
-	return err
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// deleteAvatarKeys removes every size of the avatar and returns the objects nobody references anymore
+func (r *repository) deleteAvatarKeys(ctx context.Context, userID string) ([]string, error) {
+	var (
+		query = `
+		WITH deleted AS (
+			DELETE FROM user_avatars WHERE user_id = $1
+			RETURNING object_key
+		)
+		SELECT DISTINCT d.object_key,
+			NOT EXISTS (
+				SELECT 1 FROM user_avatars a
+				WHERE a.object_key = d.object_key AND a.user_id <> $1
+			)
+		FROM deleted d;
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		found  bool
+		unused []string
+	)
+	for rows.Next() {
+		var (
+			key      string
+			isUnused bool
+		)
+		if err := rows.Scan(&key, &isUnused); err != nil {
+			return nil, err
+		}
+		found = true
+		if isUnused {
+			unused = append(unused, key)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, ErrAvatarNotFound
+	}
+	return unused, nil
 }
@@ -2,30 +2,201 @@ package avatars
 
 import (
 	"context"
+	"errors"
+	"io"
+	"slices"
+	"uiren/internal/app/users"
+	"uiren/internal/infrastracture/storage"
 	"uiren/pkg/logger"
+
+	"github.com/google/uuid"
 )
 
+//go:generate mockgen -source service.go -destination service_mock.go -package avatars
+
 type avatarRepository interface {
-	uploadAvatar(ctx context.Context, req UploadAvatarRequest) error
+	getAvatarKey(ctx context.Context, userID string, size int) (string, error)
+	replaceAvatarKeys(ctx context.Context, userID string, keys map[int]string) ([]string, error)
+	deleteAvatarKeys(ctx context.Context, userID string) ([]string, error)
+}
+
+type objectStorage interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// legacyStorage keeps the files avatars were saved in before user_avatars
+type legacyStorage interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type userService interface {
+	GetUserByID(ctx context.Context, id string) (users.UserDTO, error)
+	GetAllUsers(ctx context.Context) ([]users.UserDTO, error)
 }
 
 type AvatarService struct {
-	repo avatarRepository
+	repo          avatarRepository
+	storage       objectStorage
+	userService   userService
+	legacyStorage legacyStorage
 }
 
-func NewAvatarService(avatarRepository avatarRepository) *AvatarService {
+func NewAvatarService(avatarRepository avatarRepository, storage objectStorage, userService userService) *AvatarService {
 	return &AvatarService{
-		repo: avatarRepository,
+		repo:        avatarRepository,
+		storage:     storage,
+		userService: userService,
 	}
 }
 
+// WithLegacyStorage converts avatars uploaded before user_avatars when they are first requested
+func (s *AvatarService) WithLegacyStorage(legacyStorage legacyStorage) {
+	s.legacyStorage = legacyStorage
+}
+
+// UploadAvatar validates the jpeg, png or webp upload and stores it in every size,
+// the variants are content addressed, so the same image is stored once
 func (s *AvatarService) UploadAvatar(ctx context.Context, req UploadAvatarRequest) error {
 	logger.Info("AvatarService.UploadAvatar new request")
 
-	if err := s.repo.uploadAvatar(ctx, req); err != nil {
-		logger.Error("AvatarService.UploadAvatar repo.uploadAvatar: ", err)
+	content, fileType, err := readFileContentAndType(io.LimitReader(req.File, maxAvatarBytes+1))
+	if err != nil {
+		logger.Error("AvatarService.UploadAvatar readFileContentAndType: ", err)
+		return err
+	}
+	if len(content) > maxAvatarBytes {
+		return ErrAvatarTooLarge
+	}
+	if !allowedTypes[fileType] {
+		return ErrUnsupportedType
+	}
+
+	if err := s.storeAvatar(ctx, req.UserId, content, fileType); err != nil {
+		logger.Error("AvatarService.UploadAvatar storeAvatar: ", err)
+		return err
+	}
+
+	// an older legacy file must not be converted over the new avatar
+	s.deleteLegacyAvatar(ctx, req.UserId)
+
+	return nil
+}
+
+func (s *AvatarService) storeAvatar(ctx context.Context, userID string, content []byte, fileType string) error {
+	variants, err := processAvatar(content, fileType)
+	if err != nil {
+		return err
+	}
+
+	// objects are stored before the avatar points to them
+	keys := make(map[int]string, len(variants))
+	for size, variant := range variants {
+		key := storage.ContentKey(storagePrefix, variant, ".png")
+		if err := s.storage.Put(ctx, key, variant, contentType); err != nil {
+			return err
+		}
+		keys[size] = key
+	}
+
+	unused, err := s.repo.replaceAvatarKeys(ctx, userID, keys)
+	if err != nil {
+		return err
+	}
+
+	s.deleteObjects(ctx, unused)
+
+	return nil
+}
+
+// DeleteAvatar removes the uploaded avatar, the user gets the identicon again
+func (s *AvatarService) DeleteAvatar(ctx context.Context, userID string) error {
+	logger.Info("AvatarService.DeleteAvatar new request")
+
+	hadLegacy := s.deleteLegacyAvatar(ctx, userID)
+
+	unused, err := s.repo.deleteAvatarKeys(ctx, userID)
+	if errors.Is(err, ErrAvatarNotFound) && hadLegacy {
+		return nil
+	}
+	if err != nil {
+		logger.Error("AvatarService.DeleteAvatar repo.deleteAvatarKeys: ", err)
 		return err
 	}
 
+	s.deleteObjects(ctx, unused)
+
 	return nil
 }
+
+// deleteObjects is best effort, an object left behind only takes space
+func (s *AvatarService) deleteObjects(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			logger.Error("AvatarService.deleteObjects storage.Delete: ", err)
+		}
+	}
+}
+
+// GetAvatarURL returns the address of the variant of the size, 0 size means DefaultSize
+func (s *AvatarService) GetAvatarURL(ctx context.Context, userID string, size int) (string, error) {
+	logger.Info("AvatarService.GetAvatarURL new request")
+
+	if size == 0 {
+		size = DefaultSize
+	}
+	if !slices.Contains(Sizes, size) {
+		return "", ErrInvalidSize
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return "", ErrAvatarNotFound
+	}
+
+	key, err := s.repo.getAvatarKey(ctx, userID, size)
+	if errors.Is(err, ErrAvatarNotFound) {
+		key, err = s.convertLegacyAvatar(ctx, userID, size)
+	}
+	if err != nil {
+		logger.Error("AvatarService.GetAvatarURL repo.getAvatarKey: ", err)
+		return "", err
+	}
+
+	url, err := s.storage.URL(ctx, key)
+	if err != nil {
+		logger.Error("AvatarService.GetAvatarURL storage.URL: ", err)
+		return "", err
+	}
+
+	return url, nil
+}
+
+// GetIdenticon returns the generated svg avatar of the user who didn't upload one
+func (s *AvatarService) GetIdenticon(ctx context.Context, userID string, size int) (Avatar, error) {
+	logger.Info("AvatarService.GetIdenticon new request")
+
+	if size == 0 {
+		size = DefaultSize
+	}
+	if !slices.Contains(Sizes, size) {
+		return Avatar{}, ErrInvalidSize
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return Avatar{}, ErrAvatarNotFound
+	}
+
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		logger.Error("AvatarService.GetIdenticon userService.GetUserByID: ", err)
+		if errors.Is(err, users.ErrUserNotFound) {
+			return Avatar{}, ErrAvatarNotFound
+		}
+		return Avatar{}, err
+	}
+
+	return Avatar{
+		Content:     identicon(user.ID, initials(user.Firstname, user.Lastname, user.Username), size),
+		ContentType: identiconContentType,
+	}, nil
+}
@@ -0,0 +1,252 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package avatars is a generated GoMock package.
+package avatars
+
+import (
+	context "context"
+	reflect "reflect"
+	users "uiren/internal/app/users"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockavatarRepository is a mock of avatarRepository interface.
+type MockavatarRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockavatarRepositoryMockRecorder
+}
+
+// MockavatarRepositoryMockRecorder is the mock recorder for MockavatarRepository.
+type MockavatarRepositoryMockRecorder struct {
+	mock *MockavatarRepository
+}
+
+// NewMockavatarRepository creates a new mock instance.
+func NewMockavatarRepository(ctrl *gomock.Controller) *MockavatarRepository {
+	mock := &MockavatarRepository{ctrl: ctrl}
+	mock.recorder = &MockavatarRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockavatarRepository) EXPECT() *MockavatarRepositoryMockRecorder {
+	return m.recorder
+}
+
+// deleteAvatarKeys mocks base method.
+func (m *MockavatarRepository) deleteAvatarKeys(ctx context.Context, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "deleteAvatarKeys", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// deleteAvatarKeys indicates an expected call of deleteAvatarKeys.
+func (mr *MockavatarRepositoryMockRecorder) deleteAvatarKeys(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "deleteAvatarKeys", reflect.TypeOf((*MockavatarRepository)(nil).deleteAvatarKeys), ctx, userID)
+}
+
+// getAvatarKey mocks base method.
+func (m *MockavatarRepository) getAvatarKey(ctx context.Context, userID string, size int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getAvatarKey", ctx, userID, size)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getAvatarKey indicates an expected call of getAvatarKey.
+func (mr *MockavatarRepositoryMockRecorder) getAvatarKey(ctx, userID, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getAvatarKey", reflect.TypeOf((*MockavatarRepository)(nil).getAvatarKey), ctx, userID, size)
+}
+
+// replaceAvatarKeys mocks base method.
+func (m *MockavatarRepository) replaceAvatarKeys(ctx context.Context, userID string, keys map[int]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "replaceAvatarKeys", ctx, userID, keys)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// replaceAvatarKeys indicates an expected call of replaceAvatarKeys.
+func (mr *MockavatarRepositoryMockRecorder) replaceAvatarKeys(ctx, userID, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "replaceAvatarKeys", reflect.TypeOf((*MockavatarRepository)(nil).replaceAvatarKeys), ctx, userID, keys)
+}
+
+// MockobjectStorage is a mock of objectStorage interface.
+type MockobjectStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockobjectStorageMockRecorder
+}
+
+// MockobjectStorageMockRecorder is the mock recorder for MockobjectStorage.
+type MockobjectStorageMockRecorder struct {
+	mock *MockobjectStorage
+}
+
+// NewMockobjectStorage creates a new mock instance.
+func NewMockobjectStorage(ctrl *gomock.Controller) *MockobjectStorage {
+	mock := &MockobjectStorage{ctrl: ctrl}
+	mock.recorder = &MockobjectStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockobjectStorage) EXPECT() *MockobjectStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockobjectStorage) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockobjectStorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockobjectStorage)(nil).Delete), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockobjectStorage) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, content, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockobjectStorageMockRecorder) Put(ctx, key, content, contentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockobjectStorage)(nil).Put), ctx, key, content, contentType)
+}
+
+// URL mocks base method.
+func (m *MockobjectStorage) URL(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockobjectStorageMockRecorder) URL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockobjectStorage)(nil).URL), ctx, key)
+}
+
+// MocklegacyStorage is a mock of legacyStorage interface.
+type MocklegacyStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MocklegacyStorageMockRecorder
+}
+
+// MocklegacyStorageMockRecorder is the mock recorder for MocklegacyStorage.
+type MocklegacyStorageMockRecorder struct {
+	mock *MocklegacyStorage
+}
+
+// NewMocklegacyStorage creates a new mock instance.
+func NewMocklegacyStorage(ctrl *gomock.Controller) *MocklegacyStorage {
+	mock := &MocklegacyStorage{ctrl: ctrl}
+	mock.recorder = &MocklegacyStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocklegacyStorage) EXPECT() *MocklegacyStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MocklegacyStorage) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MocklegacyStorageMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MocklegacyStorage)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MocklegacyStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MocklegacyStorageMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MocklegacyStorage)(nil).Get), ctx, key)
+}
+
+// MockuserService is a mock of userService interface.
+type MockuserService struct {
+	ctrl     *gomock.Controller
+	recorder *MockuserServiceMockRecorder
+}
+
+// MockuserServiceMockRecorder is the mock recorder for MockuserService.
+type MockuserServiceMockRecorder struct {
+	mock *MockuserService
+}
+
+// NewMockuserService creates a new mock instance.
+func NewMockuserService(ctrl *gomock.Controller) *MockuserService {
+	mock := &MockuserService{ctrl: ctrl}
+	mock.recorder = &MockuserServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockuserService) EXPECT() *MockuserServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAllUsers mocks base method.
+func (m *MockuserService) GetAllUsers(ctx context.Context) ([]users.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUsers", ctx)
+	ret0, _ := ret[0].([]users.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllUsers indicates an expected call of GetAllUsers.
+func (mr *MockuserServiceMockRecorder) GetAllUsers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockuserService)(nil).GetAllUsers), ctx)
+}
+
+// GetUserByID mocks base method.
+func (m *MockuserService) GetUserByID(ctx context.Context, id string) (users.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(users.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockuserServiceMockRecorder) GetUserByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockuserService)(nil).GetUserByID), ctx, id)
+}
@@ -0,0 +1,414 @@
+package avatars
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+	"uiren/internal/app/users"
+	objectstorage "uiren/internal/infrastracture/storage"
+	"uiren/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+var (
+	red  = color.NRGBA{R: 255, A: 255}
+	blue = color.NRGBA{B: 255, A: 255}
+)
+
+// halves returns the image with the left half red and the right half blue
+func halves(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.SetNRGBA(x, y, red)
+			} else {
+				img.SetNRGBA(x, y, blue)
+			}
+		}
+	}
+	return img
+}
+
+// withOrientation inserts the EXIF segment with the orientation after the jpeg SOI marker
+func withOrientation(content []byte, orientation byte) []byte {
+	tiff := []byte{'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	return append(append(append([]byte{}, content[:2]...), segment...), content[2:]...)
+}
+
+// withDimensions rewrites the png header, so only the declared size is huge
+func withDimensions(content []byte, w, h uint32) []byte {
+	patched := append([]byte{}, content...)
+	// signature 8, length 4, "IHDR" 4, width 4, height 4
+	binary.BigEndian.PutUint32(patched[16:], w)
+	binary.BigEndian.PutUint32(patched[20:], h)
+	binary.BigEndian.PutUint32(patched[29:], crc32.ChecksumIEEE(patched[12:29]))
+	return patched
+}
+
+func decodePNG(t *testing.T, content []byte) image.Image {
+	img, err := png.Decode(bytes.NewReader(content))
+	require.NoError(t, err)
+	return img
+}
+
+func isColor(c color.Color, expected color.NRGBA) bool {
+	r, g, b, _ := c.RGBA()
+	er, eg, eb, _ := expected.RGBA()
+	near := func(a, b uint32) bool { return a+0x2000 > b && b+0x2000 > a }
+	return near(r, er) && near(g, eg) && near(b, eb)
+}
+
+func Test_AvatarService_UploadAvatar_success(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockavatarRepository(ctrl)
+		storage = NewMockobjectStorage(ctrl)
+		service = NewAvatarService(repo, storage, NewMockuserService(ctrl))
+	)
+
+	t.Run("png is cropped and resized", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, halves(300, 100)))
+
+		objects := expectPut(storage, ctx)
+		repo.EXPECT().replaceAvatarKeys(ctx, "user-1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, keys map[int]string) ([]string, error) {
+			assert.Len(t, keys, len(Sizes))
+			for _, size := range Sizes {
+				assert.True(t, strings.HasPrefix(keys[size], "avatars/"))
+				img := decodePNG(t, objects[keys[size]])
+				assert.Equal(t, image.Rect(0, 0, size, size), img.Bounds())
+				// the center square of 300x100 is 100..200, red 100..150 and blue 150..200
+				assert.True(t, isColor(img.At(0, size/2), red))
+				assert.True(t, isColor(img.At(size-1, size/2), blue))
+			}
+			return nil, nil
+		})
+
+		assert.NoError(t, service.UploadAvatar(ctx, UploadAvatarRequest{UserId: "user-1", File: &buf}))
+	})
+
+	t.Run("jpeg orientation is applied and exif is dropped", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, halves(80, 80), &jpeg.Options{Quality: 100}))
+		// orientation 6 is rotated 90 degrees clockwise, the left half goes to the top
+		content := withOrientation(buf.Bytes(), 6)
+
+		objects := expectPut(storage, ctx)
+		repo.EXPECT().replaceAvatarKeys(ctx, "user-2", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, keys map[int]string) ([]string, error) {
+			img := decodePNG(t, objects[keys[64]])
+			assert.True(t, isColor(img.At(32, 4), red))
+			assert.True(t, isColor(img.At(32, 59), blue))
+			assert.False(t, bytes.Contains(objects[keys[64]], []byte("Exif")))
+			return nil, nil
+		})
+
+		assert.NoError(t, service.UploadAvatar(ctx, UploadAvatarRequest{UserId: "user-2", File: bytes.NewReader(content)}))
+	})
+
+	t.Run("unused objects of the previous avatar are deleted", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, halves(10, 10)))
+
+		expectPut(storage, ctx)
+		repo.EXPECT().replaceAvatarKeys(ctx, "user-3", gomock.Any()).Return([]string{"avatars/old-64.png", "avatars/old-128.png"}, nil)
+		storage.EXPECT().Delete(ctx, "avatars/old-64.png").Return(nil)
+		storage.EXPECT().Delete(ctx, "avatars/old-128.png").Return(errors.New("s3 unavailable"))
+
+		assert.NoError(t, service.UploadAvatar(ctx, UploadAvatarRequest{UserId: "user-3", File: &buf}))
+	})
+
+	t.Run("storage failed", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, halves(10, 10)))
+		errPut := errors.New("s3 unavailable")
+
+		storage.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), "image/png").Return(errPut)
+
+		assert.Equal(t, errPut, service.UploadAvatar(ctx, UploadAvatarRequest{UserId: "user-4", File: &buf}))
+	})
+}
+
+// expectPut stores every variant in the returned map by key
+func expectPut(storage *MockobjectStorage, ctx context.Context) map[string][]byte {
+	objects := make(map[string][]byte)
+	storage.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), "image/png").Times(len(Sizes)).
+		DoAndReturn(func(_ context.Context, key string, content []byte, _ string) error {
+			objects[key] = content
+			return nil
+		})
+	return objects
+}
+
+func Test_AvatarService_UploadAvatar_invalid(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockavatarRepository(ctrl)
+		storage = NewMockobjectStorage(ctrl)
+		service = NewAvatarService(repo, storage, NewMockuserService(ctrl))
+	)
+
+	var pngFile, gifFile bytes.Buffer
+	require.NoError(t, png.Encode(&pngFile, halves(10, 10)))
+	require.NoError(t, gif.Encode(&gifFile, halves(10, 10), nil))
+
+	for name, test := range map[string]struct {
+		content []byte
+		err     error
+	}{
+		"gif":             {content: gifFile.Bytes(), err: ErrUnsupportedType},
+		"text":            {content: []byte("<svg></svg>"), err: ErrUnsupportedType},
+		"too large":       {content: bytes.Repeat([]byte{0}, maxAvatarBytes+1), err: ErrAvatarTooLarge},
+		"too many pixels": {content: withDimensions(pngFile.Bytes(), 10000, 10000), err: ErrTooManyPixels},
+		"broken png":      {content: pngFile.Bytes()[:60], err: ErrInvalidImage},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := service.UploadAvatar(ctx, UploadAvatarRequest{UserId: "user-1", File: bytes.NewReader(test.content)})
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func Test_AvatarService_GetAvatarURL(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockavatarRepository(ctrl)
+		storage = NewMockobjectStorage(ctrl)
+		service = NewAvatarService(repo, storage, NewMockuserService(ctrl))
+		userID  = "0b6c1c52-7a43-4bd2-9d0c-3b3f1d5c8a11"
+	)
+
+	t.Run("default size", func(t *testing.T) {
+		repo.EXPECT().getAvatarKey(ctx, userID, DefaultSize).Return("avatars/abc.png", nil)
+		storage.EXPECT().URL(ctx, "avatars/abc.png").Return("https://cdn.uiren.kz/avatars/abc.png", nil)
+
+		url, err := service.GetAvatarURL(ctx, userID, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://cdn.uiren.kz/avatars/abc.png", url)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := service.GetAvatarURL(ctx, userID, 100)
+		assert.Equal(t, ErrInvalidSize, err)
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		_, err := service.GetAvatarURL(ctx, "../user", 64)
+		assert.Equal(t, ErrAvatarNotFound, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().getAvatarKey(ctx, userID, 512).Return("", ErrAvatarNotFound)
+
+		_, err := service.GetAvatarURL(ctx, userID, 512)
+		assert.True(t, errors.Is(err, ErrAvatarNotFound))
+	})
+}
+
+func Test_AvatarService_GetAvatarURL_legacy(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockavatarRepository(ctrl)
+		storage = NewMockobjectStorage(ctrl)
+		legacy  = NewMocklegacyStorage(ctrl)
+		service = NewAvatarService(repo, storage, NewMockuserService(ctrl))
+		userID  = "0b6c1c52-7a43-4bd2-9d0c-3b3f1d5c8a11"
+		sized   = "avatars/avatar-" + userID + "-512.png"
+		origin  = "avatars/avatar-" + userID + ".png"
+		content bytes.Buffer
+	)
+	service.WithLegacyStorage(legacy)
+	require.NoError(t, png.Encode(&content, halves(300, 200)))
+
+	t.Run("converted on first request", func(t *testing.T) {
+		repo.EXPECT().getAvatarKey(ctx, userID, 64).Return("", ErrAvatarNotFound)
+		legacy.EXPECT().Get(ctx, sized).Return(nil, objectstorage.ErrObjectNotFound).Times(2)
+		legacy.EXPECT().Get(ctx, origin).Return(content.Bytes(), nil).Times(2)
+		storage.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), contentType).Return(nil).Times(len(Sizes))
+		repo.EXPECT().replaceAvatarKeys(ctx, userID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, keys map[int]string) ([]string, error) {
+			assert.Len(t, keys, len(Sizes))
+			return nil, nil
+		})
+		legacy.EXPECT().Delete(ctx, origin).Return(nil)
+		repo.EXPECT().getAvatarKey(ctx, userID, 64).Return("avatars/abc.png", nil)
+		storage.EXPECT().URL(ctx, "avatars/abc.png").Return("https://cdn.uiren.kz/avatars/abc.png", nil)
+
+		url, err := service.GetAvatarURL(ctx, userID, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://cdn.uiren.kz/avatars/abc.png", url)
+	})
+
+	t.Run("invalid legacy file is skipped", func(t *testing.T) {
+		repo.EXPECT().getAvatarKey(ctx, userID, 128).Return("", ErrAvatarNotFound)
+		legacy.EXPECT().Get(ctx, sized).Return([]byte("not an image"), nil)
+		legacy.EXPECT().Get(ctx, origin).Return(nil, objectstorage.ErrObjectNotFound)
+
+		_, err := service.GetAvatarURL(ctx, userID, 128)
+		assert.Equal(t, ErrAvatarNotFound, err)
+	})
+
+	t.Run("deleted without conversion", func(t *testing.T) {
+		legacy.EXPECT().Get(ctx, sized).Return(nil, objectstorage.ErrObjectNotFound)
+		legacy.EXPECT().Get(ctx, origin).Return(content.Bytes(), nil)
+		legacy.EXPECT().Delete(ctx, origin).Return(nil)
+		repo.EXPECT().deleteAvatarKeys(ctx, userID).Return(nil, ErrAvatarNotFound)
+
+		assert.NoError(t, service.DeleteAvatar(ctx, userID))
+	})
+}
+
+func Test_AvatarService_BackfillLegacyAvatars(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		repo        = NewMockavatarRepository(ctrl)
+		storage     = NewMockobjectStorage(ctrl)
+		legacy      = NewMocklegacyStorage(ctrl)
+		userService = NewMockuserService(ctrl)
+		service     = NewAvatarService(repo, storage, userService)
+		errDB       = errors.New("database error")
+		content     bytes.Buffer
+	)
+	service.WithLegacyStorage(legacy)
+	require.NoError(t, png.Encode(&content, halves(100, 100)))
+
+	userService.EXPECT().GetAllUsers(ctx).Return([]users.UserDTO{{ID: "legacy"}, {ID: "uploaded"}, {ID: "none"}, {ID: "broken"}}, nil)
+	// the legacy file is converted
+	repo.EXPECT().getAvatarKey(ctx, "legacy", DefaultSize).Return("", ErrAvatarNotFound)
+	legacy.EXPECT().Get(ctx, "avatars/avatar-legacy-512.png").Return(content.Bytes(), nil).Times(2)
+	storage.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), contentType).Return(nil).Times(len(Sizes))
+	repo.EXPECT().replaceAvatarKeys(ctx, "legacy", gomock.Any()).Return(nil, nil)
+	legacy.EXPECT().Delete(ctx, "avatars/avatar-legacy-512.png").Return(nil)
+	repo.EXPECT().getAvatarKey(ctx, "legacy", DefaultSize).Return("avatars/abc.png", nil)
+
+	// the user uploaded an avatar after the migration, the legacy file is stale
+	repo.EXPECT().getAvatarKey(ctx, "uploaded", DefaultSize).Return("avatars/def.png", nil)
+	legacy.EXPECT().Get(ctx, "avatars/avatar-uploaded.png").Return(content.Bytes(), nil)
+	legacy.EXPECT().Delete(ctx, "avatars/avatar-uploaded.png").Return(nil)
+
+	// no legacy file
+	repo.EXPECT().getAvatarKey(ctx, "none", DefaultSize).Return("", ErrAvatarNotFound)
+
+	repo.EXPECT().getAvatarKey(ctx, "broken", DefaultSize).Return("", errDB)
+
+	// every other legacy key is missing, gomock matches the expectations above first
+	legacy.EXPECT().Get(ctx, gomock.Any()).Return(nil, objectstorage.ErrObjectNotFound).AnyTimes()
+
+	report, err := service.BackfillLegacyAvatars(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, BackfillReport{Converted: 1, Stale: 1, Failed: []string{"broken"}}, report)
+}
+
+func Test_AvatarService_DeleteAvatar(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockavatarRepository(ctrl)
+		storage = NewMockobjectStorage(ctrl)
+		service = NewAvatarService(repo, storage, NewMockuserService(ctrl))
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().deleteAvatarKeys(ctx, "user-1").Return([]string{"avatars/a.png"}, nil)
+		storage.EXPECT().Delete(ctx, "avatars/a.png").Return(nil)
+
+		assert.NoError(t, service.DeleteAvatar(ctx, "user-1"))
+	})
+
+	t.Run("objects shared with another avatar are kept", func(t *testing.T) {
+		repo.EXPECT().deleteAvatarKeys(ctx, "user-2").Return(nil, nil)
+
+		assert.NoError(t, service.DeleteAvatar(ctx, "user-2"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().deleteAvatarKeys(ctx, "user-3").Return(nil, ErrAvatarNotFound)
+
+		assert.Equal(t, ErrAvatarNotFound, service.DeleteAvatar(ctx, "user-3"))
+	})
+}
+
+func Test_AvatarService_GetIdenticon(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		userService = NewMockuserService(ctrl)
+		service     = NewAvatarService(NewMockavatarRepository(ctrl), NewMockobjectStorage(ctrl), userService)
+		userID      = "0b6c1c52-7a43-4bd2-9d0c-3b3f1d5c8a11"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		userService.EXPECT().GetUserByID(ctx, userID).Return(users.UserDTO{ID: userID, Username: "seab", Firstname: "айдана", Lastname: "Серик"}, nil).Times(2)
+
+		avatar, err := service.GetIdenticon(ctx, userID, 64)
+		require.NoError(t, err)
+		assert.Equal(t, "image/svg+xml", avatar.ContentType)
+		assert.Contains(t, string(avatar.Content), `width="64" height="64"`)
+		assert.Contains(t, string(avatar.Content), ">АС</text>")
+
+		again, err := service.GetIdenticon(ctx, userID, 64)
+		require.NoError(t, err)
+		assert.Equal(t, avatar, again)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		userService.EXPECT().GetUserByID(ctx, userID).Return(users.UserDTO{}, users.ErrUserNotFound)
+
+		_, err := service.GetIdenticon(ctx, userID, 0)
+		assert.Equal(t, ErrAvatarNotFound, err)
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		_, err := service.GetIdenticon(ctx, "seab", 0)
+		assert.Equal(t, ErrAvatarNotFound, err)
+	})
+}
+
+func Test_identicon(t *testing.T) {
+	t.Parallel()
+
+	assert.NotEqual(t, identicon("user-1", "S", 64), identicon("user-2", "S", 64))
+	assert.Contains(t, string(identicon("user-1", "<&>", 64)), "&lt;&amp;&gt;")
+
+	assert.Equal(t, "SA", initials("seab", "asan", "user"))
+	assert.Equal(t, "S", initials("  seab", "", "user"))
+	assert.Equal(t, "U", initials("", "", "user"))
+	assert.Equal(t, "2", initials("", "", "_2pac"))
+	assert.Equal(t, "", initials("", "", ""))
+}
@@ -6,10 +6,6 @@ import (
 	"net/http"
 )
 
-func generateAvatarFilename(id string) string {
-	return "avatar-" + id + ".png"
-}
-
 func readFileContentAndType(file io.Reader) ([]byte, string, error) {
 	var buf bytes.Buffer
 
@@ -0,0 +1,43 @@
+package bundles
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+func Encode(bundle Bundle, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON, "":
+		return json.MarshalIndent(bundle, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(bundle)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func Decode(data []byte, format string) (Bundle, error) {
+	var (
+		bundle Bundle
+		err    error
+	)
+
+	switch format {
+	case FormatJSON, "":
+		err = json.Unmarshal(data, &bundle)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &bundle)
+	default:
+		return Bundle{}, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	if bundle.Version != bundleVersion {
+		return Bundle{}, ErrUnsupportedVersion
+	}
+
+	return bundle, nil
+}
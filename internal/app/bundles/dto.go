@@ -0,0 +1,335 @@
+package bundles
+
+import (
+	"slices"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/lessons"
+	"uiren/internal/app/modules"
+)
+
+const (
+	bundleVersion = 1
+
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+
+	ItemModule   = "module"
+	ItemLesson   = "lesson"
+	ItemExercise = "exercise"
+)
+
+// Bundle is a self-contained copy of a module with its lessons and exercises.
+// Publication status is not part of the bundle, imported content keeps the status of the target environment
+type Bundle struct {
+	Version   int              `json:"version" yaml:"version"`
+	Module    ModuleBundle     `json:"module" yaml:"module"`
+	Lessons   []LessonBundle   `json:"lessons" yaml:"lessons"`
+	Exercises []ExerciseBundle `json:"exercises" yaml:"exercises"`
+}
+
+type ModuleBundle struct {
+	Code        string             `json:"code" yaml:"code"`
+	Title       string             `json:"title" yaml:"title"`
+	Description string             `json:"description" yaml:"description"`
+	Goal        string             `json:"goal" yaml:"goal"`
+	Difficulty  string             `json:"difficulty" yaml:"difficulty"`
+	UnlockReq   UnlockRequirements `json:"unlock_requirements" yaml:"unlock_requirements"`
+	Reward      Reward             `json:"reward" yaml:"reward"`
+	Lessons     []string           `json:"lessons" yaml:"lessons"`
+}
+
+type UnlockRequirements struct {
+	PrevModuleCode string  `json:"previous_module" yaml:"previous_module"`
+	MinimumXP      float64 `json:"min_xp" yaml:"min_xp"`
+}
+
+type Reward struct {
+	XP    float64 `json:"xp" yaml:"xp"`
+	Badge string  `json:"badge" yaml:"badge"`
+}
+
+type LessonBundle struct {
+	Code        string   `json:"code" yaml:"code"`
+	Title       string   `json:"title" yaml:"title"`
+	Description string   `json:"description" yaml:"description"`
+	Exercises   []string `json:"exercises" yaml:"exercises"`
+}
+
+type ExerciseBundle struct {
+	Code          string   `json:"code" yaml:"code"`
+	ExerciseType  string   `json:"type" yaml:"type"`
+	Question      string   `json:"question" yaml:"question"`
+	Hints         []string `json:"hints" yaml:"hints"`
+	Explanation   string   `json:"explanation" yaml:"explanation"`
+	Options       []string `json:"options,omitempty" yaml:"options,omitempty"`
+	CorrectAnswer string   `json:"correct_answer,omitempty" yaml:"correct_answer,omitempty"`
+	CorrectOrder  []string `json:"correct_order,omitempty" yaml:"correct_order,omitempty"`
+	Pairs         []Pair   `json:"pairs,omitempty" yaml:"pairs,omitempty"`
+
+	Blanks         [][]string `json:"blanks,omitempty" yaml:"blanks,omitempty"`
+	CorrectAnswers []string   `json:"correct_answers,omitempty" yaml:"correct_answers,omitempty"`
+	IsTrue         *bool      `json:"is_true,omitempty" yaml:"is_true,omitempty"`
+
+	// media asset ids, assets are not copied, the target must have assets with the same ids
+	ImageID     string   `json:"image_id,omitempty" yaml:"image_id,omitempty"`
+	AudioID     string   `json:"audio_id,omitempty" yaml:"audio_id,omitempty"`
+	OptionMedia []string `json:"option_media,omitempty" yaml:"option_media,omitempty"`
+}
+
+type Pair struct {
+	Term  string `json:"term" yaml:"term"`
+	Match string `json:"match" yaml:"match"`
+}
+
+type ImportItem struct {
+	Type   string `json:"type" yaml:"type"`
+	Code   string `json:"code" yaml:"code"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun    bool         `json:"dry_run" yaml:"dry_run"`
+	Created   []ImportItem `json:"created" yaml:"created"`
+	Updated   []ImportItem `json:"updated" yaml:"updated"`
+	Unchanged []ImportItem `json:"unchanged" yaml:"unchanged"`
+	Conflicts []ImportItem `json:"conflicts" yaml:"conflicts"`
+}
+
+func newImportReport(dryRun bool) ImportReport {
+	return ImportReport{
+		DryRun:    dryRun,
+		Created:   make([]ImportItem, 0),
+		Updated:   make([]ImportItem, 0),
+		Unchanged: make([]ImportItem, 0),
+		Conflicts: make([]ImportItem, 0),
+	}
+}
+
+func moduleToBundle(module modules.ModuleWithLessons) ModuleBundle {
+	lessonCodes := make([]string, 0, len(module.Lessons))
+	for _, lesson := range module.Lessons {
+		lessonCodes = append(lessonCodes, lesson.Code)
+	}
+
+	return ModuleBundle{
+		Code:        module.Code,
+		Title:       module.Title,
+		Description: module.Description,
+		Goal:        module.Goal,
+		Difficulty:  module.Difficulty,
+		UnlockReq: UnlockRequirements{
+			PrevModuleCode: module.UnlockReq.PrevModuleCode,
+			MinimumXP:      module.UnlockReq.MinimumXP,
+		},
+		Reward: Reward{
+			XP:    module.Reward.XP,
+			Badge: module.Reward.Badge,
+		},
+		Lessons: lessonCodes,
+	}
+}
+
+func lessonToBundle(lesson lessons.LessonDTO) LessonBundle {
+	exerciseCodes := make([]string, 0, len(lesson.Exercises))
+	for _, exercise := range lesson.Exercises {
+		exerciseCodes = append(exerciseCodes, exercise.Code)
+	}
+
+	return LessonBundle{
+		Code:        lesson.Code,
+		Title:       lesson.Title,
+		Description: lesson.Description,
+		Exercises:   exerciseCodes,
+	}
+}
+
+func exerciseToBundle(exercise exercises.Exercise) ExerciseBundle {
+	pairs := make([]Pair, 0, len(exercise.Pairs))
+	for _, pair := range exercise.Pairs {
+		pairs = append(pairs, Pair{Term: pair.Term, Match: pair.Match})
+	}
+	if exercise.Pairs == nil {
+		pairs = nil
+	}
+
+	return ExerciseBundle{
+		Code:          exercise.Code,
+		ExerciseType:  exercise.ExerciseType,
+		Question:      exercise.Question,
+		Hints:         exercise.Hints,
+		Explanation:   exercise.Explanation,
+		Options:       exercise.Options,
+		CorrectAnswer: exercise.CorrectAnswer,
+		CorrectOrder:  exercise.CorrectOrder,
+		Pairs:         pairs,
+
+		Blanks:         exercise.Blanks,
+		CorrectAnswers: exercise.CorrectAnswers,
+		IsTrue:         exercise.IsTrue,
+
+		ImageID:     exercise.ImageID,
+		AudioID:     exercise.AudioID,
+		OptionMedia: exercise.OptionMedia,
+	}
+}
+
+func (m ModuleBundle) toCreateDTO() modules.CreateModuleDTO {
+	return modules.CreateModuleDTO{
+		Code:        m.Code,
+		Title:       m.Title,
+		Description: m.Description,
+		Goal:        m.Goal,
+		Difficulty:  m.Difficulty,
+		UnlockReq:   m.unlockReq(),
+		Reward:      m.reward(),
+	}
+}
+
+func (m ModuleBundle) toUpdateDTO() modules.UpdateModuleDTO {
+	unlockReq, reward := m.unlockReq(), m.reward()
+	return modules.UpdateModuleDTO{
+		Title:       &m.Title,
+		Description: &m.Description,
+		Goal:        &m.Goal,
+		Difficulty:  &m.Difficulty,
+		UnlockReq:   &unlockReq,
+		Reward:      &reward,
+	}
+}
+
+func (m ModuleBundle) unlockReq() modules.UnlockRequirements {
+	return modules.UnlockRequirements{
+		PrevModuleCode: m.UnlockReq.PrevModuleCode,
+		MinimumXP:      m.UnlockReq.MinimumXP,
+	}
+}
+
+func (m ModuleBundle) reward() modules.Reward {
+	return modules.Reward{
+		XP:    m.Reward.XP,
+		Badge: m.Reward.Badge,
+	}
+}
+
+// sameContent compares editable fields, lesson list is synced separately
+func (m ModuleBundle) sameContent(other ModuleBundle) bool {
+	return m.Title == other.Title &&
+		m.Description == other.Description &&
+		m.Goal == other.Goal &&
+		m.Difficulty == other.Difficulty &&
+		m.UnlockReq == other.UnlockReq &&
+		m.Reward == other.Reward
+}
+
+func (l LessonBundle) toCreateDTO() lessons.CreateLessonDTO {
+	return lessons.CreateLessonDTO{
+		Code:        l.Code,
+		Title:       l.Title,
+		Description: l.Description,
+	}
+}
+
+func (l LessonBundle) toUpdateDTO() lessons.UpdateLessonDTO {
+	return lessons.UpdateLessonDTO{
+		Title:       &l.Title,
+		Description: &l.Description,
+	}
+}
+
+func (l LessonBundle) sameContent(other LessonBundle) bool {
+	return l.Title == other.Title &&
+		l.Description == other.Description
+}
+
+func (e ExerciseBundle) toCreateDTO() exercises.CreateExerciseDTO {
+	dto := exercises.CreateExerciseDTO{
+		Code:         e.Code,
+		ExerciseType: e.ExerciseType,
+		Question:     e.Question,
+		Hints:        e.Hints,
+		Explanation:  e.Explanation,
+		Options:      e.Options,
+		CorrectOrder: e.CorrectOrder,
+		Pairs:        e.exercisePairs(),
+
+		Blanks:         e.Blanks,
+		CorrectAnswers: e.CorrectAnswers,
+		IsTrue:         e.IsTrue,
+
+		ImageID:     e.ImageID,
+		AudioID:     e.AudioID,
+		OptionMedia: e.OptionMedia,
+	}
+	if e.CorrectAnswer != "" {
+		dto.CorrectAnswer = &e.CorrectAnswer
+	}
+	return dto
+}
+
+func (e ExerciseBundle) toUpdateDTO() exercises.UpdateExerciseDTO {
+	dto := exercises.UpdateExerciseDTO{
+		Question:     &e.Question,
+		Hints:        e.Hints,
+		Explanation:  &e.Explanation,
+		Options:      e.Options,
+		CorrectOrder: e.CorrectOrder,
+		Pairs:        e.exercisePairs(),
+
+		Blanks:         e.Blanks,
+		CorrectAnswers: e.CorrectAnswers,
+		IsTrue:         e.IsTrue,
+
+		// empty ids detach the media the target has
+		ImageID:     &e.ImageID,
+		AudioID:     &e.AudioID,
+		OptionMedia: e.OptionMedia,
+	}
+	if e.CorrectAnswer != "" {
+		dto.CorrectAnswer = &e.CorrectAnswer
+	}
+	return dto
+}
+
+// mediaIDs returns the asset ids the exercise references
+func (e ExerciseBundle) mediaIDs() []string {
+	ids := make([]string, 0, 2+len(e.OptionMedia))
+	for _, id := range append([]string{e.ImageID, e.AudioID}, e.OptionMedia...) {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (e ExerciseBundle) exercisePairs() []exercises.Pair {
+	if e.Pairs == nil {
+		return nil
+	}
+
+	pairs := make([]exercises.Pair, 0, len(e.Pairs))
+	for _, pair := range e.Pairs {
+		pairs = append(pairs, exercises.Pair{Term: pair.Term, Match: pair.Match})
+	}
+	return pairs
+}
+
+func (e ExerciseBundle) sameContent(other ExerciseBundle) bool {
+	return e.ExerciseType == other.ExerciseType &&
+		e.Question == other.Question &&
+		slices.Equal(e.Hints, other.Hints) &&
+		e.Explanation == other.Explanation &&
+		slices.Equal(e.Options, other.Options) &&
+		e.CorrectAnswer == other.CorrectAnswer &&
+		slices.Equal(e.CorrectOrder, other.CorrectOrder) &&
+		slices.Equal(e.Pairs, other.Pairs) &&
+		slices.EqualFunc(e.Blanks, other.Blanks, slices.Equal[[]string]) &&
+		slices.Equal(e.CorrectAnswers, other.CorrectAnswers) &&
+		equalBool(e.IsTrue, other.IsTrue) &&
+		e.ImageID == other.ImageID &&
+		e.AudioID == other.AudioID &&
+		slices.Equal(e.OptionMedia, other.OptionMedia)
+}
+
+func equalBool(a, b *bool) bool {
+	return a == b || (a != nil && b != nil && *a == *b)
+}
@@ -0,0 +1,10 @@
+package bundles
+
+import "errors"
+
+var (
+	ErrUnsupportedFormat  = errors.New("unsupported bundle format")
+	ErrUnsupportedVersion = errors.New("unsupported bundle version")
+	ErrInvalidBundle      = errors.New("invalid bundle")
+	ErrImportConflicts    = errors.New("bundle has conflicts, nothing was imported")
+)
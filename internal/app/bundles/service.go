@@ -0,0 +1,489 @@
+package bundles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/lessons"
+	"uiren/internal/app/media"
+	"uiren/internal/app/modules"
+	"uiren/pkg/logger"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package bundles
+
+var (
+	codeRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+type modulesService interface {
+	GetModule(ctx context.Context, code string) (modules.ModuleWithLessons, error)
+	CreateModule(ctx context.Context, dto modules.CreateModuleDTO) (primitive.ObjectID, error)
+	UpdateModule(ctx context.Context, code string, dto modules.UpdateModuleDTO) error
+	AddLessonToList(ctx context.Context, code, lessonCode string) error
+	DeleteLessonFromList(ctx context.Context, code, lessonCode string) error
+	ReorderLessons(ctx context.Context, code string, lessonCodes []string) error
+}
+
+type lessonsService interface {
+	GetLesson(ctx context.Context, code string) (lessons.LessonDTO, error)
+	LessonExists(ctx context.Context, code string) (bool, error)
+	CreateLesson(ctx context.Context, dto lessons.CreateLessonDTO) (primitive.ObjectID, error)
+	UpdateLesson(ctx context.Context, code string, dto lessons.UpdateLessonDTO) error
+	AddExerciseToList(ctx context.Context, code, exerciseCode string) error
+	DeleteExerciseFromList(ctx context.Context, code, exerciseCode string) error
+	ReorderExercises(ctx context.Context, code string, exerciseCodes []string) error
+}
+
+type exerciseService interface {
+	GetExercise(ctx context.Context, code string) (exercises.Exercise, error)
+	ExerciseExists(ctx context.Context, code string) (bool, error)
+	ValidateExercise(dto exercises.CreateExerciseDTO) error
+	CreateExercise(ctx context.Context, dto exercises.CreateExerciseDTO) (primitive.ObjectID, error)
+	UpdateExercise(ctx context.Context, code string, dto exercises.UpdateExerciseDTO) error
+}
+
+type mediaService interface {
+	GetAssets(ctx context.Context, ids []string) ([]media.Asset, error)
+}
+
+type BundleService struct {
+	modulesService  modulesService
+	lessonsService  lessonsService
+	exerciseService exerciseService
+	mediaService    mediaService
+}
+
+func NewBundleService(modulesService modulesService, lessonsService lessonsService, exerciseService exerciseService) *BundleService {
+	return &BundleService{
+		modulesService:  modulesService,
+		lessonsService:  lessonsService,
+		exerciseService: exerciseService,
+	}
+}
+
+// WithMediaService checks that media referenced by imported exercises exists in the target
+func (s *BundleService) WithMediaService(mediaService mediaService) {
+	s.mediaService = mediaService
+}
+
+// ExportModule collects the module with all its lessons and exercises, lists keep their order
+func (s *BundleService) ExportModule(ctx context.Context, code string) (Bundle, error) {
+	logger.Info("BundleService.ExportModule new request")
+
+	module, err := s.modulesService.GetModule(ctx, code)
+	if err != nil {
+		logger.Error("BundleService.ExportModule modulesService.GetModule: ", err)
+		return Bundle{}, err
+	}
+
+	bundle := Bundle{
+		Version:   bundleVersion,
+		Module:    moduleToBundle(module),
+		Lessons:   make([]LessonBundle, 0, len(module.Lessons)),
+		Exercises: make([]ExerciseBundle, 0),
+	}
+
+	seen := make(map[string]struct{})
+	for _, lesson := range module.Lessons {
+		bundle.Lessons = append(bundle.Lessons, lessonToBundle(lesson))
+
+		for _, exercise := range lesson.Exercises {
+			if _, ok := seen[exercise.Code]; ok {
+				continue
+			}
+			seen[exercise.Code] = struct{}{}
+			bundle.Exercises = append(bundle.Exercises, exerciseToBundle(exercise))
+		}
+	}
+
+	return bundle, nil
+}
+
+// ImportBundle upserts bundle content by code. Nothing is written when the bundle has conflicts or in dry run mode,
+// the report shows what would be created, updated or left unchanged
+func (s *BundleService) ImportBundle(ctx context.Context, bundle Bundle, dryRun bool) (ImportReport, error) {
+	logger.Info("BundleService.ImportBundle new request")
+
+	plan, err := s.planImport(ctx, bundle)
+	if err != nil {
+		logger.Error("BundleService.ImportBundle planImport: ", err)
+		return ImportReport{}, err
+	}
+	plan.report.DryRun = dryRun
+
+	if len(plan.report.Conflicts) > 0 {
+		return plan.report, ErrImportConflicts
+	}
+	if dryRun {
+		return plan.report, nil
+	}
+
+	if err := s.applyImport(ctx, plan); err != nil {
+		logger.Error("BundleService.ImportBundle applyImport: ", err)
+		return plan.report, err
+	}
+
+	return plan.report, nil
+}
+
+type importAction int
+
+const (
+	actionNone importAction = iota
+	actionCreate
+	actionUpdate
+)
+
+type plannedItem struct {
+	action      importAction
+	syncList    bool
+	currentList []string
+}
+
+type importPlan struct {
+	report    ImportReport
+	bundle    Bundle
+	exercises []plannedItem
+	lessons   []plannedItem
+	module    plannedItem
+}
+
+func (p *importPlan) record(itemType, code string, item plannedItem) {
+	entry := ImportItem{Type: itemType, Code: code}
+	switch {
+	case item.action == actionCreate:
+		p.report.Created = append(p.report.Created, entry)
+	case item.action == actionUpdate || item.syncList:
+		p.report.Updated = append(p.report.Updated, entry)
+	default:
+		p.report.Unchanged = append(p.report.Unchanged, entry)
+	}
+}
+
+func (p *importPlan) conflict(itemType, code, reason string) {
+	p.report.Conflicts = append(p.report.Conflicts, ImportItem{Type: itemType, Code: code, Reason: reason})
+}
+
+func (s *BundleService) planImport(ctx context.Context, bundle Bundle) (*importPlan, error) {
+	plan := &importPlan{
+		report:    newImportReport(false),
+		bundle:    bundle,
+		exercises: make([]plannedItem, len(bundle.Exercises)),
+		lessons:   make([]plannedItem, len(bundle.Lessons)),
+	}
+
+	bundleExercises := make(map[string]struct{}, len(bundle.Exercises))
+	for i, exercise := range bundle.Exercises {
+		if !plan.checkCode(ItemExercise, exercise.Code, bundleExercises) {
+			continue
+		}
+
+		item, reason, err := s.planExercise(ctx, exercise)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			plan.conflict(ItemExercise, exercise.Code, reason)
+			continue
+		}
+		plan.exercises[i] = item
+		plan.record(ItemExercise, exercise.Code, item)
+	}
+
+	bundleLessons := make(map[string]struct{}, len(bundle.Lessons))
+	for i, lesson := range bundle.Lessons {
+		if !plan.checkCode(ItemLesson, lesson.Code, bundleLessons) {
+			continue
+		}
+
+		reason, err := s.checkReferences(ctx, lesson.Exercises, bundleExercises, ItemExercise, s.exerciseService.ExerciseExists)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			plan.conflict(ItemLesson, lesson.Code, reason)
+			continue
+		}
+
+		item, err := s.planLesson(ctx, lesson)
+		if err != nil {
+			return nil, err
+		}
+		plan.lessons[i] = item
+		plan.record(ItemLesson, lesson.Code, item)
+	}
+
+	module := bundle.Module
+	if !plan.checkCode(ItemModule, module.Code, make(map[string]struct{})) {
+		return plan, nil
+	}
+
+	reason, err := s.checkReferences(ctx, module.Lessons, bundleLessons, ItemLesson, s.lessonsService.LessonExists)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		plan.conflict(ItemModule, module.Code, reason)
+		return plan, nil
+	}
+
+	item, err := s.planModule(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+	plan.module = item
+	plan.record(ItemModule, module.Code, item)
+
+	return plan, nil
+}
+
+// checkCode validates code format and uniqueness inside the bundle
+func (p *importPlan) checkCode(itemType, code string, seen map[string]struct{}) bool {
+	if !codeRegex.MatchString(code) {
+		p.conflict(itemType, code, "invalid code")
+		return false
+	}
+	if _, ok := seen[code]; ok {
+		p.conflict(itemType, code, "duplicate code in bundle")
+		return false
+	}
+	seen[code] = struct{}{}
+	return true
+}
+
+// checkReferences returns a conflict reason if a list references codes that are neither in the bundle nor in the target
+func (s *BundleService) checkReferences(
+	ctx context.Context,
+	codes []string,
+	inBundle map[string]struct{},
+	itemType string,
+	exists func(ctx context.Context, code string) (bool, error),
+) (string, error) {
+	listed := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		if _, ok := listed[code]; ok {
+			return fmt.Sprintf("%s %s is listed twice", itemType, code), nil
+		}
+		listed[code] = struct{}{}
+
+		if _, ok := inBundle[code]; ok {
+			continue
+		}
+
+		ok, err := exists(ctx, code)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return fmt.Sprintf("%s %s is neither in the bundle nor in the target", itemType, code), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (s *BundleService) planExercise(ctx context.Context, exercise ExerciseBundle) (plannedItem, string, error) {
+	current, err := s.exerciseService.GetExercise(ctx, exercise.Code)
+	switch {
+	case errors.Is(err, exercises.ErrNotFound):
+		if reason, err := s.validateExercise(ctx, exercise); reason != "" || err != nil {
+			return plannedItem{}, reason, err
+		}
+		return plannedItem{action: actionCreate}, "", nil
+	case err != nil:
+		return plannedItem{}, "", err
+	}
+
+	if current.ExerciseType != exercise.ExerciseType {
+		return plannedItem{}, fmt.Sprintf("exercise type cannot be changed from %s to %s", current.ExerciseType, exercise.ExerciseType), nil
+	}
+
+	if exerciseToBundle(current).sameContent(exercise) {
+		return plannedItem{action: actionNone}, "", nil
+	}
+
+	if reason, err := s.validateExercise(ctx, exercise); reason != "" || err != nil {
+		return plannedItem{}, reason, err
+	}
+
+	return plannedItem{action: actionUpdate}, "", nil
+}
+
+// validateExercise returns a conflict reason if the exercise is invalid or references media
+// the target doesn't have, assets are not part of the bundle and are matched by id
+func (s *BundleService) validateExercise(ctx context.Context, exercise ExerciseBundle) (string, error) {
+	if err := s.exerciseService.ValidateExercise(exercise.toCreateDTO()); err != nil {
+		return err.Error(), nil
+	}
+
+	ids := exercise.mediaIDs()
+	if s.mediaService == nil || len(ids) == 0 {
+		return "", nil
+	}
+
+	assets, err := s.mediaService.GetAssets(ctx, ids)
+	if errors.Is(err, media.ErrAssetNotFound) {
+		return fmt.Sprintf("%s in the target", err), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	kinds := make(map[string]string, len(assets))
+	for _, asset := range assets {
+		kinds[asset.ID] = asset.Kind
+	}
+	if exercise.ImageID != "" && kinds[exercise.ImageID] != media.KindImage {
+		return fmt.Sprintf("image_id %s is not an image in the target", exercise.ImageID), nil
+	}
+	if exercise.AudioID != "" && kinds[exercise.AudioID] != media.KindAudio {
+		return fmt.Sprintf("audio_id %s is not audio in the target", exercise.AudioID), nil
+	}
+
+	return "", nil
+}
+
+func (s *BundleService) planLesson(ctx context.Context, lesson LessonBundle) (plannedItem, error) {
+	current, err := s.lessonsService.GetLesson(ctx, lesson.Code)
+	if errors.Is(err, lessons.ErrNotFound) {
+		return plannedItem{action: actionCreate, syncList: len(lesson.Exercises) > 0}, nil
+	}
+	if err != nil {
+		return plannedItem{}, err
+	}
+
+	currentBundle := lessonToBundle(current)
+	item := plannedItem{
+		action:      actionNone,
+		syncList:    !slices.Equal(currentBundle.Exercises, lesson.Exercises),
+		currentList: currentBundle.Exercises,
+	}
+	if !currentBundle.sameContent(lesson) {
+		item.action = actionUpdate
+	}
+
+	return item, nil
+}
+
+func (s *BundleService) planModule(ctx context.Context, module ModuleBundle) (plannedItem, error) {
+	current, err := s.modulesService.GetModule(ctx, module.Code)
+	if errors.Is(err, modules.ErrNotFound) {
+		return plannedItem{action: actionCreate, syncList: len(module.Lessons) > 0}, nil
+	}
+	if err != nil {
+		return plannedItem{}, err
+	}
+
+	currentBundle := moduleToBundle(current)
+	item := plannedItem{
+		action:      actionNone,
+		syncList:    !slices.Equal(currentBundle.Lessons, module.Lessons),
+		currentList: currentBundle.Lessons,
+	}
+	if !currentBundle.sameContent(module) {
+		item.action = actionUpdate
+	}
+
+	return item, nil
+}
+
+// applyImport writes children first so lists never point to missing content
+func (s *BundleService) applyImport(ctx context.Context, plan *importPlan) error {
+	for i, exercise := range plan.bundle.Exercises {
+		switch plan.exercises[i].action {
+		case actionCreate:
+			if _, err := s.exerciseService.CreateExercise(ctx, exercise.toCreateDTO()); err != nil {
+				return fmt.Errorf("exercise %s: %w", exercise.Code, err)
+			}
+		case actionUpdate:
+			if err := s.exerciseService.UpdateExercise(ctx, exercise.Code, exercise.toUpdateDTO()); err != nil {
+				return fmt.Errorf("exercise %s: %w", exercise.Code, err)
+			}
+		}
+	}
+
+	for i, lesson := range plan.bundle.Lessons {
+		item := plan.lessons[i]
+		switch item.action {
+		case actionCreate:
+			if _, err := s.lessonsService.CreateLesson(ctx, lesson.toCreateDTO()); err != nil {
+				return fmt.Errorf("lesson %s: %w", lesson.Code, err)
+			}
+		case actionUpdate:
+			if err := s.lessonsService.UpdateLesson(ctx, lesson.Code, lesson.toUpdateDTO()); err != nil {
+				return fmt.Errorf("lesson %s: %w", lesson.Code, err)
+			}
+		}
+
+		if item.syncList {
+			err := syncList(ctx, lesson.Code, item.currentList, lesson.Exercises,
+				s.lessonsService.AddExerciseToList, s.lessonsService.DeleteExerciseFromList, s.lessonsService.ReorderExercises)
+			if err != nil {
+				return fmt.Errorf("lesson %s exercises list: %w", lesson.Code, err)
+			}
+		}
+	}
+
+	module, item := plan.bundle.Module, plan.module
+	switch item.action {
+	case actionCreate:
+		if _, err := s.modulesService.CreateModule(ctx, module.toCreateDTO()); err != nil {
+			return fmt.Errorf("module %s: %w", module.Code, err)
+		}
+	case actionUpdate:
+		if err := s.modulesService.UpdateModule(ctx, module.Code, module.toUpdateDTO()); err != nil {
+			return fmt.Errorf("module %s: %w", module.Code, err)
+		}
+	}
+
+	if item.syncList {
+		err := syncList(ctx, module.Code, item.currentList, module.Lessons,
+			s.modulesService.AddLessonToList, s.modulesService.DeleteLessonFromList, s.modulesService.ReorderLessons)
+		if err != nil {
+			return fmt.Errorf("module %s lessons list: %w", module.Code, err)
+		}
+	}
+
+	return nil
+}
+
+// syncList removes codes missing from the bundle, appends new ones
+// and then puts the whole list into the bundle order in one write
+func syncList(
+	ctx context.Context,
+	code string,
+	current, desired []string,
+	add, remove func(ctx context.Context, code, itemCode string) error,
+	reorder func(ctx context.Context, code string, itemCodes []string) error,
+) error {
+	list := make([]string, 0, len(desired))
+	for _, itemCode := range current {
+		if !slices.Contains(desired, itemCode) {
+			if err := remove(ctx, code, itemCode); err != nil {
+				return err
+			}
+			continue
+		}
+		list = append(list, itemCode)
+	}
+	for _, itemCode := range desired {
+		if !slices.Contains(current, itemCode) {
+			if err := add(ctx, code, itemCode); err != nil {
+				return err
+			}
+			list = append(list, itemCode)
+		}
+	}
+
+	if slices.Equal(list, desired) {
+		return nil
+	}
+
+	return reorder(ctx, code, desired)
+}
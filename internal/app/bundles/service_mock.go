@@ -0,0 +1,384 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package bundles is a generated GoMock package.
+package bundles
+
+import (
+	context "context"
+	reflect "reflect"
+	exercises "uiren/internal/app/exercises"
+	lessons "uiren/internal/app/lessons"
+	media "uiren/internal/app/media"
+	modules "uiren/internal/app/modules"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockmodulesService is a mock of modulesService interface.
+type MockmodulesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockmodulesServiceMockRecorder
+}
+
+// MockmodulesServiceMockRecorder is the mock recorder for MockmodulesService.
+type MockmodulesServiceMockRecorder struct {
+	mock *MockmodulesService
+}
+
+// NewMockmodulesService creates a new mock instance.
+func NewMockmodulesService(ctrl *gomock.Controller) *MockmodulesService {
+	mock := &MockmodulesService{ctrl: ctrl}
+	mock.recorder = &MockmodulesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmodulesService) EXPECT() *MockmodulesServiceMockRecorder {
+	return m.recorder
+}
+
+// AddLessonToList mocks base method.
+func (m *MockmodulesService) AddLessonToList(ctx context.Context, code, lessonCode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLessonToList", ctx, code, lessonCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLessonToList indicates an expected call of AddLessonToList.
+func (mr *MockmodulesServiceMockRecorder) AddLessonToList(ctx, code, lessonCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLessonToList", reflect.TypeOf((*MockmodulesService)(nil).AddLessonToList), ctx, code, lessonCode)
+}
+
+// CreateModule mocks base method.
+func (m *MockmodulesService) CreateModule(ctx context.Context, dto modules.CreateModuleDTO) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModule", ctx, dto)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateModule indicates an expected call of CreateModule.
+func (mr *MockmodulesServiceMockRecorder) CreateModule(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModule", reflect.TypeOf((*MockmodulesService)(nil).CreateModule), ctx, dto)
+}
+
+// DeleteLessonFromList mocks base method.
+func (m *MockmodulesService) DeleteLessonFromList(ctx context.Context, code, lessonCode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLessonFromList", ctx, code, lessonCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLessonFromList indicates an expected call of DeleteLessonFromList.
+func (mr *MockmodulesServiceMockRecorder) DeleteLessonFromList(ctx, code, lessonCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLessonFromList", reflect.TypeOf((*MockmodulesService)(nil).DeleteLessonFromList), ctx, code, lessonCode)
+}
+
+// GetModule mocks base method.
+func (m *MockmodulesService) GetModule(ctx context.Context, code string) (modules.ModuleWithLessons, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModule", ctx, code)
+	ret0, _ := ret[0].(modules.ModuleWithLessons)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModule indicates an expected call of GetModule.
+func (mr *MockmodulesServiceMockRecorder) GetModule(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModule", reflect.TypeOf((*MockmodulesService)(nil).GetModule), ctx, code)
+}
+
+// ReorderLessons mocks base method.
+func (m *MockmodulesService) ReorderLessons(ctx context.Context, code string, lessonCodes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderLessons", ctx, code, lessonCodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderLessons indicates an expected call of ReorderLessons.
+func (mr *MockmodulesServiceMockRecorder) ReorderLessons(ctx, code, lessonCodes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderLessons", reflect.TypeOf((*MockmodulesService)(nil).ReorderLessons), ctx, code, lessonCodes)
+}
+
+// UpdateModule mocks base method.
+func (m *MockmodulesService) UpdateModule(ctx context.Context, code string, dto modules.UpdateModuleDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModule", ctx, code, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModule indicates an expected call of UpdateModule.
+func (mr *MockmodulesServiceMockRecorder) UpdateModule(ctx, code, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModule", reflect.TypeOf((*MockmodulesService)(nil).UpdateModule), ctx, code, dto)
+}
+
+// MocklessonsService is a mock of lessonsService interface.
+type MocklessonsService struct {
+	ctrl     *gomock.Controller
+	recorder *MocklessonsServiceMockRecorder
+}
+
+// MocklessonsServiceMockRecorder is the mock recorder for MocklessonsService.
+type MocklessonsServiceMockRecorder struct {
+	mock *MocklessonsService
+}
+
+// NewMocklessonsService creates a new mock instance.
+func NewMocklessonsService(ctrl *gomock.Controller) *MocklessonsService {
+	mock := &MocklessonsService{ctrl: ctrl}
+	mock.recorder = &MocklessonsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocklessonsService) EXPECT() *MocklessonsServiceMockRecorder {
+	return m.recorder
+}
+
+// AddExerciseToList mocks base method.
+func (m *MocklessonsService) AddExerciseToList(ctx context.Context, code, exerciseCode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddExerciseToList", ctx, code, exerciseCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddExerciseToList indicates an expected call of AddExerciseToList.
+func (mr *MocklessonsServiceMockRecorder) AddExerciseToList(ctx, code, exerciseCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExerciseToList", reflect.TypeOf((*MocklessonsService)(nil).AddExerciseToList), ctx, code, exerciseCode)
+}
+
+// CreateLesson mocks base method.
+func (m *MocklessonsService) CreateLesson(ctx context.Context, dto lessons.CreateLessonDTO) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLesson", ctx, dto)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLesson indicates an expected call of CreateLesson.
+func (mr *MocklessonsServiceMockRecorder) CreateLesson(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLesson", reflect.TypeOf((*MocklessonsService)(nil).CreateLesson), ctx, dto)
+}
+
+// DeleteExerciseFromList mocks base method.
+func (m *MocklessonsService) DeleteExerciseFromList(ctx context.Context, code, exerciseCode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExerciseFromList", ctx, code, exerciseCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExerciseFromList indicates an expected call of DeleteExerciseFromList.
+func (mr *MocklessonsServiceMockRecorder) DeleteExerciseFromList(ctx, code, exerciseCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExerciseFromList", reflect.TypeOf((*MocklessonsService)(nil).DeleteExerciseFromList), ctx, code, exerciseCode)
+}
+
+// GetLesson mocks base method.
+func (m *MocklessonsService) GetLesson(ctx context.Context, code string) (lessons.LessonDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLesson", ctx, code)
+	ret0, _ := ret[0].(lessons.LessonDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLesson indicates an expected call of GetLesson.
+func (mr *MocklessonsServiceMockRecorder) GetLesson(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLesson", reflect.TypeOf((*MocklessonsService)(nil).GetLesson), ctx, code)
+}
+
+// LessonExists mocks base method.
+func (m *MocklessonsService) LessonExists(ctx context.Context, code string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LessonExists", ctx, code)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LessonExists indicates an expected call of LessonExists.
+func (mr *MocklessonsServiceMockRecorder) LessonExists(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LessonExists", reflect.TypeOf((*MocklessonsService)(nil).LessonExists), ctx, code)
+}
+
+// ReorderExercises mocks base method.
+func (m *MocklessonsService) ReorderExercises(ctx context.Context, code string, exerciseCodes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderExercises", ctx, code, exerciseCodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderExercises indicates an expected call of ReorderExercises.
+func (mr *MocklessonsServiceMockRecorder) ReorderExercises(ctx, code, exerciseCodes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderExercises", reflect.TypeOf((*MocklessonsService)(nil).ReorderExercises), ctx, code, exerciseCodes)
+}
+
+// UpdateLesson mocks base method.
+func (m *MocklessonsService) UpdateLesson(ctx context.Context, code string, dto lessons.UpdateLessonDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLesson", ctx, code, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLesson indicates an expected call of UpdateLesson.
+func (mr *MocklessonsServiceMockRecorder) UpdateLesson(ctx, code, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLesson", reflect.TypeOf((*MocklessonsService)(nil).UpdateLesson), ctx, code, dto)
+}
+
+// MockexerciseService is a mock of exerciseService interface.
+type MockexerciseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockexerciseServiceMockRecorder
+}
+
+// MockexerciseServiceMockRecorder is the mock recorder for MockexerciseService.
+type MockexerciseServiceMockRecorder struct {
+	mock *MockexerciseService
+}
+
+// NewMockexerciseService creates a new mock instance.
+func NewMockexerciseService(ctrl *gomock.Controller) *MockexerciseService {
+	mock := &MockexerciseService{ctrl: ctrl}
+	mock.recorder = &MockexerciseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockexerciseService) EXPECT() *MockexerciseServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateExercise mocks base method.
+func (m *MockexerciseService) CreateExercise(ctx context.Context, dto exercises.CreateExerciseDTO) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExercise", ctx, dto)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExercise indicates an expected call of CreateExercise.
+func (mr *MockexerciseServiceMockRecorder) CreateExercise(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExercise", reflect.TypeOf((*MockexerciseService)(nil).CreateExercise), ctx, dto)
+}
+
+// ExerciseExists mocks base method.
+func (m *MockexerciseService) ExerciseExists(ctx context.Context, code string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExerciseExists", ctx, code)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExerciseExists indicates an expected call of ExerciseExists.
+func (mr *MockexerciseServiceMockRecorder) ExerciseExists(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExerciseExists", reflect.TypeOf((*MockexerciseService)(nil).ExerciseExists), ctx, code)
+}
+
+// GetExercise mocks base method.
+func (m *MockexerciseService) GetExercise(ctx context.Context, code string) (exercises.Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExercise", ctx, code)
+	ret0, _ := ret[0].(exercises.Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExercise indicates an expected call of GetExercise.
+func (mr *MockexerciseServiceMockRecorder) GetExercise(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExercise", reflect.TypeOf((*MockexerciseService)(nil).GetExercise), ctx, code)
+}
+
+// UpdateExercise mocks base method.
+func (m *MockexerciseService) UpdateExercise(ctx context.Context, code string, dto exercises.UpdateExerciseDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExercise", ctx, code, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExercise indicates an expected call of UpdateExercise.
+func (mr *MockexerciseServiceMockRecorder) UpdateExercise(ctx, code, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExercise", reflect.TypeOf((*MockexerciseService)(nil).UpdateExercise), ctx, code, dto)
+}
+
+// ValidateExercise mocks base method.
+func (m *MockexerciseService) ValidateExercise(dto exercises.CreateExerciseDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateExercise", dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateExercise indicates an expected call of ValidateExercise.
+func (mr *MockexerciseServiceMockRecorder) ValidateExercise(dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateExercise", reflect.TypeOf((*MockexerciseService)(nil).ValidateExercise), dto)
+}
+
+// MockmediaService is a mock of mediaService interface.
+type MockmediaService struct {
+	ctrl     *gomock.Controller
+	recorder *MockmediaServiceMockRecorder
+}
+
+// MockmediaServiceMockRecorder is the mock recorder for MockmediaService.
+type MockmediaServiceMockRecorder struct {
+	mock *MockmediaService
+}
+
+// NewMockmediaService creates a new mock instance.
+func NewMockmediaService(ctrl *gomock.Controller) *MockmediaService {
+	mock := &MockmediaService{ctrl: ctrl}
+	mock.recorder = &MockmediaServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmediaService) EXPECT() *MockmediaServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAssets mocks base method.
+func (m *MockmediaService) GetAssets(ctx context.Context, ids []string) ([]media.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssets", ctx, ids)
+	ret0, _ := ret[0].([]media.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssets indicates an expected call of GetAssets.
+func (mr *MockmediaServiceMockRecorder) GetAssets(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssets", reflect.TypeOf((*MockmediaService)(nil).GetAssets), ctx, ids)
+}
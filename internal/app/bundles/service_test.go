@@ -0,0 +1,398 @@
+package bundles
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/lessons"
+	"uiren/internal/app/media"
+	"uiren/internal/app/modules"
+	"uiren/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+var (
+	testExercise = exercises.Exercise{
+		Code:          "ex1",
+		ExerciseType:  "manual_typing",
+		Question:      "Сәлем?",
+		Hints:         []string{"hello"},
+		CorrectAnswer: "hello",
+	}
+	testLesson = lessons.LessonDTO{
+		Code:      "lesson1",
+		Title:     "Greetings",
+		Exercises: []exercises.Exercise{testExercise},
+	}
+	testModule = modules.ModuleWithLessons{
+		Code:    "module1",
+		Title:   "Basics",
+		Reward:  modules.Reward{XP: 100},
+		Lessons: []lessons.LessonDTO{testLesson},
+	}
+)
+
+func Test_BundleService_ExportModule(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		modulesSrv  = NewMockmodulesService(ctrl)
+		lessonsSrv  = NewMocklessonsService(ctrl)
+		exerciseSrv = NewMockexerciseService(ctrl)
+		srv         = NewBundleService(modulesSrv, lessonsSrv, exerciseSrv)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		module := testModule
+		module.Lessons = []lessons.LessonDTO{testLesson, {Code: "lesson2", Exercises: []exercises.Exercise{testExercise}}}
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+
+		bundle, err := srv.ExportModule(ctx, "module1")
+		assert.NoError(t, err)
+		assert.Equal(t, bundleVersion, bundle.Version)
+		assert.Equal(t, []string{"lesson1", "lesson2"}, bundle.Module.Lessons)
+		assert.Len(t, bundle.Lessons, 2)
+		assert.Equal(t, []ExerciseBundle{exerciseToBundle(testExercise)}, bundle.Exercises)
+	})
+
+	t.Run("module not found", func(t *testing.T) {
+		modulesSrv.EXPECT().GetModule(ctx, "unknown").Return(modules.ModuleWithLessons{}, modules.ErrNotFound)
+
+		_, err := srv.ExportModule(ctx, "unknown")
+		assert.Equal(t, modules.ErrNotFound, err)
+	})
+}
+
+func Test_BundleService_ImportBundle(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		modulesSrv  = NewMockmodulesService(ctrl)
+		lessonsSrv  = NewMocklessonsService(ctrl)
+		exerciseSrv = NewMockexerciseService(ctrl)
+		srv         = NewBundleService(modulesSrv, lessonsSrv, exerciseSrv)
+		bundle      = Bundle{
+			Version:   bundleVersion,
+			Module:    moduleToBundle(testModule),
+			Lessons:   []LessonBundle{lessonToBundle(testLesson)},
+			Exercises: []ExerciseBundle{exerciseToBundle(testExercise)},
+		}
+	)
+
+	t.Run("dry run on empty target", func(t *testing.T) {
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).Return(nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lessons.LessonDTO{}, lessons.ErrNotFound)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(modules.ModuleWithLessons{}, modules.ErrNotFound)
+
+		report, err := srv.ImportBundle(ctx, bundle, true)
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, []ImportItem{
+			{Type: ItemExercise, Code: "ex1"},
+			{Type: ItemLesson, Code: "lesson1"},
+			{Type: ItemModule, Code: "module1"},
+		}, report.Created)
+		assert.Empty(t, report.Conflicts)
+	})
+
+	t.Run("import into empty target", func(t *testing.T) {
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).Return(nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lessons.LessonDTO{}, lessons.ErrNotFound)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(modules.ModuleWithLessons{}, modules.ErrNotFound)
+
+		gomock.InOrder(
+			exerciseSrv.EXPECT().CreateExercise(ctx, gomock.Any()).Return(primitive.NewObjectID(), nil),
+			lessonsSrv.EXPECT().CreateLesson(ctx, gomock.Any()).Return(primitive.NewObjectID(), nil),
+			lessonsSrv.EXPECT().AddExerciseToList(ctx, "lesson1", "ex1").Return(nil),
+			modulesSrv.EXPECT().CreateModule(ctx, gomock.Any()).Return(primitive.NewObjectID(), nil),
+			modulesSrv.EXPECT().AddLessonToList(ctx, "module1", "lesson1").Return(nil),
+		)
+
+		report, err := srv.ImportBundle(ctx, bundle, false)
+		assert.NoError(t, err)
+		assert.Len(t, report.Created, 3)
+	})
+
+	t.Run("unchanged and updated", func(t *testing.T) {
+		changed := testModule
+		changed.Title = "Old title"
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(testExercise, nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(testLesson, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(changed, nil)
+		modulesSrv.EXPECT().UpdateModule(ctx, "module1", gomock.Any()).Return(nil)
+
+		report, err := srv.ImportBundle(ctx, bundle, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []ImportItem{{Type: ItemModule, Code: "module1"}}, report.Updated)
+		assert.Len(t, report.Unchanged, 2)
+	})
+
+	t.Run("lists are synced and reordered", func(t *testing.T) {
+		reordered := bundle
+		reordered.Lessons = []LessonBundle{lessonToBundle(testLesson)}
+		reordered.Lessons[0].Exercises = []string{"ex2", "ex1"}
+		reordered.Exercises = append(reordered.Exercises, ExerciseBundle{
+			Code: "ex2", ExerciseType: testExercise.ExerciseType, Question: "q2", CorrectAnswer: "a2",
+		})
+		current := testLesson
+		current.Exercises = []exercises.Exercise{testExercise, {Code: "old"}}
+
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(testExercise, nil)
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex2").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).Return(nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(current, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(testModule, nil)
+
+		gomock.InOrder(
+			exerciseSrv.EXPECT().CreateExercise(ctx, gomock.Any()).Return(primitive.NewObjectID(), nil),
+			lessonsSrv.EXPECT().DeleteExerciseFromList(ctx, "lesson1", "old").Return(nil),
+			lessonsSrv.EXPECT().AddExerciseToList(ctx, "lesson1", "ex2").Return(nil),
+			lessonsSrv.EXPECT().ReorderExercises(ctx, "lesson1", []string{"ex2", "ex1"}).Return(nil),
+		)
+
+		report, err := srv.ImportBundle(ctx, reordered, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []ImportItem{{Type: ItemLesson, Code: "lesson1"}}, report.Updated)
+	})
+
+	t.Run("conflicts abort import", func(t *testing.T) {
+		conflicting := bundle
+		conflicting.Lessons = []LessonBundle{{Code: "lesson1", Exercises: []string{"ex1", "missing"}}}
+		existing := testExercise
+		existing.ExerciseType = "multiple_choice"
+
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(existing, nil)
+		exerciseSrv.EXPECT().ExerciseExists(ctx, "missing").Return(false, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(testModule, nil)
+
+		report, err := srv.ImportBundle(ctx, conflicting, false)
+		assert.Equal(t, ErrImportConflicts, err)
+		assert.Len(t, report.Conflicts, 2)
+		assert.Equal(t, ItemExercise, report.Conflicts[0].Type)
+		assert.Equal(t, ItemLesson, report.Conflicts[1].Type)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		invalid := bundle
+		invalid.Exercises = nil
+		invalid.Lessons = nil
+		invalid.Module.Code = "bad code"
+		invalid.Module.Lessons = nil
+
+		report, err := srv.ImportBundle(ctx, invalid, true)
+		assert.Equal(t, ErrImportConflicts, err)
+		assert.Equal(t, []ImportItem{{Type: ItemModule, Code: "bad code", Reason: "invalid code"}}, report.Conflicts)
+	})
+}
+
+func Test_BundleService_ImportBundle_media(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		modulesSrv  = NewMockmodulesService(ctrl)
+		lessonsSrv  = NewMocklessonsService(ctrl)
+		exerciseSrv = NewMockexerciseService(ctrl)
+		mediaSrv    = NewMockmediaService(ctrl)
+		srv         = NewBundleService(modulesSrv, lessonsSrv, exerciseSrv)
+		withMedia   = exercises.Exercise{
+			Code:          "ex1",
+			ExerciseType:  "multiple_choice",
+			Question:      "Какое слово означает «кот»?",
+			Options:       []string{"мысық", "ит"},
+			CorrectAnswer: "мысық",
+			ImageID:       "image-1",
+			OptionMedia:   []string{"audio-1", ""},
+		}
+		lesson = lessons.LessonDTO{Code: "lesson1", Title: "Animals", Exercises: []exercises.Exercise{withMedia}}
+		module = modules.ModuleWithLessons{Code: "module1", Title: "Basics", Lessons: []lessons.LessonDTO{lesson}}
+		assets = []media.Asset{{ID: "image-1", Kind: media.KindImage}, {ID: "audio-1", Kind: media.KindAudio}}
+	)
+	srv.WithMediaService(mediaSrv)
+
+	modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+	bundle, err := srv.ExportModule(ctx, "module1")
+	assert.NoError(t, err)
+	assert.Equal(t, "image-1", bundle.Exercises[0].ImageID)
+	assert.Equal(t, []string{"audio-1", ""}, bundle.Exercises[0].OptionMedia)
+
+	t.Run("created with media", func(t *testing.T) {
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).Return(nil)
+		mediaSrv.EXPECT().GetAssets(ctx, []string{"image-1", "audio-1"}).Return(assets, nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lesson, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+		exerciseSrv.EXPECT().CreateExercise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, dto exercises.CreateExerciseDTO) (primitive.ObjectID, error) {
+			assert.Equal(t, "image-1", dto.ImageID)
+			assert.Equal(t, []string{"audio-1", ""}, dto.OptionMedia)
+			return primitive.NewObjectID(), nil
+		})
+
+		report, err := srv.ImportBundle(ctx, bundle, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []ImportItem{{Type: ItemExercise, Code: "ex1"}}, report.Created)
+	})
+
+	t.Run("re-import into the source is unchanged", func(t *testing.T) {
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(withMedia, nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lesson, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+
+		report, err := srv.ImportBundle(ctx, bundle, true)
+		assert.NoError(t, err)
+		assert.Len(t, report.Unchanged, 3)
+	})
+
+	t.Run("changed media is updated", func(t *testing.T) {
+		withoutImage := withMedia
+		withoutImage.ImageID = ""
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(withoutImage, nil)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).Return(nil)
+		mediaSrv.EXPECT().GetAssets(ctx, []string{"image-1", "audio-1"}).Return(assets, nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lesson, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+		exerciseSrv.EXPECT().UpdateExercise(ctx, "ex1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, dto exercises.UpdateExerciseDTO) error {
+			assert.Equal(t, "image-1", *dto.ImageID)
+			assert.Equal(t, "", *dto.AudioID)
+			return nil
+		})
+
+		report, err := srv.ImportBundle(ctx, bundle, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []ImportItem{{Type: ItemExercise, Code: "ex1"}}, report.Updated)
+	})
+
+	t.Run("missing asset is a conflict in dry run", func(t *testing.T) {
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).Return(nil)
+		mediaSrv.EXPECT().GetAssets(ctx, []string{"image-1", "audio-1"}).Return(nil, fmt.Errorf("%w: image-1", media.ErrAssetNotFound))
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lesson, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+
+		report, err := srv.ImportBundle(ctx, bundle, true)
+		assert.Equal(t, ErrImportConflicts, err)
+		assert.Equal(t, []ImportItem{{Type: ItemExercise, Code: "ex1", Reason: "media asset not found: image-1 in the target"}}, report.Conflicts)
+	})
+
+	t.Run("asset of another kind is a conflict", func(t *testing.T) {
+		exerciseSrv.EXPECT().GetExercise(ctx, "ex1").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).Return(nil)
+		mediaSrv.EXPECT().GetAssets(ctx, []string{"image-1", "audio-1"}).Return([]media.Asset{{ID: "image-1", Kind: media.KindAudio}, assets[1]}, nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lesson, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+
+		report, err := srv.ImportBundle(ctx, bundle, true)
+		assert.Equal(t, ErrImportConflicts, err)
+		assert.Equal(t, "image_id image-1 is not an image in the target", report.Conflicts[0].Reason)
+	})
+}
+
+func Test_BundleService_ImportBundle_listenAndType(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		modulesSrv  = NewMockmodulesService(ctrl)
+		lessonsSrv  = NewMocklessonsService(ctrl)
+		exerciseSrv = NewMockexerciseService(ctrl)
+		mediaSrv    = NewMockmediaService(ctrl)
+		srv         = NewBundleService(modulesSrv, lessonsSrv, exerciseSrv)
+		// real type validation, listen_and_type fails without audio_id
+		validate  = exercises.NewExerciseService(nil).ValidateExercise
+		listening = exercises.Exercise{
+			Code:           "listen1",
+			ExerciseType:   "listen_and_type",
+			Question:       "Что вы услышали?",
+			AudioID:        "audio-1",
+			CorrectAnswers: []string{"Сәлеметсіз бе"},
+		}
+		lesson = lessons.LessonDTO{Code: "lesson1", Title: "Listening", Exercises: []exercises.Exercise{listening}}
+		module = modules.ModuleWithLessons{Code: "module1", Title: "Basics", Lessons: []lessons.LessonDTO{lesson}}
+	)
+	srv.WithMediaService(mediaSrv)
+
+	modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+	exported, err := srv.ExportModule(ctx, "module1")
+	assert.NoError(t, err)
+
+	// the audio survives encoding
+	data, err := Encode(exported, FormatYAML)
+	assert.NoError(t, err)
+	bundle, err := Decode(data, FormatYAML)
+	assert.NoError(t, err)
+	assert.Equal(t, "audio-1", bundle.Exercises[0].AudioID)
+
+	t.Run("created in another environment", func(t *testing.T) {
+		exerciseSrv.EXPECT().GetExercise(ctx, "listen1").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).DoAndReturn(validate)
+		mediaSrv.EXPECT().GetAssets(ctx, []string{"audio-1"}).Return([]media.Asset{{ID: "audio-1", Kind: media.KindAudio}}, nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lessons.LessonDTO{}, lessons.ErrNotFound)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(modules.ModuleWithLessons{}, modules.ErrNotFound)
+
+		report, err := srv.ImportBundle(ctx, bundle, true)
+		assert.NoError(t, err)
+		assert.Empty(t, report.Conflicts)
+		assert.Len(t, report.Created, 3)
+	})
+
+	t.Run("re-import into the source is unchanged", func(t *testing.T) {
+		exerciseSrv.EXPECT().GetExercise(ctx, "listen1").Return(listening, nil)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lesson, nil)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(module, nil)
+
+		report, err := srv.ImportBundle(ctx, bundle, true)
+		assert.NoError(t, err)
+		assert.Len(t, report.Unchanged, 3)
+	})
+
+	t.Run("without audio is a conflict", func(t *testing.T) {
+		withoutAudio := bundle
+		withoutAudio.Exercises = []ExerciseBundle{bundle.Exercises[0]}
+		withoutAudio.Exercises[0].AudioID = ""
+		exerciseSrv.EXPECT().GetExercise(ctx, "listen1").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		exerciseSrv.EXPECT().ValidateExercise(gomock.Any()).DoAndReturn(validate)
+		lessonsSrv.EXPECT().GetLesson(ctx, "lesson1").Return(lessons.LessonDTO{}, lessons.ErrNotFound)
+		modulesSrv.EXPECT().GetModule(ctx, "module1").Return(modules.ModuleWithLessons{}, modules.ErrNotFound)
+
+		report, err := srv.ImportBundle(ctx, withoutAudio, true)
+		assert.Equal(t, ErrImportConflicts, err)
+		assert.Equal(t, []ImportItem{{Type: ItemExercise, Code: "listen1", Reason: exercises.ErrAudioRequired.Error()}}, report.Conflicts)
+	})
+}
+
+func Test_Codec(t *testing.T) {
+	t.Parallel()
+	bundle := Bundle{
+		Version:   bundleVersion,
+		Module:    moduleToBundle(testModule),
+		Lessons:   []LessonBundle{lessonToBundle(testLesson)},
+		Exercises: []ExerciseBundle{exerciseToBundle(testExercise)},
+	}
+
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		data, err := Encode(bundle, format)
+		assert.NoError(t, err)
+
+		decoded, err := Decode(data, format)
+		assert.NoError(t, err)
+		assert.Equal(t, bundle, decoded)
+	}
+
+	_, err := Decode([]byte(`{"version": 2}`), FormatJSON)
+	assert.Equal(t, ErrUnsupportedVersion, err)
+
+	_, err = Encode(bundle, "xml")
+	assert.Equal(t, ErrUnsupportedFormat, err)
+}
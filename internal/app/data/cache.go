@@ -0,0 +1,133 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"uiren/pkg/logger"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	maxNegativeTTL = 30 * time.Second
+)
+
+// CachePolicy describes how long a cache family lives in redis.
+// Entry is served as is during TTL, then during StaleTTL it is still served
+// but reloaded in background. Not found codes are remembered for NegativeTTL.
+type CachePolicy struct {
+	TTL         time.Duration
+	StaleTTL    time.Duration
+	NegativeTTL time.Duration
+}
+
+type cacheEntry[T any] struct {
+	Value      T         `json:"value"`
+	NotFound   bool      `json:"not_found,omitempty"`
+	FreshUntil time.Time `json:"fresh_until"`
+}
+
+// readThroughCache loads values on miss and keeps them in redis,
+// concurrent misses of the same key share one load
+type readThroughCache[T any] struct {
+	name        string
+	redisClient redisClient
+	policy      CachePolicy
+	notFoundErr error
+	group       singleflight.Group
+	now         func() time.Time
+}
+
+func newReadThroughCache[T any](name string, redisClient redisClient, policy CachePolicy, notFoundErr error) *readThroughCache[T] {
+	return &readThroughCache[T]{
+		name:        name,
+		redisClient: redisClient,
+		policy:      policy,
+		notFoundErr: notFoundErr,
+		now:         time.Now,
+	}
+}
+
+func (c *readThroughCache[T]) Get(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	entry, ok := c.read(ctx, key)
+	if ok {
+		if entry.NotFound {
+			var zero T
+			return zero, c.notFoundErr
+		}
+
+		if c.now().After(entry.FreshUntil) {
+			// reload without the request context, it may be canceled before the load ends
+			c.group.DoChan(key, func() (interface{}, error) {
+				return c.load(context.WithoutCancel(ctx), key, load)
+			})
+		}
+
+		return entry.Value, nil
+	}
+
+	result, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return c.load(ctx, key, load)
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return result.(T), nil
+}
+
+func (c *readThroughCache[T]) read(ctx context.Context, key string) (cacheEntry[T], bool) {
+	var entry cacheEntry[T]
+
+	data, err := c.redisClient.Get(ctx, key)
+	if err != nil {
+		logger.Error("readThroughCache.read "+c.name+" redisClient.Get: ", err)
+		return entry, false
+	}
+
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		logger.Error("readThroughCache.read "+c.name+" json.Unmarshal: ", err)
+		return entry, false
+	}
+
+	return entry, true
+}
+
+func (c *readThroughCache[T]) load(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	value, err := load(ctx)
+	if err != nil {
+		if c.notFoundErr != nil && c.policy.NegativeTTL > 0 && errors.Is(err, c.notFoundErr) {
+			c.write(ctx, key, cacheEntry[T]{NotFound: true, FreshUntil: c.now().Add(c.policy.NegativeTTL)}, c.policy.NegativeTTL)
+		}
+		return value, err
+	}
+
+	c.write(ctx, key, cacheEntry[T]{Value: value, FreshUntil: c.now().Add(c.policy.TTL)}, c.policy.TTL+c.policy.StaleTTL)
+
+	return value, nil
+}
+
+// write stores the entry, cache errors must not fail the read itself
+func (c *readThroughCache[T]) write(ctx context.Context, key string, entry cacheEntry[T], ttl time.Duration) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		logger.Error("readThroughCache.write "+c.name+" json.Marshal: ", err)
+		return
+	}
+
+	if err := c.redisClient.Set(ctx, key, data, &ttl); err != nil {
+		logger.Error("readThroughCache.write "+c.name+" redisClient.Set: ", err)
+	}
+}
+
+// defaultCachePolicy keeps entries fresh for dataTTL and serves them stale for one more dataTTL
+func defaultCachePolicy(dataTTL time.Duration) CachePolicy {
+	return CachePolicy{
+		TTL:         dataTTL,
+		StaleTTL:    dataTTL,
+		NegativeTTL: min(dataTTL, maxNegativeTTL),
+	}
+}
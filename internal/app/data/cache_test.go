@@ -0,0 +1,152 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"uiren/internal/app/lessons"
+
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCachePolicy = CachePolicy{
+	TTL:         time.Minute,
+	StaleTTL:    time.Minute,
+	NegativeTTL: time.Second,
+}
+
+func freshEntry[T any](value T) string {
+	data, _ := json.Marshal(cacheEntry[T]{Value: value, FreshUntil: time.Now().Add(time.Hour)})
+	return string(data)
+}
+
+func Test_readThroughCache_Get(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx      = context.TODO()
+		ctrl     = gomock.NewController(t)
+		redisCli = NewMockredisClient(ctrl)
+		cache    = newReadThroughCache[string]("test", redisCli, testCachePolicy, lessons.ErrNotFound)
+		errRepo  = errors.New("repo error")
+	)
+
+	t.Run("miss stores value with ttl and stale window", func(t *testing.T) {
+		ttl := testCachePolicy.TTL + testCachePolicy.StaleTTL
+		redisCli.EXPECT().Get(ctx, "key").Return("", redis.Nil)
+		redisCli.EXPECT().Set(ctx, "key", gomock.Any(), &ttl).Return(nil)
+
+		value, err := cache.Get(ctx, "key", func(ctx context.Context) (string, error) {
+			return "value", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("not found is cached", func(t *testing.T) {
+		ttl := testCachePolicy.NegativeTTL
+		var stored []byte
+		redisCli.EXPECT().Get(ctx, "missing").Return("", redis.Nil)
+		redisCli.EXPECT().Set(ctx, "missing", gomock.Any(), &ttl).
+			DoAndReturn(func(ctx context.Context, key string, value interface{}, ttl *time.Duration) error {
+				stored = value.([]byte)
+				return nil
+			})
+
+		_, err := cache.Get(ctx, "missing", func(ctx context.Context) (string, error) {
+			return "", lessons.ErrNotFound
+		})
+		assert.Equal(t, lessons.ErrNotFound, err)
+
+		redisCli.EXPECT().Get(ctx, "missing").Return(string(stored), nil)
+
+		_, err = cache.Get(ctx, "missing", func(ctx context.Context) (string, error) {
+			t.Fatal("negative entry must not be reloaded")
+			return "", nil
+		})
+		assert.Equal(t, lessons.ErrNotFound, err)
+	})
+
+	t.Run("other errors are not cached", func(t *testing.T) {
+		redisCli.EXPECT().Get(ctx, "key").Return("", redis.Nil)
+
+		_, err := cache.Get(ctx, "key", func(ctx context.Context) (string, error) {
+			return "", errRepo
+		})
+		assert.Equal(t, errRepo, err)
+	})
+
+	t.Run("stale value is served and reloaded", func(t *testing.T) {
+		stale, _ := json.Marshal(cacheEntry[string]{Value: "old", FreshUntil: time.Now().Add(-time.Second)})
+		reloaded := make(chan struct{})
+		redisCli.EXPECT().Get(ctx, "stale").Return(string(stale), nil)
+		redisCli.EXPECT().Set(gomock.Any(), "stale", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, value interface{}, ttl *time.Duration) error {
+				close(reloaded)
+				return nil
+			})
+
+		value, err := cache.Get(ctx, "stale", func(ctx context.Context) (string, error) {
+			return "new", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "old", value)
+
+		select {
+		case <-reloaded:
+		case <-time.After(time.Second):
+			t.Fatal("stale entry was not reloaded")
+		}
+	})
+}
+
+func Test_readThroughCache_singleflight(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx      = context.TODO()
+		ctrl     = gomock.NewController(t)
+		redisCli = NewMockredisClient(ctrl)
+		cache    = newReadThroughCache[string]("test", redisCli, testCachePolicy, nil)
+		loads    atomic.Int32
+		release  = make(chan struct{})
+		started  sync.WaitGroup
+		done     sync.WaitGroup
+		callers  = 10
+	)
+
+	started.Add(callers)
+	redisCli.EXPECT().Get(ctx, "key").DoAndReturn(func(ctx context.Context, key string) (string, error) {
+		started.Done()
+		return "", redis.Nil
+	}).Times(callers)
+	redisCli.EXPECT().Set(ctx, "key", gomock.Any(), gomock.Any()).Return(nil)
+
+	load := func(ctx context.Context) (string, error) {
+		loads.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer done.Done()
+			value, err := cache.Get(ctx, "key", load)
+			assert.NoError(t, err)
+			assert.Equal(t, "value", value)
+		}()
+	}
+
+	started.Wait()
+	// give the callers time to join the in-flight load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+}
@@ -18,6 +18,7 @@ type UserInfo struct {
 	Progress  *users.UserProgress `json:"progress"`
 	CreatedAt time.Time           `json:"created_at"`
 	AvatarURL string              `json:"avatar_url"`
+	HasAvatar bool                `json:"has_avatar"`
 }
 
 type ModulesList struct {
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"context"
+	"uiren/internal/app/events"
+	"uiren/pkg/logger"
+)
+
+// HandleEvent evicts cached data which depends on the changed entity,
+// it is subscribed to the events bus
+func (s *DataService) HandleEvent(ctx context.Context, event events.Event) {
+	keys, err := s.dependentKeys(ctx, event)
+	if err != nil {
+		logger.Error("DataService.HandleEvent dependentKeys: ", err)
+	}
+
+	for _, key := range keys {
+		if err := s.redisClient.Delete(ctx, key); err != nil {
+			logger.Error("DataService.HandleEvent redisClient.Delete: ", err)
+		}
+	}
+}
+
+// dependentKeys returns cache keys built from the entity: the lesson is cached together
+// with its exercises and the modules list holds the lesson codes of every module
+func (s *DataService) dependentKeys(ctx context.Context, event events.Event) ([]string, error) {
+	switch event.Type {
+	case events.ModuleChanged:
+		return []string{getModulesCacheKey}, nil
+	case events.LessonChanged:
+		return []string{generateLessonKey(event.Code), getModulesCacheKey}, nil
+	case events.ExerciseChanged:
+		keys := []string{generateExerciseKey(event.Code)}
+		if s.lessonsService == nil {
+			return keys, nil
+		}
+
+		lessonCodes, err := s.lessonsService.GetLessonsReferencingExercise(ctx, event.Code)
+		if err != nil {
+			return keys, err
+		}
+		for _, code := range lessonCodes {
+			keys = append(keys, generateLessonKey(code))
+		}
+
+		return keys, nil
+	case events.AchievementChanged:
+		return []string{getAchievementsCacheKey}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,57 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"uiren/internal/app/events"
+
+	"github.com/golang/mock/gomock"
+)
+
+func Test_dataService_HandleEvent(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		redisClient    = NewMockredisClient(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+		service        = &DataService{redisClient: redisClient, lessonsService: lessonsService}
+		errRepo        = errors.New("repo error")
+	)
+
+	t.Run("module", func(t *testing.T) {
+		redisClient.EXPECT().Delete(ctx, getModulesCacheKey).Return(nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.ModuleChanged, Code: "module1"})
+	})
+
+	t.Run("lesson evicts modules list", func(t *testing.T) {
+		redisClient.EXPECT().Delete(ctx, generateLessonKey("lesson1")).Return(nil)
+		redisClient.EXPECT().Delete(ctx, getModulesCacheKey).Return(nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.LessonChanged, Code: "lesson1"})
+	})
+
+	t.Run("exercise evicts lessons using it", func(t *testing.T) {
+		lessonsService.EXPECT().GetLessonsReferencingExercise(ctx, "ex1").Return([]string{"lesson1", "lesson2"}, nil)
+		redisClient.EXPECT().Delete(ctx, generateExerciseKey("ex1")).Return(nil)
+		redisClient.EXPECT().Delete(ctx, generateLessonKey("lesson1")).Return(nil)
+		redisClient.EXPECT().Delete(ctx, generateLessonKey("lesson2")).Return(errors.New("redis error"))
+
+		service.HandleEvent(ctx, events.Event{Type: events.ExerciseChanged, Code: "ex1"})
+	})
+
+	t.Run("exercise lookup fail still evicts exercise", func(t *testing.T) {
+		lessonsService.EXPECT().GetLessonsReferencingExercise(ctx, "ex1").Return(nil, errRepo)
+		redisClient.EXPECT().Delete(ctx, generateExerciseKey("ex1")).Return(nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.ExerciseChanged, Code: "ex1"})
+	})
+
+	t.Run("achievement", func(t *testing.T) {
+		redisClient.EXPECT().Delete(ctx, getAchievementsCacheKey).Return(nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.AchievementChanged, Code: "1"})
+	})
+}
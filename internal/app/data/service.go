@@ -2,10 +2,11 @@ package data
 
 import (
 	"context"
-	"encoding/json"
+	"slices"
 	"time"
 	"uiren/internal/app/achievements"
 	"uiren/internal/app/exercises"
+	"uiren/internal/app/hearts"
 	"uiren/internal/app/lessons"
 	"uiren/internal/app/modules"
 	"uiren/internal/app/progress"
@@ -20,21 +21,36 @@ const (
 	getAchievementsCacheKey = "all_achievements_list"
 )
 
+const (
+	CacheModules      = "modules"
+	CacheLessons      = "lessons"
+	CacheExercises    = "exercises"
+	CacheAchievements = "achievements"
+	CacheLeaderboard  = "xp_leaderboard"
+)
+
+var CacheFamilies = []string{CacheModules, CacheLessons, CacheExercises, CacheAchievements, CacheLeaderboard}
+
 type userService interface {
 	GetUserByUsername(ctx context.Context, username string) (users.UserDTO, error)
 	GetUserProgress(ctx context.Context, id string) (users.UserProgress, error)
 }
 
 type modulesService interface {
-	GetModulesList(ctx context.Context) ([]modules.Module, error)
+	GetPublishedModulesList(ctx context.Context) ([]modules.Module, error)
 }
 
 type lessonsService interface {
-	GetLesson(ctx context.Context, code string) (lessons.LessonDTO, error)
+	GetPublishedLesson(ctx context.Context, code string) (lessons.LessonDTO, error)
+	GetLessonsReferencingExercise(ctx context.Context, exerciseCode string) ([]string, error)
 }
 
 type exerciseService interface {
-	GetExercise(ctx context.Context, code string) (exercises.Exercise, error)
+	GetPublishedExercise(ctx context.Context, code string) (exercises.Exercise, error)
+}
+
+type mediaService interface {
+	ResolveURLs(ctx context.Context, ids []string) (map[string]string, error)
 }
 
 type redisClient interface {
@@ -51,6 +67,14 @@ type achievementsService interface {
 	GetAllAchievements(ctx context.Context) ([]achievements.AchievementDTO, error)
 }
 
+type friendshipService interface {
+	GetBlockedUsernames(ctx context.Context, username string) ([]string, error)
+}
+
+type heartsService interface {
+	GetHearts(ctx context.Context, userID string) (hearts.Hearts, error)
+}
+
 type DataService struct {
 	redisClient         redisClient
 	userService         userService
@@ -58,9 +82,17 @@ type DataService struct {
 	lessonsService      lessonsService
 	exerciseService     exerciseService
 	achievementsService achievementsService
+	friendshipService   friendshipService
 	progressService     progressService
-	dataTTL             time.Duration
+	mediaService        mediaService
+	heartsService       heartsService
 	xpLeaderboardLimit  int
+
+	modulesCache      *readThroughCache[[]modules.Module]
+	lessonsCache      *readThroughCache[lessons.LessonDTO]
+	exercisesCache    *readThroughCache[exercises.Exercise]
+	achievementsCache *readThroughCache[[]achievements.AchievementDTO]
+	leaderboardCache  *readThroughCache[progress.XPLeaderboard]
 }
 
 func NewDataService(
@@ -69,11 +101,34 @@ func NewDataService(
 	modulesService modulesService,
 	dataTTL time.Duration,
 ) *DataService {
+	policy := defaultCachePolicy(dataTTL)
+
 	return &DataService{
 		redisClient:    redisClient,
 		userService:    userService,
 		modulesService: modulesService,
-		dataTTL:        dataTTL,
+
+		modulesCache:      newReadThroughCache[[]modules.Module](CacheModules, redisClient, policy, nil),
+		lessonsCache:      newReadThroughCache[lessons.LessonDTO](CacheLessons, redisClient, policy, lessons.ErrNotFound),
+		exercisesCache:    newReadThroughCache[exercises.Exercise](CacheExercises, redisClient, policy, exercises.ErrNotFound),
+		achievementsCache: newReadThroughCache[[]achievements.AchievementDTO](CacheAchievements, redisClient, policy, nil),
+		leaderboardCache:  newReadThroughCache[progress.XPLeaderboard](CacheLeaderboard, redisClient, policy, nil),
+	}
+}
+
+// WithCachePolicy overrides TTLs of one cache family, by default all families live for dataTTL
+func (s *DataService) WithCachePolicy(family string, policy CachePolicy) {
+	switch family {
+	case CacheModules:
+		s.modulesCache.policy = policy
+	case CacheLessons:
+		s.lessonsCache.policy = policy
+	case CacheExercises:
+		s.exercisesCache.policy = policy
+	case CacheAchievements:
+		s.achievementsCache.policy = policy
+	case CacheLeaderboard:
+		s.leaderboardCache.policy = policy
 	}
 }
 
@@ -98,6 +153,18 @@ func (s *DataService) WithAchievementService(achievementsService achievementsSer
 	s.achievementsService = achievementsService
 }
 
+func (s *DataService) WithFriendshipService(friendshipService friendshipService) {
+	s.friendshipService = friendshipService
+}
+
+func (s *DataService) WithMediaService(mediaService mediaService) {
+	s.mediaService = mediaService
+}
+
+func (s *DataService) WithHeartsService(heartsService heartsService) {
+	s.heartsService = heartsService
+}
+
 func (s *DataService) GetUserWithProgress(ctx context.Context, username string) (UserInfo, error) {
 	logger.Info("DataService.GetUser new request")
 
@@ -113,6 +180,15 @@ func (s *DataService) GetUserWithProgress(ctx context.Context, username string)
 		return UserInfo{}, err
 	}
 
+	if s.heartsService != nil {
+		userHearts, err := s.heartsService.GetHearts(ctx, userDTO.ID)
+		if err != nil {
+			logger.Error("DataService.GetUser heartsService.GetHearts: ", err)
+			return UserInfo{}, err
+		}
+		userProgress.Hearts = &userHearts
+	}
+
 	return UserInfo{
 		ID:        userDTO.ID,
 		Username:  userDTO.Username,
@@ -147,114 +223,78 @@ func (s *DataService) GetUserWithoutProgress(ctx context.Context, username strin
 
 func (s *DataService) GetPublicModules(ctx context.Context) (ModulesList, error) {
 	logger.Info("DataService.GetModules new request")
-	var modulesList []modules.Module
-	data, err := s.redisClient.Get(ctx, getModulesCacheKey)
 
+	modulesList, err := s.modulesCache.Get(ctx, getModulesCacheKey, s.modulesService.GetPublishedModulesList)
 	if err != nil {
-		logger.Error("DataService.GetModules redis.Get: ", err)
-		modulesList, err = s.modulesService.GetModulesList(ctx)
-		if err != nil {
-			logger.Error("DataService.GetModules modulesService.GetModules: ", err)
-			return ModulesList{}, err
-		}
-		newData, err := json.Marshal(modulesList)
-		if err != nil {
-			logger.Error("DataService.GetModules json.Marshal: ", err)
-			return ModulesList{}, err
-		}
-
-		err = s.redisClient.Set(ctx, getModulesCacheKey, newData, &s.dataTTL)
-		if err != nil {
-			logger.Error("DataService.GetModules redisClient.Set: ", err)
-		}
-		return ModulesList{
-			Modules: modulesList,
-			Total:   len(modulesList),
-		}, nil
-	}
-
-	err = json.Unmarshal([]byte(data), &modulesList)
-	if err != nil {
-		logger.Error("DataService.GetModules json.Unmarshal: ", err)
+		logger.Error("DataService.GetModules modulesService.GetPublishedModulesList: ", err)
 		return ModulesList{}, err
 	}
+
 	return ModulesList{
 		Modules: modulesList,
 		Total:   len(modulesList),
 	}, nil
 }
 
-func (s *DataService) GetXPLeaderboard(ctx context.Context) (XPLeaderboard, error) {
+// GetXPLeaderboard returns cached leaderboard without users blocked by the viewer or blocking them,
+// ranks stay the same as in the shared leaderboard
+func (s *DataService) GetXPLeaderboard(ctx context.Context, viewer string) (XPLeaderboard, error) {
 	logger.Info("DataService.GetXPLeaderboard new request")
-	var leaderboard progress.XPLeaderboard
 
 	key := generateXpLeaderboardKey(s.xpLeaderboardLimit)
-	data, err := s.redisClient.Get(ctx, key)
+	leaderboard, err := s.leaderboardCache.Get(ctx, key, func(ctx context.Context) (progress.XPLeaderboard, error) {
+		return s.progressService.GetXPLeaderboard(ctx, s.xpLeaderboardLimit)
+	})
 	if err != nil {
-		logger.Error("DataService.GetXPLeaderboard redis.Get: ", err)
-
-		leaderboard, err = s.progressService.GetXPLeaderboard(ctx, s.xpLeaderboardLimit)
-		if err != nil {
-			logger.Error("DataService.GetXPLeaderboard progressService.GetXPLeaderboard: ", err)
-			return XPLeaderboard{}, err
-		}
-
-		newData, err := json.Marshal(leaderboard)
-		if err != nil {
-			logger.Error("DataService.GetXPLeaderboard json.Marshal: ", err)
-			return XPLeaderboard{}, err
-		}
-
-		err = s.redisClient.Set(ctx, key, newData, &s.dataTTL)
-		if err != nil {
-			logger.Error("DataService.GetXPLeaderboard redisClient.Set: ", err)
-		}
+		logger.Error("DataService.GetXPLeaderboard progressService.GetXPLeaderboard: ", err)
+		return XPLeaderboard{}, err
+	}
 
+	if s.friendshipService == nil || viewer == "" {
 		return XPLeaderboard{Board: leaderboard}, nil
 	}
 
-	err = json.Unmarshal([]byte(data), &leaderboard)
+	blocked, err := s.friendshipService.GetBlockedUsernames(ctx, viewer)
 	if err != nil {
-		logger.Error("DataService.GetXPLeaderboard json.Unmarshal: ", err)
+		logger.Error("DataService.GetXPLeaderboard friendshipService.GetBlockedUsernames: ", err)
 		return XPLeaderboard{}, err
 	}
+	if len(blocked) == 0 {
+		return XPLeaderboard{Board: leaderboard}, nil
+	}
+
+	hidden := make(map[string]struct{}, len(blocked))
+	for _, username := range blocked {
+		hidden[username] = struct{}{}
+	}
+
+	// cached leaderboard is shared between viewers, so leaders are copied before filtering
+	leaders := make([]progress.XPLeaderboardEntry, 0, len(leaderboard.Leaders))
+	for _, leader := range leaderboard.Leaders {
+		if _, ok := hidden[leader.Username]; ok {
+			continue
+		}
+		leaders = append(leaders, leader)
+	}
+	leaderboard.Leaders = leaders
 
 	return XPLeaderboard{Board: leaderboard}, nil
 }
 
 func (s *DataService) GetPublicLesson(ctx context.Context, code string) (lessons.LessonDTO, error) {
 	logger.Info("DataService.GetPublicLesson new request")
-	var lesson lessons.LessonDTO
-
-	key := generateLessonKey(code)
-	data, err := s.redisClient.Get(ctx, key)
 
+	lesson, err := s.lessonsCache.Get(ctx, generateLessonKey(code), func(ctx context.Context) (lessons.LessonDTO, error) {
+		return s.lessonsService.GetPublishedLesson(ctx, code)
+	})
 	if err != nil {
-		logger.Error("DataService.GetPublicLesson redis.Get: ", err)
-
-		lesson, err = s.lessonsService.GetLesson(ctx, code)
-		if err != nil {
-			logger.Error("DataService.GetPublicLesson lessonsService.GetLesson: ", err)
-			return lessons.LessonDTO{}, err
-		}
-
-		newData, err := json.Marshal(lesson)
-		if err != nil {
-			logger.Error("DataService.GetPublicLesson json.Marshal: ", err)
-			return lessons.LessonDTO{}, err
-		}
-
-		err = s.redisClient.Set(ctx, key, newData, &s.dataTTL)
-		if err != nil {
-			logger.Error("DataService.GetPublicLesson redisClient.Set: ", err)
-		}
-
-		return lesson, nil
+		logger.Error("DataService.GetPublicLesson lessonsService.GetPublishedLesson: ", err)
+		return lessons.LessonDTO{}, err
 	}
 
-	err = json.Unmarshal([]byte(data), &lesson)
+	lesson.Exercises, err = s.resolveMedia(ctx, lesson.Exercises)
 	if err != nil {
-		logger.Error("DataService.GetPublicLesson json.Unmarshal: ", err)
+		logger.Error("DataService.GetPublicLesson resolveMedia: ", err)
 		return lessons.LessonDTO{}, err
 	}
 
@@ -263,78 +303,60 @@ func (s *DataService) GetPublicLesson(ctx context.Context, code string) (lessons
 
 func (s *DataService) GetPublicExercise(ctx context.Context, code string) (exercises.Exercise, error) {
 	logger.Info("DataService.GetPublicExercise new request")
-	var exercise exercises.Exercise
-
-	key := generateExerciseKey(code)
-	data, err := s.redisClient.Get(ctx, key)
 
+	exercise, err := s.exercisesCache.Get(ctx, generateExerciseKey(code), func(ctx context.Context) (exercises.Exercise, error) {
+		return s.exerciseService.GetPublishedExercise(ctx, code)
+	})
 	if err != nil {
-		logger.Error("DataService.GetPublicExercise redis.Get: ", err)
-
-		exercise, err = s.exerciseService.GetExercise(ctx, code)
-		if err != nil {
-			logger.Error("DataService.GetPublicExercise lessonsService.GetLesson: ", err)
-			return exercises.Exercise{}, err
-		}
-
-		newData, err := json.Marshal(exercise)
-		if err != nil {
-			logger.Error("DataService.GetPublicExercise json.Marshal: ", err)
-			return exercises.Exercise{}, err
-		}
-
-		err = s.redisClient.Set(ctx, key, newData, &s.dataTTL)
-		if err != nil {
-			logger.Error("DataService.GetPublicExercise redisClient.Set: ", err)
-		}
-
-		return exercise, nil
+		logger.Error("DataService.GetPublicExercise exerciseService.GetPublishedExercise: ", err)
+		return exercises.Exercise{}, err
 	}
 
-	err = json.Unmarshal([]byte(data), &exercise)
+	resolved, err := s.resolveMedia(ctx, []exercises.Exercise{exercise})
 	if err != nil {
-		logger.Error("DataService.GetPublicExercise json.Unmarshal: ", err)
+		logger.Error("DataService.GetPublicExercise resolveMedia: ", err)
 		return exercises.Exercise{}, err
 	}
 
-	return exercise, nil
+	return resolved[0], nil
 }
 
-func (s *DataService) GetPublicAchievements(ctx context.Context) ([]achievements.AchievementDTO, error) {
-	logger.Info("DataService.GetPublicAchievements new request")
-	var achievements []achievements.AchievementDTO
+// resolveMedia returns a copy of the exercises with media urls,
+// urls are resolved after the cache because presigned ones expire
+func (s *DataService) resolveMedia(ctx context.Context, exerciseList []exercises.Exercise) ([]exercises.Exercise, error) {
+	if s.mediaService == nil {
+		return exerciseList, nil
+	}
 
-	key := getAchievementsCacheKey
-	data, err := s.redisClient.Get(ctx, key)
+	var ids []string
+	for _, exercise := range exerciseList {
+		ids = append(ids, exercise.MediaIDs()...)
+	}
+	if len(ids) == 0 {
+		return exerciseList, nil
+	}
 
+	urls, err := s.mediaService.ResolveURLs(ctx, ids)
 	if err != nil {
-		logger.Error("DataService.GetPublicAchievements redis.Get: ", err)
-
-		achievements, err = s.achievementsService.GetAllAchievements(ctx)
-		if err != nil {
-			logger.Error("DataService.GetPublicAchievements achievementsService.GetAllAchievements: ", err)
-			return nil, err
-		}
-
-		newData, err := json.Marshal(achievements)
-		if err != nil {
-			logger.Error("DataService.GetPublicAchievements json.Marshal: ", err)
-			return nil, err
-		}
-
-		err = s.redisClient.Set(ctx, key, newData, &s.dataTTL)
-		if err != nil {
-			logger.Error("DataService.GetPublicAchievements redisClient.Set: ", err)
-		}
+		return nil, err
+	}
 
-		return achievements, nil
+	// the cached value is shared with concurrent requests
+	resolved := slices.Clone(exerciseList)
+	for i := range resolved {
+		resolved[i].ResolveMedia(urls)
 	}
+	return resolved, nil
+}
+
+func (s *DataService) GetPublicAchievements(ctx context.Context) ([]achievements.AchievementDTO, error) {
+	logger.Info("DataService.GetPublicAchievements new request")
 
-	err = json.Unmarshal([]byte(data), &achievements)
+	achievementsList, err := s.achievementsCache.Get(ctx, getAchievementsCacheKey, s.achievementsService.GetAllAchievements)
 	if err != nil {
-		logger.Error("DataService.GetPublicAchievements json.Unmarshal: ", err)
+		logger.Error("DataService.GetPublicAchievements achievementsService.GetAllAchievements: ", err)
 		return nil, err
 	}
 
-	return achievements, nil
+	return achievementsList, nil
 }
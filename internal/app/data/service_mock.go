@@ -10,6 +10,7 @@ import (
 	time "time"
 	achievements "uiren/internal/app/achievements"
 	exercises "uiren/internal/app/exercises"
+	hearts "uiren/internal/app/hearts"
 	lessons "uiren/internal/app/lessons"
 	modules "uiren/internal/app/modules"
 	progress "uiren/internal/app/progress"
@@ -94,19 +95,19 @@ func (m *MockmodulesService) EXPECT() *MockmodulesServiceMockRecorder {
 	return m.recorder
 }
 
-// GetModulesList mocks base method.
-func (m *MockmodulesService) GetModulesList(ctx context.Context) ([]modules.Module, error) {
+// GetPublishedModulesList mocks base method.
+func (m *MockmodulesService) GetPublishedModulesList(ctx context.Context) ([]modules.Module, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetModulesList", ctx)
+	ret := m.ctrl.Call(m, "GetPublishedModulesList", ctx)
 	ret0, _ := ret[0].([]modules.Module)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetModulesList indicates an expected call of GetModulesList.
-func (mr *MockmodulesServiceMockRecorder) GetModulesList(ctx interface{}) *gomock.Call {
+// GetPublishedModulesList indicates an expected call of GetPublishedModulesList.
+func (mr *MockmodulesServiceMockRecorder) GetPublishedModulesList(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModulesList", reflect.TypeOf((*MockmodulesService)(nil).GetModulesList), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedModulesList", reflect.TypeOf((*MockmodulesService)(nil).GetPublishedModulesList), ctx)
 }
 
 // MocklessonsService is a mock of lessonsService interface.
@@ -132,19 +133,34 @@ func (m *MocklessonsService) EXPECT() *MocklessonsServiceMockRecorder {
 	return m.recorder
 }
 
-// GetLesson mocks base method.
-func (m *MocklessonsService) GetLesson(ctx context.Context, code string) (lessons.LessonDTO, error) {
+// GetLessonsReferencingExercise mocks base method.
+func (m *MocklessonsService) GetLessonsReferencingExercise(ctx context.Context, exerciseCode string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLesson", ctx, code)
+	ret := m.ctrl.Call(m, "GetLessonsReferencingExercise", ctx, exerciseCode)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLessonsReferencingExercise indicates an expected call of GetLessonsReferencingExercise.
+func (mr *MocklessonsServiceMockRecorder) GetLessonsReferencingExercise(ctx, exerciseCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLessonsReferencingExercise", reflect.TypeOf((*MocklessonsService)(nil).GetLessonsReferencingExercise), ctx, exerciseCode)
+}
+
+// GetPublishedLesson mocks base method.
+func (m *MocklessonsService) GetPublishedLesson(ctx context.Context, code string) (lessons.LessonDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishedLesson", ctx, code)
 	ret0, _ := ret[0].(lessons.LessonDTO)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLesson indicates an expected call of GetLesson.
-func (mr *MocklessonsServiceMockRecorder) GetLesson(ctx, code interface{}) *gomock.Call {
+// GetPublishedLesson indicates an expected call of GetPublishedLesson.
+func (mr *MocklessonsServiceMockRecorder) GetPublishedLesson(ctx, code interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLesson", reflect.TypeOf((*MocklessonsService)(nil).GetLesson), ctx, code)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedLesson", reflect.TypeOf((*MocklessonsService)(nil).GetPublishedLesson), ctx, code)
 }
 
 // MockexerciseService is a mock of exerciseService interface.
@@ -170,19 +186,57 @@ func (m *MockexerciseService) EXPECT() *MockexerciseServiceMockRecorder {
 	return m.recorder
 }
 
-// GetExercise mocks base method.
-func (m *MockexerciseService) GetExercise(ctx context.Context, code string) (exercises.Exercise, error) {
+// GetPublishedExercise mocks base method.
+func (m *MockexerciseService) GetPublishedExercise(ctx context.Context, code string) (exercises.Exercise, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExercise", ctx, code)
+	ret := m.ctrl.Call(m, "GetPublishedExercise", ctx, code)
 	ret0, _ := ret[0].(exercises.Exercise)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetExercise indicates an expected call of GetExercise.
-func (mr *MockexerciseServiceMockRecorder) GetExercise(ctx, code interface{}) *gomock.Call {
+// GetPublishedExercise indicates an expected call of GetPublishedExercise.
+func (mr *MockexerciseServiceMockRecorder) GetPublishedExercise(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedExercise", reflect.TypeOf((*MockexerciseService)(nil).GetPublishedExercise), ctx, code)
+}
+
+// MockmediaService is a mock of mediaService interface.
+type MockmediaService struct {
+	ctrl     *gomock.Controller
+	recorder *MockmediaServiceMockRecorder
+}
+
+// MockmediaServiceMockRecorder is the mock recorder for MockmediaService.
+type MockmediaServiceMockRecorder struct {
+	mock *MockmediaService
+}
+
+// NewMockmediaService creates a new mock instance.
+func NewMockmediaService(ctrl *gomock.Controller) *MockmediaService {
+	mock := &MockmediaService{ctrl: ctrl}
+	mock.recorder = &MockmediaServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmediaService) EXPECT() *MockmediaServiceMockRecorder {
+	return m.recorder
+}
+
+// ResolveURLs mocks base method.
+func (m *MockmediaService) ResolveURLs(ctx context.Context, ids []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveURLs", ctx, ids)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveURLs indicates an expected call of ResolveURLs.
+func (mr *MockmediaServiceMockRecorder) ResolveURLs(ctx, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExercise", reflect.TypeOf((*MockexerciseService)(nil).GetExercise), ctx, code)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveURLs", reflect.TypeOf((*MockmediaService)(nil).ResolveURLs), ctx, ids)
 }
 
 // MockredisClient is a mock of redisClient interface.
@@ -326,3 +380,79 @@ func (mr *MockachievementsServiceMockRecorder) GetAllAchievements(ctx interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAchievements", reflect.TypeOf((*MockachievementsService)(nil).GetAllAchievements), ctx)
 }
+
+// MockfriendshipService is a mock of friendshipService interface.
+type MockfriendshipService struct {
+	ctrl     *gomock.Controller
+	recorder *MockfriendshipServiceMockRecorder
+}
+
+// MockfriendshipServiceMockRecorder is the mock recorder for MockfriendshipService.
+type MockfriendshipServiceMockRecorder struct {
+	mock *MockfriendshipService
+}
+
+// NewMockfriendshipService creates a new mock instance.
+func NewMockfriendshipService(ctrl *gomock.Controller) *MockfriendshipService {
+	mock := &MockfriendshipService{ctrl: ctrl}
+	mock.recorder = &MockfriendshipServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockfriendshipService) EXPECT() *MockfriendshipServiceMockRecorder {
+	return m.recorder
+}
+
+// GetBlockedUsernames mocks base method.
+func (m *MockfriendshipService) GetBlockedUsernames(ctx context.Context, username string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockedUsernames", ctx, username)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockedUsernames indicates an expected call of GetBlockedUsernames.
+func (mr *MockfriendshipServiceMockRecorder) GetBlockedUsernames(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockedUsernames", reflect.TypeOf((*MockfriendshipService)(nil).GetBlockedUsernames), ctx, username)
+}
+
+// MockheartsService is a mock of heartsService interface.
+type MockheartsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockheartsServiceMockRecorder
+}
+
+// MockheartsServiceMockRecorder is the mock recorder for MockheartsService.
+type MockheartsServiceMockRecorder struct {
+	mock *MockheartsService
+}
+
+// NewMockheartsService creates a new mock instance.
+func NewMockheartsService(ctrl *gomock.Controller) *MockheartsService {
+	mock := &MockheartsService{ctrl: ctrl}
+	mock.recorder = &MockheartsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockheartsService) EXPECT() *MockheartsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetHearts mocks base method.
+func (m *MockheartsService) GetHearts(ctx context.Context, userID string) (hearts.Hearts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHearts", ctx, userID)
+	ret0, _ := ret[0].(hearts.Hearts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHearts indicates an expected call of GetHearts.
+func (mr *MockheartsServiceMockRecorder) GetHearts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHearts", reflect.TypeOf((*MockheartsService)(nil).GetHearts), ctx, userID)
+}
@@ -2,12 +2,12 @@ package data
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"testing"
 	"time"
 	"uiren/internal/app/achievements"
 	"uiren/internal/app/exercises"
+	"uiren/internal/app/hearts"
 	"uiren/internal/app/lessons"
 	"uiren/internal/app/modules"
 	"uiren/internal/app/progress"
@@ -81,6 +81,27 @@ func Test_dataService_GetUser(t *testing.T) {
 		assert.Equal(t, err, errRepo)
 		assert.Equal(t, UserInfo{}, result)
 	})
+	t.Run("(with progress) hearts", func(t *testing.T) {
+		heartsService := NewMockheartsService(ctrl)
+		service := &DataService{userService: userService, heartsService: heartsService}
+		userHearts := hearts.Hearts{Current: 5, Max: 5}
+		userService.EXPECT().GetUserByUsername(ctx, repoReturn.Username).Return(repoReturn, nil)
+		userService.EXPECT().GetUserProgress(ctx, repoReturn.ID).Return(progress, nil)
+		heartsService.EXPECT().GetHearts(ctx, repoReturn.ID).Return(userHearts, nil)
+		result, err := service.GetUserWithProgress(ctx, repoReturn.Username)
+		assert.NoError(t, err)
+		assert.Equal(t, &userHearts, result.Progress.Hearts)
+	})
+	t.Run("(with progress) hearts failed", func(t *testing.T) {
+		heartsService := NewMockheartsService(ctrl)
+		service := &DataService{userService: userService, heartsService: heartsService}
+		userService.EXPECT().GetUserByUsername(ctx, repoReturn.Username).Return(repoReturn, nil)
+		userService.EXPECT().GetUserProgress(ctx, repoReturn.ID).Return(progress, nil)
+		heartsService.EXPECT().GetHearts(ctx, repoReturn.ID).Return(hearts.Hearts{}, errRepo)
+		result, err := service.GetUserWithProgress(ctx, repoReturn.Username)
+		assert.Equal(t, err, errRepo)
+		assert.Equal(t, UserInfo{}, result)
+	})
 	t.Run("(no progress) success", func(t *testing.T) {
 		userService.EXPECT().GetUserByUsername(ctx, repoReturn.Username).Return(repoReturn, nil)
 		result, err := service.GetUserWithoutProgress(ctx, repoReturn.Username)
@@ -110,7 +131,7 @@ func Test_dataService_GetPublicModules(t *testing.T) {
 		ctrl           = gomock.NewController(t)
 		modulesService = NewMockmodulesService(ctrl)
 		redisCli       = NewMockredisClient(ctrl)
-		service        = &DataService{modulesService: modulesService, redisClient: redisCli, dataTTL: time.Microsecond}
+		service        = &DataService{modulesService: modulesService, modulesCache: newReadThroughCache[[]modules.Module](CacheModules, redisCli, testCachePolicy, nil)}
 		errRepo        = errors.New("ere")
 		returnRepo     = []modules.Module{
 			{
@@ -128,7 +149,7 @@ func Test_dataService_GetPublicModules(t *testing.T) {
 					Badge: "starter",
 				},
 				Lessons:   []string{"lesson-001", "lesson-002"},
-				CreatedAt: time.Unix(999999, 999),
+				CreatedAt: time.Unix(999999, 999).UTC(),
 				DeletedAt: nil,
 			},
 			{
@@ -146,7 +167,7 @@ func Test_dataService_GetPublicModules(t *testing.T) {
 					Badge: "alphabet_master",
 				},
 				Lessons:   []string{"lesson-003", "lesson-004"},
-				CreatedAt: time.Unix(999999, 999),
+				CreatedAt: time.Unix(999999, 999).UTC(),
 				DeletedAt: nil,
 			},
 			{
@@ -164,15 +185,14 @@ func Test_dataService_GetPublicModules(t *testing.T) {
 					Badge: "conversationalist",
 				},
 				Lessons:   []string{"lesson-005", "lesson-006"},
-				CreatedAt: time.Unix(999999, 999),
+				CreatedAt: time.Unix(999999, 999).UTC(),
 				DeletedAt: nil,
 			},
 		}
 	)
 
 	t.Run("success(redis)", func(t *testing.T) {
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Get(ctx, getModulesCacheKey).Return(string(data), nil)
+		redisCli.EXPECT().Get(ctx, getModulesCacheKey).Return(freshEntry(returnRepo), nil)
 
 		result, err := service.GetPublicModules(ctx)
 		assert.NoError(t, err)
@@ -183,9 +203,8 @@ func Test_dataService_GetPublicModules(t *testing.T) {
 	})
 	t.Run("success(db) redis-set no error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, getModulesCacheKey).Return("", redis.Nil)
-		modulesService.EXPECT().GetModulesList(ctx).Return(returnRepo, nil)
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Set(ctx, getModulesCacheKey, data, &service.dataTTL).Return(nil)
+		modulesService.EXPECT().GetPublishedModulesList(ctx).Return(returnRepo, nil)
+		redisCli.EXPECT().Set(ctx, getModulesCacheKey, gomock.Any(), gomock.Any()).Return(nil)
 
 		result, err := service.GetPublicModules(ctx)
 		assert.NoError(t, err)
@@ -197,9 +216,8 @@ func Test_dataService_GetPublicModules(t *testing.T) {
 
 	t.Run("success(db) redis-set error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, getModulesCacheKey).Return("", redis.Nil)
-		modulesService.EXPECT().GetModulesList(ctx).Return(returnRepo, nil)
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Set(ctx, getModulesCacheKey, data, &service.dataTTL).Return(redis.Nil)
+		modulesService.EXPECT().GetPublishedModulesList(ctx).Return(returnRepo, nil)
+		redisCli.EXPECT().Set(ctx, getModulesCacheKey, gomock.Any(), gomock.Any()).Return(redis.Nil)
 
 		result, err := service.GetPublicModules(ctx)
 		assert.NoError(t, err)
@@ -211,7 +229,7 @@ func Test_dataService_GetPublicModules(t *testing.T) {
 
 	t.Run("modulesService error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, getModulesCacheKey).Return("", redis.Nil)
-		modulesService.EXPECT().GetModulesList(ctx).Return(nil, errRepo)
+		modulesService.EXPECT().GetPublishedModulesList(ctx).Return(nil, errRepo)
 
 		result, err := service.GetPublicModules(ctx)
 		assert.Equal(t, err, errRepo)
@@ -226,8 +244,8 @@ func Test_dataService_GetPublicLesson(t *testing.T) {
 		ctrl          = gomock.NewController(t)
 		lessonService = NewMocklessonsService(ctrl)
 		redisCli      = NewMockredisClient(ctrl)
-		mockTime      = time.Unix(123165, 156)
-		service       = &DataService{lessonsService: lessonService, redisClient: redisCli, dataTTL: time.Microsecond}
+		mockTime      = time.Unix(123165, 156).UTC()
+		service       = &DataService{lessonsService: lessonService, lessonsCache: newReadThroughCache[lessons.LessonDTO](CacheLessons, redisCli, testCachePolicy, lessons.ErrNotFound)}
 		errRepo       = errors.New("ere")
 		returnRepo    = lessons.LessonDTO{
 			Code:        "lesson-001",
@@ -276,8 +294,7 @@ func Test_dataService_GetPublicLesson(t *testing.T) {
 	)
 
 	t.Run("success(redis)", func(t *testing.T) {
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Get(ctx, generateLessonKey(returnRepo.Code)).Return(string(data), nil)
+		redisCli.EXPECT().Get(ctx, generateLessonKey(returnRepo.Code)).Return(freshEntry(returnRepo), nil)
 
 		result, err := service.GetPublicLesson(ctx, returnRepo.Code)
 		assert.NoError(t, err)
@@ -285,9 +302,8 @@ func Test_dataService_GetPublicLesson(t *testing.T) {
 	})
 	t.Run("success(db) redis-set no error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateLessonKey(returnRepo.Code)).Return("", redis.Nil)
-		lessonService.EXPECT().GetLesson(ctx, returnRepo.Code).Return(returnRepo, nil)
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Set(ctx, generateLessonKey(returnRepo.Code), data, &service.dataTTL).Return(nil)
+		lessonService.EXPECT().GetPublishedLesson(ctx, returnRepo.Code).Return(returnRepo, nil)
+		redisCli.EXPECT().Set(ctx, generateLessonKey(returnRepo.Code), gomock.Any(), gomock.Any()).Return(nil)
 
 		result, err := service.GetPublicLesson(ctx, returnRepo.Code)
 		assert.NoError(t, err)
@@ -296,9 +312,8 @@ func Test_dataService_GetPublicLesson(t *testing.T) {
 
 	t.Run("success(db) redis-set error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateLessonKey(returnRepo.Code)).Return("", redis.Nil)
-		lessonService.EXPECT().GetLesson(ctx, returnRepo.Code).Return(returnRepo, nil)
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Set(ctx, generateLessonKey(returnRepo.Code), data, &service.dataTTL).Return(redis.Nil)
+		lessonService.EXPECT().GetPublishedLesson(ctx, returnRepo.Code).Return(returnRepo, nil)
+		redisCli.EXPECT().Set(ctx, generateLessonKey(returnRepo.Code), gomock.Any(), gomock.Any()).Return(redis.Nil)
 
 		result, err := service.GetPublicLesson(ctx, returnRepo.Code)
 		assert.NoError(t, err)
@@ -307,7 +322,7 @@ func Test_dataService_GetPublicLesson(t *testing.T) {
 
 	t.Run("lessonService error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateLessonKey(returnRepo.Code)).Return("", redis.Nil)
-		lessonService.EXPECT().GetLesson(ctx, returnRepo.Code).Return(lessons.LessonDTO{}, errRepo)
+		lessonService.EXPECT().GetPublishedLesson(ctx, returnRepo.Code).Return(lessons.LessonDTO{}, errRepo)
 
 		result, err := service.GetPublicLesson(ctx, returnRepo.Code)
 		assert.Equal(t, err, errRepo)
@@ -322,8 +337,8 @@ func Test_dataService_GetPublicExercise(t *testing.T) {
 		ctrl            = gomock.NewController(t)
 		exerciseService = NewMockexerciseService(ctrl)
 		redisCli        = NewMockredisClient(ctrl)
-		mockTime        = time.Unix(123165, 156)
-		service         = &DataService{exerciseService: exerciseService, redisClient: redisCli, dataTTL: time.Microsecond}
+		mockTime        = time.Unix(123165, 156).UTC()
+		service         = &DataService{exerciseService: exerciseService, exercisesCache: newReadThroughCache[exercises.Exercise](CacheExercises, redisCli, testCachePolicy, exercises.ErrNotFound)}
 		errRepo         = errors.New("ere")
 		returnRepo      = exercises.Exercise{
 			Code:          "ex-001",
@@ -339,8 +354,7 @@ func Test_dataService_GetPublicExercise(t *testing.T) {
 	)
 
 	t.Run("success(redis)", func(t *testing.T) {
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Get(ctx, generateExerciseKey(returnRepo.Code)).Return(string(data), nil)
+		redisCli.EXPECT().Get(ctx, generateExerciseKey(returnRepo.Code)).Return(freshEntry(returnRepo), nil)
 
 		result, err := service.GetPublicExercise(ctx, returnRepo.Code)
 		assert.NoError(t, err)
@@ -348,9 +362,8 @@ func Test_dataService_GetPublicExercise(t *testing.T) {
 	})
 	t.Run("success(db) redis-set no error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateExerciseKey(returnRepo.Code)).Return("", redis.Nil)
-		exerciseService.EXPECT().GetExercise(ctx, returnRepo.Code).Return(returnRepo, nil)
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Set(ctx, generateExerciseKey(returnRepo.Code), data, &service.dataTTL).Return(nil)
+		exerciseService.EXPECT().GetPublishedExercise(ctx, returnRepo.Code).Return(returnRepo, nil)
+		redisCli.EXPECT().Set(ctx, generateExerciseKey(returnRepo.Code), gomock.Any(), gomock.Any()).Return(nil)
 
 		result, err := service.GetPublicExercise(ctx, returnRepo.Code)
 		assert.NoError(t, err)
@@ -359,9 +372,8 @@ func Test_dataService_GetPublicExercise(t *testing.T) {
 
 	t.Run("success(db) redis-set error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateExerciseKey(returnRepo.Code)).Return("", redis.Nil)
-		exerciseService.EXPECT().GetExercise(ctx, returnRepo.Code).Return(returnRepo, nil)
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Set(ctx, generateExerciseKey(returnRepo.Code), data, &service.dataTTL).Return(redis.Nil)
+		exerciseService.EXPECT().GetPublishedExercise(ctx, returnRepo.Code).Return(returnRepo, nil)
+		redisCli.EXPECT().Set(ctx, generateExerciseKey(returnRepo.Code), gomock.Any(), gomock.Any()).Return(redis.Nil)
 
 		result, err := service.GetPublicExercise(ctx, returnRepo.Code)
 		assert.NoError(t, err)
@@ -370,7 +382,7 @@ func Test_dataService_GetPublicExercise(t *testing.T) {
 
 	t.Run("exerciseService error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateExerciseKey(returnRepo.Code)).Return("", redis.Nil)
-		exerciseService.EXPECT().GetExercise(ctx, returnRepo.Code).Return(exercises.Exercise{}, errRepo)
+		exerciseService.EXPECT().GetPublishedExercise(ctx, returnRepo.Code).Return(exercises.Exercise{}, errRepo)
 
 		result, err := service.GetPublicExercise(ctx, returnRepo.Code)
 		assert.Equal(t, err, errRepo)
@@ -385,7 +397,7 @@ func Test_dataService_GetXPLeaderboard(t *testing.T) {
 		ctrl            = gomock.NewController(t)
 		progressService = NewMockprogressService(ctrl)
 		redisCli        = NewMockredisClient(ctrl)
-		service         = &DataService{progressService: progressService, xpLeaderboardLimit: 200, redisClient: redisCli, dataTTL: time.Microsecond}
+		service         = &DataService{progressService: progressService, xpLeaderboardLimit: 200, leaderboardCache: newReadThroughCache[progress.XPLeaderboard](CacheLeaderboard, redisCli, testCachePolicy, nil)}
 		errRepo         = errors.New("ere")
 		returnRepo      = progress.XPLeaderboard{
 			Leaders: []progress.XPLeaderboardEntry{
@@ -413,10 +425,9 @@ func Test_dataService_GetXPLeaderboard(t *testing.T) {
 	)
 
 	t.Run("success(redis)", func(t *testing.T) {
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Get(ctx, generateXpLeaderboardKey(200)).Return(string(data), nil)
+		redisCli.EXPECT().Get(ctx, generateXpLeaderboardKey(200)).Return(freshEntry(returnRepo), nil)
 
-		result, err := service.GetXPLeaderboard(ctx)
+		result, err := service.GetXPLeaderboard(ctx, "")
 		assert.NoError(t, err)
 		assert.Equal(t, result, XPLeaderboard{
 			Board: returnRepo,
@@ -425,10 +436,9 @@ func Test_dataService_GetXPLeaderboard(t *testing.T) {
 	t.Run("success(db) redis-set no error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateXpLeaderboardKey(200)).Return("", redis.Nil)
 		progressService.EXPECT().GetXPLeaderboard(ctx, 200).Return(returnRepo, nil)
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Set(ctx, generateXpLeaderboardKey(200), data, &service.dataTTL).Return(nil)
+		redisCli.EXPECT().Set(ctx, generateXpLeaderboardKey(200), gomock.Any(), gomock.Any()).Return(nil)
 
-		result, err := service.GetXPLeaderboard(ctx)
+		result, err := service.GetXPLeaderboard(ctx, "")
 		assert.NoError(t, err)
 		assert.Equal(t, result, XPLeaderboard{Board: returnRepo})
 	})
@@ -436,10 +446,9 @@ func Test_dataService_GetXPLeaderboard(t *testing.T) {
 	t.Run("success(db) redis-set error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateXpLeaderboardKey(200)).Return("", redis.Nil)
 		progressService.EXPECT().GetXPLeaderboard(ctx, 200).Return(returnRepo, nil)
-		data, _ := json.Marshal(returnRepo)
-		redisCli.EXPECT().Set(ctx, generateXpLeaderboardKey(200), data, &service.dataTTL).Return(redis.Nil)
+		redisCli.EXPECT().Set(ctx, generateXpLeaderboardKey(200), gomock.Any(), gomock.Any()).Return(redis.Nil)
 
-		result, err := service.GetXPLeaderboard(ctx)
+		result, err := service.GetXPLeaderboard(ctx, "")
 		assert.NoError(t, err)
 		assert.Equal(t, result, XPLeaderboard{Board: returnRepo})
 	})
@@ -448,7 +457,55 @@ func Test_dataService_GetXPLeaderboard(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, generateXpLeaderboardKey(200)).Return("", redis.Nil)
 		progressService.EXPECT().GetXPLeaderboard(ctx, 200).Return(progress.XPLeaderboard{}, errRepo)
 
-		result, err := service.GetXPLeaderboard(ctx)
+		result, err := service.GetXPLeaderboard(ctx, "")
+		assert.Equal(t, err, errRepo)
+		assert.Equal(t, result, XPLeaderboard{})
+	})
+}
+
+func Test_dataService_GetXPLeaderboard_blockedUsers(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx               = context.TODO()
+		ctrl              = gomock.NewController(t)
+		friendshipService = NewMockfriendshipService(ctrl)
+		redisCli          = NewMockredisClient(ctrl)
+		service           = &DataService{friendshipService: friendshipService, xpLeaderboardLimit: 200, leaderboardCache: newReadThroughCache[progress.XPLeaderboard](CacheLeaderboard, redisCli, testCachePolicy, nil)}
+		errRepo           = errors.New("ere")
+		board             = progress.XPLeaderboard{
+			Leaders: []progress.XPLeaderboardEntry{
+				{Rank: 1, UserID: "user-001", Username: "admin_hero", XP: 1500},
+				{Rank: 2, UserID: "user-002", Username: "kaz_learn", XP: 1200},
+				{Rank: 3, UserID: "user-003", Username: "viewer", XP: 900},
+			},
+			Total: 3,
+		}
+	)
+
+	t.Run("blocked users hidden", func(t *testing.T) {
+		redisCli.EXPECT().Get(ctx, generateXpLeaderboardKey(200)).Return(freshEntry(board), nil)
+		friendshipService.EXPECT().GetBlockedUsernames(ctx, "viewer").Return([]string{"kaz_learn"}, nil)
+
+		result, err := service.GetXPLeaderboard(ctx, "viewer")
+		assert.NoError(t, err)
+		assert.Equal(t, result.Board.Leaders, []progress.XPLeaderboardEntry{board.Leaders[0], board.Leaders[2]})
+		assert.Equal(t, result.Board.Total, 3)
+	})
+
+	t.Run("no blocked users", func(t *testing.T) {
+		redisCli.EXPECT().Get(ctx, generateXpLeaderboardKey(200)).Return(freshEntry(board), nil)
+		friendshipService.EXPECT().GetBlockedUsernames(ctx, "viewer").Return(nil, nil)
+
+		result, err := service.GetXPLeaderboard(ctx, "viewer")
+		assert.NoError(t, err)
+		assert.Equal(t, result, XPLeaderboard{Board: board})
+	})
+
+	t.Run("friendshipService error", func(t *testing.T) {
+		redisCli.EXPECT().Get(ctx, generateXpLeaderboardKey(200)).Return(freshEntry(board), nil)
+		friendshipService.EXPECT().GetBlockedUsernames(ctx, "viewer").Return(nil, errRepo)
+
+		result, err := service.GetXPLeaderboard(ctx, "viewer")
 		assert.Equal(t, err, errRepo)
 		assert.Equal(t, result, XPLeaderboard{})
 	})
@@ -461,7 +518,7 @@ func Test_dataService_GetAchievements(t *testing.T) {
 		ctrl                  = gomock.NewController(t)
 		achService            = NewMockachievementsService(ctrl)
 		redisCli              = NewMockredisClient(ctrl)
-		service               = &DataService{achievementsService: achService, xpLeaderboardLimit: 200, redisClient: redisCli, dataTTL: time.Microsecond}
+		service               = &DataService{achievementsService: achService, achievementsCache: newReadThroughCache[[]achievements.AchievementDTO](CacheAchievements, redisCli, testCachePolicy, nil)}
 		errRepo               = errors.New("ere")
 		mockAchievementLevels = []achievements.AchievementLevel{
 			{
@@ -470,8 +527,8 @@ func Test_dataService_GetAchievements(t *testing.T) {
 				Level:       1,
 				Description: "Log in for the first time",
 				Threshold:   1,
-				CreatedAt:   time.Unix(444, 11),
-				UpdatedAt:   time.Unix(444, 11),
+				CreatedAt:   time.Unix(444, 11).UTC(),
+				UpdatedAt:   time.Unix(444, 11).UTC(),
 			},
 			{
 				AchID:       1,
@@ -479,8 +536,8 @@ func Test_dataService_GetAchievements(t *testing.T) {
 				Level:       2,
 				Description: "Log in 5 times",
 				Threshold:   5,
-				CreatedAt:   time.Unix(444, 11),
-				UpdatedAt:   time.Unix(444, 11),
+				CreatedAt:   time.Unix(444, 11).UTC(),
+				UpdatedAt:   time.Unix(444, 11).UTC(),
 			},
 			{
 				AchID:       2,
@@ -488,8 +545,8 @@ func Test_dataService_GetAchievements(t *testing.T) {
 				Level:       1,
 				Description: "Complete your first lesson",
 				Threshold:   1,
-				CreatedAt:   time.Unix(444, 11),
-				UpdatedAt:   time.Unix(444, 11),
+				CreatedAt:   time.Unix(444, 11).UTC(),
+				UpdatedAt:   time.Unix(444, 11).UTC(),
 			},
 		}
 
@@ -498,24 +555,23 @@ func Test_dataService_GetAchievements(t *testing.T) {
 				ID:        1,
 				Name:      "First Login",
 				Levels:    mockAchievementLevels[:2],
-				CreatedAt: time.Unix(444, 11),
-				UpdatedAt: time.Unix(444, 11),
+				CreatedAt: time.Unix(444, 11).UTC(),
+				UpdatedAt: time.Unix(444, 11).UTC(),
 				DeletedAt: nil,
 			},
 			{
 				ID:        2,
 				Name:      "First Lesson",
 				Levels:    mockAchievementLevels[2:3],
-				CreatedAt: time.Unix(444, 11),
-				UpdatedAt: time.Unix(444, 11),
+				CreatedAt: time.Unix(444, 11).UTC(),
+				UpdatedAt: time.Unix(444, 11).UTC(),
 				DeletedAt: nil,
 			},
 		}
 	)
 
 	t.Run("success(redis)", func(t *testing.T) {
-		data, _ := json.Marshal(MockAchievements)
-		redisCli.EXPECT().Get(ctx, getAchievementsCacheKey).Return(string(data), nil)
+		redisCli.EXPECT().Get(ctx, getAchievementsCacheKey).Return(freshEntry(MockAchievements), nil)
 
 		result, err := service.GetPublicAchievements(ctx)
 		assert.NoError(t, err)
@@ -524,8 +580,7 @@ func Test_dataService_GetAchievements(t *testing.T) {
 	t.Run("success(db) redis-set no error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, getAchievementsCacheKey).Return("", redis.Nil)
 		achService.EXPECT().GetAllAchievements(ctx).Return(MockAchievements, nil)
-		data, _ := json.Marshal(MockAchievements)
-		redisCli.EXPECT().Set(ctx, getAchievementsCacheKey, data, &service.dataTTL).Return(nil)
+		redisCli.EXPECT().Set(ctx, getAchievementsCacheKey, gomock.Any(), gomock.Any()).Return(nil)
 
 		result, err := service.GetPublicAchievements(ctx)
 		assert.NoError(t, err)
@@ -535,8 +590,7 @@ func Test_dataService_GetAchievements(t *testing.T) {
 	t.Run("success(db) redis-set error", func(t *testing.T) {
 		redisCli.EXPECT().Get(ctx, getAchievementsCacheKey).Return("", redis.Nil)
 		achService.EXPECT().GetAllAchievements(ctx).Return(MockAchievements, nil)
-		data, _ := json.Marshal(MockAchievements)
-		redisCli.EXPECT().Set(ctx, getAchievementsCacheKey, data, &service.dataTTL).Return(redis.Nil)
+		redisCli.EXPECT().Set(ctx, getAchievementsCacheKey, gomock.Any(), gomock.Any()).Return(redis.Nil)
 
 		result, err := service.GetPublicAchievements(ctx)
 		assert.NoError(t, err)
@@ -551,3 +605,48 @@ func Test_dataService_GetAchievements(t *testing.T) {
 		assert.Nil(t, result)
 	})
 }
+
+func Test_dataService_GetPublicLesson_media(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx           = context.TODO()
+		ctrl          = gomock.NewController(t)
+		lessonService = NewMocklessonsService(ctrl)
+		mediaService  = NewMockmediaService(ctrl)
+		redisCli      = NewMockredisClient(ctrl)
+		service       = &DataService{lessonsService: lessonService, lessonsCache: newReadThroughCache[lessons.LessonDTO](CacheLessons, redisCli, testCachePolicy, lessons.ErrNotFound)}
+		errMedia      = errors.New("storage is down")
+		cached        = lessons.LessonDTO{
+			Code: "lesson-001",
+			Exercises: []exercises.Exercise{
+				{Code: "ex-001", AudioID: "audio-id", OptionMedia: []string{"image-id", ""}},
+				{Code: "ex-002"},
+			},
+		}
+	)
+	service.WithMediaService(mediaService)
+
+	t.Run("urls are resolved after the cache", func(t *testing.T) {
+		redisCli.EXPECT().Get(ctx, generateLessonKey(cached.Code)).Return(freshEntry(cached), nil)
+		mediaService.EXPECT().ResolveURLs(ctx, []string{"audio-id", "image-id"}).Return(map[string]string{
+			"audio-id": "https://cdn.uiren.kz/media/a.mp3",
+			"image-id": "https://cdn.uiren.kz/media/b.png",
+		}, nil)
+
+		result, err := service.GetPublicLesson(ctx, cached.Code)
+		assert.NoError(t, err)
+		assert.Equal(t, &exercises.ExerciseMedia{
+			AudioURL:   "https://cdn.uiren.kz/media/a.mp3",
+			OptionURLs: []string{"https://cdn.uiren.kz/media/b.png", ""},
+		}, result.Exercises[0].Media)
+		assert.Nil(t, result.Exercises[1].Media)
+	})
+
+	t.Run("media error", func(t *testing.T) {
+		redisCli.EXPECT().Get(ctx, generateLessonKey(cached.Code)).Return(freshEntry(cached), nil)
+		mediaService.EXPECT().ResolveURLs(ctx, gomock.Any()).Return(nil, errMedia)
+
+		_, err := service.GetPublicLesson(ctx, cached.Code)
+		assert.Equal(t, errMedia, err)
+	})
+}
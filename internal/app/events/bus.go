@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	ModuleChanged      = "module_changed"
+	LessonChanged      = "lesson_changed"
+	ExerciseChanged    = "exercise_changed"
+	AchievementChanged = "achievement_changed"
+)
+
+// progress events, Code is the lesson, module, badge or achievement the user made progress in
+const (
+	LessonCompleted    = "lesson_completed"
+	ModuleFinished     = "module_finished"
+	BadgeEarned        = "badge_earned"
+	AchievementLevelUp = "achievement_level_up"
+	StreakMilestone    = "streak_milestone"
+	XPGained           = "xp_gained"
+)
+
+// friendship events, Username is the user the event is addressed to, Code is the other user
+const (
+	FriendRequestReceived = "friend_request_received"
+	FriendRequestAccepted = "friend_request_accepted"
+)
+
+// Event tells subscribers that an entity was created, changed or deleted
+// or that the user made progress
+type Event struct {
+	Type string
+	Code string
+
+	// UserID and Value are set only for progress events,
+	// Value is the reached achievement level, the streak length in days or the gained XP
+	UserID   string
+	Value    int
+	Username string
+}
+
+type Handler func(ctx context.Context, event Event)
+
+// Bus delivers events to subscribers synchronously,
+// so everything derived from the change is handled before the request returns
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Bus_Publish(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx      = context.TODO()
+		bus      = NewBus()
+		received []string
+	)
+
+	bus.Publish(ctx, Event{Type: ModuleChanged, Code: "module1"})
+
+	bus.Subscribe(func(ctx context.Context, event Event) {
+		received = append(received, "first:"+event.Code)
+	})
+	bus.Subscribe(func(ctx context.Context, event Event) {
+		received = append(received, "second:"+event.Code)
+	})
+
+	bus.Publish(ctx, Event{Type: LessonChanged, Code: "lesson1"})
+
+	assert.Equal(t, []string{"first:lesson1", "second:lesson1"}, received)
+}
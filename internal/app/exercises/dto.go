@@ -4,13 +4,6 @@ import (
 	"time"
 )
 
-const (
-	multipleChoiceType = "multiple_choice"
-	manualTypingType   = "manual_typing"
-	matchPairsType     = "match_pairs"
-	orderWordsType     = "order_words"
-)
-
 type Pair struct {
 	Term  string `bson:"term" json:"term"`   // match_pairs
 	Match string `bson:"match" json:"match"` // match_pairs
@@ -23,6 +16,7 @@ type Exercise struct {
 	Question     string     `bson:"question" json:"question"`
 	Hints        []string   `bson:"hints" json:"hints"`
 	Explanation  string     `bson:"explanation" json:"explanation"`
+	Status       string     `bson:"status" json:"status"`
 	CreatedAt    time.Time  `bson:"created_at" json:"created_at"`
 	DeletedAt    *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
 
@@ -37,58 +31,296 @@ type Exercise struct {
 
 	// match_pairs
 	Pairs []Pair `bson:"pairs,omitempty" json:"pairs,omitempty"`
+
+	// fill_in_blank, accepted answers of every blank in the order of blanks in the question
+	Blanks [][]string `bson:"blanks,omitempty" json:"blanks,omitempty"`
+
+	// translate_sentence, listen_and_type, other accepted answers of manual_typing
+	CorrectAnswers []string `bson:"correct_answers,omitempty" json:"correct_answers,omitempty"`
+
+	// true_false
+	IsTrue *bool `bson:"is_true,omitempty" json:"is_true,omitempty"`
+
+	// media asset ids, OptionMedia has one entry per option, empty for options without media
+	ImageID     string   `bson:"image_id,omitempty" json:"image_id,omitempty"`
+	AudioID     string   `bson:"audio_id,omitempty" json:"audio_id,omitempty"`
+	OptionMedia []string `bson:"option_media,omitempty" json:"option_media,omitempty"`
+
+	// Media is resolved from the asset ids on every public read and never stored
+	Media *ExerciseMedia `bson:"-" json:"media,omitempty"`
+}
+
+type ExerciseMedia struct {
+	ImageURL   string   `json:"image_url,omitempty"`
+	AudioURL   string   `json:"audio_url,omitempty"`
+	OptionURLs []string `json:"option_urls,omitempty"`
+}
+
+// MediaIDs lists every asset the exercise is attached to
+func (e Exercise) MediaIDs() []string {
+	var ids []string
+	for _, id := range append([]string{e.ImageID, e.AudioID}, e.OptionMedia...) {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// ResolveMedia fills Media from asset urls keyed by asset id
+func (e *Exercise) ResolveMedia(urls map[string]string) {
+	e.Media = nil
+	if len(e.MediaIDs()) == 0 {
+		return
+	}
+
+	media := &ExerciseMedia{
+		ImageURL: urls[e.ImageID],
+		AudioURL: urls[e.AudioID],
+	}
+	if len(e.OptionMedia) > 0 {
+		media.OptionURLs = make([]string, len(e.OptionMedia))
+		for i, id := range e.OptionMedia {
+			media.OptionURLs[i] = urls[id]
+		}
+	}
+	e.Media = media
 }
 
 // repo dto
 type CreateExerciseDTO struct {
-	Code          string     `bson:"code" json:"code"`
-	ExerciseType  string     `bson:"type" json:"type"`
-	Question      string     `bson:"question" json:"question"`
-	Hints         []string   `bson:"hints" json:"hints"`
-	Explanation   string     `bson:"explanation" json:"explanation"`
-	CreatedAt     time.Time  `bson:"created_at" json:"created_at"`
-	DeletedAt     *time.Time `bson:"deleted_at" json:"deleted_at"`
-	Options       []string   `bson:"options,omitempty" json:"options,omitempty"`
-	CorrectAnswer *string    `bson:"correct_answer,omitempty" json:"correct_answer,omitempty"`
-	CorrectOrder  []string   `bson:"correct_order,omitempty" json:"correct_order,omitempty"`
-	Pairs         []Pair     `bson:"pairs,omitempty" json:"pairs,omitempty"`
+	Code           string     `bson:"code" json:"code"`
+	ExerciseType   string     `bson:"type" json:"type"`
+	Question       string     `bson:"question" json:"question"`
+	Hints          []string   `bson:"hints" json:"hints"`
+	Explanation    string     `bson:"explanation" json:"explanation"`
+	Status         string     `bson:"status" json:"-"`
+	CreatedAt      time.Time  `bson:"created_at" json:"created_at"`
+	DeletedAt      *time.Time `bson:"deleted_at" json:"deleted_at"`
+	Options        []string   `bson:"options,omitempty" json:"options,omitempty"`
+	CorrectAnswer  *string    `bson:"correct_answer,omitempty" json:"correct_answer,omitempty"`
+	CorrectOrder   []string   `bson:"correct_order,omitempty" json:"correct_order,omitempty"`
+	Pairs          []Pair     `bson:"pairs,omitempty" json:"pairs,omitempty"`
+	Blanks         [][]string `bson:"blanks,omitempty" json:"blanks,omitempty"`
+	CorrectAnswers []string   `bson:"correct_answers,omitempty" json:"correct_answers,omitempty"`
+	IsTrue         *bool      `bson:"is_true,omitempty" json:"is_true,omitempty"`
+	ImageID        string     `bson:"image_id,omitempty" json:"image_id,omitempty"`
+	AudioID        string     `bson:"audio_id,omitempty" json:"audio_id,omitempty"`
+	OptionMedia    []string   `bson:"option_media,omitempty" json:"option_media,omitempty"`
 }
 
 type UpdateExerciseDTO struct {
-	Question      *string  `bson:"question,omitempty" json:"question,omitempty"`
-	Hints         []string `bson:"hints,omitempty" json:"hints,omitempty"`
-	Explanation   *string  `bson:"explanation,omitempty" json:"explanation,omitempty"`
-	Options       []string `bson:"options,omitempty" json:"options,omitempty"`
-	CorrectAnswer *string  `bson:"correct_answer,omitempty" json:"correct_answer,omitempty"`
-	CorrectOrder  []string `bson:"correct_order,omitempty" json:"correct_order,omitempty"`
-	Pairs         []Pair   `bson:"pairs,omitempty" json:"pairs,omitempty"`
-}
-
-type ExerciseDTO interface {
-	GetOptions() []string
-	GetCorrectAnswer() *string
-	GetCorrectOrder() []string
-	GetPairs() []Pair
-	SetOptions([]string)
-	SetCorrectAnswer(*string)
-	SetCorrectOrder([]string)
-	SetPairs([]Pair)
-}
-
-func (dto *CreateExerciseDTO) GetOptions() []string           { return dto.Options }
-func (dto *CreateExerciseDTO) GetCorrectAnswer() *string      { return dto.CorrectAnswer }
-func (dto *CreateExerciseDTO) GetCorrectOrder() []string      { return dto.CorrectOrder }
-func (dto *CreateExerciseDTO) GetPairs() []Pair               { return dto.Pairs }
-func (dto *CreateExerciseDTO) SetOptions(opts []string)       { dto.Options = opts }
-func (dto *CreateExerciseDTO) SetCorrectAnswer(ans *string)   { dto.CorrectAnswer = ans }
-func (dto *CreateExerciseDTO) SetCorrectOrder(order []string) { dto.CorrectOrder = order }
-func (dto *CreateExerciseDTO) SetPairs(pairs []Pair)          { dto.Pairs = pairs }
-
-func (dto *UpdateExerciseDTO) GetOptions() []string           { return dto.Options }
-func (dto *UpdateExerciseDTO) GetCorrectAnswer() *string      { return dto.CorrectAnswer }
-func (dto *UpdateExerciseDTO) GetCorrectOrder() []string      { return dto.CorrectOrder }
-func (dto *UpdateExerciseDTO) GetPairs() []Pair               { return dto.Pairs }
-func (dto *UpdateExerciseDTO) SetOptions(opts []string)       { dto.Options = opts }
-func (dto *UpdateExerciseDTO) SetCorrectAnswer(ans *string)   { dto.CorrectAnswer = ans }
-func (dto *UpdateExerciseDTO) SetCorrectOrder(order []string) { dto.CorrectOrder = order }
-func (dto *UpdateExerciseDTO) SetPairs(pairs []Pair)          { dto.Pairs = pairs }
+	Question       *string    `bson:"question,omitempty" json:"question,omitempty"`
+	Hints          []string   `bson:"hints,omitempty" json:"hints,omitempty"`
+	Explanation    *string    `bson:"explanation,omitempty" json:"explanation,omitempty"`
+	Options        []string   `bson:"options,omitempty" json:"options,omitempty"`
+	CorrectAnswer  *string    `bson:"correct_answer,omitempty" json:"correct_answer,omitempty"`
+	CorrectOrder   []string   `bson:"correct_order,omitempty" json:"correct_order,omitempty"`
+	Pairs          []Pair     `bson:"pairs,omitempty" json:"pairs,omitempty"`
+	Blanks         [][]string `bson:"blanks,omitempty" json:"blanks,omitempty"`
+	CorrectAnswers []string   `bson:"correct_answers,omitempty" json:"correct_answers,omitempty"`
+	IsTrue         *bool      `bson:"is_true,omitempty" json:"is_true,omitempty"`
+
+	// an empty ImageID or AudioID detaches the asset,
+	// options replaced without OptionMedia lose their media
+	ImageID     *string  `bson:"image_id,omitempty" json:"image_id,omitempty"`
+	AudioID     *string  `bson:"audio_id,omitempty" json:"audio_id,omitempty"`
+	OptionMedia []string `bson:"option_media,omitempty" json:"option_media,omitempty"`
+}
+
+// Fields are the type specific fields of a create or update request,
+// Question and AudioID are only read by type handlers that depend on them
+type Fields struct {
+	Question       *string
+	AudioID        *string
+	Options        []string
+	CorrectAnswer  *string
+	CorrectOrder   []string
+	Pairs          []Pair
+	Blanks         [][]string
+	CorrectAnswers []string
+	IsTrue         *bool
+}
+
+func (dto CreateExerciseDTO) fields() Fields {
+	fields := Fields{
+		Question:       &dto.Question,
+		Options:        dto.Options,
+		CorrectAnswer:  dto.CorrectAnswer,
+		CorrectOrder:   dto.CorrectOrder,
+		Pairs:          dto.Pairs,
+		Blanks:         dto.Blanks,
+		CorrectAnswers: dto.CorrectAnswers,
+		IsTrue:         dto.IsTrue,
+	}
+	if dto.AudioID != "" {
+		fields.AudioID = &dto.AudioID
+	}
+	return fields
+}
+
+func (dto *CreateExerciseDTO) setFields(fields Fields) {
+	dto.Options = fields.Options
+	dto.CorrectAnswer = fields.CorrectAnswer
+	dto.CorrectOrder = fields.CorrectOrder
+	dto.Pairs = fields.Pairs
+	dto.Blanks = fields.Blanks
+	dto.CorrectAnswers = fields.CorrectAnswers
+	dto.IsTrue = fields.IsTrue
+}
+
+// toExercise is the created exercise as it is inserted
+func (dto CreateExerciseDTO) toExercise() Exercise {
+	exercise := Exercise{
+		Code:           dto.Code,
+		ExerciseType:   dto.ExerciseType,
+		Question:       dto.Question,
+		Hints:          dto.Hints,
+		Explanation:    dto.Explanation,
+		Status:         dto.Status,
+		CreatedAt:      dto.CreatedAt,
+		DeletedAt:      dto.DeletedAt,
+		Options:        dto.Options,
+		CorrectOrder:   dto.CorrectOrder,
+		Pairs:          dto.Pairs,
+		Blanks:         dto.Blanks,
+		CorrectAnswers: dto.CorrectAnswers,
+		IsTrue:         dto.IsTrue,
+		ImageID:        dto.ImageID,
+		AudioID:        dto.AudioID,
+		OptionMedia:    dto.OptionMedia,
+	}
+	if dto.CorrectAnswer != nil {
+		exercise.CorrectAnswer = *dto.CorrectAnswer
+	}
+	return exercise
+}
+
+func (dto UpdateExerciseDTO) fields() Fields {
+	return Fields{
+		Question:       dto.Question,
+		AudioID:        dto.AudioID,
+		Options:        dto.Options,
+		CorrectAnswer:  dto.CorrectAnswer,
+		CorrectOrder:   dto.CorrectOrder,
+		Pairs:          dto.Pairs,
+		Blanks:         dto.Blanks,
+		CorrectAnswers: dto.CorrectAnswers,
+		IsTrue:         dto.IsTrue,
+	}
+}
+
+func (dto *UpdateExerciseDTO) setFields(fields Fields) {
+	dto.Options = fields.Options
+	dto.CorrectAnswer = fields.CorrectAnswer
+	dto.CorrectOrder = fields.CorrectOrder
+	dto.Pairs = fields.Pairs
+	dto.Blanks = fields.Blanks
+	dto.CorrectAnswers = fields.CorrectAnswers
+	dto.IsTrue = fields.IsTrue
+}
+
+// Answer is what the learner submits, only the field of the exercise type is read
+type Answer struct {
+	// multiple_choice, manual_typing, translate_sentence, listen_and_type
+	Text string `json:"text,omitempty"`
+	// fill_in_blank, in the order of blanks
+	Blanks []string `json:"blanks,omitempty"`
+	// order_words
+	Order []string `json:"order,omitempty"`
+	// match_pairs
+	Pairs []Pair `json:"pairs,omitempty"`
+	// true_false
+	Value *bool `json:"value,omitempty"`
+}
+
+type GradeResult struct {
+	// Correct is set only for the fully correct answer
+	Correct bool `json:"correct"`
+	// Score is from 0 to 1, match_pairs and order_words give partial credit
+	Score       float64 `json:"score"`
+	Explanation string  `json:"explanation,omitempty"`
+	// Feedback is set when a typed answer was accepted with a typo or without diacritics
+	Feedback *Feedback `json:"feedback,omitempty"`
+	// Elements are in the order of the answer pairs or words
+	Elements []ElementResult `json:"elements,omitempty"`
+}
+
+// ElementResult grades one pair of match_pairs or one word of order_words
+type ElementResult struct {
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+	// Expected is the correct match of the term or the correct word at the position
+	Expected string `json:"expected,omitempty"`
+}
+
+// DefaultExerciseXP is the XP of a fully correct exercise in a lesson
+const DefaultExerciseXP = 10
+
+// LessonGrade is the result of all exercises of a lesson, XP is weighted by score
+type LessonGrade struct {
+	Results []ExerciseGrade `json:"results"`
+	Score   float64         `json:"score"`
+	XP      int             `json:"xp"`
+}
+
+type ExerciseGrade struct {
+	Code string `json:"code"`
+	GradeResult
+}
+
+type Feedback struct {
+	// Kind is typo or diacritics
+	Kind string `json:"kind"`
+	// Expected is the accepted answer closest to the learner's one
+	Expected string `json:"expected"`
+}
+
+// LearnerExercise is the exercise as the learner sees it, without the answers
+type LearnerExercise struct {
+	Code     string   `json:"code"`
+	Type     string   `json:"type"`
+	Question string   `json:"question"`
+	Hints    []string `json:"hints"`
+
+	// multiple_choice, order_words, word bank of fill_in_blank
+	Options []string `json:"options,omitempty"`
+	// match_pairs, matches are sorted so their order doesn't give the pairs away
+	Terms   []string `json:"terms,omitempty"`
+	Matches []string `json:"matches,omitempty"`
+	// fill_in_blank, number of blanks
+	Blanks int `json:"blanks,omitempty"`
+
+	Media *ExerciseMedia `json:"media,omitempty"`
+}
+
+// Preview shows admins the exercise as the learner sees it together with the solution
+type Preview struct {
+	Learner  LearnerExercise `json:"learner"`
+	Solution string          `json:"solution"`
+}
+
+// Schema describes the type specific fields of an exercise type for the admin editor
+type Schema struct {
+	Type   string        `json:"type"`
+	Fields []SchemaField `json:"fields"`
+}
+
+type SchemaField struct {
+	Name string `json:"name"`
+	// Kind is string, strings, pairs, blanks, bool or audio
+	Kind        string `json:"kind"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// SortFields lists fields the exercises list can be sorted by, the first one is the default
+var SortFields = []string{"created_at", "code"}
+
+// ListFilter narrows the exercises list, empty fields are not applied
+type ListFilter struct {
+	Type        string
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
@@ -1,14 +1,38 @@
 package exercises
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
-	ErrNotFound              = errors.New("exercise not found")
-	ErrCodeAlreadyExists     = errors.New("code already exists")
-	ErrIncorrectType         = errors.New("incorrect exercise type")
-	ErrOptionsRequired       = errors.New("correct options required")
-	ErrCorrectAnswerRequired = errors.New("correct answer required")
-	ErrPairsRequired         = errors.New("correct pairs required")
-	ErrCorrectOrderRequired  = errors.New("correct order required")
-	ErrNoFieldsToUpdate      = errors.New("no fields to update")
+	ErrNotFound               = errors.New("exercise not found")
+	ErrCodeAlreadyExists      = errors.New("code already exists")
+	ErrIncorrectType          = errors.New("incorrect exercise type")
+	ErrOptionsRequired        = errors.New("correct options required")
+	ErrCorrectAnswerRequired  = errors.New("correct answer required")
+	ErrPairsRequired          = errors.New("correct pairs required")
+	ErrCorrectOrderRequired   = errors.New("correct order required")
+	ErrNoFieldsToUpdate       = errors.New("no fields to update")
+	ErrReferenced             = errors.New("exercise is used in lessons")
+	ErrOptionMediaMismatch    = errors.New("option media must have one entry per option")
+	ErrBlanksRequired         = errors.New("accepted answers required for every blank")
+	ErrBlanksMismatch         = errors.New("question must have one ___ per blank")
+	ErrCorrectAnswersRequired = errors.New("correct answers required")
+	ErrIsTrueRequired         = errors.New("is_true required")
+	ErrAudioRequired          = errors.New("audio required")
 )
+
+// ReferencedError is returned when exercise can't be deleted without cascade
+type ReferencedError struct {
+	Parents []string
+}
+
+func (e *ReferencedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrReferenced, strings.Join(e.Parents, ", "))
+}
+
+func (e *ReferencedError) Unwrap() error {
+	return ErrReferenced
+}
@@ -0,0 +1,142 @@
+package exercises
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	FeedbackTypo       = "typo"
+	FeedbackDiacritics = "diacritics"
+)
+
+// typos are forgiven only in answers of at least minTypoLength letters,
+// in shorter words one letter changes the word
+const minTypoLength = 4
+
+type MatcherConfig struct {
+	// FoldDiacritics accepts Kazakh, Russian and Turkish letters typed without diacritics, ә as а, ё as е, ş as s
+	FoldDiacritics bool
+	// MaxTypos is the largest Levenshtein distance accepted as a typo, 0 disables typos
+	MaxTypos int
+}
+
+var DefaultMatcherConfig = MatcherConfig{
+	FoldDiacritics: true,
+	MaxTypos:       1,
+}
+
+// Matcher compares typed answers ignoring case, whitespace and punctuation
+type Matcher struct {
+	config MatcherConfig
+}
+
+func NewMatcher(config MatcherConfig) *Matcher {
+	return &Matcher{
+		config: config,
+	}
+}
+
+// Match accepts the text when it equals any accepted answer,
+// answers with folded diacritics or a typo are accepted with feedback
+func (m *Matcher) Match(text string, accepted []string) (bool, *Feedback) {
+	normalized := normalizeAnswer(text)
+	for _, answer := range accepted {
+		if normalized == normalizeAnswer(answer) {
+			return true, nil
+		}
+	}
+
+	if m.config.FoldDiacritics {
+		folded := foldDiacritics(normalized)
+		for _, answer := range accepted {
+			if folded == foldDiacritics(normalizeAnswer(answer)) {
+				return true, &Feedback{Kind: FeedbackDiacritics, Expected: answer}
+			}
+		}
+	}
+
+	if m.config.MaxTypos > 0 {
+		var (
+			closest  string
+			distance = m.config.MaxTypos + 1
+		)
+		for _, answer := range accepted {
+			expected := normalizeAnswer(answer)
+			if len([]rune(expected)) < minTypoLength {
+				continue
+			}
+			if d := levenshtein(m.comparable(normalized), m.comparable(expected)); d < distance {
+				closest, distance = answer, d
+			}
+		}
+		if closest != "" {
+			return true, &Feedback{Kind: FeedbackTypo, Expected: closest}
+		}
+	}
+
+	return false, nil
+}
+
+func (m *Matcher) comparable(text string) string {
+	if m.config.FoldDiacritics {
+		return foldDiacritics(text)
+	}
+	return text
+}
+
+// normalizeAnswer lowercases the text, replaces punctuation with spaces and collapses whitespace
+func normalizeAnswer(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return ' '
+		}
+		return unicode.ToLower(r)
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+var diacritics = map[rune]rune{
+	// kazakh
+	'ә': 'а', 'ғ': 'г', 'қ': 'к', 'ң': 'н', 'ө': 'о', 'ұ': 'у', 'ү': 'у', 'һ': 'х', 'і': 'и',
+	// russian
+	'ё': 'е', 'й': 'и',
+	// turkish
+	'ç': 'c', 'ğ': 'g', 'ı': 'i', 'ö': 'o', 'ş': 's', 'ü': 'u',
+}
+
+// foldDiacritics replaces letters with their base letters, lowercase input is expected
+func foldDiacritics(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			// combining marks of decomposed input, ü typed as u and U+0308
+			return -1
+		}
+		if base, ok := diacritics[r]; ok {
+			return base
+		}
+		return r
+	}, text)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,123 @@
+package exercises
+
+import (
+	"sync"
+)
+
+// TypeHandler is everything the service knows about one exercise type,
+// a new type is added by registering its handler
+type TypeHandler interface {
+	Type() string
+	Schema() Schema
+	// Normalize validates the request and returns only the fields of the type
+	Normalize(fields Fields) (Fields, error)
+	// Project fills the type specific part of the learner view
+	Project(exercise Exercise, learner *LearnerExercise)
+	Grade(exercise Exercise, answer Answer) GradeResult
+	// Solution renders the correct answer for the admin preview
+	Solution(exercise Exercise) string
+}
+
+// Registry keeps exercise type handlers in the order they were registered
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]TypeHandler
+	types    []string
+}
+
+func NewRegistry(handlers ...TypeHandler) *Registry {
+	r := &Registry{
+		handlers: make(map[string]TypeHandler, len(handlers)),
+	}
+	for _, handler := range handlers {
+		r.Register(handler)
+	}
+	return r
+}
+
+// Register adds the handler or replaces the handler of the same type
+func (r *Registry) Register(handler TypeHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.handlers[handler.Type()]; !ok {
+		r.types = append(r.types, handler.Type())
+	}
+	r.handlers[handler.Type()] = handler
+}
+
+func (r *Registry) Get(exerciseType string) (TypeHandler, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handler, ok := r.handlers[exerciseType]
+	if !ok {
+		return nil, ErrIncorrectType
+	}
+	return handler, nil
+}
+
+func (r *Registry) Schemas() []Schema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schemas := make([]Schema, 0, len(r.types))
+	for _, exerciseType := range r.types {
+		schemas = append(schemas, r.handlers[exerciseType].Schema())
+	}
+	return schemas
+}
+
+// normalize returns the fields of the type, anything else in the request is dropped
+func (r *Registry) normalize(exerciseType string, fields Fields) (Fields, error) {
+	handler, err := r.Get(exerciseType)
+	if err != nil {
+		return Fields{}, err
+	}
+	return handler.Normalize(fields)
+}
+
+func (r *Registry) project(exercise Exercise) (LearnerExercise, error) {
+	handler, err := r.Get(exercise.ExerciseType)
+	if err != nil {
+		return LearnerExercise{}, err
+	}
+
+	learner := LearnerExercise{
+		Code:     exercise.Code,
+		Type:     exercise.ExerciseType,
+		Question: exercise.Question,
+		Hints:    exercise.Hints,
+		Media:    exercise.Media,
+	}
+	handler.Project(exercise, &learner)
+	return learner, nil
+}
+
+func (r *Registry) grade(exercise Exercise, answer Answer) (GradeResult, error) {
+	handler, err := r.Get(exercise.ExerciseType)
+	if err != nil {
+		return GradeResult{}, err
+	}
+
+	result := handler.Grade(exercise, answer)
+	// all or nothing types leave the score to follow the result
+	if result.Correct {
+		result.Score = 1
+	}
+	result.Explanation = exercise.Explanation
+	return result, nil
+}
+
+func (r *Registry) preview(exercise Exercise) (Preview, error) {
+	learner, err := r.project(exercise)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	handler, _ := r.Get(exercise.ExerciseType)
+	return Preview{
+		Learner:  learner,
+		Solution: handler.Solution(exercise),
+	}, nil
+}
@@ -3,8 +3,11 @@ package exercises
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
+	"uiren/internal/app/publication"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -48,6 +51,15 @@ func (r *exercisesRepository) getExercisesByCodes(ctx context.Context, codes []s
 		return nil, err
 	}
 
+	// $in does not keep the order of codes, lists in parents are ordered
+	position := make(map[string]int, len(codes))
+	for i, code := range codes {
+		position[code] = i
+	}
+	sort.SliceStable(response, func(i, j int) bool {
+		return position[response[i].Code] < position[response[j].Code]
+	})
+
 	return response, nil
 }
 
@@ -62,6 +74,7 @@ func (r *exercisesRepository) getExercise(ctx context.Context, code string) (Exe
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return Exercise{}, ErrNotFound
 		}
+		return Exercise{}, err
 	}
 
 	return response, nil
@@ -130,48 +143,176 @@ func (r *exercisesRepository) getExerciseType(ctx context.Context, code string)
 	return exerciseType, nil
 }
 
-func (r *exercisesRepository) updateExercise(ctx context.Context, code string, dto UpdateExerciseDTO) error {
+func (r *exercisesRepository) updateExercise(ctx context.Context, code string, dto UpdateExerciseDTO) (Exercise, error) {
 	var (
-		collection = r.db.Collection(exercisesCollection)
-		filter     = bson.M{"code": code, "deleted_at": nil}
-		update     = bson.M{}
+		filter = bson.M{"code": code, "deleted_at": nil}
+		set    = bson.M{}
+		unset  = bson.M{}
 	)
 
 	if dto.Question != nil {
-		update["question"] = *dto.Question
+		set["question"] = *dto.Question
 	}
 	if dto.Hints != nil {
-		update["hints"] = dto.Hints
+		set["hints"] = dto.Hints
 	}
 	if dto.Explanation != nil {
-		update["explanation"] = *dto.Explanation
+		set["explanation"] = *dto.Explanation
 	}
 	if dto.Options != nil {
-		update["options"] = dto.Options
+		set["options"] = dto.Options
 	}
 	if dto.CorrectAnswer != nil {
-		update["correct_answer"] = *dto.CorrectAnswer
+		set["correct_answer"] = *dto.CorrectAnswer
 	}
 	if dto.CorrectOrder != nil {
-		update["correct_order"] = dto.CorrectOrder
+		set["correct_order"] = dto.CorrectOrder
 	}
 	if dto.Pairs != nil {
-		update["pairs"] = dto.Pairs
+		set["pairs"] = dto.Pairs
+	}
+	if dto.Blanks != nil {
+		set["blanks"] = dto.Blanks
+	}
+	if dto.CorrectAnswers != nil {
+		set["correct_answers"] = dto.CorrectAnswers
 	}
-	if len(update) == 0 {
-		return ErrNoFieldsToUpdate
+	if dto.IsTrue != nil {
+		set["is_true"] = *dto.IsTrue
+	}
+	if dto.ImageID != nil {
+		setOrUnset(set, unset, "image_id", *dto.ImageID)
+	}
+	if dto.AudioID != nil {
+		setOrUnset(set, unset, "audio_id", *dto.AudioID)
+	}
+	if dto.OptionMedia != nil {
+		set["option_media"] = dto.OptionMedia
+	} else if dto.Options != nil {
+		// media of the old options may point to other options now
+		unset["option_media"] = ""
+	}
+	if len(set) == 0 && len(unset) == 0 {
+		return Exercise{}, ErrNoFieldsToUpdate
 	}
 
-	update = bson.M{"$set": update}
-	res, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
 	}
+	exercise, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Exercise{}, ErrNotFound
+	}
+	return exercise, err
+}
 
-	if res.MatchedCount == 0 {
-		return ErrNotFound
+// restoreExercise overwrites exercise content, type specific fields missing in the snapshot are removed
+func (r *exercisesRepository) restoreExercise(ctx context.Context, code string, exercise Exercise) (Exercise, error) {
+	var (
+		filter = bson.M{"code": code, "deleted_at": nil, "type": exercise.ExerciseType}
+		set    = bson.M{
+			"question":    exercise.Question,
+			"hints":       exercise.Hints,
+			"explanation": exercise.Explanation,
+		}
+		unset = bson.M{}
+	)
+
+	if exercise.Options != nil {
+		set["options"] = exercise.Options
+	} else {
+		unset["options"] = ""
+	}
+	if exercise.CorrectAnswer != "" {
+		set["correct_answer"] = exercise.CorrectAnswer
+	} else {
+		unset["correct_answer"] = ""
+	}
+	if exercise.CorrectOrder != nil {
+		set["correct_order"] = exercise.CorrectOrder
+	} else {
+		unset["correct_order"] = ""
+	}
+	if exercise.Pairs != nil {
+		set["pairs"] = exercise.Pairs
+	} else {
+		unset["pairs"] = ""
+	}
+	if exercise.Blanks != nil {
+		set["blanks"] = exercise.Blanks
+	} else {
+		unset["blanks"] = ""
+	}
+	if exercise.CorrectAnswers != nil {
+		set["correct_answers"] = exercise.CorrectAnswers
+	} else {
+		unset["correct_answers"] = ""
+	}
+	if exercise.IsTrue != nil {
+		set["is_true"] = *exercise.IsTrue
+	} else {
+		unset["is_true"] = ""
+	}
+	setOrUnset(set, unset, "image_id", exercise.ImageID)
+	setOrUnset(set, unset, "audio_id", exercise.AudioID)
+	if exercise.OptionMedia != nil {
+		set["option_media"] = exercise.OptionMedia
+	} else {
+		unset["option_media"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	restored, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Exercise{}, ErrNotFound
+	}
+	return restored, err
+}
+
+func setOrUnset(set, unset bson.M, field, value string) {
+	if value != "" {
+		set[field] = value
+	} else {
+		unset[field] = ""
 	}
-	return nil
+}
+
+// setExerciseStatus changes status only if nobody changed it since it was read
+func (r *exercisesRepository) setExerciseStatus(ctx context.Context, code, from, to string) (Exercise, error) {
+	var (
+		filter = bson.M{"code": code, "deleted_at": nil, "status": from}
+		update = bson.M{"$set": bson.M{"status": to}}
+	)
+
+	exercise, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Exercise{}, publication.ErrStatusChanged
+	}
+	return exercise, err
+}
+
+// findAndUpdate returns the exercise as the update left it, revisions are saved from it
+// so a write that lands right after this one doesn't get into this revision
+func (r *exercisesRepository) findAndUpdate(ctx context.Context, filter, update bson.M) (Exercise, error) {
+	var (
+		collection = r.db.Collection(exercisesCollection)
+		opts       = options.FindOneAndUpdate().SetReturnDocument(options.After)
+		response   Exercise
+	)
+
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&response); err != nil {
+		return Exercise{}, err
+	}
+
+	return response, nil
 }
 
 func (r *exercisesRepository) getAllExercises(ctx context.Context) ([]Exercise, error) {
@@ -194,6 +335,60 @@ func (r *exercisesRepository) getAllExercises(ctx context.Context) ([]Exercise,
 	return result, nil
 }
 
+func (r *exercisesRepository) listExercises(ctx context.Context, filter ListFilter, params pagination.Params) ([]Exercise, error) {
+	var (
+		collection = r.db.Collection(exercisesCollection)
+		query      = bson.M{"deleted_at": nil}
+		opts       = options.Find().SetSort(pagination.MongoSort(params, params.SortBy, "code")).SetLimit(int64(params.Fetch()))
+		result     []Exercise
+	)
+
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if created := createdRange(filter); len(created) > 0 {
+		query["created_at"] = created
+	}
+
+	if params.After != nil {
+		var value interface{} = params.After.Value
+		if params.SortBy == "created_at" {
+			createdAt, err := params.After.TimeValue()
+			if err != nil {
+				return nil, err
+			}
+			value = createdAt
+		}
+		query = bson.M{"$and": bson.A{query, pagination.MongoAfter(params, params.SortBy, value, "code", params.After.Key)}}
+	}
+
+	cur, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func createdRange(filter ListFilter) bson.M {
+	created := bson.M{}
+	if filter.CreatedFrom != nil {
+		created["$gte"] = *filter.CreatedFrom
+	}
+	if filter.CreatedTo != nil {
+		created["$lt"] = *filter.CreatedTo
+	}
+	return created
+}
+
 func (r *exercisesRepository) exerciseExists(ctx context.Context, code string) (bool, error) {
 	var (
 		collection = r.db.Collection(exercisesCollection)
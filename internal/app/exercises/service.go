@@ -2,8 +2,16 @@ package exercises
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strings"
 	"time"
+	"uiren/internal/app/events"
+	"uiren/internal/app/media"
+	"uiren/internal/app/publication"
+	"uiren/internal/app/revisions"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -14,24 +22,91 @@ type repository interface {
 	getExercisesByCodes(ctx context.Context, codes []string) ([]Exercise, error)
 	getExercise(ctx context.Context, code string) (Exercise, error)
 	createExercise(ctx context.Context, dto CreateExerciseDTO) (primitive.ObjectID, error)
-	updateExercise(ctx context.Context, code string, dto UpdateExerciseDTO) error
+	updateExercise(ctx context.Context, code string, dto UpdateExerciseDTO) (Exercise, error)
+	restoreExercise(ctx context.Context, code string, exercise Exercise) (Exercise, error)
+	setExerciseStatus(ctx context.Context, code, from, to string) (Exercise, error)
 	getExerciseType(ctx context.Context, code string) (string, error)
 	deleteExercise(ctx context.Context, code string) error
 
 	getAllExercises(ctx context.Context) ([]Exercise, error)
+	listExercises(ctx context.Context, filter ListFilter, params pagination.Params) ([]Exercise, error)
 	exerciseExists(ctx context.Context, code string) (bool, error)
 }
 
+type publisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
+type revisionService interface {
+	SaveRevision(ctx context.Context, entityType, code string, document interface{}) (int, error)
+	GetRevision(ctx context.Context, entityType, code string, version int) (revisions.Revision, error)
+}
+
+// referenceTracker knows which lessons use an exercise, lessons package implements it
+type referenceTracker interface {
+	GetLessonsReferencingExercise(ctx context.Context, exerciseCode string) ([]string, error)
+	UnlinkExercise(ctx context.Context, exerciseCode string) error
+}
+
+type mediaService interface {
+	GetAssets(ctx context.Context, ids []string) ([]media.Asset, error)
+}
+
 type ExerciseService struct {
-	repo repository
+	repo             repository
+	revisionService  revisionService
+	publisher        publisher
+	referenceTracker referenceTracker
+	mediaService     mediaService
+	matcher          *Matcher
+	types            *Registry
+	exerciseXP       int
 }
 
 func NewExerciseService(repo repository) *ExerciseService {
+	matcher := NewMatcher(DefaultMatcherConfig)
+
 	return &ExerciseService{
-		repo: repo,
+		repo:       repo,
+		matcher:    matcher,
+		types:      NewRegistry(builtinTypes(matcher)...),
+		exerciseXP: DefaultExerciseXP,
 	}
 }
 
+// SetExerciseXP sets the XP of a fully correct exercise in a lesson
+func (s *ExerciseService) SetExerciseXP(xp int) {
+	s.exerciseXP = xp
+}
+
+// SetMatcherConfig changes how typed answers are compared by every built in type
+func (s *ExerciseService) SetMatcherConfig(config MatcherConfig) {
+	s.matcher.config = config
+}
+
+// RegisterType adds an exercise type or replaces a built in one
+func (s *ExerciseService) RegisterType(handler TypeHandler) {
+	s.types.Register(handler)
+}
+
+func (s *ExerciseService) WithRevisionService(revisionService revisionService) {
+	s.revisionService = revisionService
+}
+
+func (s *ExerciseService) WithPublisher(publisher publisher) {
+	s.publisher = publisher
+}
+
+func (s *ExerciseService) WithReferenceTracker(referenceTracker referenceTracker) {
+	s.referenceTracker = referenceTracker
+}
+
+// WithMediaService enables attaching media assets to exercises,
+// without it asset ids are stored unchecked
+func (s *ExerciseService) WithMediaService(mediaService mediaService) {
+	s.mediaService = mediaService
+}
+
 func (s ExerciseService) GetExercisesByCodes(ctx context.Context, codes []string) ([]Exercise, error) {
 	logger.Info("ExerciseService.GetExercisesbyCodes new request")
 
@@ -56,14 +131,36 @@ func (s ExerciseService) GetExercise(ctx context.Context, code string) (Exercise
 	return exercise, nil
 }
 
-func (s ExerciseService) DeleteExercise(ctx context.Context, code string) error {
+// DeleteExercise refuses to delete an exercise used in lessons unless cascade is set,
+// with cascade the exercise is removed from every lesson first
+func (s ExerciseService) DeleteExercise(ctx context.Context, code string, cascade bool) error {
 	logger.Info("ExerciseService.DeleteExercise new request")
 
+	if s.referenceTracker != nil {
+		parents, err := s.referenceTracker.GetLessonsReferencingExercise(ctx, code)
+		if err != nil {
+			logger.Error("ExerciseService.DeleteExercise referenceTracker.GetLessonsReferencingExercise: ", err)
+			return err
+		}
+
+		if len(parents) > 0 {
+			if !cascade {
+				return &ReferencedError{Parents: parents}
+			}
+			if err := s.referenceTracker.UnlinkExercise(ctx, code); err != nil {
+				logger.Error("ExerciseService.DeleteExercise referenceTracker.UnlinkExercise: ", err)
+				return err
+			}
+		}
+	}
+
 	if err := s.repo.deleteExercise(ctx, code); err != nil {
 		logger.Error("ExerciseService.DeleteExercise repo.deleteExercise: ", err)
 		return err
 	}
 
+	s.publish(ctx, code)
+
 	return nil
 }
 
@@ -76,24 +173,22 @@ func (s ExerciseService) CreateExercise(ctx context.Context, dto CreateExerciseD
 	newDTO.Question = dto.Question
 	newDTO.Hints = dto.Hints
 	newDTO.Explanation = dto.Explanation
+	newDTO.Status = publication.StatusDraft
 	newDTO.CreatedAt = time.Now()
 	newDTO.DeletedAt = nil
 
-	var err error
-	switch dto.ExerciseType {
-	case multipleChoiceType:
-		err = normalizeMultipleChoiceExerciseDTO(&dto, &newDTO)
-	case manualTypingType:
-		err = normalizeManualTypingExerciseDTO(&dto, &newDTO)
-	case matchPairsType:
-		err = normalizeMatchPairsExerciseDTO(&dto, &newDTO)
-	case orderWordsType:
-		err = normalizeOrderWordsExerciseDTO(&dto, &newDTO)
-	default:
-		err = ErrIncorrectType
-	}
-	if err != nil {
-		logger.Error("ExerciseService.CreateExercise normalizeExerciseDTO: ", err)
+	fields, err := s.types.normalize(dto.ExerciseType, dto.fields())
+	if err != nil {
+		logger.Error("ExerciseService.CreateExercise types.normalize: ", err)
+		return primitive.NilObjectID, err
+	}
+	newDTO.setFields(fields)
+
+	newDTO.ImageID = dto.ImageID
+	newDTO.AudioID = dto.AudioID
+	newDTO.OptionMedia = dto.OptionMedia
+	if err := s.validateMedia(ctx, newDTO.ImageID, newDTO.AudioID, newDTO.OptionMedia, newDTO.Options); err != nil {
+		logger.Error("ExerciseService.CreateExercise validateMedia: ", err)
 		return primitive.NilObjectID, err
 	}
 
@@ -102,6 +197,8 @@ func (s ExerciseService) CreateExercise(ctx context.Context, dto CreateExerciseD
 		logger.Error("ExerciseService.CreateExercise repo.createExercise: ", err)
 		return primitive.NilObjectID, err
 	}
+	s.saveRevision(ctx, newDTO.toExercise())
+	s.publish(ctx, newDTO.Code)
 
 	return oid, nil
 }
@@ -119,31 +216,86 @@ func (s ExerciseService) UpdateExercise(ctx context.Context, code string, dto Up
 		return err
 	}
 
-	switch exerciseType {
-	case multipleChoiceType:
-		err = normalizeMultipleChoiceExerciseDTO(&dto, &newDTO)
-	case manualTypingType:
-		err = normalizeManualTypingExerciseDTO(&dto, &newDTO)
-	case matchPairsType:
-		err = normalizeMatchPairsExerciseDTO(&dto, &newDTO)
-	case orderWordsType:
-		err = normalizeOrderWordsExerciseDTO(&dto, &newDTO)
-	default:
-		err = ErrIncorrectType
-	}
+	fields, err := s.types.normalize(exerciseType, dto.fields())
 	if err != nil {
-		logger.Error("ExerciseService.UpdateExercise normalizeExerciseDTO: ", err)
+		logger.Error("ExerciseService.UpdateExercise types.normalize: ", err)
+		return err
+	}
+	newDTO.setFields(fields)
+
+	newDTO.ImageID = dto.ImageID
+	newDTO.AudioID = dto.AudioID
+	newDTO.OptionMedia = dto.OptionMedia
+	if err := s.validateMedia(ctx, valueOf(newDTO.ImageID), valueOf(newDTO.AudioID), newDTO.OptionMedia, newDTO.Options); err != nil {
+		logger.Error("ExerciseService.UpdateExercise validateMedia: ", err)
 		return err
 	}
 
-	if err := s.repo.updateExercise(ctx, code, newDTO); err != nil {
+	exercise, err := s.repo.updateExercise(ctx, code, newDTO)
+	if err != nil {
 		logger.Error("ExerciseService.UpdateExercise updateExercise: ", err)
 		return err
 	}
+	s.saveRevision(ctx, exercise)
+	s.publish(ctx, code)
 
 	return nil
 }
 
+// ValidateExercise checks type specific fields of the exercise without saving it
+func (s ExerciseService) ValidateExercise(dto CreateExerciseDTO) error {
+	fields, err := s.types.normalize(dto.ExerciseType, dto.fields())
+	if err != nil {
+		return err
+	}
+	return validateOptionMedia(dto.OptionMedia, fields.Options)
+}
+
+// validateMedia checks that attached assets exist and image_id and audio_id point to assets of that kind,
+// options may use assets of any kind
+func (s ExerciseService) validateMedia(ctx context.Context, imageID, audioID string, optionMedia, options []string) error {
+	if err := validateOptionMedia(optionMedia, options); err != nil {
+		return err
+	}
+	if s.mediaService == nil {
+		return nil
+	}
+
+	ids := append([]string{imageID, audioID}, optionMedia...)
+	assets, err := s.mediaService.GetAssets(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	kinds := make(map[string]string, len(assets))
+	for _, asset := range assets {
+		kinds[asset.ID] = asset.Kind
+	}
+	if imageID != "" && kinds[imageID] != media.KindImage {
+		return fmt.Errorf("%w: image_id must be an image", media.ErrWrongKind)
+	}
+	if audioID != "" && kinds[audioID] != media.KindAudio {
+		return fmt.Errorf("%w: audio_id must be audio", media.ErrWrongKind)
+	}
+
+	return nil
+}
+
+// validateOptionMedia requires one media entry per option, options are matched by position
+func validateOptionMedia(optionMedia, options []string) error {
+	if optionMedia != nil && len(optionMedia) != len(options) {
+		return ErrOptionMediaMismatch
+	}
+	return nil
+}
+
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 func (s ExerciseService) GetAllExercises(ctx context.Context) ([]Exercise, error) {
 	logger.Info("ExerciseService.GetAllExercises new requests")
 
@@ -156,6 +308,26 @@ func (s ExerciseService) GetAllExercises(ctx context.Context) ([]Exercise, error
 	return exercises, nil
 }
 
+// ListExercises returns one page of exercises
+func (s ExerciseService) ListExercises(ctx context.Context, filter ListFilter, params pagination.Params) (pagination.Page[Exercise], error) {
+	logger.Info("ExerciseService.ListExercises new request")
+
+	exercises, err := s.repo.listExercises(ctx, filter, params)
+	if err != nil {
+		logger.Error("ExerciseService.ListExercises repo.listExercises: ", err)
+		return pagination.Page[Exercise]{}, err
+	}
+
+	return pagination.NewPage(exercises, params, exerciseCursor), nil
+}
+
+func exerciseCursor(exercise Exercise, sortBy string) (string, string) {
+	if sortBy == "code" {
+		return exercise.Code, exercise.Code
+	}
+	return pagination.FormatTime(exercise.CreatedAt), exercise.Code
+}
+
 func (s ExerciseService) ExerciseExists(ctx context.Context, code string) (bool, error) {
 	logger.Info("ExerciseService.ExerciseExists new request")
 
@@ -166,3 +338,212 @@ func (s ExerciseService) ExerciseExists(ctx context.Context, code string) (bool,
 	}
 	return exists, nil
 }
+
+func (s ExerciseService) GetPublishedExercise(ctx context.Context, code string) (Exercise, error) {
+	logger.Info("ExerciseService.GetPublishedExercise new request")
+
+	exercise, err := s.repo.getExercise(ctx, code)
+	if err != nil {
+		logger.Error("ExerciseService.GetPublishedExercise repo.getExercise: ", err)
+		return Exercise{}, err
+	}
+
+	if exercise.Status != publication.StatusPublished {
+		return Exercise{}, ErrNotFound
+	}
+
+	return exercise, nil
+}
+
+// GradeAnswer checks the learner answer to the published exercise
+func (s ExerciseService) GradeAnswer(ctx context.Context, code string, answer Answer) (GradeResult, error) {
+	logger.Info("ExerciseService.GradeAnswer new request")
+
+	exercise, err := s.GetPublishedExercise(ctx, code)
+	if err != nil {
+		logger.Error("ExerciseService.GradeAnswer GetPublishedExercise: ", err)
+		return GradeResult{}, err
+	}
+
+	result, err := s.types.grade(exercise, answer)
+	if err != nil {
+		logger.Error("ExerciseService.GradeAnswer types.grade: ", err)
+		return GradeResult{}, err
+	}
+
+	return result, nil
+}
+
+// GradeLesson grades the answers to the lesson exercises by their codes, an exercise without an answer scores 0.
+// Every exercise gives its share of XP weighted by score
+func (s ExerciseService) GradeLesson(exercises []Exercise, answers map[string]Answer) (LessonGrade, error) {
+	logger.Info("ExerciseService.GradeLesson new request")
+
+	grade := LessonGrade{Results: make([]ExerciseGrade, 0, len(exercises))}
+	var total float64
+	for _, exercise := range exercises {
+		result, err := s.types.grade(exercise, answers[exercise.Code])
+		if err != nil {
+			logger.Error("ExerciseService.GradeLesson types.grade: ", err)
+			return LessonGrade{}, err
+		}
+		total += result.Score
+		grade.Results = append(grade.Results, ExerciseGrade{Code: exercise.Code, GradeResult: result})
+	}
+
+	if len(exercises) > 0 {
+		grade.Score = total / float64(len(exercises))
+	}
+	grade.XP = s.ScoreXP(total)
+	return grade, nil
+}
+
+// ScoreXP converts the summed score of lesson exercises to XP
+func (s ExerciseService) ScoreXP(score float64) int {
+	return int(math.Round(score * float64(s.exerciseXP)))
+}
+
+// ToLearner hides the answers of the exercise
+func (s ExerciseService) ToLearner(exercise Exercise) (LearnerExercise, error) {
+	return s.types.project(exercise)
+}
+
+// PreviewExercise shows the exercise as the learner sees it with its solution, any status can be previewed
+func (s ExerciseService) PreviewExercise(ctx context.Context, code string) (Preview, error) {
+	logger.Info("ExerciseService.PreviewExercise new request")
+
+	exercise, err := s.repo.getExercise(ctx, code)
+	if err != nil {
+		logger.Error("ExerciseService.PreviewExercise repo.getExercise: ", err)
+		return Preview{}, err
+	}
+
+	preview, err := s.types.preview(exercise)
+	if err != nil {
+		logger.Error("ExerciseService.PreviewExercise types.preview: ", err)
+		return Preview{}, err
+	}
+
+	return preview, nil
+}
+
+// GetTypeSchemas describes every registered exercise type
+func (s ExerciseService) GetTypeSchemas() []Schema {
+	return s.types.Schemas()
+}
+
+func (s ExerciseService) SetExerciseStatus(ctx context.Context, code, status string) error {
+	logger.Info("ExerciseService.SetExerciseStatus new request")
+
+	exercise, err := s.repo.getExercise(ctx, code)
+	if err != nil {
+		logger.Error("ExerciseService.SetExerciseStatus repo.getExercise: ", err)
+		return err
+	}
+
+	if err := publication.ValidateTransition(exercise.Status, status); err != nil {
+		logger.Error("ExerciseService.SetExerciseStatus publication.ValidateTransition: ", err)
+		return err
+	}
+
+	exercise, err = s.repo.setExerciseStatus(ctx, code, exercise.Status, status)
+	if err != nil {
+		logger.Error("ExerciseService.SetExerciseStatus repo.setExerciseStatus: ", err)
+		return err
+	}
+	s.saveRevision(ctx, exercise)
+	s.publish(ctx, code)
+
+	return nil
+}
+
+// ValidateExercisesPublished checks that every code points to an existing published exercise
+func (s ExerciseService) ValidateExercisesPublished(ctx context.Context, codes []string) error {
+	logger.Info("ExerciseService.ValidateExercisesPublished new request")
+
+	if len(codes) == 0 {
+		return nil
+	}
+
+	exercises, err := s.repo.getExercisesByCodes(ctx, codes)
+	if err != nil {
+		logger.Error("ExerciseService.ValidateExercisesPublished repo.getExercisesByCodes: ", err)
+		return err
+	}
+
+	statuses := make(map[string]string, len(exercises))
+	for _, exercise := range exercises {
+		statuses[exercise.Code] = exercise.Status
+	}
+
+	var missing, unpublished []string
+	for _, code := range codes {
+		status, ok := statuses[code]
+		switch {
+		case !ok:
+			missing = append(missing, code)
+		case status != publication.StatusPublished:
+			unpublished = append(unpublished, code)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, strings.Join(missing, ", "))
+	}
+	if len(unpublished) > 0 {
+		return publication.NotPublishedError("exercises", unpublished)
+	}
+
+	return nil
+}
+
+// RollbackExercise restores exercise content from the given revision, the rollback itself is saved as a new revision
+func (s ExerciseService) RollbackExercise(ctx context.Context, code string, version int) error {
+	logger.Info("ExerciseService.RollbackExercise new request")
+
+	if s.revisionService == nil {
+		return revisions.ErrHistoryNotAvailable
+	}
+
+	revision, err := s.revisionService.GetRevision(ctx, revisions.EntityExercise, code, version)
+	if err != nil {
+		logger.Error("ExerciseService.RollbackExercise revisionService.GetRevision: ", err)
+		return err
+	}
+
+	var exercise Exercise
+	if err := revision.Decode(&exercise); err != nil {
+		logger.Error("ExerciseService.RollbackExercise revision.Decode: ", err)
+		return err
+	}
+
+	restored, err := s.repo.restoreExercise(ctx, code, exercise)
+	if err != nil {
+		logger.Error("ExerciseService.RollbackExercise repo.restoreExercise: ", err)
+		return err
+	}
+	s.saveRevision(ctx, restored)
+	s.publish(ctx, code)
+
+	return nil
+}
+
+// saveRevision snapshots the exercise as the write left it, history errors must not fail the write itself
+func (s ExerciseService) saveRevision(ctx context.Context, exercise Exercise) {
+	if s.revisionService == nil {
+		return
+	}
+
+	if _, err := s.revisionService.SaveRevision(ctx, revisions.EntityExercise, exercise.Code, exercise); err != nil {
+		logger.Error("ExerciseService.saveRevision revisionService.SaveRevision: ", err)
+	}
+}
+
+// publish notifies subscribers that the exercise changed
+func (s ExerciseService) publish(ctx context.Context, code string) {
+	if s.publisher == nil {
+		return
+	}
+
+	s.publisher.Publish(ctx, events.Event{Type: events.ExerciseChanged, Code: code})
+}
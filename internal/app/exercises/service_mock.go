@@ -7,6 +7,10 @@ package exercises
 import (
 	context "context"
 	reflect "reflect"
+	events "uiren/internal/app/events"
+	media "uiren/internal/app/media"
+	revisions "uiren/internal/app/revisions"
+	pagination "uiren/pkg/pagination"
 
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
@@ -139,12 +143,58 @@ func (mr *MockrepositoryMockRecorder) getExercisesByCodes(ctx, codes interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getExercisesByCodes", reflect.TypeOf((*Mockrepository)(nil).getExercisesByCodes), ctx, codes)
 }
 
+// listExercises mocks base method.
+func (m *Mockrepository) listExercises(ctx context.Context, filter ListFilter, params pagination.Params) ([]Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "listExercises", ctx, filter, params)
+	ret0, _ := ret[0].([]Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// listExercises indicates an expected call of listExercises.
+func (mr *MockrepositoryMockRecorder) listExercises(ctx, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "listExercises", reflect.TypeOf((*Mockrepository)(nil).listExercises), ctx, filter, params)
+}
+
+// restoreExercise mocks base method.
+func (m *Mockrepository) restoreExercise(ctx context.Context, code string, exercise Exercise) (Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "restoreExercise", ctx, code, exercise)
+	ret0, _ := ret[0].(Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// restoreExercise indicates an expected call of restoreExercise.
+func (mr *MockrepositoryMockRecorder) restoreExercise(ctx, code, exercise interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "restoreExercise", reflect.TypeOf((*Mockrepository)(nil).restoreExercise), ctx, code, exercise)
+}
+
+// setExerciseStatus mocks base method.
+func (m *Mockrepository) setExerciseStatus(ctx context.Context, code, from, to string) (Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "setExerciseStatus", ctx, code, from, to)
+	ret0, _ := ret[0].(Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// setExerciseStatus indicates an expected call of setExerciseStatus.
+func (mr *MockrepositoryMockRecorder) setExerciseStatus(ctx, code, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "setExerciseStatus", reflect.TypeOf((*Mockrepository)(nil).setExerciseStatus), ctx, code, from, to)
+}
+
 // updateExercise mocks base method.
-func (m *Mockrepository) updateExercise(ctx context.Context, code string, dto UpdateExerciseDTO) error {
+func (m *Mockrepository) updateExercise(ctx context.Context, code string, dto UpdateExerciseDTO) (Exercise, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "updateExercise", ctx, code, dto)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // updateExercise indicates an expected call of updateExercise.
@@ -152,3 +202,181 @@ func (mr *MockrepositoryMockRecorder) updateExercise(ctx, code, dto interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateExercise", reflect.TypeOf((*Mockrepository)(nil).updateExercise), ctx, code, dto)
 }
+
+// Mockpublisher is a mock of publisher interface.
+type Mockpublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockpublisherMockRecorder
+}
+
+// MockpublisherMockRecorder is the mock recorder for Mockpublisher.
+type MockpublisherMockRecorder struct {
+	mock *Mockpublisher
+}
+
+// NewMockpublisher creates a new mock instance.
+func NewMockpublisher(ctrl *gomock.Controller) *Mockpublisher {
+	mock := &Mockpublisher{ctrl: ctrl}
+	mock.recorder = &MockpublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockpublisher) EXPECT() *MockpublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *Mockpublisher) Publish(ctx context.Context, event events.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", ctx, event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockpublisherMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*Mockpublisher)(nil).Publish), ctx, event)
+}
+
+// MockrevisionService is a mock of revisionService interface.
+type MockrevisionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockrevisionServiceMockRecorder
+}
+
+// MockrevisionServiceMockRecorder is the mock recorder for MockrevisionService.
+type MockrevisionServiceMockRecorder struct {
+	mock *MockrevisionService
+}
+
+// NewMockrevisionService creates a new mock instance.
+func NewMockrevisionService(ctrl *gomock.Controller) *MockrevisionService {
+	mock := &MockrevisionService{ctrl: ctrl}
+	mock.recorder = &MockrevisionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrevisionService) EXPECT() *MockrevisionServiceMockRecorder {
+	return m.recorder
+}
+
+// GetRevision mocks base method.
+func (m *MockrevisionService) GetRevision(ctx context.Context, entityType, code string, version int) (revisions.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, entityType, code, version)
+	ret0, _ := ret[0].(revisions.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockrevisionServiceMockRecorder) GetRevision(ctx, entityType, code, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockrevisionService)(nil).GetRevision), ctx, entityType, code, version)
+}
+
+// SaveRevision mocks base method.
+func (m *MockrevisionService) SaveRevision(ctx context.Context, entityType, code string, document interface{}) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRevision", ctx, entityType, code, document)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRevision indicates an expected call of SaveRevision.
+func (mr *MockrevisionServiceMockRecorder) SaveRevision(ctx, entityType, code, document interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevision", reflect.TypeOf((*MockrevisionService)(nil).SaveRevision), ctx, entityType, code, document)
+}
+
+// MockreferenceTracker is a mock of referenceTracker interface.
+type MockreferenceTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockreferenceTrackerMockRecorder
+}
+
+// MockreferenceTrackerMockRecorder is the mock recorder for MockreferenceTracker.
+type MockreferenceTrackerMockRecorder struct {
+	mock *MockreferenceTracker
+}
+
+// NewMockreferenceTracker creates a new mock instance.
+func NewMockreferenceTracker(ctrl *gomock.Controller) *MockreferenceTracker {
+	mock := &MockreferenceTracker{ctrl: ctrl}
+	mock.recorder = &MockreferenceTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockreferenceTracker) EXPECT() *MockreferenceTrackerMockRecorder {
+	return m.recorder
+}
+
+// GetLessonsReferencingExercise mocks base method.
+func (m *MockreferenceTracker) GetLessonsReferencingExercise(ctx context.Context, exerciseCode string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLessonsReferencingExercise", ctx, exerciseCode)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLessonsReferencingExercise indicates an expected call of GetLessonsReferencingExercise.
+func (mr *MockreferenceTrackerMockRecorder) GetLessonsReferencingExercise(ctx, exerciseCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLessonsReferencingExercise", reflect.TypeOf((*MockreferenceTracker)(nil).GetLessonsReferencingExercise), ctx, exerciseCode)
+}
+
+// UnlinkExercise mocks base method.
+func (m *MockreferenceTracker) UnlinkExercise(ctx context.Context, exerciseCode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkExercise", ctx, exerciseCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlinkExercise indicates an expected call of UnlinkExercise.
+func (mr *MockreferenceTrackerMockRecorder) UnlinkExercise(ctx, exerciseCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkExercise", reflect.TypeOf((*MockreferenceTracker)(nil).UnlinkExercise), ctx, exerciseCode)
+}
+
+// MockmediaService is a mock of mediaService interface.
+type MockmediaService struct {
+	ctrl     *gomock.Controller
+	recorder *MockmediaServiceMockRecorder
+}
+
+// MockmediaServiceMockRecorder is the mock recorder for MockmediaService.
+type MockmediaServiceMockRecorder struct {
+	mock *MockmediaService
+}
+
+// NewMockmediaService creates a new mock instance.
+func NewMockmediaService(ctrl *gomock.Controller) *MockmediaService {
+	mock := &MockmediaService{ctrl: ctrl}
+	mock.recorder = &MockmediaServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmediaService) EXPECT() *MockmediaServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAssets mocks base method.
+func (m *MockmediaService) GetAssets(ctx context.Context, ids []string) ([]media.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssets", ctx, ids)
+	ret0, _ := ret[0].([]media.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssets indicates an expected call of GetAssets.
+func (mr *MockmediaServiceMockRecorder) GetAssets(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssets", reflect.TypeOf((*MockmediaService)(nil).GetAssets), ctx, ids)
+}
@@ -5,10 +5,15 @@ import (
 	"errors"
 	"testing"
 	"time"
+	"uiren/internal/app/media"
+	"uiren/internal/app/publication"
+	"uiren/internal/app/revisions"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -111,7 +116,7 @@ func Test_exerciseService_DeleteExercise_success(t *testing.T) {
 
 	repo.EXPECT().deleteExercise(ctx, "test_code").Return(nil)
 
-	err := srv.DeleteExercise(ctx, "test_code")
+	err := srv.DeleteExercise(ctx, "test_code", false)
 	assert.NoError(t, err)
 }
 
@@ -127,7 +132,7 @@ func Test_exerciseService_DeleteExercise_repoError(t *testing.T) {
 
 	repo.EXPECT().deleteExercise(ctx, "test_code").Return(repoError)
 
-	err := srv.DeleteExercise(ctx, "test_code")
+	err := srv.DeleteExercise(ctx, "test_code", false)
 	assert.Error(t, err)
 	assert.Equal(t, repoError, err)
 }
@@ -421,7 +426,7 @@ func Test_exerciseService_UpdateExercise_success(t *testing.T) {
 			CorrectAnswer: &correctAnswer,
 		}
 		repo.EXPECT().getExerciseType(ctx, code).Return(multipleChoiceType, nil)
-		repo.EXPECT().updateExercise(ctx, code, dto).Return(nil)
+		repo.EXPECT().updateExercise(ctx, code, dto).Return(Exercise{}, nil)
 
 		err := srv.UpdateExercise(ctx, code, dto)
 		assert.NoError(t, err)
@@ -435,7 +440,7 @@ func Test_exerciseService_UpdateExercise_success(t *testing.T) {
 			CorrectAnswer: &correctAnswer,
 		}
 		repo.EXPECT().getExerciseType(ctx, code).Return(manualTypingType, nil)
-		repo.EXPECT().updateExercise(ctx, code, dto).Return(nil)
+		repo.EXPECT().updateExercise(ctx, code, dto).Return(Exercise{}, nil)
 
 		err := srv.UpdateExercise(ctx, code, dto)
 		assert.NoError(t, err)
@@ -449,7 +454,7 @@ func Test_exerciseService_UpdateExercise_success(t *testing.T) {
 			Pairs:       pairs,
 		}
 		repo.EXPECT().getExerciseType(ctx, code).Return(matchPairsType, nil)
-		repo.EXPECT().updateExercise(ctx, code, dto).Return(nil)
+		repo.EXPECT().updateExercise(ctx, code, dto).Return(Exercise{}, nil)
 
 		err := srv.UpdateExercise(ctx, code, dto)
 		assert.NoError(t, err)
@@ -464,7 +469,7 @@ func Test_exerciseService_UpdateExercise_success(t *testing.T) {
 			CorrectOrder: correctOrder,
 		}
 		repo.EXPECT().getExerciseType(ctx, code).Return(orderWordsType, nil)
-		repo.EXPECT().updateExercise(ctx, code, dto).Return(nil)
+		repo.EXPECT().updateExercise(ctx, code, dto).Return(Exercise{}, nil)
 
 		err := srv.UpdateExercise(ctx, code, dto)
 		assert.NoError(t, err)
@@ -599,7 +604,7 @@ func Test_exerciseService_UpdateExercise_fail(t *testing.T) {
 			CorrectAnswer: &correctAnswer,
 		}
 		repo.EXPECT().getExerciseType(ctx, code).Return(multipleChoiceType, nil)
-		repo.EXPECT().updateExercise(ctx, code, dto).Return(ErrNoFieldsToUpdate)
+		repo.EXPECT().updateExercise(ctx, code, dto).Return(Exercise{}, ErrNoFieldsToUpdate)
 
 		err := srv.UpdateExercise(ctx, code, dto)
 
@@ -724,3 +729,837 @@ func Test_exerciseService_ExerciseExists(t *testing.T) {
 		assert.False(t, exists)
 	})
 }
+
+func Test_exerciseService_RollbackExercise(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		repo        = NewMockrepository(ctrl)
+		revisionSrv = NewMockrevisionService(ctrl)
+		srv         = NewExerciseService(repo)
+		code        = "exercise1"
+		revision    = revisions.Revision{
+			EntityType: revisions.EntityExercise,
+			Code:       code,
+			Version:    1,
+			Snapshot: bson.M{
+				"code":           code,
+				"type":           manualTypingType,
+				"question":       "old question",
+				"correct_answer": "answer",
+			},
+		}
+		restored = Exercise{Code: code, ExerciseType: manualTypingType, Question: "old question", CorrectAnswer: "answer"}
+	)
+
+	t.Run("history not configured", func(t *testing.T) {
+		err := srv.RollbackExercise(ctx, code, 1)
+		assert.Equal(t, revisions.ErrHistoryNotAvailable, err)
+	})
+
+	srv.WithRevisionService(revisionSrv)
+
+	t.Run("success", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityExercise, code, 1).Return(revision, nil)
+		repo.EXPECT().restoreExercise(ctx, code, restored).Return(restored, nil)
+		revisionSrv.EXPECT().SaveRevision(ctx, revisions.EntityExercise, code, restored).Return(2, nil)
+
+		err := srv.RollbackExercise(ctx, code, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("exercise not found", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityExercise, code, 1).Return(revision, nil)
+		repo.EXPECT().restoreExercise(ctx, code, restored).Return(Exercise{}, ErrNotFound)
+
+		err := srv.RollbackExercise(ctx, code, 1)
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func Test_exerciseService_ValidateExercisesPublished(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx   = context.TODO()
+		ctrl  = gomock.NewController(t)
+		repo  = NewMockrepository(ctrl)
+		srv   = NewExerciseService(repo)
+		codes = []string{"ex1", "ex2"}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().getExercisesByCodes(ctx, codes).Return([]Exercise{
+			{Code: "ex1", Status: publication.StatusPublished},
+			{Code: "ex2", Status: publication.StatusPublished},
+		}, nil)
+
+		err := srv.ValidateExercisesPublished(ctx, codes)
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing exercise", func(t *testing.T) {
+		repo.EXPECT().getExercisesByCodes(ctx, codes).Return([]Exercise{
+			{Code: "ex1", Status: publication.StatusPublished},
+		}, nil)
+
+		err := srv.ValidateExercisesPublished(ctx, codes)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("exercise in review", func(t *testing.T) {
+		repo.EXPECT().getExercisesByCodes(ctx, codes).Return([]Exercise{
+			{Code: "ex1", Status: publication.StatusPublished},
+			{Code: "ex2", Status: publication.StatusInReview},
+		}, nil)
+
+		err := srv.ValidateExercisesPublished(ctx, codes)
+		assert.True(t, errors.Is(err, publication.ErrNotPublished))
+		assert.Contains(t, err.Error(), "ex2")
+	})
+}
+
+func Test_exerciseService_SetExerciseStatus(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx  = context.TODO()
+		ctrl = gomock.NewController(t)
+		repo = NewMockrepository(ctrl)
+		srv  = NewExerciseService(repo)
+		code = "ex1"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().getExercise(ctx, code).Return(Exercise{Code: code, Status: publication.StatusDraft}, nil)
+		repo.EXPECT().setExerciseStatus(ctx, code, publication.StatusDraft, publication.StatusInReview).Return(Exercise{}, nil)
+
+		err := srv.SetExerciseStatus(ctx, code, publication.StatusInReview)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		repo.EXPECT().getExercise(ctx, code).Return(Exercise{Code: code, Status: publication.StatusDraft}, nil)
+
+		err := srv.SetExerciseStatus(ctx, code, "hidden")
+		assert.Equal(t, publication.ErrInvalidStatus, err)
+	})
+
+	t.Run("draft exercise is hidden from learners", func(t *testing.T) {
+		repo.EXPECT().getExercise(ctx, code).Return(Exercise{Code: code, Status: publication.StatusDraft}, nil)
+
+		_, err := srv.GetPublishedExercise(ctx, code)
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func Test_exerciseService_DeleteExercise_referenced(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockrepository(ctrl)
+		tracker = NewMockreferenceTracker(ctrl)
+		srv     = NewExerciseService(repo)
+		code    = "test_code"
+		parents = []string{"lesson1", "lesson2"}
+	)
+	srv.WithReferenceTracker(tracker)
+
+	t.Run("refuse without cascade", func(t *testing.T) {
+		tracker.EXPECT().GetLessonsReferencingExercise(ctx, code).Return(parents, nil)
+
+		err := srv.DeleteExercise(ctx, code, false)
+		var referencedErr *ReferencedError
+		assert.True(t, errors.As(err, &referencedErr))
+		assert.Equal(t, parents, referencedErr.Parents)
+		assert.True(t, errors.Is(err, ErrReferenced))
+	})
+
+	t.Run("cascade unlinks lessons", func(t *testing.T) {
+		gomock.InOrder(
+			tracker.EXPECT().GetLessonsReferencingExercise(ctx, code).Return(parents, nil),
+			tracker.EXPECT().UnlinkExercise(ctx, code).Return(nil),
+			repo.EXPECT().deleteExercise(ctx, code).Return(nil),
+		)
+
+		err := srv.DeleteExercise(ctx, code, true)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not referenced", func(t *testing.T) {
+		tracker.EXPECT().GetLessonsReferencingExercise(ctx, code).Return(nil, nil)
+		repo.EXPECT().deleteExercise(ctx, code).Return(nil)
+
+		err := srv.DeleteExercise(ctx, code, false)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_exerciseService_ListExercises(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx       = context.TODO()
+		ctrl      = gomock.NewController(t)
+		repo      = NewMockrepository(ctrl)
+		service   = &ExerciseService{repo: repo}
+		createdAt = time.Date(2025, 5, 17, 10, 0, 0, 0, time.UTC)
+		filter    = ListFilter{Type: manualTypingType}
+		params    = pagination.Params{Limit: 2, SortBy: "created_at", Order: pagination.OrderDesc}
+		exercises = []Exercise{
+			{Code: "ex3", CreatedAt: createdAt.Add(time.Hour)},
+			{Code: "ex2", CreatedAt: createdAt},
+			{Code: "ex1", CreatedAt: createdAt},
+		}
+		repoErr = errors.New("repo error")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().listExercises(ctx, filter, params).Return(exercises, nil)
+
+		page, err := service.ListExercises(ctx, filter, params)
+		assert.NoError(t, err)
+		assert.Equal(t, exercises[:2], page.Items)
+
+		cursor, err := pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, "ex2", cursor.Key)
+		value, err := cursor.TimeValue()
+		assert.NoError(t, err)
+		assert.True(t, createdAt.Equal(value))
+	})
+
+	t.Run("repo failed", func(t *testing.T) {
+		repo.EXPECT().listExercises(ctx, filter, params).Return(nil, repoErr)
+
+		_, err := service.ListExercises(ctx, filter, params)
+		assert.Equal(t, repoErr, err)
+	})
+}
+
+func Test_exerciseService_CreateExercise_media(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx          = context.TODO()
+		ctrl         = gomock.NewController(t)
+		repo         = NewMockrepository(ctrl)
+		mediaService = NewMockmediaService(ctrl)
+		srv          = NewExerciseService(repo)
+
+		correctAnswer = "cat"
+		image         = media.Asset{ID: "image-id", Kind: media.KindImage}
+		audio         = media.Asset{ID: "audio-id", Kind: media.KindAudio}
+	)
+	srv.WithMediaService(mediaService)
+
+	t.Run("image, audio and option media", func(t *testing.T) {
+		dto := CreateExerciseDTO{
+			Code:          "listen",
+			ExerciseType:  multipleChoiceType,
+			Options:       []string{"cat", "dog"},
+			CorrectAnswer: &correctAnswer,
+			ImageID:       image.ID,
+			AudioID:       audio.ID,
+			OptionMedia:   []string{image.ID, ""},
+		}
+		mediaService.EXPECT().GetAssets(ctx, []string{image.ID, audio.ID, image.ID, ""}).Return([]media.Asset{image, audio}, nil)
+		repo.EXPECT().createExercise(ctx, createExerciseDTOMatcher{dto: dto}).Return(primitive.ObjectID{}, nil)
+
+		_, err := srv.CreateExercise(ctx, dto)
+		assert.NoError(t, err)
+	})
+
+	t.Run("audio as image", func(t *testing.T) {
+		dto := CreateExerciseDTO{
+			Code:          "listen",
+			ExerciseType:  manualTypingType,
+			CorrectAnswer: &correctAnswer,
+			ImageID:       audio.ID,
+		}
+		mediaService.EXPECT().GetAssets(ctx, []string{audio.ID, ""}).Return([]media.Asset{audio}, nil)
+
+		_, err := srv.CreateExercise(ctx, dto)
+		assert.ErrorIs(t, err, media.ErrWrongKind)
+	})
+
+	t.Run("missing asset", func(t *testing.T) {
+		dto := CreateExerciseDTO{
+			Code:          "listen",
+			ExerciseType:  manualTypingType,
+			CorrectAnswer: &correctAnswer,
+			AudioID:       "missing",
+		}
+		mediaService.EXPECT().GetAssets(ctx, []string{"", "missing"}).Return(nil, media.ErrAssetNotFound)
+
+		_, err := srv.CreateExercise(ctx, dto)
+		assert.ErrorIs(t, err, media.ErrAssetNotFound)
+	})
+
+	t.Run("option media without options", func(t *testing.T) {
+		dto := CreateExerciseDTO{
+			Code:          "listen",
+			ExerciseType:  manualTypingType,
+			CorrectAnswer: &correctAnswer,
+			OptionMedia:   []string{image.ID},
+		}
+
+		_, err := srv.CreateExercise(ctx, dto)
+		assert.Equal(t, ErrOptionMediaMismatch, err)
+	})
+}
+
+func Test_exerciseService_UpdateExercise_media(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx          = context.TODO()
+		ctrl         = gomock.NewController(t)
+		repo         = NewMockrepository(ctrl)
+		mediaService = NewMockmediaService(ctrl)
+		srv          = NewExerciseService(repo)
+
+		correctAnswer = "cat"
+		detach        = ""
+		audioID       = "audio-id"
+	)
+	srv.WithMediaService(mediaService)
+
+	t.Run("replace audio and detach image", func(t *testing.T) {
+		dto := UpdateExerciseDTO{
+			CorrectAnswer: &correctAnswer,
+			ImageID:       &detach,
+			AudioID:       &audioID,
+		}
+		repo.EXPECT().getExerciseType(ctx, "code").Return(manualTypingType, nil)
+		mediaService.EXPECT().GetAssets(ctx, []string{"", audioID}).Return([]media.Asset{{ID: audioID, Kind: media.KindAudio}}, nil)
+		repo.EXPECT().updateExercise(ctx, "code", dto).Return(Exercise{}, nil)
+
+		assert.NoError(t, srv.UpdateExercise(ctx, "code", dto))
+	})
+
+	t.Run("option media does not match options", func(t *testing.T) {
+		dto := UpdateExerciseDTO{
+			Options:       []string{"cat", "dog"},
+			CorrectAnswer: &correctAnswer,
+			OptionMedia:   []string{audioID},
+		}
+		repo.EXPECT().getExerciseType(ctx, "code").Return(multipleChoiceType, nil)
+
+		assert.Equal(t, ErrOptionMediaMismatch, srv.UpdateExercise(ctx, "code", dto))
+	})
+}
+
+func Test_Exercise_ResolveMedia(t *testing.T) {
+	t.Parallel()
+
+	exercise := Exercise{
+		ImageID:     "image-id",
+		OptionMedia: []string{"", "audio-id"},
+		Media:       &ExerciseMedia{AudioURL: "stale"},
+	}
+	exercise.ResolveMedia(map[string]string{"image-id": "https://cdn/image.png", "audio-id": "https://cdn/audio.mp3"})
+
+	assert.Equal(t, []string{"image-id", "audio-id"}, exercise.MediaIDs())
+	assert.Equal(t, &ExerciseMedia{
+		ImageURL:   "https://cdn/image.png",
+		OptionURLs: []string{"", "https://cdn/audio.mp3"},
+	}, exercise.Media)
+
+	text := Exercise{Media: &ExerciseMedia{ImageURL: "stale"}}
+	text.ResolveMedia(nil)
+	assert.Nil(t, text.Media)
+}
+
+func Test_exerciseService_CreateExercise_newTypes(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx    = context.TODO()
+		ctrl   = gomock.NewController(t)
+		repo   = NewMockrepository(ctrl)
+		srv    = NewExerciseService(repo)
+		isTrue = true
+	)
+
+	t.Run("fill in blank with several blanks", func(t *testing.T) {
+		dto := CreateExerciseDTO{
+			Code:         "blanks",
+			ExerciseType: fillInBlankType,
+			Question:     "Менің ___ Аян, мен ___ тұрамын",
+			Blanks:       [][]string{{" атым ", "атым", ""}, {"Алматыда", "Астанада"}},
+		}
+		repo.EXPECT().createExercise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, created CreateExerciseDTO) (primitive.ObjectID, error) {
+			assert.Equal(t, [][]string{{"атым"}, {"Алматыда", "Астанада"}}, created.Blanks)
+			return primitive.NilObjectID, nil
+		})
+
+		_, err := srv.CreateExercise(ctx, dto)
+		assert.NoError(t, err)
+	})
+
+	t.Run("translate sentence with several translations", func(t *testing.T) {
+		dto := CreateExerciseDTO{
+			Code:           "translate",
+			ExerciseType:   translateSentenceType,
+			Question:       "Меня зовут Аян",
+			CorrectAnswers: []string{"Менің атым Аян", "Мен Аянмын"},
+		}
+		repo.EXPECT().createExercise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, created CreateExerciseDTO) (primitive.ObjectID, error) {
+			assert.Equal(t, dto.CorrectAnswers, created.CorrectAnswers)
+			return primitive.NilObjectID, nil
+		})
+
+		_, err := srv.CreateExercise(ctx, dto)
+		assert.NoError(t, err)
+	})
+
+	t.Run("true false", func(t *testing.T) {
+		dto := CreateExerciseDTO{Code: "tf", ExerciseType: trueFalseType, Question: "'Ит' означает собаку", IsTrue: &isTrue}
+		repo.EXPECT().createExercise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, created CreateExerciseDTO) (primitive.ObjectID, error) {
+			assert.Equal(t, &isTrue, created.IsTrue)
+			return primitive.NilObjectID, nil
+		})
+
+		_, err := srv.CreateExercise(ctx, dto)
+		assert.NoError(t, err)
+	})
+
+	for name, test := range map[string]struct {
+		dto CreateExerciseDTO
+		err error
+	}{
+		"blanks missing":        {dto: CreateExerciseDTO{ExerciseType: fillInBlankType, Question: "___"}, err: ErrBlanksRequired},
+		"blank without answers": {dto: CreateExerciseDTO{ExerciseType: fillInBlankType, Question: "___ ___", Blanks: [][]string{{"a"}, {" "}}}, err: ErrBlanksRequired},
+		"blanks mismatch":       {dto: CreateExerciseDTO{ExerciseType: fillInBlankType, Question: "___", Blanks: [][]string{{"a"}, {"b"}}}, err: ErrBlanksMismatch},
+		"no translations":       {dto: CreateExerciseDTO{ExerciseType: translateSentenceType, CorrectAnswers: []string{""}}, err: ErrCorrectAnswersRequired},
+		"no is_true":            {dto: CreateExerciseDTO{ExerciseType: trueFalseType}, err: ErrIsTrueRequired},
+		"listen without audio":  {dto: CreateExerciseDTO{ExerciseType: listenAndTypeType, CorrectAnswers: []string{"сәлем"}}, err: ErrAudioRequired},
+		"listen without answer": {dto: CreateExerciseDTO{ExerciseType: listenAndTypeType, AudioID: "audio-id"}, err: ErrCorrectAnswersRequired},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := srv.CreateExercise(ctx, test.dto)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func Test_exerciseService_GradeAnswer(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx    = context.TODO()
+		ctrl   = gomock.NewController(t)
+		repo   = NewMockrepository(ctrl)
+		srv    = NewExerciseService(repo)
+		isTrue = true
+		no     = false
+	)
+
+	for name, test := range map[string]struct {
+		exercise Exercise
+		answer   Answer
+		correct  bool
+	}{
+		"multiple choice": {
+			exercise: Exercise{ExerciseType: multipleChoiceType, Options: []string{"Сәлем", "Рақмет"}, CorrectAnswer: "Рақмет"},
+			answer:   Answer{Text: "Рақмет"},
+			correct:  true,
+		},
+		"manual typing ignores case and spaces": {
+			exercise: Exercise{ExerciseType: manualTypingType, CorrectAnswer: "Қайырлы таң"},
+			answer:   Answer{Text: "  қайырлы   таң "},
+			correct:  true,
+		},
+		"order words": {
+			exercise: Exercise{ExerciseType: orderWordsType, CorrectOrder: []string{"Менің", "атым", "Аян"}},
+			answer:   Answer{Order: []string{"атым", "Менің", "Аян"}},
+		},
+		"match pairs in any order": {
+			exercise: Exercise{ExerciseType: matchPairsType, Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Sun"}}},
+			answer:   Answer{Pairs: []Pair{{Term: "Күн", Match: "Sun"}, {Term: "Ит", Match: "Dog"}}},
+			correct:  true,
+		},
+		"match pairs repeated term": {
+			exercise: Exercise{ExerciseType: matchPairsType, Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Sun"}}},
+			answer:   Answer{Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Ит", Match: "Dog"}}},
+		},
+		"fill in blank": {
+			exercise: Exercise{ExerciseType: fillInBlankType, Blanks: [][]string{{"атым"}, {"Алматыда", "Астанада"}}},
+			answer:   Answer{Blanks: []string{"атым", "астанада"}},
+			correct:  true,
+		},
+		"fill in blank missing blank": {
+			exercise: Exercise{ExerciseType: fillInBlankType, Blanks: [][]string{{"атым"}, {"Алматыда"}}},
+			answer:   Answer{Blanks: []string{"атым"}},
+		},
+		"translate sentence any translation": {
+			exercise: Exercise{ExerciseType: translateSentenceType, CorrectAnswers: []string{"Менің атым Аян", "Мен Аянмын"}},
+			answer:   Answer{Text: "мен аянмын"},
+			correct:  true,
+		},
+		"listen and type": {
+			exercise: Exercise{ExerciseType: listenAndTypeType, CorrectAnswers: []string{"сәлем"}},
+			answer:   Answer{Text: "сау бол"},
+		},
+		"true false": {
+			exercise: Exercise{ExerciseType: trueFalseType, IsTrue: &isTrue},
+			answer:   Answer{Value: &no},
+		},
+		"true false without answer": {
+			exercise: Exercise{ExerciseType: trueFalseType, IsTrue: &isTrue},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			exercise := test.exercise
+			exercise.Code = name
+			exercise.Status = publication.StatusPublished
+			exercise.Explanation = "explanation"
+			repo.EXPECT().getExercise(ctx, name).Return(exercise, nil)
+
+			result, err := srv.GradeAnswer(ctx, name, test.answer)
+			assert.NoError(t, err)
+			assert.Equal(t, test.correct, result.Correct)
+			assert.Equal(t, "explanation", result.Explanation)
+			if test.correct {
+				assert.Equal(t, 1.0, result.Score)
+			}
+		})
+	}
+
+	t.Run("draft exercise", func(t *testing.T) {
+		repo.EXPECT().getExercise(ctx, "draft").Return(Exercise{Code: "draft", Status: publication.StatusDraft}, nil)
+
+		_, err := srv.GradeAnswer(ctx, "draft", Answer{})
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func Test_exerciseService_GradeAnswer_partialCredit(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx  = context.TODO()
+		ctrl = gomock.NewController(t)
+		repo = NewMockrepository(ctrl)
+		srv  = NewExerciseService(repo)
+	)
+
+	for name, test := range map[string]struct {
+		exercise Exercise
+		answer   Answer
+		expected GradeResult
+	}{
+		"match pairs one of two": {
+			exercise: Exercise{ExerciseType: matchPairsType, Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Sun"}}},
+			answer:   Answer{Pairs: []Pair{{Term: "Ит", Match: "dog"}, {Term: "Күн", Match: "Book"}}},
+			expected: GradeResult{Score: 0.5, Elements: []ElementResult{
+				{Text: "Ит", Correct: true},
+				{Text: "Күн", Expected: "Sun"},
+			}},
+		},
+		"match pairs repeated and missing terms": {
+			exercise: Exercise{ExerciseType: matchPairsType, Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Sun"}, {Term: "Кітап", Match: "Book"}, {Term: "Су", Match: "Water"}}},
+			answer:   Answer{Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Sun"}}},
+			expected: GradeResult{Score: 0.5, Elements: []ElementResult{
+				{Text: "Ит", Correct: true},
+				{Text: "Ит"},
+				{Text: "Күн", Correct: true},
+			}},
+		},
+		"order words swapped": {
+			exercise: Exercise{ExerciseType: orderWordsType, CorrectOrder: []string{"Менің", "атым", "Аян"}},
+			answer:   Answer{Order: []string{"атым", "Менің", "Аян"}},
+			expected: GradeResult{Score: 2.0 / 3, Elements: []ElementResult{
+				{Text: "атым", Correct: true},
+				{Text: "Менің", Expected: "атым"},
+				{Text: "Аян", Correct: true},
+			}},
+		},
+		"order words extra word": {
+			exercise: Exercise{ExerciseType: orderWordsType, CorrectOrder: []string{"Мен", "мектепке", "барамын"}},
+			answer:   Answer{Order: []string{"Мен", "үйге", "мектепке", "барамын"}},
+			expected: GradeResult{Score: 0.75, Elements: []ElementResult{
+				{Text: "Мен", Correct: true},
+				{Text: "үйге", Expected: "мектепке"},
+				{Text: "мектепке", Correct: true},
+				{Text: "барамын", Correct: true},
+			}},
+		},
+		"order words correct": {
+			exercise: Exercise{ExerciseType: orderWordsType, CorrectOrder: []string{"Менің", "атым", "Аян"}},
+			answer:   Answer{Order: []string{"менің", "атым", "Аян"}},
+			expected: GradeResult{Correct: true, Score: 1, Elements: []ElementResult{
+				{Text: "менің", Correct: true},
+				{Text: "атым", Correct: true},
+				{Text: "Аян", Correct: true},
+			}},
+		},
+		"order words without answer": {
+			exercise: Exercise{ExerciseType: orderWordsType, CorrectOrder: []string{"Менің", "атым", "Аян"}},
+			expected: GradeResult{Elements: []ElementResult{}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			exercise := test.exercise
+			exercise.Code = name
+			exercise.Status = publication.StatusPublished
+			repo.EXPECT().getExercise(ctx, name).Return(exercise, nil)
+
+			result, err := srv.GradeAnswer(ctx, name, test.answer)
+			assert.NoError(t, err)
+			assert.InDelta(t, test.expected.Score, result.Score, 1e-9)
+			result.Score = test.expected.Score
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func Test_exerciseService_GradeLesson(t *testing.T) {
+	t.Parallel()
+	var (
+		ctrl      = gomock.NewController(t)
+		srv       = NewExerciseService(NewMockrepository(ctrl))
+		exercises = []Exercise{
+			{Code: "ex-1", ExerciseType: multipleChoiceType, Options: []string{"Сәлем", "Рақмет"}, CorrectAnswer: "Рақмет"},
+			{Code: "ex-2", ExerciseType: matchPairsType, Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Sun"}}},
+			{Code: "ex-3", ExerciseType: manualTypingType, CorrectAnswer: "Қайырлы таң"},
+		}
+		answers = map[string]Answer{
+			"ex-1": {Text: "Рақмет"},
+			"ex-2": {Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Dog"}}},
+		}
+	)
+
+	t.Run("xp weighted by score", func(t *testing.T) {
+		grade, err := srv.GradeLesson(exercises, answers)
+		assert.NoError(t, err)
+		assert.Len(t, grade.Results, 3)
+		assert.Equal(t, "ex-2", grade.Results[1].Code)
+		assert.Equal(t, 0.5, grade.Results[1].Score)
+		assert.False(t, grade.Results[2].Correct)
+		assert.Equal(t, 0.5, grade.Score)
+		assert.Equal(t, 15, grade.XP)
+	})
+
+	t.Run("exercise xp", func(t *testing.T) {
+		srv := NewExerciseService(NewMockrepository(ctrl))
+		srv.SetExerciseXP(5)
+
+		grade, err := srv.GradeLesson(exercises, answers)
+		assert.NoError(t, err)
+		assert.Equal(t, 8, grade.XP)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := srv.GradeLesson([]Exercise{{Code: "ex-4", ExerciseType: "dictation"}}, nil)
+		assert.Equal(t, ErrIncorrectType, err)
+	})
+}
+
+// spellingType is a custom exercise type registered in tests
+type spellingType struct {
+	manualTyping
+}
+
+func (spellingType) Type() string { return "spelling" }
+
+func (spellingType) Schema() Schema {
+	return Schema{Type: "spelling", Fields: []SchemaField{{Name: "correct_answer", Kind: kindString, Required: true}}}
+}
+
+func (spellingType) Grade(exercise Exercise, answer Answer) GradeResult {
+	return GradeResult{Correct: answer.Text == exercise.CorrectAnswer}
+}
+
+func Test_exerciseService_RegisterType(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx           = context.TODO()
+		ctrl          = gomock.NewController(t)
+		repo          = NewMockrepository(ctrl)
+		srv           = NewExerciseService(repo)
+		correctAnswer = "Қазақстан"
+	)
+	srv.RegisterType(spellingType{})
+
+	t.Run("schemas keep the registration order", func(t *testing.T) {
+		schemas := srv.GetTypeSchemas()
+		assert.Len(t, schemas, 9)
+		assert.Equal(t, multipleChoiceType, schemas[0].Type)
+		assert.Equal(t, "spelling", schemas[8].Type)
+	})
+
+	t.Run("create custom type", func(t *testing.T) {
+		dto := CreateExerciseDTO{Code: "spelling", ExerciseType: "spelling", CorrectAnswer: &correctAnswer, Options: []string{"dropped"}}
+		repo.EXPECT().createExercise(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, created CreateExerciseDTO) (primitive.ObjectID, error) {
+			assert.Equal(t, &correctAnswer, created.CorrectAnswer)
+			assert.Nil(t, created.Options)
+			return primitive.NilObjectID, nil
+		})
+
+		_, err := srv.CreateExercise(ctx, dto)
+		assert.NoError(t, err)
+	})
+
+	t.Run("grade custom type", func(t *testing.T) {
+		repo.EXPECT().getExercise(ctx, "spelling").Return(Exercise{Code: "spelling", ExerciseType: "spelling", CorrectAnswer: correctAnswer, Status: publication.StatusPublished}, nil)
+
+		result, err := srv.GradeAnswer(ctx, "spelling", Answer{Text: "қазақстан"})
+		assert.NoError(t, err)
+		assert.False(t, result.Correct)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := srv.ToLearner(Exercise{ExerciseType: "dictation"})
+		assert.Equal(t, ErrIncorrectType, err)
+	})
+}
+
+func Test_exerciseService_ToLearner(t *testing.T) {
+	t.Parallel()
+	var (
+		ctrl = gomock.NewController(t)
+		srv  = NewExerciseService(NewMockrepository(ctrl))
+	)
+
+	for name, test := range map[string]struct {
+		exercise Exercise
+		expected LearnerExercise
+	}{
+		"multiple choice": {
+			exercise: Exercise{ExerciseType: multipleChoiceType, Options: []string{"Сәлем", "Рақмет"}, CorrectAnswer: "Рақмет"},
+			expected: LearnerExercise{Type: multipleChoiceType, Options: []string{"Сәлем", "Рақмет"}},
+		},
+		"match pairs": {
+			exercise: Exercise{ExerciseType: matchPairsType, Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Sun"}, {Term: "Кітап", Match: "Book"}}},
+			expected: LearnerExercise{Type: matchPairsType, Terms: []string{"Ит", "Күн", "Кітап"}, Matches: []string{"Book", "Dog", "Sun"}},
+		},
+		"fill in blank": {
+			exercise: Exercise{ExerciseType: fillInBlankType, Blanks: [][]string{{"атым"}, {"Алматыда"}}, Options: []string{"атым", "Алматыда"}},
+			expected: LearnerExercise{Type: fillInBlankType, Blanks: 2, Options: []string{"атым", "Алматыда"}},
+		},
+		"listen and type keeps media": {
+			exercise: Exercise{ExerciseType: listenAndTypeType, CorrectAnswers: []string{"сәлем"}, Media: &ExerciseMedia{AudioURL: "https://cdn/a.mp3"}},
+			expected: LearnerExercise{Type: listenAndTypeType, Media: &ExerciseMedia{AudioURL: "https://cdn/a.mp3"}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			learner, err := srv.ToLearner(test.exercise)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, learner)
+		})
+	}
+}
+
+func Test_exerciseService_PreviewExercise(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx    = context.TODO()
+		ctrl   = gomock.NewController(t)
+		repo   = NewMockrepository(ctrl)
+		srv    = NewExerciseService(repo)
+		isTrue = false
+	)
+
+	for name, test := range map[string]struct {
+		exercise Exercise
+		solution string
+	}{
+		"fill in blank": {
+			exercise: Exercise{ExerciseType: fillInBlankType, Question: "Менің ___ Аян, мен ___ тұрамын", Blanks: [][]string{{"атым"}, {"Алматыда", "Астанада"}}},
+			solution: "Менің [атым] Аян, мен [Алматыда / Астанада] тұрамын",
+		},
+		"match pairs": {
+			exercise: Exercise{ExerciseType: matchPairsType, Pairs: []Pair{{Term: "Ит", Match: "Dog"}, {Term: "Күн", Match: "Sun"}}},
+			solution: "Ит — Dog, Күн — Sun",
+		},
+		"order words": {
+			exercise: Exercise{ExerciseType: orderWordsType, CorrectOrder: []string{"Менің", "атым", "Аян"}},
+			solution: "Менің атым Аян",
+		},
+		"true false": {
+			exercise: Exercise{ExerciseType: trueFalseType, IsTrue: &isTrue},
+			solution: "false",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			exercise := test.exercise
+			exercise.Code = name
+			exercise.Status = publication.StatusDraft
+			repo.EXPECT().getExercise(ctx, name).Return(exercise, nil)
+
+			preview, err := srv.PreviewExercise(ctx, name)
+			assert.NoError(t, err)
+			assert.Equal(t, test.solution, preview.Solution)
+			assert.Equal(t, name, preview.Learner.Code)
+		})
+	}
+}
+
+func Test_exerciseService_GradeAnswer_feedback(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx      = context.TODO()
+		ctrl     = gomock.NewController(t)
+		repo     = NewMockrepository(ctrl)
+		srv      = NewExerciseService(repo)
+		strict   = NewExerciseService(repo)
+		exercise = Exercise{
+			Code:           "typing",
+			ExerciseType:   manualTypingType,
+			CorrectAnswer:  "Қайырлы таң!",
+			CorrectAnswers: []string{"Қайырлы таңыңыз"},
+			Status:         publication.StatusPublished,
+		}
+	)
+	strict.SetMatcherConfig(MatcherConfig{})
+
+	for name, test := range map[string]struct {
+		srv      *ExerciseService
+		text     string
+		correct  bool
+		feedback *Feedback
+	}{
+		"punctuation and case":   {srv: srv, text: "қайырлы  таң", correct: true},
+		"other accepted answer":  {srv: srv, text: "Қайырлы таңыңыз.", correct: true},
+		"without diacritics":     {srv: srv, text: "Кайырлы тан", correct: true, feedback: &Feedback{Kind: FeedbackDiacritics, Expected: "Қайырлы таң!"}},
+		"typo":                   {srv: srv, text: "Қайрлы таң", correct: true, feedback: &Feedback{Kind: FeedbackTypo, Expected: "Қайырлы таң!"}},
+		"typo without diacritic": {srv: srv, text: "Каирлы тан", correct: true, feedback: &Feedback{Kind: FeedbackTypo, Expected: "Қайырлы таң!"}},
+		"two typos":              {srv: srv, text: "Қарлы тан", correct: false},
+		"strict diacritics":      {srv: strict, text: "Кайырлы тан", correct: false},
+		"strict typo":            {srv: strict, text: "Қайрлы таң", correct: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo.EXPECT().getExercise(ctx, exercise.Code).Return(exercise, nil)
+
+			result, err := test.srv.GradeAnswer(ctx, exercise.Code, Answer{Text: test.text})
+			assert.NoError(t, err)
+			assert.Equal(t, test.correct, result.Correct)
+			assert.Equal(t, test.feedback, result.Feedback)
+		})
+	}
+}
+
+func Test_Matcher(t *testing.T) {
+	t.Parallel()
+	matcher := NewMatcher(DefaultMatcherConfig)
+
+	for name, test := range map[string]struct {
+		text     string
+		accepted []string
+		correct  bool
+		feedback *Feedback
+	}{
+		"turkish":              {text: "Gunaydin", accepted: []string{"Günaydın"}, correct: true, feedback: &Feedback{Kind: FeedbackDiacritics, Expected: "Günaydın"}},
+		"turkish capital i":    {text: "istanbul", accepted: []string{"İstanbul"}, correct: true},
+		"decomposed letters":   {text: "u\u0308", accepted: []string{"ü"}, correct: true, feedback: &Feedback{Kind: FeedbackDiacritics, Expected: "ü"}},
+		"russian yo":           {text: "еж", accepted: []string{"ёж"}, correct: true, feedback: &Feedback{Kind: FeedbackDiacritics, Expected: "ёж"}},
+		"short words no typos": {text: "ит", accepted: []string{"от"}},
+		"closest answer":       {text: "Мен Аянмн", accepted: []string{"Менің атым Аян", "Мен Аянмын"}, correct: true, feedback: &Feedback{Kind: FeedbackTypo, Expected: "Мен Аянмын"}},
+		"exact beats typo":     {text: "кітап", accepted: []string{"кітаб", "кітап"}, correct: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			correct, feedback := matcher.Match(test.text, test.accepted)
+			assert.Equal(t, test.correct, correct)
+			assert.Equal(t, test.feedback, feedback)
+		})
+	}
+
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 1, levenshtein("таң", "тан"))
+}
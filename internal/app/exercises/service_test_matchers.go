@@ -42,6 +42,9 @@ func (m createExerciseDTOMatcher) Matches(x interface{}) bool {
 	if dto.Question != dto.Question {
 		return false
 	}
+	if dto.ImageID != m.dto.ImageID || dto.AudioID != m.dto.AudioID || !slices.Equal(dto.OptionMedia, m.dto.OptionMedia) {
+		return false
+	}
 
 	return true
 }
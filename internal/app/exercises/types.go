@@ -0,0 +1,424 @@
+package exercises
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	multipleChoiceType = "multiple_choice"
+	manualTypingType   = "manual_typing"
+	matchPairsType     = "match_pairs"
+	orderWordsType     = "order_words"
+
+	fillInBlankType       = "fill_in_blank"
+	translateSentenceType = "translate_sentence"
+	trueFalseType         = "true_false"
+	listenAndTypeType     = "listen_and_type"
+)
+
+// blankMarker marks a blank in the question of fill_in_blank
+const blankMarker = "___"
+
+// schema field kinds
+const (
+	kindString  = "string"
+	kindStrings = "strings"
+	kindPairs   = "pairs"
+	kindBlanks  = "blanks"
+	kindBool    = "bool"
+	kindAudio   = "audio"
+)
+
+// builtinTypes share the matcher of typed answers
+func builtinTypes(matcher *Matcher) []TypeHandler {
+	return []TypeHandler{
+		multipleChoice{},
+		manualTyping{matcher: matcher},
+		matchPairs{},
+		orderWords{},
+		fillInBlank{matcher: matcher},
+		translateSentence{matcher: matcher},
+		trueFalse{},
+		listenAndType{translateSentence{matcher: matcher}},
+	}
+}
+
+type multipleChoice struct{}
+
+func (multipleChoice) Type() string { return multipleChoiceType }
+
+func (multipleChoice) Schema() Schema {
+	return Schema{Type: multipleChoiceType, Fields: []SchemaField{
+		{Name: "options", Kind: kindStrings, Required: true},
+		{Name: "correct_answer", Kind: kindString, Required: true, Description: "one of options"},
+	}}
+}
+
+func (multipleChoice) Normalize(fields Fields) (Fields, error) {
+	if fields.Options == nil {
+		return Fields{}, ErrOptionsRequired
+	}
+	if fields.CorrectAnswer == nil {
+		return Fields{}, ErrCorrectAnswerRequired
+	}
+	return Fields{Options: fields.Options, CorrectAnswer: fields.CorrectAnswer}, nil
+}
+
+func (multipleChoice) Project(exercise Exercise, learner *LearnerExercise) {
+	learner.Options = exercise.Options
+}
+
+func (multipleChoice) Grade(exercise Exercise, answer Answer) GradeResult {
+	return GradeResult{Correct: sameText(answer.Text, exercise.CorrectAnswer)}
+}
+
+func (multipleChoice) Solution(exercise Exercise) string { return exercise.CorrectAnswer }
+
+type manualTyping struct {
+	matcher *Matcher
+}
+
+func (manualTyping) Type() string { return manualTypingType }
+
+func (manualTyping) Schema() Schema {
+	return Schema{Type: manualTypingType, Fields: []SchemaField{
+		{Name: "correct_answer", Kind: kindString, Required: true},
+		{Name: "correct_answers", Kind: kindStrings, Description: "other accepted answers"},
+	}}
+}
+
+func (manualTyping) Normalize(fields Fields) (Fields, error) {
+	if fields.CorrectAnswer == nil {
+		return Fields{}, ErrCorrectAnswerRequired
+	}
+
+	normalized := Fields{CorrectAnswer: fields.CorrectAnswer}
+	if fields.CorrectAnswers != nil {
+		normalized.CorrectAnswers = acceptedAnswers(fields.CorrectAnswers)
+	}
+	return normalized, nil
+}
+
+func (manualTyping) Project(Exercise, *LearnerExercise) {}
+
+func (t manualTyping) Grade(exercise Exercise, answer Answer) GradeResult {
+	accepted := append([]string{exercise.CorrectAnswer}, exercise.CorrectAnswers...)
+	correct, feedback := t.matcher.Match(answer.Text, accepted)
+	return GradeResult{Correct: correct, Feedback: feedback}
+}
+
+func (manualTyping) Solution(exercise Exercise) string { return exercise.CorrectAnswer }
+
+type matchPairs struct{}
+
+func (matchPairs) Type() string { return matchPairsType }
+
+func (matchPairs) Schema() Schema {
+	return Schema{Type: matchPairsType, Fields: []SchemaField{
+		{Name: "pairs", Kind: kindPairs, Required: true},
+	}}
+}
+
+func (matchPairs) Normalize(fields Fields) (Fields, error) {
+	if fields.Pairs == nil {
+		return Fields{}, ErrPairsRequired
+	}
+	return Fields{Pairs: fields.Pairs}, nil
+}
+
+func (matchPairs) Project(exercise Exercise, learner *LearnerExercise) {
+	for _, pair := range exercise.Pairs {
+		learner.Terms = append(learner.Terms, pair.Term)
+		learner.Matches = append(learner.Matches, pair.Match)
+	}
+	sort.Strings(learner.Matches)
+}
+
+// Grade gives credit for every term matched correctly, a repeated term is wrong
+func (matchPairs) Grade(exercise Exercise, answer Answer) GradeResult {
+	if len(exercise.Pairs) == 0 {
+		return GradeResult{}
+	}
+
+	matches := make(map[string]string, len(exercise.Pairs))
+	for _, pair := range exercise.Pairs {
+		matches[pair.Term] = pair.Match
+	}
+
+	result := GradeResult{Elements: make([]ElementResult, 0, len(answer.Pairs))}
+	correct := 0
+	for _, pair := range answer.Pairs {
+		match, ok := matches[pair.Term]
+		element := ElementResult{Text: pair.Term, Correct: ok && sameText(match, pair.Match)}
+		if element.Correct {
+			correct++
+		} else {
+			element.Expected = match
+		}
+		result.Elements = append(result.Elements, element)
+		delete(matches, pair.Term)
+	}
+
+	result.Correct = correct == len(exercise.Pairs) && len(answer.Pairs) == len(exercise.Pairs)
+	result.Score = float64(correct) / float64(len(exercise.Pairs))
+	return result
+}
+
+func (matchPairs) Solution(exercise Exercise) string {
+	pairs := make([]string, 0, len(exercise.Pairs))
+	for _, pair := range exercise.Pairs {
+		pairs = append(pairs, pair.Term+" — "+pair.Match)
+	}
+	return strings.Join(pairs, ", ")
+}
+
+type orderWords struct{}
+
+func (orderWords) Type() string { return orderWordsType }
+
+func (orderWords) Schema() Schema {
+	return Schema{Type: orderWordsType, Fields: []SchemaField{
+		{Name: "options", Kind: kindStrings, Required: true, Description: "words to choose from, may contain extra words"},
+		{Name: "correct_order", Kind: kindStrings, Required: true},
+	}}
+}
+
+func (orderWords) Normalize(fields Fields) (Fields, error) {
+	if fields.Options == nil {
+		return Fields{}, ErrOptionsRequired
+	}
+	if fields.CorrectOrder == nil {
+		return Fields{}, ErrCorrectOrderRequired
+	}
+	return Fields{Options: fields.Options, CorrectOrder: fields.CorrectOrder}, nil
+}
+
+func (orderWords) Project(exercise Exercise, learner *LearnerExercise) {
+	learner.Options = exercise.Options
+}
+
+// Grade credits the longest run of words in the correct relative order,
+// extra words lower the score as missing ones do
+func (orderWords) Grade(exercise Exercise, answer Answer) GradeResult {
+	total := max(len(answer.Order), len(exercise.CorrectOrder))
+	if total == 0 {
+		return GradeResult{}
+	}
+
+	inOrder := commonSubsequence(answer.Order, exercise.CorrectOrder)
+	result := GradeResult{Elements: make([]ElementResult, 0, len(answer.Order))}
+	correct := 0
+	for i, word := range answer.Order {
+		element := ElementResult{Text: word, Correct: inOrder[i]}
+		if element.Correct {
+			correct++
+		} else if i < len(exercise.CorrectOrder) {
+			element.Expected = exercise.CorrectOrder[i]
+		}
+		result.Elements = append(result.Elements, element)
+	}
+
+	result.Correct = correct == total
+	result.Score = float64(correct) / float64(total)
+	return result
+}
+
+func (orderWords) Solution(exercise Exercise) string { return strings.Join(exercise.CorrectOrder, " ") }
+
+type fillInBlank struct {
+	matcher *Matcher
+}
+
+func (fillInBlank) Type() string { return fillInBlankType }
+
+func (fillInBlank) Schema() Schema {
+	return Schema{Type: fillInBlankType, Fields: []SchemaField{
+		{Name: "blanks", Kind: kindBlanks, Required: true, Description: "accepted answers of every " + blankMarker + " in the question"},
+		{Name: "options", Kind: kindStrings, Description: "word bank"},
+	}}
+}
+
+// Normalize checks blanks against the question when it is set
+func (fillInBlank) Normalize(fields Fields) (Fields, error) {
+	if len(fields.Blanks) == 0 {
+		return Fields{}, ErrBlanksRequired
+	}
+
+	blanks := make([][]string, 0, len(fields.Blanks))
+	for _, answers := range fields.Blanks {
+		accepted := acceptedAnswers(answers)
+		if len(accepted) == 0 {
+			return Fields{}, ErrBlanksRequired
+		}
+		blanks = append(blanks, accepted)
+	}
+
+	if fields.Question != nil && strings.Count(*fields.Question, blankMarker) != len(blanks) {
+		return Fields{}, ErrBlanksMismatch
+	}
+
+	return Fields{Blanks: blanks, Options: fields.Options}, nil
+}
+
+func (fillInBlank) Project(exercise Exercise, learner *LearnerExercise) {
+	learner.Options = exercise.Options
+	learner.Blanks = len(exercise.Blanks)
+}
+
+// Grade accepts typos in blanks without feedback, it is given for a single typed answer
+func (t fillInBlank) Grade(exercise Exercise, answer Answer) GradeResult {
+	if len(answer.Blanks) != len(exercise.Blanks) {
+		return GradeResult{}
+	}
+	for i, text := range answer.Blanks {
+		if correct, _ := t.matcher.Match(text, exercise.Blanks[i]); !correct {
+			return GradeResult{}
+		}
+	}
+	return GradeResult{Correct: true}
+}
+
+func (fillInBlank) Solution(exercise Exercise) string {
+	solution := exercise.Question
+	for _, answers := range exercise.Blanks {
+		solution = strings.Replace(solution, blankMarker, "["+strings.Join(answers, " / ")+"]", 1)
+	}
+	return solution
+}
+
+type translateSentence struct {
+	matcher *Matcher
+}
+
+func (translateSentence) Type() string { return translateSentenceType }
+
+func (translateSentence) Schema() Schema {
+	return Schema{Type: translateSentenceType, Fields: []SchemaField{
+		{Name: "correct_answers", Kind: kindStrings, Required: true, Description: "every accepted translation"},
+	}}
+}
+
+func (translateSentence) Normalize(fields Fields) (Fields, error) {
+	answers := acceptedAnswers(fields.CorrectAnswers)
+	if len(answers) == 0 {
+		return Fields{}, ErrCorrectAnswersRequired
+	}
+	return Fields{CorrectAnswers: answers}, nil
+}
+
+func (translateSentence) Project(Exercise, *LearnerExercise) {}
+
+func (t translateSentence) Grade(exercise Exercise, answer Answer) GradeResult {
+	correct, feedback := t.matcher.Match(answer.Text, exercise.CorrectAnswers)
+	return GradeResult{Correct: correct, Feedback: feedback}
+}
+
+func (translateSentence) Solution(exercise Exercise) string {
+	return strings.Join(exercise.CorrectAnswers, " / ")
+}
+
+type trueFalse struct{}
+
+func (trueFalse) Type() string { return trueFalseType }
+
+func (trueFalse) Schema() Schema {
+	return Schema{Type: trueFalseType, Fields: []SchemaField{
+		{Name: "is_true", Kind: kindBool, Required: true},
+	}}
+}
+
+func (trueFalse) Normalize(fields Fields) (Fields, error) {
+	if fields.IsTrue == nil {
+		return Fields{}, ErrIsTrueRequired
+	}
+	return Fields{IsTrue: fields.IsTrue}, nil
+}
+
+func (trueFalse) Project(Exercise, *LearnerExercise) {}
+
+func (trueFalse) Grade(exercise Exercise, answer Answer) GradeResult {
+	return GradeResult{Correct: answer.Value != nil && exercise.IsTrue != nil && *answer.Value == *exercise.IsTrue}
+}
+
+func (trueFalse) Solution(exercise Exercise) string {
+	if exercise.IsTrue == nil {
+		return ""
+	}
+	return strconv.FormatBool(*exercise.IsTrue)
+}
+
+// listenAndType is graded like translate_sentence, the question is the audio
+type listenAndType struct {
+	translateSentence
+}
+
+func (listenAndType) Type() string { return listenAndTypeType }
+
+func (listenAndType) Schema() Schema {
+	return Schema{Type: listenAndTypeType, Fields: []SchemaField{
+		{Name: "audio_id", Kind: kindAudio, Required: true},
+		{Name: "correct_answers", Kind: kindStrings, Required: true},
+	}}
+}
+
+// Normalize requires the audio, its kind is checked with the rest of media
+func (t listenAndType) Normalize(fields Fields) (Fields, error) {
+	if fields.AudioID == nil || *fields.AudioID == "" {
+		return Fields{}, ErrAudioRequired
+	}
+	return t.translateSentence.Normalize(fields)
+}
+
+// acceptedAnswers trims answers and drops empty and repeated ones
+func acceptedAnswers(answers []string) []string {
+	accepted := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		answer = strings.TrimSpace(answer)
+		if answer != "" && !slices.Contains(accepted, answer) {
+			accepted = append(accepted, answer)
+		}
+	}
+	return accepted
+}
+
+// commonSubsequence marks the words of the answer that make the longest common subsequence with the correct order
+func commonSubsequence(answer, correct []string) []bool {
+	// lengths[i][j] is the subsequence length of answer[i:] and correct[j:]
+	lengths := make([][]int, len(answer)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(correct)+1)
+	}
+	for i := len(answer) - 1; i >= 0; i-- {
+		for j := len(correct) - 1; j >= 0; j-- {
+			if sameText(answer[i], correct[j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	inSubsequence := make([]bool, len(answer))
+	for i, j := 0, 0; i < len(answer) && j < len(correct); {
+		switch {
+		case sameText(answer[i], correct[j]):
+			inSubsequence[i] = true
+			i++
+			j++
+		case lengths[i+1][j] > lengths[i][j+1]:
+			i++
+		default:
+			// on a tie earlier words of the answer are kept
+			j++
+		}
+	}
+	return inSubsequence
+}
+
+// sameText ignores case and repeated whitespace
+func sameText(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
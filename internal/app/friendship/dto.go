@@ -1,5 +1,7 @@
 package friendship
 
+import "time"
+
 const (
 	statusPending  = "pending"
 	statusAccepted = "accepted"
@@ -24,6 +26,32 @@ type FriendListEntity struct {
 	Lastname  string `json:"last_name"`
 }
 
+const (
+	defaultMaxPendingRequests      = 20
+	defaultDeclinedRequestCooldown = 7 * 24 * time.Hour
+)
+
+const (
+	ReasonFriendsOfFriends = "friends_of_friends"
+	ReasonSameModule       = "same_module"
+	ReasonSimilarXP        = "similar_xp"
+
+	DefaultSuggestionsLimit = 10
+	MaxSuggestionsLimit     = 50
+
+	// similarXPRange is the largest XP difference between users with similar XP
+	similarXPRange = 300
+)
+
+// Suggestion is a user the requester may want to add as a friend
+type Suggestion struct {
+	Username      string `json:"username"`
+	Firstname     string `json:"first_name"`
+	Lastname      string `json:"last_name"`
+	Reason        string `json:"reason"`
+	MutualFriends int    `json:"mutual_friends,omitempty"`
+}
+
 type FriendshipRequestDTO struct {
 	RequesterUsername string
 	RecipientUsername string `json:"recipient_username"`
@@ -39,6 +67,14 @@ func isValidStatus(status string) bool {
 	return false
 }
 
+// sender is the user of the request who is not its recipient
+func (req FriendshipRequestDTO) sender() string {
+	if req.RequesterUsername == req.Recipient {
+		return req.RecipientUsername
+	}
+	return req.RequesterUsername
+}
+
 // user1 must be alphabetically lower than user2
 func (req *FriendshipRequestDTO) normalize() {
 	if req.RequesterUsername > req.RecipientUsername {
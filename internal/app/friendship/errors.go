@@ -9,4 +9,9 @@ var (
 	ErrFriendshipNotFound = errors.New("friendship does not exist")
 	ErrInvalidStatus      = errors.New("invalid status")
 	ErrNotRecipient       = errors.New("requester is not the friendship's recipient")
+	ErrInvalidLimit       = errors.New("invalid limit")
+	ErrBlocked            = errors.New("users are blocked")
+	ErrBlockNotFound      = errors.New("block does not exist")
+	ErrTooManyRequests    = errors.New("too many pending friend requests")
+	ErrRequestCooldown    = errors.New("friend request was declined recently")
 )
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -19,41 +20,76 @@ func NewFriendshipRepository(db *pgxpool.Pool) *repository {
 	}
 }
 
-func (r *repository) createFriendshipStatus(ctx context.Context, req FriendshipRequestDTO) (Friendship, error) {
+// createFriendshipStatus creates pending request or returns the existing one.
+// Declined request is reopened when the one who declined sends it or when the cooldown is over.
+// created is true only when the request was inserted or reopened by this call.
+// Pending requests of the sender are counted under a lock on the sender, so parallel sends can't pass the limit.
+func (r *repository) createFriendshipStatus(ctx context.Context, req FriendshipRequestDTO, maxPending int, declinedCooldown time.Duration) (friendship Friendship, created bool, err error) {
 	var (
+		lockQuery = `
+		SELECT 1 FROM users WHERE username = $1 FOR NO KEY UPDATE;
+		`
 		query = `
 		WITH inserted AS(
 			INSERT INTO friendships (user1_username, user2_username, status, recipient)
 			VALUES ($1, $2, 'pending', $3)
 			ON CONFLICT (user1_username, user2_username)
-			DO NOTHING
+			DO UPDATE SET status = 'pending', recipient = EXCLUDED.recipient, updated_at = now()
+			WHERE friendships.status = 'declined'
+				AND (
+					friendships.recipient <> EXCLUDED.recipient
+					OR friendships.updated_at < now() - make_interval(secs => $4)
+				)
 			RETURNING user1_username, user2_username, status, recipient
 		)
 
-		SELECT user1_username, user2_username, status, recipient from inserted
+		SELECT user1_username, user2_username, status, recipient, true from inserted
 		UNION ALL
-		SELECT user1_username, user2_username, status, recipient from friendships
-		WHERE user1_username = $1 AND user2_username = $2;
+		SELECT user1_username, user2_username, status, recipient, false from friendships
+		WHERE user1_username = $1 AND user2_username = $2 AND NOT EXISTS (SELECT 1 FROM inserted);
 		`
-		response Friendship
+		sender = req.sender()
 	)
 
-	if err := r.db.QueryRow(ctx, query, req.RequesterUsername, req.RecipientUsername, req.Recipient).Scan(
-		&response.Username1,
-		&response.Username2,
-		&response.Status,
-		&response.Recipient,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Friendship{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, lockQuery, sender); err != nil {
+		return Friendship{}, false, err
+	}
+
+	pending, err := countPendingRequests(ctx, tx, sender)
+	if err != nil {
+		return Friendship{}, false, err
+	}
+	if pending >= maxPending {
+		return Friendship{}, false, ErrTooManyRequests
+	}
+
+	if err := tx.QueryRow(ctx, query, req.RequesterUsername, req.RecipientUsername, req.Recipient, declinedCooldown.Seconds()).Scan(
+		&friendship.Username1,
+		&friendship.Username2,
+		&friendship.Status,
+		&friendship.Recipient,
+		&created,
 	); err != nil {
-		return Friendship{}, err
+		return Friendship{}, false, err
 	}
 
-	return response, nil
+	if err := tx.Commit(ctx); err != nil {
+		return Friendship{}, false, err
+	}
+
+	return friendship, created, nil
 }
 
 func (r *repository) changeFriendshipStatus(ctx context.Context, req FriendshipRequestDTO) (Friendship, error) {
 	var (
 		query = `
-		UPDATE friendships SET status = $1
+		UPDATE friendships SET status = $1, updated_at = now()
 		WHERE 
 			user1_username = $2 
 			AND user2_username = $3 
@@ -192,3 +228,269 @@ func (r *repository) deleteFriendship(ctx context.Context, req FriendshipRequest
 
 	return nil
 }
+
+// suggestionCandidate filters out the user, users with any friendship status with them,
+// users blocked in any direction and users who are hidden from search. The user is $1, suggested user is u.
+const suggestionCandidate = `
+	u.username <> $1
+	AND u.deleted_at IS NULL
+	AND u.is_active = true
+	AND u.is_searchable = true
+	AND NOT EXISTS (
+		SELECT 1 FROM friendships x
+		WHERE (x.user1_username = $1 AND x.user2_username = u.username)
+			OR (x.user1_username = u.username AND x.user2_username = $1)
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM user_blocks b
+		WHERE (b.blocker_username = $1 AND b.blocked_username = u.username)
+			OR (b.blocker_username = u.username AND b.blocked_username = $1)
+	)
+`
+
+func (r *repository) getFriendsOfFriends(ctx context.Context, username string, limit int) ([]Suggestion, error) {
+	var (
+		query = `
+		WITH friends AS (
+			SELECT CASE WHEN user1_username = $1 THEN user2_username ELSE user1_username END AS username
+			FROM friendships
+			WHERE (user1_username = $1 OR user2_username = $1) AND status = 'accepted'
+		), friends_of_friends AS (
+			SELECT CASE WHEN f.user1_username = fr.username THEN f.user2_username ELSE f.user1_username END AS username
+			FROM friendships f
+			JOIN friends fr ON f.user1_username = fr.username OR f.user2_username = fr.username
+			WHERE f.status = 'accepted'
+		)
+		SELECT u.username, u.first_name, u.last_name, COUNT(*) AS mutual
+		FROM friends_of_friends fof
+		JOIN users u ON u.username = fof.username
+		WHERE ` + suggestionCandidate + `
+		GROUP BY u.username, u.first_name, u.last_name
+		ORDER BY mutual DESC, u.username
+		LIMIT $2;
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, username, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Suggestion
+	for rows.Next() {
+		var (
+			suggestion = Suggestion{Reason: ReasonFriendsOfFriends}
+			firstname  sql.NullString
+			lastname   sql.NullString
+		)
+		if err := rows.Scan(&suggestion.Username, &firstname, &lastname, &suggestion.MutualFriends); err != nil {
+			return nil, err
+		}
+		suggestion.Firstname = firstname.String
+		suggestion.Lastname = lastname.String
+		result = append(result, suggestion)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *repository) getUsersInSameModule(ctx context.Context, username string, limit int) ([]Suggestion, error) {
+	var (
+		query = `
+		SELECT u.username, u.first_name, u.last_name
+		FROM users u
+		JOIN users_progress p ON p.user_id = u.id
+		JOIN users me ON me.username = $1
+		JOIN users_progress my ON my.user_id = me.id
+		WHERE p.current_module = my.current_module
+			AND ` + suggestionCandidate + `
+		ORDER BY p.last_updated DESC, u.username
+		LIMIT $2;
+		`
+	)
+
+	return r.querySuggestions(ctx, ReasonSameModule, query, username, limit)
+}
+
+func (r *repository) getUsersWithSimilarXP(ctx context.Context, username string, xpRange, limit int) ([]Suggestion, error) {
+	var (
+		query = `
+		SELECT u.username, u.first_name, u.last_name
+		FROM users u
+		JOIN users_progress p ON p.user_id = u.id
+		JOIN users me ON me.username = $1
+		LEFT JOIN users_progress my ON my.user_id = me.id
+		WHERE ABS(p.xp - COALESCE(my.xp, 0)) <= $3
+			AND ` + suggestionCandidate + `
+		ORDER BY ABS(p.xp - COALESCE(my.xp, 0)), u.username
+		LIMIT $2;
+		`
+	)
+
+	return r.querySuggestions(ctx, ReasonSimilarXP, query, username, limit, xpRange)
+}
+
+func (r *repository) querySuggestions(ctx context.Context, reason, query string, args ...interface{}) ([]Suggestion, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Suggestion
+	for rows.Next() {
+		var (
+			suggestion = Suggestion{Reason: reason}
+			firstname  sql.NullString
+			lastname   sql.NullString
+		)
+		if err := rows.Scan(&suggestion.Username, &firstname, &lastname); err != nil {
+			return nil, err
+		}
+		suggestion.Firstname = firstname.String
+		suggestion.Lastname = lastname.String
+		result = append(result, suggestion)
+	}
+
+	return result, rows.Err()
+}
+
+// countPendingRequests counts requests sent by the user that are not answered yet
+func countPendingRequests(ctx context.Context, tx pgx.Tx, sender string) (int, error) {
+	var (
+		query = `
+		SELECT COUNT(*) FROM friendships
+		WHERE 
+			(user1_username = $1 OR user2_username = $1)
+			AND recipient <> $1
+			AND status = 'pending';
+		`
+		count int
+	)
+
+	if err := tx.QueryRow(ctx, query, sender).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// blockUser deletes friendship in any status and saves the block in one statement
+func (r *repository) blockUser(ctx context.Context, blocker, blocked string) error {
+	var (
+		query = `
+		WITH deleted AS (
+			DELETE FROM friendships WHERE 
+			(user1_username = $1 AND user2_username = $2) 
+			OR (user1_username = $2 AND user2_username = $1)
+		)
+		INSERT INTO user_blocks (blocker_username, blocked_username)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker_username, blocked_username) DO NOTHING;
+		`
+	)
+
+	_, err := r.db.Exec(ctx, query, blocker, blocked)
+	return err
+}
+
+func (r *repository) unblockUser(ctx context.Context, blocker, blocked string) error {
+	var (
+		query = `
+		DELETE FROM user_blocks WHERE blocker_username = $1 AND blocked_username = $2;
+		`
+	)
+
+	commandTag, err := r.db.Exec(ctx, query, blocker, blocked)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrBlockNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) getBlockList(ctx context.Context, username string) (FriendList, error) {
+	var (
+		query = `
+		SELECT u.username, u.first_name, u.last_name FROM user_blocks b
+		JOIN users u ON b.blocked_username = u.username
+		WHERE b.blocker_username = $1
+		ORDER BY b.created_at DESC;
+		`
+		response  FriendList
+		firstname sql.NullString
+		lastname  sql.NullString
+	)
+
+	rows, err := r.db.Query(ctx, query, username)
+	if err != nil {
+		return FriendList{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entity FriendListEntity
+		if err := rows.Scan(&entity.Username, &firstname, &lastname); err != nil {
+			return FriendList{}, err
+		}
+		entity.Firstname = firstname.String
+		entity.Lastname = lastname.String
+		response.Friends = append(response.Friends, entity)
+		response.Total++
+	}
+	if err := rows.Err(); err != nil {
+		return FriendList{}, err
+	}
+
+	return response, nil
+}
+
+func (r *repository) getBlockedUsernames(ctx context.Context, username string) ([]string, error) {
+	var (
+		query = `
+		SELECT blocked_username FROM user_blocks WHERE blocker_username = $1
+		UNION
+		SELECT blocker_username FROM user_blocks WHERE blocked_username = $1;
+		`
+		usernames []string
+	)
+
+	rows, err := r.db.Query(ctx, query, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var blocked string
+		if err := rows.Scan(&blocked); err != nil {
+			return nil, err
+		}
+		usernames = append(usernames, blocked)
+	}
+
+	return usernames, rows.Err()
+}
+
+func (r *repository) isBlocked(ctx context.Context, username1, username2 string) (bool, error) {
+	var (
+		query = `
+		SELECT EXISTS (
+			SELECT 1 FROM user_blocks WHERE 
+			(blocker_username = $1 AND blocked_username = $2) 
+			OR (blocker_username = $2 AND blocked_username = $1)
+		);
+		`
+		blocked bool
+	)
+
+	if err := r.db.QueryRow(ctx, query, username1, username2).Scan(&blocked); err != nil {
+		return false, err
+	}
+
+	return blocked, nil
+}
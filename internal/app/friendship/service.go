@@ -3,6 +3,8 @@ package friendship
 import (
 	"context"
 	"errors"
+	"time"
+	"uiren/internal/app/events"
 	"uiren/internal/app/users"
 	"uiren/pkg/logger"
 )
@@ -10,30 +12,64 @@ import (
 //go:generate mockgen -source service.go -destination service_mock.go -package friendship
 
 type friendshipRepository interface {
-	createFriendshipStatus(ctx context.Context, req FriendshipRequestDTO) (Friendship, error)
+	createFriendshipStatus(ctx context.Context, req FriendshipRequestDTO, maxPending int, declinedCooldown time.Duration) (Friendship, bool, error)
 	changeFriendshipStatus(ctx context.Context, req FriendshipRequestDTO) (Friendship, error)
 	getFriendList(ctx context.Context, username string) (FriendList, error)
 	getRequestList(ctx context.Context, username string) (FriendList, error)
 	getFriendshipRecipient(ctx context.Context, username1, username2 string) (string, error)
 	deleteFriendship(ctx context.Context, req FriendshipRequestDTO) error
+
+	blockUser(ctx context.Context, blocker, blocked string) error
+	unblockUser(ctx context.Context, blocker, blocked string) error
+	getBlockList(ctx context.Context, username string) (FriendList, error)
+	getBlockedUsernames(ctx context.Context, username string) ([]string, error)
+	isBlocked(ctx context.Context, username1, username2 string) (bool, error)
+
+	getFriendsOfFriends(ctx context.Context, username string, limit int) ([]Suggestion, error)
+	getUsersInSameModule(ctx context.Context, username string, limit int) ([]Suggestion, error)
+	getUsersWithSimilarXP(ctx context.Context, username string, xpRange, limit int) ([]Suggestion, error)
 }
 
 type userService interface {
 	CheckUserExists(ctx context.Context, username string) error
 }
 
+type publisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
 type FriendshipService struct {
 	friendshipRepository friendshipRepository
 	userService          userService
+	publisher            publisher
+
+	maxPendingRequests      int
+	declinedRequestCooldown time.Duration
 }
 
 func NewFriendshipService(friendshipRepository friendshipRepository, userService userService) *FriendshipService {
 	return &FriendshipService{
-		friendshipRepository: friendshipRepository,
-		userService:          userService,
+		friendshipRepository:    friendshipRepository,
+		userService:             userService,
+		maxPendingRequests:      defaultMaxPendingRequests,
+		declinedRequestCooldown: defaultDeclinedRequestCooldown,
 	}
 }
 
+func (s *FriendshipService) WithPublisher(publisher publisher) {
+	s.publisher = publisher
+}
+
+// SetMaxPendingRequests limits requests a user sent and nobody answered yet
+func (s *FriendshipService) SetMaxPendingRequests(limit int) {
+	s.maxPendingRequests = limit
+}
+
+// SetDeclinedRequestCooldown sets how long a declined user can't send the request again
+func (s *FriendshipService) SetDeclinedRequestCooldown(cooldown time.Duration) {
+	s.declinedRequestCooldown = cooldown
+}
+
 func (s *FriendshipService) SendFriendRequest(ctx context.Context, friendshipRequest FriendshipRequestDTO) (Friendship, error) {
 	logger.Info("FriendshipService.SendFriendRequest new request")
 
@@ -45,13 +81,36 @@ func (s *FriendshipService) SendFriendRequest(ctx context.Context, friendshipReq
 		return Friendship{}, err
 	}
 
+	blocked, err := s.friendshipRepository.isBlocked(ctx, friendshipRequest.RequesterUsername, friendshipRequest.RecipientUsername)
+	if err != nil {
+		logger.Error("FriendshipService.SendFriendRequest friendshipRepository.isBlocked: ", err)
+		return Friendship{}, err
+	}
+	if blocked {
+		return Friendship{}, ErrBlocked
+	}
+
+	requester := friendshipRequest.RequesterUsername
 	friendshipRequest.Recipient = friendshipRequest.RecipientUsername
 	friendshipRequest.normalize()
-	friendship, err := s.friendshipRepository.createFriendshipStatus(ctx, friendshipRequest)
+	friendship, created, err := s.friendshipRepository.createFriendshipStatus(ctx, friendshipRequest, s.maxPendingRequests, s.declinedRequestCooldown)
+	if errors.Is(err, ErrTooManyRequests) {
+		return Friendship{}, err
+	}
 	if err != nil {
 		logger.Error("FriendshipService.SendFriendRequest friendshipRepository.setFriendshipStatus: ", err)
 		return Friendship{}, err
 	}
+
+	// declined request is reopened only after the cooldown, otherwise it is returned as is
+	if friendship.Status == statusDeclined {
+		return Friendship{}, ErrRequestCooldown
+	}
+
+	// repeated request or the reverse pending one is returned without a new notification
+	if created {
+		s.publish(ctx, events.Event{Type: events.FriendRequestReceived, Username: friendshipRequest.Recipient, Code: requester})
+	}
 	return friendship, nil
 }
 
@@ -80,6 +139,7 @@ func (s *FriendshipService) HandleFriendRequest(ctx context.Context, friendshipR
 	if recipient != friendshipRequest.RequesterUsername {
 		return Friendship{}, ErrNotRecipient
 	}
+	sender := friendshipRequest.RecipientUsername
 	friendshipRequest.Recipient = recipient
 	friendshipRequest.normalize()
 
@@ -88,6 +148,10 @@ func (s *FriendshipService) HandleFriendRequest(ctx context.Context, friendshipR
 		logger.Error("FriendshipService.AcceptFriendRequest friendshipRepository.setFriendshipStatus: ", err)
 		return Friendship{}, err
 	}
+
+	if friendship.Status == statusAccepted {
+		s.publish(ctx, events.Event{Type: events.FriendRequestAccepted, Username: sender, Code: recipient})
+	}
 	return friendship, nil
 }
 
@@ -150,3 +214,126 @@ func (s *FriendshipService) DeleteFriendship(ctx context.Context, req Friendship
 
 	return nil
 }
+
+// GetSuggestions proposes friends of friends first, then users passing the same module
+// and users with similar XP. Existing friends, requests and hidden users are skipped.
+func (s *FriendshipService) GetSuggestions(ctx context.Context, username string, limit int) ([]Suggestion, error) {
+	logger.Info("FriendshipService.GetSuggestions new request")
+
+	if limit == 0 {
+		limit = DefaultSuggestionsLimit
+	}
+	if limit < 0 || limit > MaxSuggestionsLimit {
+		return nil, ErrInvalidLimit
+	}
+
+	sources := []func() ([]Suggestion, error){
+		func() ([]Suggestion, error) {
+			return s.friendshipRepository.getFriendsOfFriends(ctx, username, limit)
+		},
+		func() ([]Suggestion, error) {
+			return s.friendshipRepository.getUsersInSameModule(ctx, username, limit)
+		},
+		func() ([]Suggestion, error) {
+			return s.friendshipRepository.getUsersWithSimilarXP(ctx, username, similarXPRange, limit)
+		},
+	}
+
+	var (
+		result = make([]Suggestion, 0, limit)
+		seen   = make(map[string]struct{})
+	)
+	for _, source := range sources {
+		if len(result) == limit {
+			break
+		}
+
+		suggestions, err := source()
+		if err != nil {
+			logger.Error("FriendshipService.GetSuggestions friendshipRepository: ", err)
+			return nil, err
+		}
+
+		for _, suggestion := range suggestions {
+			if _, ok := seen[suggestion.Username]; ok {
+				continue
+			}
+			seen[suggestion.Username] = struct{}{}
+			result = append(result, suggestion)
+			if len(result) == limit {
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// BlockUser removes friendship between users and stops requests between them,
+// blocked users don't see each other in search, suggestions and leaderboards
+func (s *FriendshipService) BlockUser(ctx context.Context, blocker, blocked string) error {
+	logger.Info("FriendshipService.BlockUser new request")
+
+	if blocker == blocked {
+		return ErrSameUser
+	}
+
+	if err := s.userService.CheckUserExists(ctx, blocked); err != nil {
+		logger.Error("FriendshipService.BlockUser userService.CheckUserExists: ", err)
+		if errors.Is(err, users.ErrUserNotFound) {
+			return ErrRecipientNotFound
+		}
+		return err
+	}
+
+	if err := s.friendshipRepository.blockUser(ctx, blocker, blocked); err != nil {
+		logger.Error("FriendshipService.BlockUser friendshipRepository.blockUser: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *FriendshipService) UnblockUser(ctx context.Context, blocker, blocked string) error {
+	logger.Info("FriendshipService.UnblockUser new request")
+
+	if err := s.friendshipRepository.unblockUser(ctx, blocker, blocked); err != nil {
+		logger.Error("FriendshipService.UnblockUser friendshipRepository.unblockUser: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetBlockList returns users blocked by the user
+func (s *FriendshipService) GetBlockList(ctx context.Context, username string) (FriendList, error) {
+	logger.Info("FriendshipService.GetBlockList new request")
+
+	blockList, err := s.friendshipRepository.getBlockList(ctx, username)
+	if err != nil {
+		logger.Error("FriendshipService.GetBlockList friendshipRepository.getBlockList: ", err)
+		return FriendList{}, err
+	}
+	return blockList, nil
+}
+
+// GetBlockedUsernames returns users blocked by the user and users who blocked them
+func (s *FriendshipService) GetBlockedUsernames(ctx context.Context, username string) ([]string, error) {
+	logger.Info("FriendshipService.GetBlockedUsernames new request")
+
+	usernames, err := s.friendshipRepository.getBlockedUsernames(ctx, username)
+	if err != nil {
+		logger.Error("FriendshipService.GetBlockedUsernames friendshipRepository.getBlockedUsernames: ", err)
+		return nil, err
+	}
+	return usernames, nil
+}
+
+// publish notifies subscribers about the friendship change
+func (s *FriendshipService) publish(ctx context.Context, event events.Event) {
+	if s.publisher == nil {
+		return
+	}
+
+	s.publisher.Publish(ctx, event)
+}
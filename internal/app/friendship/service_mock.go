@@ -7,6 +7,8 @@ package friendship
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
+	events "uiren/internal/app/events"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,6 +36,20 @@ func (m *MockfriendshipRepository) EXPECT() *MockfriendshipRepositoryMockRecorde
 	return m.recorder
 }
 
+// blockUser mocks base method.
+func (m *MockfriendshipRepository) blockUser(ctx context.Context, blocker, blocked string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "blockUser", ctx, blocker, blocked)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// blockUser indicates an expected call of blockUser.
+func (mr *MockfriendshipRepositoryMockRecorder) blockUser(ctx, blocker, blocked interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "blockUser", reflect.TypeOf((*MockfriendshipRepository)(nil).blockUser), ctx, blocker, blocked)
+}
+
 // changeFriendshipStatus mocks base method.
 func (m *MockfriendshipRepository) changeFriendshipStatus(ctx context.Context, req FriendshipRequestDTO) (Friendship, error) {
 	m.ctrl.T.Helper()
@@ -50,18 +66,19 @@ func (mr *MockfriendshipRepositoryMockRecorder) changeFriendshipStatus(ctx, req
 }
 
 // createFriendshipStatus mocks base method.
-func (m *MockfriendshipRepository) createFriendshipStatus(ctx context.Context, req FriendshipRequestDTO) (Friendship, error) {
+func (m *MockfriendshipRepository) createFriendshipStatus(ctx context.Context, req FriendshipRequestDTO, maxPending int, declinedCooldown time.Duration) (Friendship, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "createFriendshipStatus", ctx, req)
+	ret := m.ctrl.Call(m, "createFriendshipStatus", ctx, req, maxPending, declinedCooldown)
 	ret0, _ := ret[0].(Friendship)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // createFriendshipStatus indicates an expected call of createFriendshipStatus.
-func (mr *MockfriendshipRepositoryMockRecorder) createFriendshipStatus(ctx, req interface{}) *gomock.Call {
+func (mr *MockfriendshipRepositoryMockRecorder) createFriendshipStatus(ctx, req, maxPending, declinedCooldown interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createFriendshipStatus", reflect.TypeOf((*MockfriendshipRepository)(nil).createFriendshipStatus), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createFriendshipStatus", reflect.TypeOf((*MockfriendshipRepository)(nil).createFriendshipStatus), ctx, req, maxPending, declinedCooldown)
 }
 
 // deleteFriendship mocks base method.
@@ -78,6 +95,36 @@ func (mr *MockfriendshipRepositoryMockRecorder) deleteFriendship(ctx, req interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "deleteFriendship", reflect.TypeOf((*MockfriendshipRepository)(nil).deleteFriendship), ctx, req)
 }
 
+// getBlockList mocks base method.
+func (m *MockfriendshipRepository) getBlockList(ctx context.Context, username string) (FriendList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getBlockList", ctx, username)
+	ret0, _ := ret[0].(FriendList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getBlockList indicates an expected call of getBlockList.
+func (mr *MockfriendshipRepositoryMockRecorder) getBlockList(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getBlockList", reflect.TypeOf((*MockfriendshipRepository)(nil).getBlockList), ctx, username)
+}
+
+// getBlockedUsernames mocks base method.
+func (m *MockfriendshipRepository) getBlockedUsernames(ctx context.Context, username string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getBlockedUsernames", ctx, username)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getBlockedUsernames indicates an expected call of getBlockedUsernames.
+func (mr *MockfriendshipRepositoryMockRecorder) getBlockedUsernames(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getBlockedUsernames", reflect.TypeOf((*MockfriendshipRepository)(nil).getBlockedUsernames), ctx, username)
+}
+
 // getFriendList mocks base method.
 func (m *MockfriendshipRepository) getFriendList(ctx context.Context, username string) (FriendList, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +140,21 @@ func (mr *MockfriendshipRepositoryMockRecorder) getFriendList(ctx, username inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getFriendList", reflect.TypeOf((*MockfriendshipRepository)(nil).getFriendList), ctx, username)
 }
 
+// getFriendsOfFriends mocks base method.
+func (m *MockfriendshipRepository) getFriendsOfFriends(ctx context.Context, username string, limit int) ([]Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getFriendsOfFriends", ctx, username, limit)
+	ret0, _ := ret[0].([]Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getFriendsOfFriends indicates an expected call of getFriendsOfFriends.
+func (mr *MockfriendshipRepositoryMockRecorder) getFriendsOfFriends(ctx, username, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getFriendsOfFriends", reflect.TypeOf((*MockfriendshipRepository)(nil).getFriendsOfFriends), ctx, username, limit)
+}
+
 // getFriendshipRecipient mocks base method.
 func (m *MockfriendshipRepository) getFriendshipRecipient(ctx context.Context, username1, username2 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +185,65 @@ func (mr *MockfriendshipRepositoryMockRecorder) getRequestList(ctx, username int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getRequestList", reflect.TypeOf((*MockfriendshipRepository)(nil).getRequestList), ctx, username)
 }
 
+// getUsersInSameModule mocks base method.
+func (m *MockfriendshipRepository) getUsersInSameModule(ctx context.Context, username string, limit int) ([]Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getUsersInSameModule", ctx, username, limit)
+	ret0, _ := ret[0].([]Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getUsersInSameModule indicates an expected call of getUsersInSameModule.
+func (mr *MockfriendshipRepositoryMockRecorder) getUsersInSameModule(ctx, username, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getUsersInSameModule", reflect.TypeOf((*MockfriendshipRepository)(nil).getUsersInSameModule), ctx, username, limit)
+}
+
+// getUsersWithSimilarXP mocks base method.
+func (m *MockfriendshipRepository) getUsersWithSimilarXP(ctx context.Context, username string, xpRange, limit int) ([]Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getUsersWithSimilarXP", ctx, username, xpRange, limit)
+	ret0, _ := ret[0].([]Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getUsersWithSimilarXP indicates an expected call of getUsersWithSimilarXP.
+func (mr *MockfriendshipRepositoryMockRecorder) getUsersWithSimilarXP(ctx, username, xpRange, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getUsersWithSimilarXP", reflect.TypeOf((*MockfriendshipRepository)(nil).getUsersWithSimilarXP), ctx, username, xpRange, limit)
+}
+
+// isBlocked mocks base method.
+func (m *MockfriendshipRepository) isBlocked(ctx context.Context, username1, username2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "isBlocked", ctx, username1, username2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// isBlocked indicates an expected call of isBlocked.
+func (mr *MockfriendshipRepositoryMockRecorder) isBlocked(ctx, username1, username2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "isBlocked", reflect.TypeOf((*MockfriendshipRepository)(nil).isBlocked), ctx, username1, username2)
+}
+
+// unblockUser mocks base method.
+func (m *MockfriendshipRepository) unblockUser(ctx context.Context, blocker, blocked string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "unblockUser", ctx, blocker, blocked)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// unblockUser indicates an expected call of unblockUser.
+func (mr *MockfriendshipRepositoryMockRecorder) unblockUser(ctx, blocker, blocked interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "unblockUser", reflect.TypeOf((*MockfriendshipRepository)(nil).unblockUser), ctx, blocker, blocked)
+}
+
 // MockuserService is a mock of userService interface.
 type MockuserService struct {
 	ctrl     *gomock.Controller
@@ -159,3 +280,38 @@ func (mr *MockuserServiceMockRecorder) CheckUserExists(ctx, username interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserExists", reflect.TypeOf((*MockuserService)(nil).CheckUserExists), ctx, username)
 }
+
+// Mockpublisher is a mock of publisher interface.
+type Mockpublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockpublisherMockRecorder
+}
+
+// MockpublisherMockRecorder is the mock recorder for Mockpublisher.
+type MockpublisherMockRecorder struct {
+	mock *Mockpublisher
+}
+
+// NewMockpublisher creates a new mock instance.
+func NewMockpublisher(ctrl *gomock.Controller) *Mockpublisher {
+	mock := &Mockpublisher{ctrl: ctrl}
+	mock.recorder = &MockpublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockpublisher) EXPECT() *MockpublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *Mockpublisher) Publish(ctx context.Context, event events.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", ctx, event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockpublisherMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*Mockpublisher)(nil).Publish), ctx, event)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
+	"uiren/internal/app/events"
 	"uiren/internal/app/users"
 	"uiren/pkg/logger"
 
@@ -44,7 +46,8 @@ func Test_friendshipService_SendFriendRequest_success(t *testing.T) {
 		Status:    statusPending,
 		Recipient: newReq.Recipient,
 	}
-	repo.EXPECT().createFriendshipStatus(ctx, newReq).Return(res, nil)
+	repo.EXPECT().isBlocked(ctx, req.RequesterUsername, req.RecipientUsername).Return(false, nil)
+	repo.EXPECT().createFriendshipStatus(ctx, newReq, defaultMaxPendingRequests, defaultDeclinedRequestCooldown).Return(res, true, nil)
 
 	friendship, err := srv.SendFriendRequest(ctx, req)
 	assert.NoError(t, err)
@@ -107,6 +110,7 @@ func Test_friendshipService_SendFriendRequest_fail(t *testing.T) {
 		}
 		userService.EXPECT().CheckUserExists(ctx, req.RequesterUsername).Return(nil)
 		userService.EXPECT().CheckUserExists(ctx, req.RecipientUsername).Return(nil)
+		repo.EXPECT().isBlocked(ctx, req.RequesterUsername, req.RecipientUsername).Return(false, nil)
 
 		newReq := FriendshipRequestDTO{
 			RequesterUsername: req.RequesterUsername,
@@ -115,12 +119,165 @@ func Test_friendshipService_SendFriendRequest_fail(t *testing.T) {
 		newReq.Recipient = req.RecipientUsername
 		newReq.normalize()
 
-		repo.EXPECT().createFriendshipStatus(ctx, newReq).Return(Friendship{}, repoError)
+		repo.EXPECT().createFriendshipStatus(ctx, newReq, defaultMaxPendingRequests, defaultDeclinedRequestCooldown).Return(Friendship{}, false, repoError)
 
 		_, err := srv.SendFriendRequest(ctx, req)
 		assert.Error(t, err)
 		assert.Equal(t, err, repoError)
 	})
+
+	t.Run("users are blocked", func(t *testing.T) {
+		req := FriendshipRequestDTO{
+			RequesterUsername: "user1",
+			RecipientUsername: "user2",
+		}
+		userService.EXPECT().CheckUserExists(ctx, req.RequesterUsername).Return(nil)
+		userService.EXPECT().CheckUserExists(ctx, req.RecipientUsername).Return(nil)
+		repo.EXPECT().isBlocked(ctx, req.RequesterUsername, req.RecipientUsername).Return(true, nil)
+
+		_, err := srv.SendFriendRequest(ctx, req)
+		assert.Equal(t, err, ErrBlocked)
+	})
+
+	t.Run("too many pending requests", func(t *testing.T) {
+		req := FriendshipRequestDTO{
+			RequesterUsername: "user1",
+			RecipientUsername: "user2",
+		}
+		userService.EXPECT().CheckUserExists(ctx, req.RequesterUsername).Return(nil)
+		userService.EXPECT().CheckUserExists(ctx, req.RecipientUsername).Return(nil)
+		repo.EXPECT().isBlocked(ctx, req.RequesterUsername, req.RecipientUsername).Return(false, nil)
+		repo.EXPECT().createFriendshipStatus(ctx, gomock.Any(), defaultMaxPendingRequests, defaultDeclinedRequestCooldown).Return(Friendship{}, false, ErrTooManyRequests)
+
+		_, err := srv.SendFriendRequest(ctx, req)
+		assert.Equal(t, err, ErrTooManyRequests)
+	})
+
+	t.Run("declined recently", func(t *testing.T) {
+		req := FriendshipRequestDTO{
+			RequesterUsername: "user2",
+			RecipientUsername: "user1",
+		}
+		userService.EXPECT().CheckUserExists(ctx, req.RequesterUsername).Return(nil)
+		userService.EXPECT().CheckUserExists(ctx, req.RecipientUsername).Return(nil)
+		repo.EXPECT().isBlocked(ctx, req.RequesterUsername, req.RecipientUsername).Return(false, nil)
+		repo.EXPECT().createFriendshipStatus(ctx, gomock.Any(), defaultMaxPendingRequests, defaultDeclinedRequestCooldown).Return(Friendship{
+			Username1: "user1",
+			Username2: "user2",
+			Status:    statusDeclined,
+			Recipient: "user1",
+		}, false, nil)
+
+		_, err := srv.SendFriendRequest(ctx, req)
+		assert.Equal(t, err, ErrRequestCooldown)
+	})
+}
+
+func Test_friendshipService_SendFriendRequest_limits(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		userService = NewMockuserService(ctrl)
+		repo        = NewMockfriendshipRepository(ctrl)
+		srv         = NewFriendshipService(repo, userService)
+		req         = FriendshipRequestDTO{
+			RequesterUsername: "user1",
+			RecipientUsername: "user2",
+		}
+		res = Friendship{Username1: "user1", Username2: "user2", Status: statusPending, Recipient: "user2"}
+	)
+	srv.SetMaxPendingRequests(3)
+	srv.SetDeclinedRequestCooldown(time.Hour)
+
+	userService.EXPECT().CheckUserExists(ctx, gomock.Any()).Return(nil).Times(2)
+	repo.EXPECT().isBlocked(ctx, req.RequesterUsername, req.RecipientUsername).Return(false, nil)
+	repo.EXPECT().createFriendshipStatus(ctx, gomock.Any(), 3, time.Hour).Return(res, true, nil)
+
+	friendship, err := srv.SendFriendRequest(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, friendship, res)
+}
+
+func Test_friendshipService_BlockUser(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		userService = NewMockuserService(ctrl)
+		repo        = NewMockfriendshipRepository(ctrl)
+		srv         = NewFriendshipService(repo, userService)
+		errRepo     = errors.New("repo error")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		userService.EXPECT().CheckUserExists(ctx, "user2").Return(nil)
+		repo.EXPECT().blockUser(ctx, "user1", "user2").Return(nil)
+
+		err := srv.BlockUser(ctx, "user1", "user2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("same user", func(t *testing.T) {
+		err := srv.BlockUser(ctx, "user1", "user1")
+		assert.Equal(t, err, ErrSameUser)
+	})
+
+	t.Run("blocked user does not exist", func(t *testing.T) {
+		userService.EXPECT().CheckUserExists(ctx, "user2").Return(users.ErrUserNotFound)
+
+		err := srv.BlockUser(ctx, "user1", "user2")
+		assert.Equal(t, err, ErrRecipientNotFound)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		userService.EXPECT().CheckUserExists(ctx, "user2").Return(nil)
+		repo.EXPECT().blockUser(ctx, "user1", "user2").Return(errRepo)
+
+		err := srv.BlockUser(ctx, "user1", "user2")
+		assert.Equal(t, err, errRepo)
+	})
+}
+
+func Test_friendshipService_UnblockUser(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		userService = NewMockuserService(ctrl)
+		repo        = NewMockfriendshipRepository(ctrl)
+		srv         = NewFriendshipService(repo, userService)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().unblockUser(ctx, "user1", "user2").Return(nil)
+
+		err := srv.UnblockUser(ctx, "user1", "user2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("block not found", func(t *testing.T) {
+		repo.EXPECT().unblockUser(ctx, "user1", "user3").Return(ErrBlockNotFound)
+
+		err := srv.UnblockUser(ctx, "user1", "user3")
+		assert.Equal(t, err, ErrBlockNotFound)
+	})
+}
+
+func Test_friendshipService_GetBlockedUsernames(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		userService = NewMockuserService(ctrl)
+		repo        = NewMockfriendshipRepository(ctrl)
+		srv         = NewFriendshipService(repo, userService)
+	)
+	repo.EXPECT().getBlockedUsernames(ctx, "user1").Return([]string{"user2", "user3"}, nil)
+
+	usernames, err := srv.GetBlockedUsernames(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, usernames, []string{"user2", "user3"})
 }
 
 func Test_friendshipService_HandleFriendRequest_success(t *testing.T) {
@@ -162,6 +319,118 @@ func Test_friendshipService_HandleFriendRequest_success(t *testing.T) {
 	assert.Equal(t, friendship, res)
 }
 
+func Test_friendshipService_publishes_events(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		userService = NewMockuserService(ctrl)
+		repo        = NewMockfriendshipRepository(ctrl)
+		pub         = NewMockpublisher(ctrl)
+		srv         = NewFriendshipService(repo, userService)
+	)
+	srv.WithPublisher(pub)
+
+	t.Run("request sent", func(t *testing.T) {
+		req := FriendshipRequestDTO{RequesterUsername: "user2", RecipientUsername: "user1"}
+		userService.EXPECT().CheckUserExists(ctx, gomock.Any()).Return(nil).Times(2)
+		repo.EXPECT().isBlocked(ctx, "user2", "user1").Return(false, nil)
+		repo.EXPECT().createFriendshipStatus(ctx, gomock.Any(), defaultMaxPendingRequests, defaultDeclinedRequestCooldown).Return(Friendship{
+			Username1: "user1",
+			Username2: "user2",
+			Status:    statusPending,
+			Recipient: "user1",
+		}, true, nil)
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.FriendRequestReceived, Username: "user1", Code: "user2"})
+
+		_, err := srv.SendFriendRequest(ctx, req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("request sent again", func(t *testing.T) {
+		req := FriendshipRequestDTO{RequesterUsername: "user2", RecipientUsername: "user1"}
+		userService.EXPECT().CheckUserExists(ctx, gomock.Any()).Return(nil).Times(2)
+		repo.EXPECT().isBlocked(ctx, "user2", "user1").Return(false, nil)
+		repo.EXPECT().createFriendshipStatus(ctx, gomock.Any(), defaultMaxPendingRequests, defaultDeclinedRequestCooldown).Return(Friendship{
+			Username1: "user1",
+			Username2: "user2",
+			Status:    statusPending,
+			Recipient: "user1",
+		}, false, nil)
+		pub.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+		friendship, err := srv.SendFriendRequest(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, statusPending, friendship.Status)
+	})
+
+	t.Run("reverse request is pending", func(t *testing.T) {
+		// user2 already asked user1, now user1 sends the request back
+		req := FriendshipRequestDTO{RequesterUsername: "user1", RecipientUsername: "user2"}
+		userService.EXPECT().CheckUserExists(ctx, gomock.Any()).Return(nil).Times(2)
+		repo.EXPECT().isBlocked(ctx, "user1", "user2").Return(false, nil)
+		repo.EXPECT().createFriendshipStatus(ctx, gomock.Any(), defaultMaxPendingRequests, defaultDeclinedRequestCooldown).Return(Friendship{
+			Username1: "user1",
+			Username2: "user2",
+			Status:    statusPending,
+			Recipient: "user1",
+		}, false, nil)
+		pub.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+		friendship, err := srv.SendFriendRequest(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", friendship.Recipient)
+	})
+
+	t.Run("reopened request goes to the recipient", func(t *testing.T) {
+		req := FriendshipRequestDTO{RequesterUsername: "user1", RecipientUsername: "user2"}
+		userService.EXPECT().CheckUserExists(ctx, gomock.Any()).Return(nil).Times(2)
+		repo.EXPECT().isBlocked(ctx, "user1", "user2").Return(false, nil)
+		repo.EXPECT().createFriendshipStatus(ctx, gomock.Any(), defaultMaxPendingRequests, defaultDeclinedRequestCooldown).Return(Friendship{
+			Username1: "user1",
+			Username2: "user2",
+			Status:    statusPending,
+			Recipient: "user2",
+		}, true, nil)
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.FriendRequestReceived, Username: "user2", Code: "user1"})
+
+		_, err := srv.SendFriendRequest(ctx, req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("request accepted", func(t *testing.T) {
+		req := FriendshipRequestDTO{RequesterUsername: "user1", RecipientUsername: "user2", Status: statusAccepted}
+		userService.EXPECT().CheckUserExists(ctx, gomock.Any()).Return(nil).Times(2)
+		repo.EXPECT().getFriendshipRecipient(ctx, "user1", "user2").Return("user1", nil)
+		repo.EXPECT().changeFriendshipStatus(ctx, gomock.Any()).Return(Friendship{
+			Username1: "user1",
+			Username2: "user2",
+			Status:    statusAccepted,
+			Recipient: "user1",
+		}, nil)
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.FriendRequestAccepted, Username: "user2", Code: "user1"})
+
+		_, err := srv.HandleFriendRequest(ctx, req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("request declined", func(t *testing.T) {
+		req := FriendshipRequestDTO{RequesterUsername: "user1", RecipientUsername: "user2", Status: statusDeclined}
+		userService.EXPECT().CheckUserExists(ctx, gomock.Any()).Return(nil).Times(2)
+		repo.EXPECT().getFriendshipRecipient(ctx, "user1", "user2").Return("user1", nil)
+		repo.EXPECT().changeFriendshipStatus(ctx, gomock.Any()).Return(Friendship{
+			Username1: "user1",
+			Username2: "user2",
+			Status:    statusDeclined,
+			Recipient: "user1",
+		}, nil)
+		pub.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := srv.HandleFriendRequest(ctx, req)
+		assert.NoError(t, err)
+	})
+}
+
 func Test_friendshipService_HandleFriendRequest_fail(t *testing.T) {
 	t.Parallel()
 	var (
@@ -409,3 +678,57 @@ func Test_FriendshipService_GetRequestList_repoError(t *testing.T) {
 	assert.Equal(t, repoError, err)
 	assert.Equal(t, FriendList{}, result)
 }
+
+func Test_friendshipService_GetSuggestions(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		userService = NewMockuserService(ctrl)
+		repo        = NewMockfriendshipRepository(ctrl)
+		srv         = NewFriendshipService(repo, userService)
+		repoErr     = errors.New("repo error")
+	)
+
+	t.Run("sources are merged without duplicates", func(t *testing.T) {
+		repo.EXPECT().getFriendsOfFriends(ctx, "user1", 3).Return([]Suggestion{
+			{Username: "user2", Reason: ReasonFriendsOfFriends, MutualFriends: 2},
+		}, nil)
+		repo.EXPECT().getUsersInSameModule(ctx, "user1", 3).Return([]Suggestion{
+			{Username: "user2", Reason: ReasonSameModule},
+			{Username: "user3", Reason: ReasonSameModule},
+		}, nil)
+		repo.EXPECT().getUsersWithSimilarXP(ctx, "user1", similarXPRange, 3).Return([]Suggestion{
+			{Username: "user4", Reason: ReasonSimilarXP},
+			{Username: "user5", Reason: ReasonSimilarXP},
+		}, nil)
+
+		suggestions, err := srv.GetSuggestions(ctx, "user1", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []Suggestion{
+			{Username: "user2", Reason: ReasonFriendsOfFriends, MutualFriends: 2},
+			{Username: "user3", Reason: ReasonSameModule},
+			{Username: "user4", Reason: ReasonSimilarXP},
+		}, suggestions)
+	})
+
+	t.Run("full page skips other sources", func(t *testing.T) {
+		repo.EXPECT().getFriendsOfFriends(ctx, "user1", 1).Return([]Suggestion{{Username: "user2"}}, nil)
+
+		suggestions, err := srv.GetSuggestions(ctx, "user1", 1)
+		assert.NoError(t, err)
+		assert.Len(t, suggestions, 1)
+	})
+
+	t.Run("repo failed", func(t *testing.T) {
+		repo.EXPECT().getFriendsOfFriends(ctx, "user1", DefaultSuggestionsLimit).Return(nil, repoErr)
+
+		_, err := srv.GetSuggestions(ctx, "user1", 0)
+		assert.Equal(t, repoErr, err)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, err := srv.GetSuggestions(ctx, "user1", MaxSuggestionsLimit+1)
+		assert.Equal(t, ErrInvalidLimit, err)
+	})
+}
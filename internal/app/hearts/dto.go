@@ -0,0 +1,22 @@
+package hearts
+
+import "time"
+
+const (
+	DefaultMaxHearts      = 5
+	DefaultRefillInterval = 4 * time.Hour
+)
+
+// Hearts is the current state with refills counted up to now
+type Hearts struct {
+	Current int `json:"current"`
+	Max     int `json:"max"`
+	// NextRefillAt is when the next heart comes back, it is empty when hearts are full
+	NextRefillAt *time.Time `json:"next_refill_at,omitempty"`
+}
+
+// record is the saved state, hearts refill from RefilledAt every refill interval
+type record struct {
+	Hearts     int
+	RefilledAt time.Time
+}
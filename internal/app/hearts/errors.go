@@ -0,0 +1,9 @@
+package hearts
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("hearts not found")
+	// errChanged means the hearts were changed by another request after they were read
+	errChanged = errors.New("hearts changed")
+)
@@ -0,0 +1,76 @@
+package hearts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewHeartsRepository(db *pgxpool.Pool) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) getHearts(ctx context.Context, userID string) (record, error) {
+	var (
+		query = `
+		SELECT hearts, refilled_at FROM user_hearts WHERE user_id = $1;
+		`
+		saved record
+	)
+
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&saved.Hearts, &saved.RefilledAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return record{}, ErrNotFound
+		}
+		return record{}, err
+	}
+
+	return saved, nil
+}
+
+// createHearts saves hearts of the user for the first time, until then hearts are full
+func (r *repository) createHearts(ctx context.Context, userID string, saved record) error {
+	var (
+		query = `
+		INSERT INTO user_hearts (user_id, hearts, refilled_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING;
+		`
+	)
+
+	res, err := r.db.Exec(ctx, query, userID, saved.Hearts, saved.RefilledAt)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return errChanged
+	}
+	return nil
+}
+
+// updateHearts saves hearts only if they are still the ones they were computed from
+func (r *repository) updateHearts(ctx context.Context, userID string, from, to record) error {
+	var (
+		query = `
+		UPDATE user_hearts SET hearts = $4, refilled_at = $5
+		WHERE user_id = $1 AND hearts = $2 AND refilled_at = $3;
+		`
+	)
+
+	res, err := r.db.Exec(ctx, query, userID, from.Hearts, from.RefilledAt, to.Hearts, to.RefilledAt)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return errChanged
+	}
+	return nil
+}
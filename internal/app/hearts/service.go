@@ -0,0 +1,158 @@
+package hearts
+
+import (
+	"context"
+	"time"
+	"uiren/pkg/logger"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package hearts
+
+type heartsRepository interface {
+	getHearts(ctx context.Context, userID string) (record, error)
+	createHearts(ctx context.Context, userID string, saved record) error
+	updateHearts(ctx context.Context, userID string, from, to record) error
+}
+
+// changeAttempts is how many times a change is retried when another request changed the hearts first
+const changeAttempts = 3
+
+// HeartsService keeps hearts of users, a heart is lost on a mistake in a lesson
+// and comes back every refill interval until hearts are full
+type HeartsService struct {
+	repo           heartsRepository
+	max            int
+	refillInterval time.Duration
+	now            func() time.Time
+}
+
+func NewHeartsService(repo heartsRepository) *HeartsService {
+	return &HeartsService{
+		repo:           repo,
+		max:            DefaultMaxHearts,
+		refillInterval: DefaultRefillInterval,
+		// timestamp columns keep the wall clock, so times are saved in UTC
+		now: func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *HeartsService) SetMaxHearts(maxHearts int) {
+	s.max = maxHearts
+}
+
+// SetRefillInterval sets how long it takes to refill one heart
+func (s *HeartsService) SetRefillInterval(interval time.Duration) {
+	s.refillInterval = interval
+}
+
+func (s *HeartsService) GetHearts(ctx context.Context, userID string) (Hearts, error) {
+	logger.Info("HeartsService.GetHearts new request")
+
+	saved, err := s.repo.getHearts(ctx, userID)
+	switch err {
+	case nil:
+	case ErrNotFound:
+		return s.toHearts(s.full()), nil
+	default:
+		logger.Error("HeartsService.GetHearts repo.getHearts: ", err)
+		return Hearts{}, err
+	}
+
+	return s.toHearts(s.refill(saved)), nil
+}
+
+// LoseHeart takes a heart for a mistake, hearts don't go below zero
+func (s *HeartsService) LoseHeart(ctx context.Context, userID string) (Hearts, error) {
+	logger.Info("HeartsService.LoseHeart new request")
+
+	hearts, err := s.change(ctx, userID, -1)
+	if err != nil {
+		logger.Error("HeartsService.LoseHeart change: ", err)
+		return Hearts{}, err
+	}
+	return hearts, nil
+}
+
+// EarnHeart gives a heart back for practice, hearts don't go above the max
+func (s *HeartsService) EarnHeart(ctx context.Context, userID string) (Hearts, error) {
+	logger.Info("HeartsService.EarnHeart new request")
+
+	hearts, err := s.change(ctx, userID, 1)
+	if err != nil {
+		logger.Error("HeartsService.EarnHeart change: ", err)
+		return Hearts{}, err
+	}
+	return hearts, nil
+}
+
+// change adds delta to the refilled hearts, it is retried when another device changed them at the same time
+func (s *HeartsService) change(ctx context.Context, userID string, delta int) (Hearts, error) {
+	for attempt := 0; ; attempt++ {
+		saved, err := s.repo.getHearts(ctx, userID)
+		found := err == nil
+		if err != nil && err != ErrNotFound {
+			return Hearts{}, err
+		}
+
+		current := s.full()
+		if found {
+			current = s.refill(saved)
+		}
+
+		changed := current
+		changed.Hearts = min(max(current.Hearts+delta, 0), s.max)
+		if current.Hearts == s.max && changed.Hearts < s.max {
+			// the first lost heart starts the refill countdown
+			changed.RefilledAt = s.now()
+		}
+
+		if found {
+			err = s.repo.updateHearts(ctx, userID, saved, changed)
+		} else {
+			err = s.repo.createHearts(ctx, userID, changed)
+		}
+		if err == errChanged && attempt+1 < changeAttempts {
+			continue
+		}
+		if err != nil {
+			return Hearts{}, err
+		}
+
+		return s.toHearts(changed), nil
+	}
+}
+
+func (s *HeartsService) full() record {
+	return record{Hearts: s.max, RefilledAt: s.now()}
+}
+
+// refill adds hearts that came back since the record was saved
+func (s *HeartsService) refill(saved record) record {
+	if saved.Hearts >= s.max {
+		return record{Hearts: s.max, RefilledAt: saved.RefilledAt}
+	}
+	if s.refillInterval <= 0 {
+		return s.full()
+	}
+
+	refilled := int(s.now().Sub(saved.RefilledAt) / s.refillInterval)
+	if refilled <= 0 {
+		return saved
+	}
+	if saved.Hearts+refilled >= s.max {
+		return s.full()
+	}
+	return record{
+		Hearts:     saved.Hearts + refilled,
+		RefilledAt: saved.RefilledAt.Add(time.Duration(refilled) * s.refillInterval),
+	}
+}
+
+func (s *HeartsService) toHearts(current record) Hearts {
+	hearts := Hearts{Current: current.Hearts, Max: s.max}
+	if current.Hearts < s.max {
+		next := current.RefilledAt.Add(s.refillInterval)
+		hearts.NextRefillAt = &next
+	}
+	return hearts
+}
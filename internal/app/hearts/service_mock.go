@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package hearts is a generated GoMock package.
+package hearts
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockheartsRepository is a mock of heartsRepository interface.
+type MockheartsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockheartsRepositoryMockRecorder
+}
+
+// MockheartsRepositoryMockRecorder is the mock recorder for MockheartsRepository.
+type MockheartsRepositoryMockRecorder struct {
+	mock *MockheartsRepository
+}
+
+// NewMockheartsRepository creates a new mock instance.
+func NewMockheartsRepository(ctrl *gomock.Controller) *MockheartsRepository {
+	mock := &MockheartsRepository{ctrl: ctrl}
+	mock.recorder = &MockheartsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockheartsRepository) EXPECT() *MockheartsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// createHearts mocks base method.
+func (m *MockheartsRepository) createHearts(ctx context.Context, userID string, saved record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createHearts", ctx, userID, saved)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// createHearts indicates an expected call of createHearts.
+func (mr *MockheartsRepositoryMockRecorder) createHearts(ctx, userID, saved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createHearts", reflect.TypeOf((*MockheartsRepository)(nil).createHearts), ctx, userID, saved)
+}
+
+// getHearts mocks base method.
+func (m *MockheartsRepository) getHearts(ctx context.Context, userID string) (record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getHearts", ctx, userID)
+	ret0, _ := ret[0].(record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getHearts indicates an expected call of getHearts.
+func (mr *MockheartsRepositoryMockRecorder) getHearts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getHearts", reflect.TypeOf((*MockheartsRepository)(nil).getHearts), ctx, userID)
+}
+
+// updateHearts mocks base method.
+func (m *MockheartsRepository) updateHearts(ctx context.Context, userID string, from, to record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "updateHearts", ctx, userID, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// updateHearts indicates an expected call of updateHearts.
+func (mr *MockheartsRepositoryMockRecorder) updateHearts(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "updateHearts", reflect.TypeOf((*MockheartsRepository)(nil).updateHearts), ctx, userID, from, to)
+}
@@ -0,0 +1,186 @@
+package hearts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"uiren/pkg/logger"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+const userID = "user-1"
+
+var now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func newTestService(t *testing.T) (*HeartsService, *MockheartsRepository) {
+	repo := NewMockheartsRepository(gomock.NewController(t))
+	srv := NewHeartsService(repo)
+	srv.now = func() time.Time { return now }
+	return srv, repo
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func Test_HeartsService_GetHearts(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx = context.TODO()
+	)
+
+	for name, test := range map[string]struct {
+		saved    record
+		err      error
+		expected Hearts
+	}{
+		"full without saved hearts": {
+			err:      ErrNotFound,
+			expected: Hearts{Current: 5, Max: 5},
+		},
+		"not refilled yet": {
+			saved:    record{Hearts: 2, RefilledAt: now.Add(-time.Hour)},
+			expected: Hearts{Current: 2, Max: 5, NextRefillAt: timePtr(now.Add(3 * time.Hour))},
+		},
+		"refilled two hearts": {
+			saved:    record{Hearts: 1, RefilledAt: now.Add(-9 * time.Hour)},
+			expected: Hearts{Current: 3, Max: 5, NextRefillAt: timePtr(now.Add(3 * time.Hour))},
+		},
+		"refilled to full": {
+			saved:    record{Hearts: 0, RefilledAt: now.Add(-48 * time.Hour)},
+			expected: Hearts{Current: 5, Max: 5},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv, repo := newTestService(t)
+			repo.EXPECT().getHearts(ctx, userID).Return(test.saved, test.err)
+
+			hearts, err := srv.GetHearts(ctx, userID)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, hearts)
+		})
+	}
+
+	t.Run("repo failed", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		errRepo := errors.New("db error")
+		repo.EXPECT().getHearts(ctx, userID).Return(record{}, errRepo)
+
+		_, err := srv.GetHearts(ctx, userID)
+		assert.Equal(t, errRepo, err)
+	})
+}
+
+func Test_HeartsService_LoseHeart(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx = context.TODO()
+	)
+
+	t.Run("first lost heart starts the refill", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		repo.EXPECT().getHearts(ctx, userID).Return(record{}, ErrNotFound)
+		repo.EXPECT().createHearts(ctx, userID, record{Hearts: 4, RefilledAt: now}).Return(nil)
+
+		hearts, err := srv.LoseHeart(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, Hearts{Current: 4, Max: 5, NextRefillAt: timePtr(now.Add(4 * time.Hour))}, hearts)
+	})
+
+	t.Run("refill keeps counting", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		saved := record{Hearts: 2, RefilledAt: now.Add(-5 * time.Hour)}
+		repo.EXPECT().getHearts(ctx, userID).Return(saved, nil)
+		repo.EXPECT().updateHearts(ctx, userID, saved, record{Hearts: 2, RefilledAt: now.Add(-time.Hour)}).Return(nil)
+
+		hearts, err := srv.LoseHeart(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, hearts.Current)
+		assert.Equal(t, now.Add(3*time.Hour), *hearts.NextRefillAt)
+	})
+
+	t.Run("not below zero", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		saved := record{Hearts: 0, RefilledAt: now.Add(-time.Hour)}
+		repo.EXPECT().getHearts(ctx, userID).Return(saved, nil)
+		repo.EXPECT().updateHearts(ctx, userID, saved, saved).Return(nil)
+
+		hearts, err := srv.LoseHeart(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, hearts.Current)
+	})
+
+	t.Run("retried when changed by another request", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		first := record{Hearts: 3, RefilledAt: now.Add(-time.Hour)}
+		second := record{Hearts: 2, RefilledAt: now.Add(-time.Hour)}
+		gomock.InOrder(
+			repo.EXPECT().getHearts(ctx, userID).Return(first, nil),
+			repo.EXPECT().updateHearts(ctx, userID, first, gomock.Any()).Return(errChanged),
+			repo.EXPECT().getHearts(ctx, userID).Return(second, nil),
+			repo.EXPECT().updateHearts(ctx, userID, second, record{Hearts: 1, RefilledAt: second.RefilledAt}).Return(nil),
+		)
+
+		hearts, err := srv.LoseHeart(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, hearts.Current)
+	})
+
+	t.Run("gives up after attempts", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		repo.EXPECT().getHearts(ctx, userID).Return(record{}, ErrNotFound).Times(changeAttempts)
+		repo.EXPECT().createHearts(ctx, userID, gomock.Any()).Return(errChanged).Times(changeAttempts)
+
+		_, err := srv.LoseHeart(ctx, userID)
+		assert.Equal(t, errChanged, err)
+	})
+}
+
+func Test_HeartsService_EarnHeart(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx = context.TODO()
+	)
+
+	t.Run("earned heart", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		saved := record{Hearts: 0, RefilledAt: now.Add(-time.Hour)}
+		repo.EXPECT().getHearts(ctx, userID).Return(saved, nil)
+		repo.EXPECT().updateHearts(ctx, userID, saved, record{Hearts: 1, RefilledAt: saved.RefilledAt}).Return(nil)
+
+		hearts, err := srv.EarnHeart(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, Hearts{Current: 1, Max: 5, NextRefillAt: timePtr(now.Add(3 * time.Hour))}, hearts)
+	})
+
+	t.Run("not above max", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		srv.SetMaxHearts(3)
+		saved := record{Hearts: 3, RefilledAt: now.Add(-time.Hour)}
+		repo.EXPECT().getHearts(ctx, userID).Return(saved, nil)
+		repo.EXPECT().updateHearts(ctx, userID, saved, saved).Return(nil)
+
+		hearts, err := srv.EarnHeart(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, Hearts{Current: 3, Max: 3}, hearts)
+	})
+
+	t.Run("refill interval", func(t *testing.T) {
+		srv, repo := newTestService(t)
+		srv.SetRefillInterval(30 * time.Minute)
+		saved := record{Hearts: 1, RefilledAt: now.Add(-time.Hour)}
+		repo.EXPECT().getHearts(ctx, userID).Return(saved, nil)
+		repo.EXPECT().updateHearts(ctx, userID, saved, record{Hearts: 4, RefilledAt: now}).Return(nil)
+
+		hearts, err := srv.EarnHeart(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, now.Add(30*time.Minute), *hearts.NextRefillAt)
+	})
+}
@@ -0,0 +1,20 @@
+package integrity
+
+const (
+	ParentModule = "module"
+	ParentLesson = "lesson"
+)
+
+// DanglingReference is a code in a parent's list that points to missing or deleted content
+type DanglingReference struct {
+	ParentType string `json:"parent_type"`
+	ParentCode string `json:"parent_code"`
+	Code       string `json:"code"`
+}
+
+type ContentHealth struct {
+	Healthy           bool                `json:"healthy"`
+	DanglingCodes     []DanglingReference `json:"dangling_codes"`
+	OrphanedExercises []string            `json:"orphaned_exercises"`
+	EmptyLessons      []string            `json:"empty_lessons"`
+}
@@ -0,0 +1,124 @@
+package integrity
+
+import (
+	"context"
+	"sort"
+	"uiren/internal/app/exercises"
+	"uiren/pkg/logger"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package integrity
+
+type modulesService interface {
+	GetModuleLessonCodes(ctx context.Context) (map[string][]string, error)
+}
+
+type lessonsService interface {
+	GetLessonExerciseCodes(ctx context.Context) (map[string][]string, error)
+}
+
+type exerciseService interface {
+	GetAllExercises(ctx context.Context) ([]exercises.Exercise, error)
+}
+
+type IntegrityService struct {
+	modulesService  modulesService
+	lessonsService  lessonsService
+	exerciseService exerciseService
+}
+
+func NewIntegrityService(modulesService modulesService, lessonsService lessonsService, exerciseService exerciseService) *IntegrityService {
+	return &IntegrityService{
+		modulesService:  modulesService,
+		lessonsService:  lessonsService,
+		exerciseService: exerciseService,
+	}
+}
+
+// GetContentHealth reports list entries pointing to missing content,
+// exercises that are not used in any lesson and lessons without exercises
+func (s *IntegrityService) GetContentHealth(ctx context.Context) (ContentHealth, error) {
+	logger.Info("IntegrityService.GetContentHealth new request")
+
+	moduleLessons, err := s.modulesService.GetModuleLessonCodes(ctx)
+	if err != nil {
+		logger.Error("IntegrityService.GetContentHealth modulesService.GetModuleLessonCodes: ", err)
+		return ContentHealth{}, err
+	}
+
+	lessonExercises, err := s.lessonsService.GetLessonExerciseCodes(ctx)
+	if err != nil {
+		logger.Error("IntegrityService.GetContentHealth lessonsService.GetLessonExerciseCodes: ", err)
+		return ContentHealth{}, err
+	}
+
+	exerciseList, err := s.exerciseService.GetAllExercises(ctx)
+	if err != nil {
+		logger.Error("IntegrityService.GetContentHealth exerciseService.GetAllExercises: ", err)
+		return ContentHealth{}, err
+	}
+
+	health := ContentHealth{
+		DanglingCodes:     make([]DanglingReference, 0),
+		OrphanedExercises: make([]string, 0),
+		EmptyLessons:      make([]string, 0),
+	}
+
+	for _, moduleCode := range sortedKeys(moduleLessons) {
+		for _, lessonCode := range moduleLessons[moduleCode] {
+			if _, ok := lessonExercises[lessonCode]; !ok {
+				health.DanglingCodes = append(health.DanglingCodes, DanglingReference{
+					ParentType: ParentModule,
+					ParentCode: moduleCode,
+					Code:       lessonCode,
+				})
+			}
+		}
+	}
+
+	existingExercises := make(map[string]struct{}, len(exerciseList))
+	for _, exercise := range exerciseList {
+		existingExercises[exercise.Code] = struct{}{}
+	}
+
+	usedExercises := make(map[string]struct{})
+	for _, lessonCode := range sortedKeys(lessonExercises) {
+		codes := lessonExercises[lessonCode]
+		if len(codes) == 0 {
+			health.EmptyLessons = append(health.EmptyLessons, lessonCode)
+		}
+
+		for _, exerciseCode := range codes {
+			usedExercises[exerciseCode] = struct{}{}
+			if _, ok := existingExercises[exerciseCode]; !ok {
+				health.DanglingCodes = append(health.DanglingCodes, DanglingReference{
+					ParentType: ParentLesson,
+					ParentCode: lessonCode,
+					Code:       exerciseCode,
+				})
+			}
+		}
+	}
+
+	for _, exercise := range exerciseList {
+		if _, ok := usedExercises[exercise.Code]; !ok {
+			health.OrphanedExercises = append(health.OrphanedExercises, exercise.Code)
+		}
+	}
+	sort.Strings(health.OrphanedExercises)
+
+	health.Healthy = len(health.DanglingCodes) == 0 &&
+		len(health.OrphanedExercises) == 0 &&
+		len(health.EmptyLessons) == 0
+
+	return health, nil
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
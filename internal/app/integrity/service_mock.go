@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package integrity is a generated GoMock package.
+package integrity
+
+import (
+	context "context"
+	reflect "reflect"
+	exercises "uiren/internal/app/exercises"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockmodulesService is a mock of modulesService interface.
+type MockmodulesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockmodulesServiceMockRecorder
+}
+
+// MockmodulesServiceMockRecorder is the mock recorder for MockmodulesService.
+type MockmodulesServiceMockRecorder struct {
+	mock *MockmodulesService
+}
+
+// NewMockmodulesService creates a new mock instance.
+func NewMockmodulesService(ctrl *gomock.Controller) *MockmodulesService {
+	mock := &MockmodulesService{ctrl: ctrl}
+	mock.recorder = &MockmodulesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmodulesService) EXPECT() *MockmodulesServiceMockRecorder {
+	return m.recorder
+}
+
+// GetModuleLessonCodes mocks base method.
+func (m *MockmodulesService) GetModuleLessonCodes(ctx context.Context) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModuleLessonCodes", ctx)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModuleLessonCodes indicates an expected call of GetModuleLessonCodes.
+func (mr *MockmodulesServiceMockRecorder) GetModuleLessonCodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleLessonCodes", reflect.TypeOf((*MockmodulesService)(nil).GetModuleLessonCodes), ctx)
+}
+
+// MocklessonsService is a mock of lessonsService interface.
+type MocklessonsService struct {
+	ctrl     *gomock.Controller
+	recorder *MocklessonsServiceMockRecorder
+}
+
+// MocklessonsServiceMockRecorder is the mock recorder for MocklessonsService.
+type MocklessonsServiceMockRecorder struct {
+	mock *MocklessonsService
+}
+
+// NewMocklessonsService creates a new mock instance.
+func NewMocklessonsService(ctrl *gomock.Controller) *MocklessonsService {
+	mock := &MocklessonsService{ctrl: ctrl}
+	mock.recorder = &MocklessonsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocklessonsService) EXPECT() *MocklessonsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetLessonExerciseCodes mocks base method.
+func (m *MocklessonsService) GetLessonExerciseCodes(ctx context.Context) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLessonExerciseCodes", ctx)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLessonExerciseCodes indicates an expected call of GetLessonExerciseCodes.
+func (mr *MocklessonsServiceMockRecorder) GetLessonExerciseCodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLessonExerciseCodes", reflect.TypeOf((*MocklessonsService)(nil).GetLessonExerciseCodes), ctx)
+}
+
+// MockexerciseService is a mock of exerciseService interface.
+type MockexerciseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockexerciseServiceMockRecorder
+}
+
+// MockexerciseServiceMockRecorder is the mock recorder for MockexerciseService.
+type MockexerciseServiceMockRecorder struct {
+	mock *MockexerciseService
+}
+
+// NewMockexerciseService creates a new mock instance.
+func NewMockexerciseService(ctrl *gomock.Controller) *MockexerciseService {
+	mock := &MockexerciseService{ctrl: ctrl}
+	mock.recorder = &MockexerciseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockexerciseService) EXPECT() *MockexerciseServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAllExercises mocks base method.
+func (m *MockexerciseService) GetAllExercises(ctx context.Context) ([]exercises.Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllExercises", ctx)
+	ret0, _ := ret[0].([]exercises.Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllExercises indicates an expected call of GetAllExercises.
+func (mr *MockexerciseServiceMockRecorder) GetAllExercises(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllExercises", reflect.TypeOf((*MockexerciseService)(nil).GetAllExercises), ctx)
+}
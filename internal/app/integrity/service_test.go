@@ -0,0 +1,70 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"uiren/internal/app/exercises"
+	"uiren/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+func Test_IntegrityService_GetContentHealth(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		modulesSrv  = NewMockmodulesService(ctrl)
+		lessonsSrv  = NewMocklessonsService(ctrl)
+		exerciseSrv = NewMockexerciseService(ctrl)
+		srv         = NewIntegrityService(modulesSrv, lessonsSrv, exerciseSrv)
+		errRepo     = errors.New("repo error")
+	)
+
+	t.Run("problems found", func(t *testing.T) {
+		modulesSrv.EXPECT().GetModuleLessonCodes(ctx).Return(map[string][]string{
+			"module1": {"lesson1", "deleted_lesson"},
+		}, nil)
+		lessonsSrv.EXPECT().GetLessonExerciseCodes(ctx).Return(map[string][]string{
+			"lesson1": {"ex1", "deleted_ex"},
+			"lesson2": {},
+		}, nil)
+		exerciseSrv.EXPECT().GetAllExercises(ctx).Return([]exercises.Exercise{
+			{Code: "ex1"},
+			{Code: "orphan"},
+		}, nil)
+
+		health, err := srv.GetContentHealth(ctx)
+		assert.NoError(t, err)
+		assert.False(t, health.Healthy)
+		assert.Equal(t, []DanglingReference{
+			{ParentType: ParentModule, ParentCode: "module1", Code: "deleted_lesson"},
+			{ParentType: ParentLesson, ParentCode: "lesson1", Code: "deleted_ex"},
+		}, health.DanglingCodes)
+		assert.Equal(t, []string{"orphan"}, health.OrphanedExercises)
+		assert.Equal(t, []string{"lesson2"}, health.EmptyLessons)
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		modulesSrv.EXPECT().GetModuleLessonCodes(ctx).Return(map[string][]string{"module1": {"lesson1"}}, nil)
+		lessonsSrv.EXPECT().GetLessonExerciseCodes(ctx).Return(map[string][]string{"lesson1": {"ex1"}}, nil)
+		exerciseSrv.EXPECT().GetAllExercises(ctx).Return([]exercises.Exercise{{Code: "ex1"}}, nil)
+
+		health, err := srv.GetContentHealth(ctx)
+		assert.NoError(t, err)
+		assert.True(t, health.Healthy)
+	})
+
+	t.Run("repo fail", func(t *testing.T) {
+		modulesSrv.EXPECT().GetModuleLessonCodes(ctx).Return(nil, errRepo)
+
+		_, err := srv.GetContentHealth(ctx)
+		assert.Equal(t, errRepo, err)
+	})
+}
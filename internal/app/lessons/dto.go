@@ -10,6 +10,7 @@ type lesson struct {
 	Title       string     `bson:"title" json:"title"`
 	Description string     `bson:"description" json:"description"`
 	Exercises   []string   `bson:"exercises" json:"exercises"`
+	Status      string     `bson:"status" json:"status"`
 	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
 	DeletedAt   *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
 }
@@ -19,6 +20,7 @@ type LessonDTO struct {
 	Title       string               `json:"title"`
 	Description string               `json:"description"`
 	Exercises   []exercises.Exercise `json:"exercises"`
+	Status      string               `json:"status"`
 	CreatedAt   time.Time            `json:"created_at"`
 	DeletedAt   time.Time            `json:"deleted_at"`
 }
@@ -33,6 +35,7 @@ func (lesson lesson) toDTO(exercises []exercises.Exercise) LessonDTO {
 		Title:       lesson.Title,
 		Description: lesson.Description,
 		Exercises:   exercises,
+		Status:      lesson.Status,
 		CreatedAt:   lesson.CreatedAt,
 		DeletedAt:   lessonDeletedAt,
 	}
@@ -45,6 +48,7 @@ type CreateLessonDTO struct {
 	Title       string     `bson:"title" json:"title"`
 	Description string     `bson:"description" json:"description"`
 	Exercises   []string   `bson:"exercises"`
+	Status      string     `bson:"status" json:"-"`
 	CreatedAt   time.Time  `bson:"created_at"`
 	DeletedAt   *time.Time `bson:"deleted_at"`
 }
@@ -53,3 +57,17 @@ type UpdateLessonDTO struct {
 	Title       *string `bson:"title" json:"title"`
 	Description *string `bson:"description" json:"description"`
 }
+
+type ReorderExercisesDTO struct {
+	Exercises []string `json:"exercises"`
+}
+
+// SortFields lists fields the lessons list can be sorted by, the first one is the default
+var SortFields = []string{"created_at", "code", "title"}
+
+// ListFilter narrows the lessons list, empty fields are not applied
+type ListFilter struct {
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
@@ -1,6 +1,10 @@
 package lessons
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	ErrCodeAlreadyExists    = errors.New("code already exists")
@@ -8,4 +12,20 @@ var (
 	ErrNotFound             = errors.New("lesson not found")
 	ErrExerciseAlreadyInSet = errors.New("exercise already in list")
 	ErrExerciseNotInList    = errors.New("exercise is not in list")
+	ErrNotPermutation       = errors.New("exercises list must contain exactly the current exercises")
+	ErrListChanged          = errors.New("exercises list was changed by another request")
+	ErrReferenced           = errors.New("lesson is used in modules")
 )
+
+// ReferencedError is returned when lesson can't be deleted without cascade
+type ReferencedError struct {
+	Parents []string
+}
+
+func (e *ReferencedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrReferenced, strings.Join(e.Parents, ", "))
+}
+
+func (e *ReferencedError) Unwrap() error {
+	return ErrReferenced
+}
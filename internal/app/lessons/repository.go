@@ -3,8 +3,11 @@ package lessons
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
+	"uiren/internal/app/publication"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -48,6 +51,15 @@ func (r *lessonRepository) getLessonsByCodes(ctx context.Context, codes []string
 		return nil, err
 	}
 
+	// $in does not keep the order of codes, lists in parents are ordered
+	position := make(map[string]int, len(codes))
+	for i, code := range codes {
+		position[code] = i
+	}
+	sort.SliceStable(response, func(i, j int) bool {
+		return position[response[i].Code] < position[response[j].Code]
+	})
+
 	return response, nil
 }
 
@@ -91,10 +103,9 @@ func (r *lessonRepository) createLesson(ctx context.Context, dto CreateLessonDTO
 	return oid, nil
 }
 
-func (r *lessonRepository) updateLesson(ctx context.Context, code string, dto UpdateLessonDTO) error {
+func (r *lessonRepository) updateLesson(ctx context.Context, code string, dto UpdateLessonDTO) (lesson, error) {
 	var (
-		collection = r.db.Collection(lessonsCollection)
-		filter     = bson.M{
+		filter = bson.M{
 			"code":       code,
 			"deleted_at": nil,
 		}
@@ -108,51 +119,112 @@ func (r *lessonRepository) updateLesson(ctx context.Context, code string, dto Up
 		update["description"] = *dto.Description
 	}
 	if len(update) == 0 {
-		return ErrNoFieldsToUpdate
+		return lesson{}, ErrNoFieldsToUpdate
 	}
 	update = bson.M{"$set": update}
 
-	res, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
+	updated, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return lesson{}, ErrNotFound
 	}
-	if res.MatchedCount == 0 {
-		return ErrNotFound
+
+	return updated, err
+}
+
+func (r *lessonRepository) restoreLesson(ctx context.Context, code string, snapshot lesson) (lesson, error) {
+	var (
+		filter = bson.M{
+			"code":       code,
+			"deleted_at": nil,
+		}
+		update = bson.M{"$set": bson.M{
+			"title":       snapshot.Title,
+			"description": snapshot.Description,
+			"exercises":   snapshot.Exercises,
+		}}
+	)
+
+	restored, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return lesson{}, ErrNotFound
 	}
 
-	return nil
+	return restored, err
 }
 
-func (r *lessonRepository) deleteLesson(ctx context.Context, code string) error {
+// setLessonStatus changes status only if nobody changed it since it was read
+func (r *lessonRepository) setLessonStatus(ctx context.Context, code, from, to string) (lesson, error) {
+	var (
+		filter = bson.M{
+			"code":       code,
+			"deleted_at": nil,
+			"status":     from,
+		}
+		update = bson.M{"$set": bson.M{"status": to}}
+	)
+
+	updated, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return lesson{}, publication.ErrStatusChanged
+	}
+
+	return updated, err
+}
+
+func (r *lessonRepository) getLessonCodesByExercise(ctx context.Context, exerciseCode string) ([]string, error) {
 	var (
 		collection = r.db.Collection(lessonsCollection)
 		filter     = bson.M{
-			"code":       code,
+			"exercises":  exerciseCode,
 			"deleted_at": nil,
 		}
-		update = bson.M{"$set": bson.M{"deleted_at": time.Now()}}
+		opts  = options.Find().SetProjection(bson.M{"code": 1, "_id": 0})
+		codes = make([]string, 0)
 	)
 
-	res, err := collection.UpdateOne(ctx, filter, update)
+	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	if res.MatchedCount == 0 {
-		return ErrNotFound
+	for cursor.Next(ctx) {
+		var lesson lesson
+		if err := cursor.Decode(&lesson); err != nil {
+			return nil, err
+		}
+		codes = append(codes, lesson.Code)
 	}
 
-	return nil
+	return codes, cursor.Err()
 }
 
-func (r *lessonRepository) addExerciseToList(ctx context.Context, code, exerciseCode string) error {
+func (r *lessonRepository) unlinkExercise(ctx context.Context, code, exerciseCode string) (lesson, error) {
+	var (
+		filter = bson.M{
+			"code":       code,
+			"exercises":  exerciseCode,
+			"deleted_at": nil,
+		}
+		update = bson.M{"$pull": bson.M{"exercises": exerciseCode}}
+	)
+
+	updated, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return lesson{}, ErrExerciseNotInList
+	}
+
+	return updated, err
+}
+
+func (r *lessonRepository) deleteLesson(ctx context.Context, code string) error {
 	var (
 		collection = r.db.Collection(lessonsCollection)
 		filter     = bson.M{
 			"code":       code,
 			"deleted_at": nil,
 		}
-		update = bson.M{"$addToSet": bson.M{"exercises": exerciseCode}}
+		update = bson.M{"$set": bson.M{"deleted_at": time.Now()}}
 	)
 
 	res, err := collection.UpdateOne(ctx, filter, update)
@@ -163,33 +235,83 @@ func (r *lessonRepository) addExerciseToList(ctx context.Context, code, exercise
 	if res.MatchedCount == 0 {
 		return ErrNotFound
 	}
-	if res.ModifiedCount == 0 {
-		return ErrExerciseAlreadyInSet
-	}
 
 	return nil
 }
 
-func (r *lessonRepository) deleteExerciseFromList(ctx context.Context, code, exerciseCode string) error {
+func (r *lessonRepository) addExerciseToList(ctx context.Context, code, exerciseCode string) (lesson, error) {
 	var (
-		collection = r.db.Collection(lessonsCollection)
-		filter     = bson.M{"code": code, "deleted_at": nil}
-		update     = bson.M{"$pull": bson.M{"exercises": exerciseCode}}
+		filter = bson.M{
+			"code":       code,
+			"deleted_at": nil,
+			"exercises":  bson.M{"$ne": exerciseCode},
+		}
+		update = bson.M{"$push": bson.M{"exercises": exerciseCode}}
 	)
 
-	res, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
+	updated, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// either there is no such lesson or the exercise is in the list already
+		if _, err := r.getLesson(ctx, code); err != nil {
+			return lesson{}, err
+		}
+		return lesson{}, ErrExerciseAlreadyInSet
 	}
 
-	if res.MatchedCount == 0 {
-		return ErrNotFound
+	return updated, err
+}
+
+func (r *lessonRepository) deleteExerciseFromList(ctx context.Context, code, exerciseCode string) (lesson, error) {
+	var (
+		filter = bson.M{"code": code, "deleted_at": nil, "exercises": exerciseCode}
+		update = bson.M{"$pull": bson.M{"exercises": exerciseCode}}
+	)
+
+	updated, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// either there is no such lesson or the exercise is not in the list
+		if _, err := r.getLesson(ctx, code); err != nil {
+			return lesson{}, err
+		}
+		return lesson{}, ErrExerciseNotInList
 	}
-	if res.ModifiedCount == 0 {
-		return ErrExerciseNotInList
+
+	return updated, err
+}
+
+// reorderExercises writes the new order only if the list still equals the one that was validated
+func (r *lessonRepository) reorderExercises(ctx context.Context, code string, current, exercises []string) (lesson, error) {
+	var (
+		filter = bson.M{
+			"code":       code,
+			"deleted_at": nil,
+			"exercises":  current,
+		}
+		update = bson.M{"$set": bson.M{"exercises": exercises}}
+	)
+
+	updated, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return lesson{}, ErrListChanged
 	}
 
-	return nil
+	return updated, err
+}
+
+// findAndUpdate returns the lesson as the update left it, revisions are saved from it
+// so a write that lands right after this one doesn't get into this revision
+func (r *lessonRepository) findAndUpdate(ctx context.Context, filter, update bson.M) (lesson, error) {
+	var (
+		collection = r.db.Collection(lessonsCollection)
+		opts       = options.FindOneAndUpdate().SetReturnDocument(options.After)
+		response   lesson
+	)
+
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&response); err != nil {
+		return lesson{}, err
+	}
+
+	return response, nil
 }
 
 func (r *lessonRepository) getAllLessons(ctx context.Context) ([]lesson, error) {
@@ -212,6 +334,57 @@ func (r *lessonRepository) getAllLessons(ctx context.Context) ([]lesson, error)
 	return result, nil
 }
 
+func (r *lessonRepository) listLessons(ctx context.Context, filter ListFilter, params pagination.Params) ([]lesson, error) {
+	var (
+		collection = r.db.Collection(lessonsCollection)
+		query      = bson.M{"deleted_at": nil}
+		opts       = options.Find().SetSort(pagination.MongoSort(params, params.SortBy, "code")).SetLimit(int64(params.Fetch()))
+		result     []lesson
+	)
+
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if created := createdRange(filter); len(created) > 0 {
+		query["created_at"] = created
+	}
+
+	if params.After != nil {
+		var value interface{} = params.After.Value
+		if params.SortBy == "created_at" {
+			createdAt, err := params.After.TimeValue()
+			if err != nil {
+				return nil, err
+			}
+			value = createdAt
+		}
+		query = bson.M{"$and": bson.A{query, pagination.MongoAfter(params, params.SortBy, value, "code", params.After.Key)}}
+	}
+
+	cur, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func createdRange(filter ListFilter) bson.M {
+	created := bson.M{}
+	if filter.CreatedFrom != nil {
+		created["$gte"] = *filter.CreatedFrom
+	}
+	if filter.CreatedTo != nil {
+		created["$lt"] = *filter.CreatedTo
+	}
+	return created
+}
+
 func (r *lessonRepository) lessonExists(ctx context.Context, code string) (bool, error) {
 	var (
 		collection = r.db.Collection(lessonsCollection)
@@ -2,9 +2,15 @@ package lessons
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
+	"uiren/internal/app/events"
 	"uiren/internal/app/exercises"
+	"uiren/internal/app/publication"
+	"uiren/internal/app/revisions"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -13,25 +19,50 @@ import (
 
 type repository interface {
 	createLesson(ctx context.Context, dto CreateLessonDTO) (primitive.ObjectID, error)
-	updateLesson(ctx context.Context, code string, dto UpdateLessonDTO) error
+	updateLesson(ctx context.Context, code string, dto UpdateLessonDTO) (lesson, error)
 	deleteLesson(ctx context.Context, code string) error
 	getLesson(ctx context.Context, code string) (lesson, error)
 	getLessonsByCodes(ctx context.Context, codes []string) ([]lesson, error)
-	addExerciseToList(ctx context.Context, code, exerciseCode string) error
-	deleteExerciseFromList(ctx context.Context, code, exerciseCode string) error
+	addExerciseToList(ctx context.Context, code, exerciseCode string) (lesson, error)
+	deleteExerciseFromList(ctx context.Context, code, exerciseCode string) (lesson, error)
+	reorderExercises(ctx context.Context, code string, current, exercises []string) (lesson, error)
+	restoreLesson(ctx context.Context, code string, snapshot lesson) (lesson, error)
+	setLessonStatus(ctx context.Context, code, from, to string) (lesson, error)
+	getLessonCodesByExercise(ctx context.Context, exerciseCode string) ([]string, error)
+	unlinkExercise(ctx context.Context, code, exerciseCode string) (lesson, error)
 
 	getAllLessons(ctx context.Context) ([]lesson, error)
+	listLessons(ctx context.Context, filter ListFilter, params pagination.Params) ([]lesson, error)
 	lessonExists(ctx context.Context, code string) (bool, error)
 }
 
 type exerciseService interface {
 	GetExercisesByCodes(ctx context.Context, codes []string) ([]exercises.Exercise, error)
 	ExerciseExists(ctx context.Context, code string) (bool, error)
+	ValidateExercisesPublished(ctx context.Context, codes []string) error
+}
+
+type publisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
+type revisionService interface {
+	SaveRevision(ctx context.Context, entityType, code string, document interface{}) (int, error)
+	GetRevision(ctx context.Context, entityType, code string, version int) (revisions.Revision, error)
+}
+
+// referenceTracker knows which modules use a lesson, modules package implements it
+type referenceTracker interface {
+	GetModulesReferencingLesson(ctx context.Context, lessonCode string) ([]string, error)
+	UnlinkLesson(ctx context.Context, lessonCode string) error
 }
 
 type LessonsService struct {
-	repo            repository
-	exerciseService exerciseService
+	repo             repository
+	exerciseService  exerciseService
+	revisionService  revisionService
+	publisher        publisher
+	referenceTracker referenceTracker
 }
 
 func NewLessonsService(repo repository, exerciseService exerciseService) *LessonsService {
@@ -41,6 +72,18 @@ func NewLessonsService(repo repository, exerciseService exerciseService) *Lesson
 	}
 }
 
+func (s *LessonsService) WithRevisionService(revisionService revisionService) {
+	s.revisionService = revisionService
+}
+
+func (s *LessonsService) WithPublisher(publisher publisher) {
+	s.publisher = publisher
+}
+
+func (s *LessonsService) WithReferenceTracker(referenceTracker referenceTracker) {
+	s.referenceTracker = referenceTracker
+}
+
 func (s LessonsService) GetLessonsByCodes(ctx context.Context, codes []string) ([]LessonDTO, error) {
 	logger.Info("LessonsService.GetLessonsByCodes new request")
 
@@ -50,14 +93,10 @@ func (s LessonsService) GetLessonsByCodes(ctx context.Context, codes []string) (
 		return nil, err
 	}
 
-	var response []LessonDTO
-	for _, lesson := range lessons {
-		exerciseList, err := s.exerciseService.GetExercisesByCodes(ctx, lesson.Exercises)
-		if err != nil {
-			logger.Error("LessonsService.GetLessonsByCodes exerciseService.GetExercisesByCodes: ", err)
-			return nil, err
-		}
-		response = append(response, lesson.toDTO(exerciseList))
+	response, err := s.withExercises(ctx, lessons)
+	if err != nil {
+		logger.Error("LessonsService.GetLessonsByCodes withExercises: ", err)
+		return nil, err
 	}
 	return response, nil
 }
@@ -84,12 +123,16 @@ func (s LessonsService) CreateLesson(ctx context.Context, dto CreateLessonDTO) (
 	logger.Info("LessonsService.CreateLesson new request")
 	dto.CreatedAt = time.Now()
 	dto.Exercises = make([]string, 0)
+	dto.Status = publication.StatusDraft
 
 	oid, err := s.repo.createLesson(ctx, dto)
 	if err != nil {
 		logger.Error("LessonsService.CreateLesson repo.createLesson: ", err)
 		return primitive.NilObjectID, err
 	}
+	// the created lesson is exactly the inserted document
+	s.saveRevision(ctx, lesson(dto))
+	s.publish(ctx, dto.Code)
 
 	return oid, nil
 }
@@ -97,22 +140,47 @@ func (s LessonsService) CreateLesson(ctx context.Context, dto CreateLessonDTO) (
 func (s LessonsService) UpdateLesson(ctx context.Context, code string, dto UpdateLessonDTO) error {
 	logger.Info("LessonsService.UpdateLesson new request")
 
-	if err := s.repo.updateLesson(ctx, code, dto); err != nil {
+	updated, err := s.repo.updateLesson(ctx, code, dto)
+	if err != nil {
 		logger.Error("LessonsService.UpdateLesson repo.updateLesson: ", err)
 		return err
 	}
+	s.saveRevision(ctx, updated)
+	s.publish(ctx, code)
 
 	return nil
 }
 
-func (s LessonsService) DeleteLesson(ctx context.Context, code string) error {
+// DeleteLesson refuses to delete a lesson used in modules unless cascade is set,
+// with cascade the lesson is removed from every module first
+func (s LessonsService) DeleteLesson(ctx context.Context, code string, cascade bool) error {
 	logger.Info("LessonsService.DeleteLesson new request")
 
+	if s.referenceTracker != nil {
+		parents, err := s.referenceTracker.GetModulesReferencingLesson(ctx, code)
+		if err != nil {
+			logger.Error("LessonsService.DeleteLesson referenceTracker.GetModulesReferencingLesson: ", err)
+			return err
+		}
+
+		if len(parents) > 0 {
+			if !cascade {
+				return &ReferencedError{Parents: parents}
+			}
+			if err := s.referenceTracker.UnlinkLesson(ctx, code); err != nil {
+				logger.Error("LessonsService.DeleteLesson referenceTracker.UnlinkLesson: ", err)
+				return err
+			}
+		}
+	}
+
 	if err := s.repo.deleteLesson(ctx, code); err != nil {
 		logger.Error("LessonsService.DeleteService repo.deleteLesson: ", err)
 		return err
 	}
 
+	s.publish(ctx, code)
+
 	return nil
 }
 
@@ -129,10 +197,27 @@ func (s LessonsService) AddExerciseToList(ctx context.Context, code, exerciseCod
 		return exercises.ErrNotFound
 	}
 
-	if err := s.repo.addExerciseToList(ctx, code, exerciseCode); err != nil {
+	lesson, err := s.repo.getLesson(ctx, code)
+	if err != nil {
+		logger.Error("LessonsService.AddExerciseToList repo.getLesson: ", err)
+		return err
+	}
+
+	// learners see every exercise of a published lesson, so it can't get a draft one
+	if lesson.Status == publication.StatusPublished {
+		if err := s.exerciseService.ValidateExercisesPublished(ctx, []string{exerciseCode}); err != nil {
+			logger.Error("LessonsService.AddExerciseToList exerciseService.ValidateExercisesPublished: ", err)
+			return err
+		}
+	}
+
+	updated, err := s.repo.addExerciseToList(ctx, code, exerciseCode)
+	if err != nil {
 		logger.Error("LessonsService.AddExerciseToList repo.addExerciseToList: ", err)
 		return err
 	}
+	s.saveRevision(ctx, updated)
+	s.publish(ctx, code)
 
 	return nil
 }
@@ -140,10 +225,38 @@ func (s LessonsService) AddExerciseToList(ctx context.Context, code, exerciseCod
 func (s LessonsService) DeleteExerciseFromList(ctx context.Context, code, exerciseCode string) error {
 	logger.Info("LessonsService.DeleteExerciseFromList new request")
 
-	if err := s.repo.deleteExerciseFromList(ctx, code, exerciseCode); err != nil {
+	updated, err := s.repo.deleteExerciseFromList(ctx, code, exerciseCode)
+	if err != nil {
 		logger.Error("LessonsService.DeleteExerciseFromList repo.addExerciseToList: ", err)
 		return err
 	}
+	s.saveRevision(ctx, updated)
+	s.publish(ctx, code)
+
+	return nil
+}
+
+// ReorderExercises replaces the exercises list with the same codes in a new order
+func (s LessonsService) ReorderExercises(ctx context.Context, code string, exerciseCodes []string) error {
+	logger.Info("LessonsService.ReorderExercises new request")
+
+	lesson, err := s.repo.getLesson(ctx, code)
+	if err != nil {
+		logger.Error("LessonsService.ReorderExercises repo.getLesson: ", err)
+		return err
+	}
+
+	if !isPermutation(lesson.Exercises, exerciseCodes) {
+		return ErrNotPermutation
+	}
+
+	lesson, err = s.repo.reorderExercises(ctx, code, lesson.Exercises, exerciseCodes)
+	if err != nil {
+		logger.Error("LessonsService.ReorderExercises repo.reorderExercises: ", err)
+		return err
+	}
+	s.saveRevision(ctx, lesson)
+	s.publish(ctx, code)
 
 	return nil
 }
@@ -157,15 +270,89 @@ func (s LessonsService) GetAllLessonsWithExercises(ctx context.Context) ([]Lesso
 		return nil, err
 	}
 
-	var result []LessonDTO
+	result, err := s.withExercises(ctx, lessons)
+	if err != nil {
+		logger.Error("LessonsService.GetAllLessonsWithExercises withExercises: ", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListLessons returns one page of lessons with their exercises
+func (s LessonsService) ListLessons(ctx context.Context, filter ListFilter, params pagination.Params) (pagination.Page[LessonDTO], error) {
+	logger.Info("LessonsService.ListLessons new request")
+
+	lessons, err := s.repo.listLessons(ctx, filter, params)
+	if err != nil {
+		logger.Error("LessonsService.ListLessons repo.listLessons: ", err)
+		return pagination.Page[LessonDTO]{}, err
+	}
+
+	page := pagination.NewPage(lessons, params, lessonCursor)
+
+	result, err := s.withExercises(ctx, page.Items)
+	if err != nil {
+		logger.Error("LessonsService.ListLessons withExercises: ", err)
+		return pagination.Page[LessonDTO]{}, err
+	}
+	if result == nil {
+		result = make([]LessonDTO, 0)
+	}
+
+	return pagination.Page[LessonDTO]{Items: result, NextCursor: page.NextCursor}, nil
+}
+
+func lessonCursor(lesson lesson, sortBy string) (string, string) {
+	switch sortBy {
+	case "title":
+		return lesson.Title, lesson.Code
+	case "code":
+		return lesson.Code, lesson.Code
+	default:
+		return pagination.FormatTime(lesson.CreatedAt), lesson.Code
+	}
+}
+
+// withExercises loads exercises of all lessons with one query and keeps the order of every list
+func (s LessonsService) withExercises(ctx context.Context, lessons []lesson) ([]LessonDTO, error) {
+	if len(lessons) == 0 {
+		return nil, nil
+	}
+
+	var codes []string
+	seen := make(map[string]struct{})
 	for _, lesson := range lessons {
-		exercises, err := s.exerciseService.GetExercisesByCodes(ctx, lesson.Exercises)
+		for _, code := range lesson.Exercises {
+			if _, ok := seen[code]; !ok {
+				seen[code] = struct{}{}
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	exercisesByCode := make(map[string]exercises.Exercise, len(codes))
+	if len(codes) > 0 {
+		exerciseList, err := s.exerciseService.GetExercisesByCodes(ctx, codes)
 		if err != nil {
-			logger.Error("LessonsService.GetAllLessonsWithExercises exerciseService.GetExercisesByCodes: ", err)
 			return nil, err
 		}
-		result = append(result, lesson.toDTO(exercises))
+		for _, exercise := range exerciseList {
+			exercisesByCode[exercise.Code] = exercise
+		}
+	}
+
+	result := make([]LessonDTO, 0, len(lessons))
+	for _, lesson := range lessons {
+		exerciseList := make([]exercises.Exercise, 0, len(lesson.Exercises))
+		for _, code := range lesson.Exercises {
+			// deleted exercises are skipped as before
+			if exercise, ok := exercisesByCode[code]; ok {
+				exerciseList = append(exerciseList, exercise)
+			}
+		}
+		result = append(result, lesson.toDTO(exerciseList))
 	}
+
 	return result, nil
 }
 
@@ -180,3 +367,282 @@ func (s LessonsService) LessonExists(ctx context.Context, code string) (bool, er
 
 	return exists, nil
 }
+
+// GetPublishedLesson returns the lesson for learners, unpublished exercises are left out
+func (s LessonsService) GetPublishedLesson(ctx context.Context, code string) (LessonDTO, error) {
+	logger.Info("LessonsService.GetPublishedLesson new request")
+
+	lesson, err := s.repo.getLesson(ctx, code)
+	if err != nil {
+		logger.Error("LessonsService.GetPublishedLesson repo.getLesson: ", err)
+		return LessonDTO{}, err
+	}
+
+	if lesson.Status != publication.StatusPublished {
+		return LessonDTO{}, ErrNotFound
+	}
+
+	exerciseList, err := s.exerciseService.GetExercisesByCodes(ctx, lesson.Exercises)
+	if err != nil {
+		logger.Error("LessonsService.GetPublishedLesson exerciseService.GetExercisesByCodes: ", err)
+		return LessonDTO{}, err
+	}
+
+	published := make([]exercises.Exercise, 0, len(exerciseList))
+	for _, exercise := range exerciseList {
+		if exercise.Status == publication.StatusPublished {
+			published = append(published, exercise)
+		}
+	}
+
+	return lesson.toDTO(published), nil
+}
+
+func (s LessonsService) SetLessonStatus(ctx context.Context, code, status string) error {
+	logger.Info("LessonsService.SetLessonStatus new request")
+
+	lesson, err := s.repo.getLesson(ctx, code)
+	if err != nil {
+		logger.Error("LessonsService.SetLessonStatus repo.getLesson: ", err)
+		return err
+	}
+
+	if err := publication.ValidateTransition(lesson.Status, status); err != nil {
+		logger.Error("LessonsService.SetLessonStatus publication.ValidateTransition: ", err)
+		return err
+	}
+
+	if status == publication.StatusPublished {
+		if err := s.exerciseService.ValidateExercisesPublished(ctx, lesson.Exercises); err != nil {
+			logger.Error("LessonsService.SetLessonStatus exerciseService.ValidateExercisesPublished: ", err)
+			return err
+		}
+	}
+
+	lesson, err = s.repo.setLessonStatus(ctx, code, lesson.Status, status)
+	if err != nil {
+		logger.Error("LessonsService.SetLessonStatus repo.setLessonStatus: ", err)
+		return err
+	}
+	s.saveRevision(ctx, lesson)
+	s.publish(ctx, code)
+
+	return nil
+}
+
+// ValidateLessonsPublished checks that every code points to an existing published lesson
+// and that all exercises of these lessons are published too
+func (s LessonsService) ValidateLessonsPublished(ctx context.Context, codes []string) error {
+	logger.Info("LessonsService.ValidateLessonsPublished new request")
+
+	if len(codes) == 0 {
+		return nil
+	}
+
+	lessons, err := s.repo.getLessonsByCodes(ctx, codes)
+	if err != nil {
+		logger.Error("LessonsService.ValidateLessonsPublished repo.getLessonsByCodes: ", err)
+		return err
+	}
+
+	byCode := make(map[string]lesson, len(lessons))
+	for _, lesson := range lessons {
+		byCode[lesson.Code] = lesson
+	}
+
+	var (
+		missing, unpublished, exerciseCodes []string
+	)
+	for _, code := range codes {
+		lesson, ok := byCode[code]
+		switch {
+		case !ok:
+			missing = append(missing, code)
+		case lesson.Status != publication.StatusPublished:
+			unpublished = append(unpublished, code)
+		default:
+			exerciseCodes = append(exerciseCodes, lesson.Exercises...)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, strings.Join(missing, ", "))
+	}
+	if len(unpublished) > 0 {
+		return publication.NotPublishedError("lessons", unpublished)
+	}
+
+	if err := s.exerciseService.ValidateExercisesPublished(ctx, exerciseCodes); err != nil {
+		logger.Error("LessonsService.ValidateLessonsPublished exerciseService.ValidateExercisesPublished: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s LessonsService) GetLessonsReferencingExercise(ctx context.Context, exerciseCode string) ([]string, error) {
+	logger.Info("LessonsService.GetLessonsReferencingExercise new request")
+
+	codes, err := s.repo.getLessonCodesByExercise(ctx, exerciseCode)
+	if err != nil {
+		logger.Error("LessonsService.GetLessonsReferencingExercise repo.getLessonCodesByExercise: ", err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// UnlinkExercise removes exercise from the lists of all lessons
+func (s LessonsService) UnlinkExercise(ctx context.Context, exerciseCode string) error {
+	logger.Info("LessonsService.UnlinkExercise new request")
+
+	codes, err := s.repo.getLessonCodesByExercise(ctx, exerciseCode)
+	if err != nil {
+		logger.Error("LessonsService.UnlinkExercise repo.getLessonCodesByExercise: ", err)
+		return err
+	}
+
+	// lessons are unlinked one by one, so the revision of each has the list its own update left
+	for _, code := range codes {
+		updated, err := s.repo.unlinkExercise(ctx, code, exerciseCode)
+		switch err {
+		case nil:
+		case ErrExerciseNotInList:
+			// unlinked by another request since the codes were read
+			continue
+		default:
+			logger.Error("LessonsService.UnlinkExercise repo.unlinkExercise: ", err)
+			return err
+		}
+		s.saveRevision(ctx, updated)
+		s.publish(ctx, code)
+	}
+
+	return nil
+}
+
+// GetLessonExerciseCodes returns exercise lists of all lessons by lesson code, as stored
+func (s LessonsService) GetLessonExerciseCodes(ctx context.Context) (map[string][]string, error) {
+	logger.Info("LessonsService.GetLessonExerciseCodes new request")
+
+	lessons, err := s.repo.getAllLessons(ctx)
+	if err != nil {
+		logger.Error("LessonsService.GetLessonExerciseCodes repo.getAllLessons: ", err)
+		return nil, err
+	}
+
+	result := make(map[string][]string, len(lessons))
+	for _, lesson := range lessons {
+		result[lesson.Code] = lesson.Exercises
+	}
+
+	return result, nil
+}
+
+// RollbackLesson restores lesson content from the given revision, the rollback itself is saved as a new revision
+func (s LessonsService) RollbackLesson(ctx context.Context, code string, version int) error {
+	logger.Info("LessonsService.RollbackLesson new request")
+
+	if s.revisionService == nil {
+		return revisions.ErrHistoryNotAvailable
+	}
+
+	revision, err := s.revisionService.GetRevision(ctx, revisions.EntityLesson, code, version)
+	if err != nil {
+		logger.Error("LessonsService.RollbackLesson revisionService.GetRevision: ", err)
+		return err
+	}
+
+	var lesson lesson
+	if err := revision.Decode(&lesson); err != nil {
+		logger.Error("LessonsService.RollbackLesson revision.Decode: ", err)
+		return err
+	}
+	if lesson.Exercises == nil {
+		lesson.Exercises = make([]string, 0)
+	}
+
+	current, err := s.repo.getLesson(ctx, code)
+	if err != nil {
+		logger.Error("LessonsService.RollbackLesson repo.getLesson: ", err)
+		return err
+	}
+
+	// the revision may list exercises deleted or unpublished since then, status is not rolled back
+	if err := s.validateExercises(ctx, current.Status, lesson.Exercises); err != nil {
+		logger.Error("LessonsService.RollbackLesson validateExercises: ", err)
+		return err
+	}
+
+	restored, err := s.repo.restoreLesson(ctx, code, lesson)
+	if err != nil {
+		logger.Error("LessonsService.RollbackLesson repo.restoreLesson: ", err)
+		return err
+	}
+	s.saveRevision(ctx, restored)
+	s.publish(ctx, code)
+
+	return nil
+}
+
+// validateExercises checks that the exercises exist and, when the lesson is published, that they are published too
+func (s LessonsService) validateExercises(ctx context.Context, status string, codes []string) error {
+	var missing []string
+	for _, code := range codes {
+		exists, err := s.exerciseService.ExerciseExists(ctx, code)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", exercises.ErrNotFound, strings.Join(missing, ", "))
+	}
+
+	if status == publication.StatusPublished {
+		return s.exerciseService.ValidateExercisesPublished(ctx, codes)
+	}
+
+	return nil
+}
+
+// saveRevision snapshots the lesson as the write left it, history errors must not fail the write itself
+func (s LessonsService) saveRevision(ctx context.Context, lesson lesson) {
+	if s.revisionService == nil {
+		return
+	}
+
+	if _, err := s.revisionService.SaveRevision(ctx, revisions.EntityLesson, lesson.Code, lesson); err != nil {
+		logger.Error("LessonsService.saveRevision revisionService.SaveRevision: ", err)
+	}
+}
+
+func isPermutation(current, desired []string) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+
+	counts := make(map[string]int, len(current))
+	for _, code := range current {
+		counts[code]++
+	}
+	for _, code := range desired {
+		if counts[code] == 0 {
+			return false
+		}
+		counts[code]--
+	}
+
+	return true
+}
+
+// publish notifies subscribers that the lesson changed
+func (s LessonsService) publish(ctx context.Context, code string) {
+	if s.publisher == nil {
+		return
+	}
+
+	s.publisher.Publish(ctx, events.Event{Type: events.LessonChanged, Code: code})
+}
@@ -7,7 +7,10 @@ package lessons
 import (
 	context "context"
 	reflect "reflect"
+	events "uiren/internal/app/events"
 	exercises "uiren/internal/app/exercises"
+	revisions "uiren/internal/app/revisions"
+	pagination "uiren/pkg/pagination"
 
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
@@ -37,11 +40,12 @@ func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
 }
 
 // addExerciseToList mocks base method.
-func (m *Mockrepository) addExerciseToList(ctx context.Context, code, exerciseCode string) error {
+func (m *Mockrepository) addExerciseToList(ctx context.Context, code, exerciseCode string) (lesson, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "addExerciseToList", ctx, code, exerciseCode)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(lesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // addExerciseToList indicates an expected call of addExerciseToList.
@@ -66,11 +70,12 @@ func (mr *MockrepositoryMockRecorder) createLesson(ctx, dto interface{}) *gomock
 }
 
 // deleteExerciseFromList mocks base method.
-func (m *Mockrepository) deleteExerciseFromList(ctx context.Context, code, exerciseCode string) error {
+func (m *Mockrepository) deleteExerciseFromList(ctx context.Context, code, exerciseCode string) (lesson, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "deleteExerciseFromList", ctx, code, exerciseCode)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(lesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // deleteExerciseFromList indicates an expected call of deleteExerciseFromList.
@@ -123,6 +128,21 @@ func (mr *MockrepositoryMockRecorder) getLesson(ctx, code interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getLesson", reflect.TypeOf((*Mockrepository)(nil).getLesson), ctx, code)
 }
 
+// getLessonCodesByExercise mocks base method.
+func (m *Mockrepository) getLessonCodesByExercise(ctx context.Context, exerciseCode string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getLessonCodesByExercise", ctx, exerciseCode)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getLessonCodesByExercise indicates an expected call of getLessonCodesByExercise.
+func (mr *MockrepositoryMockRecorder) getLessonCodesByExercise(ctx, exerciseCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getLessonCodesByExercise", reflect.TypeOf((*Mockrepository)(nil).getLessonCodesByExercise), ctx, exerciseCode)
+}
+
 // getLessonsByCodes mocks base method.
 func (m *Mockrepository) getLessonsByCodes(ctx context.Context, codes []string) ([]lesson, error) {
 	m.ctrl.T.Helper()
@@ -153,12 +173,88 @@ func (mr *MockrepositoryMockRecorder) lessonExists(ctx, code interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "lessonExists", reflect.TypeOf((*Mockrepository)(nil).lessonExists), ctx, code)
 }
 
+// listLessons mocks base method.
+func (m *Mockrepository) listLessons(ctx context.Context, filter ListFilter, params pagination.Params) ([]lesson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "listLessons", ctx, filter, params)
+	ret0, _ := ret[0].([]lesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// listLessons indicates an expected call of listLessons.
+func (mr *MockrepositoryMockRecorder) listLessons(ctx, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "listLessons", reflect.TypeOf((*Mockrepository)(nil).listLessons), ctx, filter, params)
+}
+
+// reorderExercises mocks base method.
+func (m *Mockrepository) reorderExercises(ctx context.Context, code string, current, exercises []string) (lesson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "reorderExercises", ctx, code, current, exercises)
+	ret0, _ := ret[0].(lesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// reorderExercises indicates an expected call of reorderExercises.
+func (mr *MockrepositoryMockRecorder) reorderExercises(ctx, code, current, exercises interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "reorderExercises", reflect.TypeOf((*Mockrepository)(nil).reorderExercises), ctx, code, current, exercises)
+}
+
+// restoreLesson mocks base method.
+func (m *Mockrepository) restoreLesson(ctx context.Context, code string, snapshot lesson) (lesson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "restoreLesson", ctx, code, snapshot)
+	ret0, _ := ret[0].(lesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// restoreLesson indicates an expected call of restoreLesson.
+func (mr *MockrepositoryMockRecorder) restoreLesson(ctx, code, snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "restoreLesson", reflect.TypeOf((*Mockrepository)(nil).restoreLesson), ctx, code, snapshot)
+}
+
+// setLessonStatus mocks base method.
+func (m *Mockrepository) setLessonStatus(ctx context.Context, code, from, to string) (lesson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "setLessonStatus", ctx, code, from, to)
+	ret0, _ := ret[0].(lesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// setLessonStatus indicates an expected call of setLessonStatus.
+func (mr *MockrepositoryMockRecorder) setLessonStatus(ctx, code, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "setLessonStatus", reflect.TypeOf((*Mockrepository)(nil).setLessonStatus), ctx, code, from, to)
+}
+
+// unlinkExercise mocks base method.
+func (m *Mockrepository) unlinkExercise(ctx context.Context, code, exerciseCode string) (lesson, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "unlinkExercise", ctx, code, exerciseCode)
+	ret0, _ := ret[0].(lesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// unlinkExercise indicates an expected call of unlinkExercise.
+func (mr *MockrepositoryMockRecorder) unlinkExercise(ctx, code, exerciseCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "unlinkExercise", reflect.TypeOf((*Mockrepository)(nil).unlinkExercise), ctx, code, exerciseCode)
+}
+
 // updateLesson mocks base method.
-func (m *Mockrepository) updateLesson(ctx context.Context, code string, dto UpdateLessonDTO) error {
+func (m *Mockrepository) updateLesson(ctx context.Context, code string, dto UpdateLessonDTO) (lesson, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "updateLesson", ctx, code, dto)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(lesson)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // updateLesson indicates an expected call of updateLesson.
@@ -219,3 +315,157 @@ func (mr *MockexerciseServiceMockRecorder) GetExercisesByCodes(ctx, codes interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExercisesByCodes", reflect.TypeOf((*MockexerciseService)(nil).GetExercisesByCodes), ctx, codes)
 }
+
+// ValidateExercisesPublished mocks base method.
+func (m *MockexerciseService) ValidateExercisesPublished(ctx context.Context, codes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateExercisesPublished", ctx, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateExercisesPublished indicates an expected call of ValidateExercisesPublished.
+func (mr *MockexerciseServiceMockRecorder) ValidateExercisesPublished(ctx, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateExercisesPublished", reflect.TypeOf((*MockexerciseService)(nil).ValidateExercisesPublished), ctx, codes)
+}
+
+// Mockpublisher is a mock of publisher interface.
+type Mockpublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockpublisherMockRecorder
+}
+
+// MockpublisherMockRecorder is the mock recorder for Mockpublisher.
+type MockpublisherMockRecorder struct {
+	mock *Mockpublisher
+}
+
+// NewMockpublisher creates a new mock instance.
+func NewMockpublisher(ctrl *gomock.Controller) *Mockpublisher {
+	mock := &Mockpublisher{ctrl: ctrl}
+	mock.recorder = &MockpublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockpublisher) EXPECT() *MockpublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *Mockpublisher) Publish(ctx context.Context, event events.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", ctx, event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockpublisherMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*Mockpublisher)(nil).Publish), ctx, event)
+}
+
+// MockrevisionService is a mock of revisionService interface.
+type MockrevisionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockrevisionServiceMockRecorder
+}
+
+// MockrevisionServiceMockRecorder is the mock recorder for MockrevisionService.
+type MockrevisionServiceMockRecorder struct {
+	mock *MockrevisionService
+}
+
+// NewMockrevisionService creates a new mock instance.
+func NewMockrevisionService(ctrl *gomock.Controller) *MockrevisionService {
+	mock := &MockrevisionService{ctrl: ctrl}
+	mock.recorder = &MockrevisionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrevisionService) EXPECT() *MockrevisionServiceMockRecorder {
+	return m.recorder
+}
+
+// GetRevision mocks base method.
+func (m *MockrevisionService) GetRevision(ctx context.Context, entityType, code string, version int) (revisions.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, entityType, code, version)
+	ret0, _ := ret[0].(revisions.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockrevisionServiceMockRecorder) GetRevision(ctx, entityType, code, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockrevisionService)(nil).GetRevision), ctx, entityType, code, version)
+}
+
+// SaveRevision mocks base method.
+func (m *MockrevisionService) SaveRevision(ctx context.Context, entityType, code string, document interface{}) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRevision", ctx, entityType, code, document)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRevision indicates an expected call of SaveRevision.
+func (mr *MockrevisionServiceMockRecorder) SaveRevision(ctx, entityType, code, document interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevision", reflect.TypeOf((*MockrevisionService)(nil).SaveRevision), ctx, entityType, code, document)
+}
+
+// MockreferenceTracker is a mock of referenceTracker interface.
+type MockreferenceTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockreferenceTrackerMockRecorder
+}
+
+// MockreferenceTrackerMockRecorder is the mock recorder for MockreferenceTracker.
+type MockreferenceTrackerMockRecorder struct {
+	mock *MockreferenceTracker
+}
+
+// NewMockreferenceTracker creates a new mock instance.
+func NewMockreferenceTracker(ctrl *gomock.Controller) *MockreferenceTracker {
+	mock := &MockreferenceTracker{ctrl: ctrl}
+	mock.recorder = &MockreferenceTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockreferenceTracker) EXPECT() *MockreferenceTrackerMockRecorder {
+	return m.recorder
+}
+
+// GetModulesReferencingLesson mocks base method.
+func (m *MockreferenceTracker) GetModulesReferencingLesson(ctx context.Context, lessonCode string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModulesReferencingLesson", ctx, lessonCode)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModulesReferencingLesson indicates an expected call of GetModulesReferencingLesson.
+func (mr *MockreferenceTrackerMockRecorder) GetModulesReferencingLesson(ctx, lessonCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModulesReferencingLesson", reflect.TypeOf((*MockreferenceTracker)(nil).GetModulesReferencingLesson), ctx, lessonCode)
+}
+
+// UnlinkLesson mocks base method.
+func (m *MockreferenceTracker) UnlinkLesson(ctx context.Context, lessonCode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkLesson", ctx, lessonCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlinkLesson indicates an expected call of UnlinkLesson.
+func (mr *MockreferenceTrackerMockRecorder) UnlinkLesson(ctx, lessonCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkLesson", reflect.TypeOf((*MockreferenceTracker)(nil).UnlinkLesson), ctx, lessonCode)
+}
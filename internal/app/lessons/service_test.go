@@ -6,10 +6,14 @@ import (
 	"testing"
 	"time"
 	"uiren/internal/app/exercises"
+	"uiren/internal/app/publication"
+	"uiren/internal/app/revisions"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -77,7 +81,7 @@ func Test_LessonsService_UpdateLesson_success(t *testing.T) {
 		}
 	)
 
-	repo.EXPECT().updateLesson(ctx, code, dto).Return(nil)
+	repo.EXPECT().updateLesson(ctx, code, dto).Return(lesson{}, nil)
 
 	err := srv.UpdateLesson(ctx, code, dto)
 	assert.NoError(t, err)
@@ -95,7 +99,7 @@ func Test_LessonsService_UpdateLesson_fail(t *testing.T) {
 		dto              = UpdateLessonDTO{}
 	)
 
-	repo.EXPECT().updateLesson(ctx, code, dto).Return(ErrNoFieldsToUpdate)
+	repo.EXPECT().updateLesson(ctx, code, dto).Return(lesson{}, ErrNoFieldsToUpdate)
 
 	err := srv.UpdateLesson(ctx, code, dto)
 	assert.Error(t, err)
@@ -115,7 +119,7 @@ func Test_LessonsService_DeleteLesson_success(t *testing.T) {
 
 	repo.EXPECT().deleteLesson(ctx, code).Return(nil)
 
-	err := srv.DeleteLesson(ctx, code)
+	err := srv.DeleteLesson(ctx, code, false)
 	assert.NoError(t, err)
 }
 
@@ -132,7 +136,7 @@ func Test_LessonsService_DeleteLesson_fail(t *testing.T) {
 
 	repo.EXPECT().deleteLesson(ctx, code).Return(ErrNotFound)
 
-	err := srv.DeleteLesson(ctx, code)
+	err := srv.DeleteLesson(ctx, code, false)
 	assert.Error(t, err)
 	assert.Equal(t, err, ErrNotFound)
 }
@@ -150,7 +154,8 @@ func Test_LessonsService_AddExerciseToList_success(t *testing.T) {
 	)
 
 	exercisesService.EXPECT().ExerciseExists(ctx, exerciseCode).Return(true, nil)
-	repo.EXPECT().addExerciseToList(ctx, lessonCode, exerciseCode).Return(nil)
+	repo.EXPECT().getLesson(ctx, lessonCode).Return(lesson{Code: lessonCode, Status: publication.StatusDraft}, nil)
+	repo.EXPECT().addExerciseToList(ctx, lessonCode, exerciseCode).Return(lesson{}, nil)
 
 	err := srv.AddExerciseToList(ctx, lessonCode, exerciseCode)
 	assert.NoError(t, err)
@@ -169,7 +174,8 @@ func Test_LessonsService_AddExerciseToList_fail(t *testing.T) {
 	)
 
 	exercisesService.EXPECT().ExerciseExists(ctx, exerciseCode).Return(true, nil)
-	repo.EXPECT().addExerciseToList(ctx, lessonCode, exerciseCode).Return(ErrExerciseAlreadyInSet)
+	repo.EXPECT().getLesson(ctx, lessonCode).Return(lesson{Code: lessonCode, Status: publication.StatusDraft}, nil)
+	repo.EXPECT().addExerciseToList(ctx, lessonCode, exerciseCode).Return(lesson{}, ErrExerciseAlreadyInSet)
 
 	err := srv.AddExerciseToList(ctx, lessonCode, exerciseCode)
 	assert.Error(t, err)
@@ -205,6 +211,48 @@ func Test_LessonsService_AddExerciseToList_ExerciseExists(t *testing.T) {
 
 }
 
+func Test_LessonsService_AddExerciseToList_published(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx              = context.TODO()
+		ctrl             = gomock.NewController(t)
+		exercisesService = NewMockexerciseService(ctrl)
+		repo             = NewMockrepository(ctrl)
+		srv              = NewLessonsService(repo, exercisesService)
+		lessonCode       = "lesson_code"
+		exerciseCode     = "exercise_code"
+		published        = lesson{Code: lessonCode, Status: publication.StatusPublished}
+	)
+
+	t.Run("published exercise", func(t *testing.T) {
+		exercisesService.EXPECT().ExerciseExists(ctx, exerciseCode).Return(true, nil)
+		repo.EXPECT().getLesson(ctx, lessonCode).Return(published, nil)
+		exercisesService.EXPECT().ValidateExercisesPublished(ctx, []string{exerciseCode}).Return(nil)
+		repo.EXPECT().addExerciseToList(ctx, lessonCode, exerciseCode).Return(published, nil)
+
+		err := srv.AddExerciseToList(ctx, lessonCode, exerciseCode)
+		assert.NoError(t, err)
+	})
+
+	t.Run("draft exercise", func(t *testing.T) {
+		exercisesService.EXPECT().ExerciseExists(ctx, exerciseCode).Return(true, nil)
+		repo.EXPECT().getLesson(ctx, lessonCode).Return(published, nil)
+		exercisesService.EXPECT().ValidateExercisesPublished(ctx, []string{exerciseCode}).
+			Return(publication.NotPublishedError("exercises", []string{exerciseCode}))
+
+		err := srv.AddExerciseToList(ctx, lessonCode, exerciseCode)
+		assert.ErrorIs(t, err, publication.ErrNotPublished)
+	})
+
+	t.Run("lesson not found", func(t *testing.T) {
+		exercisesService.EXPECT().ExerciseExists(ctx, exerciseCode).Return(true, nil)
+		repo.EXPECT().getLesson(ctx, lessonCode).Return(lesson{}, ErrNotFound)
+
+		err := srv.AddExerciseToList(ctx, lessonCode, exerciseCode)
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
 func Test_LessonsService_DeleteExerciseFromList_success(t *testing.T) {
 	t.Parallel()
 	var (
@@ -217,12 +265,56 @@ func Test_LessonsService_DeleteExerciseFromList_success(t *testing.T) {
 		exerciseCode     = "exercise_code"
 	)
 
-	repo.EXPECT().deleteExerciseFromList(ctx, lessonCode, exerciseCode).Return(nil)
+	repo.EXPECT().deleteExerciseFromList(ctx, lessonCode, exerciseCode).Return(lesson{}, nil)
 
 	err := srv.DeleteExerciseFromList(ctx, lessonCode, exerciseCode)
 	assert.NoError(t, err)
 }
 
+func Test_LessonsService_ReorderExercises(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx              = context.TODO()
+		ctrl             = gomock.NewController(t)
+		exercisesService = NewMockexerciseService(ctrl)
+		repo             = NewMockrepository(ctrl)
+		srv              = NewLessonsService(repo, exercisesService)
+		lessonCode       = "lesson_code"
+		current          = []string{"ex1", "ex2", "ex3"}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		reordered := []string{"ex2", "ex3", "ex1"}
+		repo.EXPECT().getLesson(ctx, lessonCode).Return(lesson{Code: lessonCode, Exercises: current}, nil)
+		repo.EXPECT().reorderExercises(ctx, lessonCode, current, reordered).Return(lesson{}, nil)
+
+		err := srv.ReorderExercises(ctx, lessonCode, reordered)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not a permutation", func(t *testing.T) {
+		for _, exercises := range [][]string{
+			{"ex1", "ex2", "ex3", "ex4"},
+			{"ex1", "ex2", "ex2"},
+			nil,
+		} {
+			repo.EXPECT().getLesson(ctx, lessonCode).Return(lesson{Code: lessonCode, Exercises: current}, nil)
+
+			err := srv.ReorderExercises(ctx, lessonCode, exercises)
+			assert.Equal(t, ErrNotPermutation, err)
+		}
+	})
+
+	t.Run("list changed", func(t *testing.T) {
+		reordered := []string{"ex3", "ex2", "ex1"}
+		repo.EXPECT().getLesson(ctx, lessonCode).Return(lesson{Code: lessonCode, Exercises: current}, nil)
+		repo.EXPECT().reorderExercises(ctx, lessonCode, current, reordered).Return(lesson{}, ErrListChanged)
+
+		err := srv.ReorderExercises(ctx, lessonCode, reordered)
+		assert.Equal(t, ErrListChanged, err)
+	})
+}
+
 func Test_LessonsService_DeleteExerciseFromList_fail(t *testing.T) {
 	t.Parallel()
 	var (
@@ -235,7 +327,7 @@ func Test_LessonsService_DeleteExerciseFromList_fail(t *testing.T) {
 		exerciseCode     = "exercise_code"
 	)
 
-	repo.EXPECT().deleteExerciseFromList(ctx, lessonCode, exerciseCode).Return(ErrExerciseNotInList)
+	repo.EXPECT().deleteExerciseFromList(ctx, lessonCode, exerciseCode).Return(lesson{}, ErrExerciseNotInList)
 
 	err := srv.DeleteExerciseFromList(ctx, lessonCode, exerciseCode)
 	assert.Error(t, err)
@@ -338,9 +430,8 @@ func Test_LessonsService_GetLessonsByCodes_success(t *testing.T) {
 	)
 
 	repo.EXPECT().getLessonsByCodes(ctx, codes).Return(lessons, nil)
-	for _, lesson := range lessons {
-		exercisesService.EXPECT().GetExercisesByCodes(ctx, lesson.Exercises).Return(exercisesMap[lesson.Code], nil)
-	}
+	exercisesService.EXPECT().GetExercisesByCodes(ctx, []string{"ex1", "ex2", "ex3"}).
+		Return([]exercises.Exercise{{Code: "ex3"}, {Code: "ex1"}, {Code: "ex2"}}, nil).Times(1)
 
 	result, err := srv.GetLessonsByCodes(ctx, codes)
 	assert.NoError(t, err)
@@ -483,8 +574,9 @@ func Test_LessonsService_GetAllLessonsWithExercises(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var resultExpected []LessonDTO
 		repo.EXPECT().getAllLessons(ctx).Return(lessons, nil)
+		exercisesService.EXPECT().GetExercisesByCodes(ctx, []string{"ex1", "ex2", "ex3"}).
+			Return(append(exerciseMap["lesson_1"], exerciseMap["lesson_2"]...), nil).Times(1)
 		for _, lesson := range lessons {
-			exercisesService.EXPECT().GetExercisesByCodes(ctx, lesson.Exercises).Return(exerciseMap[lesson.Code], nil)
 			resultExpected = append(resultExpected, lesson.toDTO(exerciseMap[lesson.Code]))
 		}
 		result, err := srv.GetAllLessonsWithExercises(ctx)
@@ -501,9 +593,275 @@ func Test_LessonsService_GetAllLessonsWithExercises(t *testing.T) {
 
 	t.Run("repo failed#2", func(t *testing.T) {
 		repo.EXPECT().getAllLessons(ctx).Return(lessons, nil)
-		exercisesService.EXPECT().GetExercisesByCodes(ctx, lessons[0].Exercises).Return(nil, repoErr)
+		exercisesService.EXPECT().GetExercisesByCodes(ctx, []string{"ex1", "ex2", "ex3"}).Return(nil, repoErr)
 		result, err := srv.GetAllLessonsWithExercises(ctx)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
 }
+
+func Test_LessonsService_RollbackLesson(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx              = context.TODO()
+		ctrl             = gomock.NewController(t)
+		exercisesService = NewMockexerciseService(ctrl)
+		repo             = NewMockrepository(ctrl)
+		revisionSrv      = NewMockrevisionService(ctrl)
+		srv              = NewLessonsService(repo, exercisesService)
+		code             = "lesson1"
+		revision         = revisions.Revision{
+			EntityType: revisions.EntityLesson,
+			Code:       code,
+			Version:    2,
+			Snapshot: bson.M{
+				"code":        code,
+				"title":       "old title",
+				"description": "old desc",
+				"exercises":   bson.A{"ex1"},
+			},
+		}
+		restored = lesson{Code: code, Title: "old title", Description: "old desc", Exercises: []string{"ex1"}}
+	)
+
+	t.Run("history not configured", func(t *testing.T) {
+		err := srv.RollbackLesson(ctx, code, 2)
+		assert.Equal(t, revisions.ErrHistoryNotAvailable, err)
+	})
+
+	srv.WithRevisionService(revisionSrv)
+
+	t.Run("success", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityLesson, code, 2).Return(revision, nil)
+		repo.EXPECT().getLesson(ctx, code).Return(lesson{Code: code, Status: publication.StatusDraft}, nil)
+		exercisesService.EXPECT().ExerciseExists(ctx, "ex1").Return(true, nil)
+		repo.EXPECT().restoreLesson(ctx, code, restored).Return(restored, nil)
+		revisionSrv.EXPECT().SaveRevision(ctx, revisions.EntityLesson, code, restored).Return(4, nil)
+
+		err := srv.RollbackLesson(ctx, code, 2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityLesson, code, 9).Return(revisions.Revision{}, revisions.ErrRevisionNotFound)
+
+		err := srv.RollbackLesson(ctx, code, 9)
+		assert.Equal(t, revisions.ErrRevisionNotFound, err)
+	})
+
+	t.Run("exercise was deleted", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityLesson, code, 2).Return(revision, nil)
+		repo.EXPECT().getLesson(ctx, code).Return(lesson{Code: code, Status: publication.StatusDraft}, nil)
+		exercisesService.EXPECT().ExerciseExists(ctx, "ex1").Return(false, nil)
+
+		err := srv.RollbackLesson(ctx, code, 2)
+		assert.ErrorIs(t, err, exercises.ErrNotFound)
+		assert.Contains(t, err.Error(), "ex1")
+	})
+
+	t.Run("published lesson gets a draft exercise", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityLesson, code, 2).Return(revision, nil)
+		repo.EXPECT().getLesson(ctx, code).Return(lesson{Code: code, Status: publication.StatusPublished}, nil)
+		exercisesService.EXPECT().ExerciseExists(ctx, "ex1").Return(true, nil)
+		exercisesService.EXPECT().ValidateExercisesPublished(ctx, []string{"ex1"}).
+			Return(publication.NotPublishedError("exercises", []string{"ex1"}))
+
+		err := srv.RollbackLesson(ctx, code, 2)
+		assert.ErrorIs(t, err, publication.ErrNotPublished)
+	})
+}
+
+func Test_LessonsService_ValidateLessonsPublished(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx              = context.TODO()
+		ctrl             = gomock.NewController(t)
+		exercisesService = NewMockexerciseService(ctrl)
+		repo             = NewMockrepository(ctrl)
+		srv              = NewLessonsService(repo, exercisesService)
+		codes            = []string{"lesson1", "lesson2"}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().getLessonsByCodes(ctx, codes).Return([]lesson{
+			{Code: "lesson1", Exercises: []string{"ex1"}, Status: publication.StatusPublished},
+			{Code: "lesson2", Exercises: []string{"ex2"}, Status: publication.StatusPublished},
+		}, nil)
+		exercisesService.EXPECT().ValidateExercisesPublished(ctx, []string{"ex1", "ex2"}).Return(nil)
+
+		err := srv.ValidateLessonsPublished(ctx, codes)
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing lesson", func(t *testing.T) {
+		repo.EXPECT().getLessonsByCodes(ctx, codes).Return([]lesson{
+			{Code: "lesson1", Status: publication.StatusPublished},
+		}, nil)
+
+		err := srv.ValidateLessonsPublished(ctx, codes)
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.Contains(t, err.Error(), "lesson2")
+	})
+
+	t.Run("draft lesson", func(t *testing.T) {
+		repo.EXPECT().getLessonsByCodes(ctx, codes).Return([]lesson{
+			{Code: "lesson1", Status: publication.StatusPublished},
+			{Code: "lesson2", Status: publication.StatusDraft},
+		}, nil)
+
+		err := srv.ValidateLessonsPublished(ctx, codes)
+		assert.True(t, errors.Is(err, publication.ErrNotPublished))
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		err := srv.ValidateLessonsPublished(ctx, nil)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_LessonsService_GetPublishedLesson(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx              = context.TODO()
+		ctrl             = gomock.NewController(t)
+		exercisesService = NewMockexerciseService(ctrl)
+		repo             = NewMockrepository(ctrl)
+		srv              = NewLessonsService(repo, exercisesService)
+		code             = "lesson1"
+		published        = exercises.Exercise{Code: "ex1", Status: publication.StatusPublished}
+		draft            = exercises.Exercise{Code: "ex2", Status: publication.StatusDraft}
+	)
+
+	t.Run("unpublished exercises are hidden", func(t *testing.T) {
+		l := lesson{Code: code, Exercises: []string{"ex1", "ex2"}, Status: publication.StatusPublished}
+		repo.EXPECT().getLesson(ctx, code).Return(l, nil)
+		exercisesService.EXPECT().GetExercisesByCodes(ctx, l.Exercises).Return([]exercises.Exercise{published, draft}, nil)
+
+		result, err := srv.GetPublishedLesson(ctx, code)
+		assert.NoError(t, err)
+		assert.Equal(t, []exercises.Exercise{published}, result.Exercises)
+	})
+
+	t.Run("draft lesson is not found", func(t *testing.T) {
+		repo.EXPECT().getLesson(ctx, code).Return(lesson{Code: code, Status: publication.StatusDraft}, nil)
+
+		_, err := srv.GetPublishedLesson(ctx, code)
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func Test_LessonsService_DeleteLesson_referenced(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx              = context.TODO()
+		ctrl             = gomock.NewController(t)
+		exercisesService = NewMockexerciseService(ctrl)
+		repo             = NewMockrepository(ctrl)
+		tracker          = NewMockreferenceTracker(ctrl)
+		srv              = NewLessonsService(repo, exercisesService)
+		code             = "lesson1"
+		parents          = []string{"module1"}
+	)
+	srv.WithReferenceTracker(tracker)
+
+	t.Run("refuse without cascade", func(t *testing.T) {
+		tracker.EXPECT().GetModulesReferencingLesson(ctx, code).Return(parents, nil)
+
+		err := srv.DeleteLesson(ctx, code, false)
+		var referencedErr *ReferencedError
+		assert.True(t, errors.As(err, &referencedErr))
+		assert.Equal(t, parents, referencedErr.Parents)
+	})
+
+	t.Run("cascade unlinks modules", func(t *testing.T) {
+		gomock.InOrder(
+			tracker.EXPECT().GetModulesReferencingLesson(ctx, code).Return(parents, nil),
+			tracker.EXPECT().UnlinkLesson(ctx, code).Return(nil),
+			repo.EXPECT().deleteLesson(ctx, code).Return(nil),
+		)
+
+		err := srv.DeleteLesson(ctx, code, true)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_LessonsService_UnlinkExercise(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx              = context.TODO()
+		ctrl             = gomock.NewController(t)
+		exercisesService = NewMockexerciseService(ctrl)
+		repo             = NewMockrepository(ctrl)
+		revisionSrv      = NewMockrevisionService(ctrl)
+		srv              = NewLessonsService(repo, exercisesService)
+		errRepo          = errors.New("repo error")
+		unlinked         = lesson{Code: "lesson1", Exercises: []string{"ex2"}}
+	)
+	srv.WithRevisionService(revisionSrv)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().getLessonCodesByExercise(ctx, "ex1").Return([]string{"lesson1", "lesson2"}, nil)
+		repo.EXPECT().unlinkExercise(ctx, "lesson1", "ex1").Return(unlinked, nil)
+		repo.EXPECT().unlinkExercise(ctx, "lesson2", "ex1").Return(lesson{}, ErrExerciseNotInList)
+		revisionSrv.EXPECT().SaveRevision(ctx, revisions.EntityLesson, "lesson1", unlinked).Return(2, nil)
+
+		err := srv.UnlinkExercise(ctx, "ex1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("repo fail", func(t *testing.T) {
+		repo.EXPECT().getLessonCodesByExercise(ctx, "ex1").Return([]string{"lesson1"}, nil)
+		repo.EXPECT().unlinkExercise(ctx, "lesson1", "ex1").Return(lesson{}, errRepo)
+
+		err := srv.UnlinkExercise(ctx, "ex1")
+		assert.Equal(t, errRepo, err)
+	})
+}
+
+func Test_LessonsService_ListLessons(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx         = context.TODO()
+		ctrl        = gomock.NewController(t)
+		repo        = NewMockrepository(ctrl)
+		exerciseSrv = NewMockexerciseService(ctrl)
+		srv         = NewLessonsService(repo, exerciseSrv)
+		filter      = ListFilter{Status: publication.StatusDraft}
+		params      = pagination.Params{Limit: 1, SortBy: "code", Order: pagination.OrderAsc}
+		lessons     = []lesson{
+			{Code: "lesson1", Exercises: []string{"ex1"}},
+			{Code: "lesson2", Exercises: []string{"ex2"}},
+		}
+		exerciseList = []exercises.Exercise{{Code: "ex1"}}
+		errRepo      = errors.New("repo err")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().listLessons(ctx, filter, params).Return(lessons, nil)
+		exerciseSrv.EXPECT().GetExercisesByCodes(ctx, []string{"ex1"}).Return(exerciseList, nil)
+
+		page, err := srv.ListLessons(ctx, filter, params)
+		assert.NoError(t, err)
+		assert.Equal(t, []LessonDTO{lessons[0].toDTO(exerciseList)}, page.Items)
+
+		cursor, err := pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, "lesson1", cursor.Key)
+	})
+
+	t.Run("empty page", func(t *testing.T) {
+		repo.EXPECT().listLessons(ctx, filter, params).Return(nil, nil)
+
+		page, err := srv.ListLessons(ctx, filter, params)
+		assert.NoError(t, err)
+		assert.NotNil(t, page.Items)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("repo failed", func(t *testing.T) {
+		repo.EXPECT().listLessons(ctx, filter, params).Return(nil, errRepo)
+
+		_, err := srv.ListLessons(ctx, filter, params)
+		assert.Equal(t, errRepo, err)
+	})
+}
@@ -0,0 +1,43 @@
+package mailing
+
+import "time"
+
+const (
+	TypeEmailVerification = "email_verification"
+)
+
+const (
+	LocaleRU      = "ru"
+	LocaleEN      = "en"
+	DefaultLocale = LocaleRU
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 20
+	defaultMaxAttempts  = 8
+	defaultBaseBackoff  = time.Minute
+	defaultMaxBackoff   = time.Hour
+	// defaultLockDuration must be longer than sending a batch,
+	// otherwise another worker takes the same messages
+	defaultLockDuration = 5 * time.Minute
+)
+
+// Email is a message to render from the templates of Type in Locale,
+// unsupported locale falls back to DefaultLocale
+type Email struct {
+	Type   string
+	Locale string
+	To     string
+	Data   map[string]string
+}
+
+// outboxMessage is a rendered email waiting to be sent
+type outboxMessage struct {
+	id       int64
+	to       string
+	subject  string
+	html     string
+	text     string
+	attempts int
+}
@@ -0,0 +1,8 @@
+package mailing
+
+import "errors"
+
+var (
+	ErrUnknownTemplate = errors.New("unknown email template")
+	ErrEmptyRecipient  = errors.New("email recipient required")
+)
@@ -0,0 +1,98 @@
+package mailing
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *pgxpool.Pool) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) enqueue(ctx context.Context, email Email, subject, html, text string) error {
+	var (
+		query = `
+		INSERT INTO email_outbox (type, locale, recipient, subject, html, text)
+		VALUES ($1, $2, $3, $4, $5, $6);
+		`
+	)
+
+	_, err := r.db.Exec(ctx, query, email.Type, email.Locale, email.To, subject, html, text)
+	return err
+}
+
+// claimDue locks the due messages for lockDuration, so workers of other instances skip them,
+// a message of a crashed worker is taken again after the lock expires
+func (r *repository) claimDue(ctx context.Context, limit int, lockDuration time.Duration) ([]outboxMessage, error) {
+	var (
+		query = `
+		UPDATE email_outbox SET locked_until = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending'
+				AND next_attempt_at <= now()
+				AND (locked_until IS NULL OR locked_until < now())
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, recipient, subject, html, text, attempts;
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, limit, lockDuration.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []outboxMessage
+	for rows.Next() {
+		var message outboxMessage
+		if err := rows.Scan(&message.id, &message.to, &message.subject, &message.html, &message.text, &message.attempts); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *repository) markSent(ctx context.Context, id int64) error {
+	var (
+		query = `
+		UPDATE email_outbox
+		SET status = 'sent', attempts = attempts + 1, sent_at = now(), locked_until = NULL, last_error = ''
+		WHERE id = $1;
+		`
+	)
+
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+// markFailed schedules the next attempt, nil nextAttemptAt means the message is given up
+func (r *repository) markFailed(ctx context.Context, id int64, lastError string, nextAttemptAt *time.Time) error {
+	var (
+		query = `
+		UPDATE email_outbox
+		SET attempts = attempts + 1,
+			last_error = $2,
+			locked_until = NULL,
+			status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE status END,
+			next_attempt_at = COALESCE($3::timestamptz, next_attempt_at)
+		WHERE id = $1;
+		`
+	)
+
+	_, err := r.db.Exec(ctx, query, id, lastError, nextAttemptAt)
+	return err
+}
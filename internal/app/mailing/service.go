@@ -0,0 +1,171 @@
+package mailing
+
+import (
+	"context"
+	"time"
+	"uiren/internal/infrastracture/mail"
+	"uiren/pkg/logger"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package mailing
+
+// Mailer delivers one rendered message, the SMTP mailer in production
+type Mailer interface {
+	Send(ctx context.Context, message mail.Message) error
+}
+
+type outboxRepository interface {
+	enqueue(ctx context.Context, email Email, subject, html, text string) error
+	claimDue(ctx context.Context, limit int, lockDuration time.Duration) ([]outboxMessage, error)
+	markSent(ctx context.Context, id int64) error
+	markFailed(ctx context.Context, id int64, lastError string, nextAttemptAt *time.Time) error
+}
+
+// MailingService renders emails into the outbox, the worker started by Run sends them
+// and retries failed ones with exponential backoff
+type MailingService struct {
+	outboxRepository outboxRepository
+	mailer           Mailer
+	templates        *templates
+
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	lockDuration time.Duration
+	now          func() time.Time
+}
+
+func NewMailingService(outboxRepository outboxRepository, mailer Mailer) *MailingService {
+	return &MailingService{
+		outboxRepository: outboxRepository,
+		mailer:           mailer,
+		templates:        newTemplates(),
+		pollInterval:     defaultPollInterval,
+		batchSize:        defaultBatchSize,
+		maxAttempts:      defaultMaxAttempts,
+		baseBackoff:      defaultBaseBackoff,
+		maxBackoff:       defaultMaxBackoff,
+		lockDuration:     defaultLockDuration,
+		now:              time.Now,
+	}
+}
+
+func (s *MailingService) SetPollInterval(interval time.Duration) {
+	s.pollInterval = interval
+}
+
+func (s *MailingService) SetMaxAttempts(maxAttempts int) {
+	s.maxAttempts = maxAttempts
+}
+
+// SetBaseBackoff sets the delay after the first failed attempt
+func (s *MailingService) SetBaseBackoff(base time.Duration) {
+	s.baseBackoff = base
+}
+
+func (s *MailingService) SetMaxBackoff(max time.Duration) {
+	s.maxBackoff = max
+}
+
+// Enqueue renders the email and saves it to the outbox, the email is sent by the worker
+func (s *MailingService) Enqueue(ctx context.Context, email Email) error {
+	logger.Info("MailingService.Enqueue new request")
+
+	if email.To == "" {
+		return ErrEmptyRecipient
+	}
+
+	if !s.templates.has(email.Type, email.Locale) {
+		email.Locale = DefaultLocale
+	}
+
+	subject, html, text, err := s.templates.render(email)
+	if err != nil {
+		logger.Error("MailingService.Enqueue templates.render: ", err)
+		return err
+	}
+
+	if err := s.outboxRepository.enqueue(ctx, email, subject, html, text); err != nil {
+		logger.Error("MailingService.Enqueue outboxRepository.enqueue: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Run sends due emails every poll interval until ctx is done
+func (s *MailingService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// a full batch means more emails are due, they are sent without waiting
+		for s.processBatch(ctx) == s.batchSize && ctx.Err() == nil {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processBatch sends one batch of due emails and returns its size
+func (s *MailingService) processBatch(ctx context.Context) int {
+	messages, err := s.outboxRepository.claimDue(ctx, s.batchSize, s.lockDuration)
+	if err != nil {
+		logger.Error("MailingService.processBatch outboxRepository.claimDue: ", err)
+		return 0
+	}
+
+	for _, message := range messages {
+		s.send(ctx, message)
+	}
+
+	return len(messages)
+}
+
+func (s *MailingService) send(ctx context.Context, message outboxMessage) {
+	err := s.mailer.Send(ctx, mail.Message{
+		To:      []string{message.to},
+		Subject: message.subject,
+		HTML:    message.html,
+		Text:    message.text,
+	})
+	if err == nil {
+		if err := s.outboxRepository.markSent(ctx, message.id); err != nil {
+			logger.Error("MailingService.send outboxRepository.markSent: ", err)
+		}
+		return
+	}
+
+	attempt := message.attempts + 1
+	logger.Warn("MailingService.send mailer.Send attempt ", attempt, ": ", err)
+
+	var nextAttemptAt *time.Time
+	if attempt < s.maxAttempts {
+		next := s.now().Add(s.backoff(attempt))
+		nextAttemptAt = &next
+	} else {
+		logger.Error("MailingService.send giving up on email ", message.id, ": ", err)
+	}
+
+	if err := s.outboxRepository.markFailed(ctx, message.id, err.Error(), nextAttemptAt); err != nil {
+		logger.Error("MailingService.send outboxRepository.markFailed: ", err)
+	}
+}
+
+// backoff doubles the delay after every failed attempt up to maxBackoff
+func (s *MailingService) backoff(attempt int) time.Duration {
+	delay := s.baseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= s.maxBackoff {
+			return s.maxBackoff
+		}
+	}
+	return min(delay, s.maxBackoff)
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package mailing is a generated GoMock package.
+package mailing
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	mail "uiren/internal/infrastracture/mail"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMailer is a mock of Mailer interface.
+type MockMailer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailerMockRecorder
+}
+
+// MockMailerMockRecorder is the mock recorder for MockMailer.
+type MockMailerMockRecorder struct {
+	mock *MockMailer
+}
+
+// NewMockMailer creates a new mock instance.
+func NewMockMailer(ctrl *gomock.Controller) *MockMailer {
+	mock := &MockMailer{ctrl: ctrl}
+	mock.recorder = &MockMailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailer) EXPECT() *MockMailerMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockMailer) Send(ctx context.Context, message mail.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMailerMockRecorder) Send(ctx, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMailer)(nil).Send), ctx, message)
+}
+
+// MockoutboxRepository is a mock of outboxRepository interface.
+type MockoutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockoutboxRepositoryMockRecorder
+}
+
+// MockoutboxRepositoryMockRecorder is the mock recorder for MockoutboxRepository.
+type MockoutboxRepositoryMockRecorder struct {
+	mock *MockoutboxRepository
+}
+
+// NewMockoutboxRepository creates a new mock instance.
+func NewMockoutboxRepository(ctrl *gomock.Controller) *MockoutboxRepository {
+	mock := &MockoutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockoutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockoutboxRepository) EXPECT() *MockoutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// claimDue mocks base method.
+func (m *MockoutboxRepository) claimDue(ctx context.Context, limit int, lockDuration time.Duration) ([]outboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "claimDue", ctx, limit, lockDuration)
+	ret0, _ := ret[0].([]outboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// claimDue indicates an expected call of claimDue.
+func (mr *MockoutboxRepositoryMockRecorder) claimDue(ctx, limit, lockDuration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "claimDue", reflect.TypeOf((*MockoutboxRepository)(nil).claimDue), ctx, limit, lockDuration)
+}
+
+// enqueue mocks base method.
+func (m *MockoutboxRepository) enqueue(ctx context.Context, email Email, subject, html, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "enqueue", ctx, email, subject, html, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// enqueue indicates an expected call of enqueue.
+func (mr *MockoutboxRepositoryMockRecorder) enqueue(ctx, email, subject, html, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "enqueue", reflect.TypeOf((*MockoutboxRepository)(nil).enqueue), ctx, email, subject, html, text)
+}
+
+// markFailed mocks base method.
+func (m *MockoutboxRepository) markFailed(ctx context.Context, id int64, lastError string, nextAttemptAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "markFailed", ctx, id, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// markFailed indicates an expected call of markFailed.
+func (mr *MockoutboxRepositoryMockRecorder) markFailed(ctx, id, lastError, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "markFailed", reflect.TypeOf((*MockoutboxRepository)(nil).markFailed), ctx, id, lastError, nextAttemptAt)
+}
+
+// markSent mocks base method.
+func (m *MockoutboxRepository) markSent(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "markSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// markSent indicates an expected call of markSent.
+func (mr *MockoutboxRepositoryMockRecorder) markSent(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "markSent", reflect.TypeOf((*MockoutboxRepository)(nil).markSent), ctx, id)
+}
@@ -0,0 +1,130 @@
+package mailing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"uiren/internal/infrastracture/mail"
+	"uiren/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+func Test_MailingService_Enqueue(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockoutboxRepository(ctrl)
+		mailer  = NewMockMailer(ctrl)
+		service = NewMailingService(repo, mailer)
+		data    = map[string]string{"Username": "seab", "Link": "https://uiren.kz/api/verify/seab/code?a=1&b=2"}
+	)
+
+	t.Run("english", func(t *testing.T) {
+		email := Email{Type: TypeEmailVerification, Locale: LocaleEN, To: "seab@mail.kz", Data: data}
+		repo.EXPECT().enqueue(ctx, email, "Uiren. Email verification", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ Email, _, html, text string) error {
+				assert.Contains(t, html, "Hello, seab!")
+				assert.Contains(t, html, `href="https://uiren.kz/api/verify/seab/code?a=1&amp;b=2"`)
+				assert.True(t, strings.HasPrefix(text, "Hello, seab!"))
+				assert.Contains(t, text, "https://uiren.kz/api/verify/seab/code?a=1&b=2")
+				return nil
+			})
+
+		assert.NoError(t, service.Enqueue(ctx, email))
+	})
+
+	t.Run("unsupported locale falls back to default", func(t *testing.T) {
+		email := Email{Type: TypeEmailVerification, Locale: "de", To: "seab@mail.kz", Data: data}
+		expected := email
+		expected.Locale = DefaultLocale
+		repo.EXPECT().enqueue(ctx, expected, "Uiren. Подтверждение почты", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ Email, _, html, text string) error {
+				assert.Contains(t, html, "Здравствуйте, seab!")
+				assert.Contains(t, text, "Здравствуйте, seab!")
+				return nil
+			})
+
+		assert.NoError(t, service.Enqueue(ctx, email))
+	})
+
+	t.Run("html is escaped", func(t *testing.T) {
+		email := Email{Type: TypeEmailVerification, Locale: LocaleEN, To: "seab@mail.kz", Data: map[string]string{"Username": "<b>seab</b>"}}
+		repo.EXPECT().enqueue(ctx, email, gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ Email, _, html, _ string) error {
+				assert.Contains(t, html, "&lt;b&gt;seab&lt;/b&gt;")
+				return nil
+			})
+
+		assert.NoError(t, service.Enqueue(ctx, email))
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		err := service.Enqueue(ctx, Email{Type: "unknown", Locale: LocaleEN, To: "seab@mail.kz"})
+		assert.Equal(t, ErrUnknownTemplate, err)
+	})
+
+	t.Run("empty recipient", func(t *testing.T) {
+		err := service.Enqueue(ctx, Email{Type: TypeEmailVerification, Locale: LocaleEN})
+		assert.Equal(t, ErrEmptyRecipient, err)
+	})
+}
+
+func Test_MailingService_processBatch(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockoutboxRepository(ctrl)
+		mailer  = NewMockMailer(ctrl)
+		service = NewMailingService(repo, mailer)
+		now     = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		errSMTP = errors.New("421 service not available")
+	)
+	service.now = func() time.Time { return now }
+	service.SetMaxAttempts(3)
+
+	sent := outboxMessage{id: 1, to: "seab@mail.kz", subject: "s", html: "h", text: "t"}
+	retried := outboxMessage{id: 2, to: "asan@mail.kz", attempts: 1}
+	givenUp := outboxMessage{id: 3, to: "nurs@mail.kz", attempts: 2}
+
+	repo.EXPECT().claimDue(ctx, defaultBatchSize, defaultLockDuration).Return([]outboxMessage{sent, retried, givenUp}, nil)
+
+	mailer.EXPECT().Send(ctx, mail.Message{To: []string{"seab@mail.kz"}, Subject: "s", HTML: "h", Text: "t"}).Return(nil)
+	repo.EXPECT().markSent(ctx, int64(1)).Return(nil)
+
+	nextAttemptAt := now.Add(2 * time.Minute)
+	mailer.EXPECT().Send(ctx, mail.Message{To: []string{"asan@mail.kz"}}).Return(errSMTP)
+	repo.EXPECT().markFailed(ctx, int64(2), errSMTP.Error(), &nextAttemptAt).Return(nil)
+
+	mailer.EXPECT().Send(ctx, mail.Message{To: []string{"nurs@mail.kz"}}).Return(errSMTP)
+	repo.EXPECT().markFailed(ctx, int64(3), errSMTP.Error(), nil).Return(nil)
+
+	assert.Equal(t, 3, service.processBatch(ctx))
+}
+
+func Test_MailingService_backoff(t *testing.T) {
+	t.Parallel()
+	service := NewMailingService(nil, nil)
+	service.SetBaseBackoff(time.Minute)
+	service.SetMaxBackoff(10 * time.Minute)
+
+	for attempt, expected := range map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		4: 8 * time.Minute,
+		5: 10 * time.Minute,
+		9: 10 * time.Minute,
+	} {
+		assert.Equal(t, expected, service.backoff(attempt), "attempt %d", attempt)
+	}
+}
@@ -0,0 +1,182 @@
+package mailing
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"uiren/internal/infrastracture/mail"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts everything and keeps received messages,
+// recipients in reject get a permanent error
+type fakeSMTPServer struct {
+	listener net.Listener
+	reject   map[string]bool
+
+	mu       sync.Mutex
+	auth     []string
+	messages []string
+}
+
+func newFakeSMTPServer(t *testing.T, reject ...string) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTPServer{listener: listener, reject: make(map[string]bool)}
+	for _, to := range reject {
+		server.reject["<"+to+">"] = true
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250-localhost\r\n250 AUTH PLAIN")
+		case "AUTH":
+			s.mu.Lock()
+			s.auth = append(s.auth, arg)
+			s.mu.Unlock()
+			text.PrintfLine("235 authenticated")
+		case "MAIL":
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			if s.reject[strings.TrimPrefix(arg, "TO:")] {
+				text.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) received() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.auth, s.messages
+}
+
+func Test_SMTPMailer_Send(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx    = context.TODO()
+		server = newFakeSMTPServer(t, "bad@mail.kz")
+		mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+			Address:     server.listener.Addr().String(),
+			Username:    "noreply@uiren.kz",
+			Password:    "secret",
+			FromName:    "Uiren",
+			FromAddress: "noreply@uiren.kz",
+			Timeout:     5 * time.Second,
+		})
+	)
+
+	err := mailer.Send(ctx, mail.Message{
+		To:      []string{"seab@mail.kz"},
+		Subject: "Uiren. Email verification",
+		HTML:    "<p>Hello</p>",
+		Text:    "Hello",
+	})
+	require.NoError(t, err)
+
+	err = mailer.Send(ctx, mail.Message{To: []string{"bad@mail.kz"}, Subject: "s", Text: "t"})
+	assert.ErrorContains(t, err, "550")
+
+	assert.Equal(t, mail.ErrNoRecipients, mailer.Send(ctx, mail.Message{}))
+
+	auth, messages := server.received()
+	require.Len(t, messages, 1)
+	assert.Len(t, auth, 2)
+	assert.True(t, strings.HasPrefix(auth[0], "PLAIN "))
+	assert.Contains(t, messages[0], "To: <seab@mail.kz>")
+	assert.Contains(t, messages[0], "Subject: Uiren. Email verification")
+	assert.Contains(t, messages[0], "text/html")
+	assert.Contains(t, messages[0], "<p>Hello</p>")
+}
+
+// Test_MailingService_outbox sends the rendered verification email
+// through the real SMTP mailer
+func Test_MailingService_outbox(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockoutboxRepository(ctrl)
+		server  = newFakeSMTPServer(t)
+		mailer  = mail.NewSMTPMailer(mail.SMTPConfig{Address: server.listener.Addr().String(), FromAddress: "noreply@uiren.kz"})
+		service = NewMailingService(repo, mailer)
+		stored  outboxMessage
+	)
+
+	repo.EXPECT().enqueue(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, email Email, subject, html, text string) error {
+			stored = outboxMessage{id: 7, to: email.To, subject: subject, html: html, text: text}
+			return nil
+		})
+	require.NoError(t, service.Enqueue(ctx, Email{
+		Type:   TypeEmailVerification,
+		Locale: LocaleEN,
+		To:     "seab@mail.kz",
+		Data:   map[string]string{"Username": "seab", "Link": "https://uiren.kz/api/verify/seab/code"},
+	}))
+
+	repo.EXPECT().claimDue(ctx, defaultBatchSize, defaultLockDuration).DoAndReturn(
+		func(context.Context, int, time.Duration) ([]outboxMessage, error) {
+			return []outboxMessage{stored}, nil
+		})
+	repo.EXPECT().markSent(ctx, int64(7)).Return(nil)
+
+	assert.Equal(t, 1, service.processBatch(ctx))
+
+	auth, messages := server.received()
+	require.Len(t, messages, 1)
+	assert.Empty(t, auth)
+	assert.Contains(t, messages[0], "Subject: Uiren. Email verification")
+	assert.Contains(t, messages[0], "Hello, seab!")
+}
@@ -0,0 +1,78 @@
+package mailing
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// every type has <locale>/<type>.txt with "subject" and "text" blocks and <locale>/<type>.html
+//
+//go:embed templates
+var templateFiles embed.FS
+
+var locales = []string{LocaleRU, LocaleEN}
+
+type templateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates are parsed once, the embedded files can't be broken at runtime
+type templates struct {
+	sets map[string]templateSet
+}
+
+func newTemplates() *templates {
+	t := &templates{sets: make(map[string]templateSet)}
+
+	for _, locale := range locales {
+		files, err := templateFiles.ReadDir("templates/" + locale)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, file := range files {
+			messageType, ok := strings.CutSuffix(file.Name(), ".txt")
+			if !ok {
+				continue
+			}
+			path := "templates/" + locale + "/" + messageType
+
+			t.sets[locale+"/"+messageType] = templateSet{
+				text: texttemplate.Must(texttemplate.ParseFS(templateFiles, path+".txt")),
+				html: htmltemplate.Must(htmltemplate.ParseFS(templateFiles, path+".html")),
+			}
+		}
+	}
+
+	return t
+}
+
+// render returns subject, html and text of the email
+func (t *templates) render(email Email) (string, string, string, error) {
+	set, ok := t.sets[email.Locale+"/"+email.Type]
+	if !ok {
+		return "", "", "", ErrUnknownTemplate
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", email.Data); err != nil {
+		return "", "", "", err
+	}
+	if err := set.text.ExecuteTemplate(&text, "text", email.Data); err != nil {
+		return "", "", "", err
+	}
+	if err := set.html.Execute(&html, email.Data); err != nil {
+		return "", "", "", err
+	}
+
+	return strings.TrimSpace(subject.String()), html.String(), strings.TrimSpace(text.String()), nil
+}
+
+func (t *templates) has(messageType, locale string) bool {
+	_, ok := t.sets[locale+"/"+messageType]
+	return ok
+}
@@ -0,0 +1,233 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"time"
+)
+
+// detectType sniffs the content type, http.DetectContentType knows mp3 only with the ID3 tag
+// and reports ogg without the codec, so audio is checked by its own headers
+func detectType(content []byte) string {
+	switch {
+	case isMP3(content):
+		return "audio/mpeg"
+	case bytes.HasPrefix(content, []byte("OggS")):
+		return "audio/ogg"
+	case len(content) >= 12 && string(content[:4]) == "RIFF" && string(content[8:12]) == "WAVE":
+		return "audio/wav"
+	}
+	return http.DetectContentType(content)
+}
+
+func audioDuration(content []byte, contentType string) (time.Duration, error) {
+	switch contentType {
+	case "audio/mpeg":
+		return mp3Duration(content)
+	case "audio/ogg":
+		return oggDuration(content)
+	case "audio/wav":
+		return wavDuration(content)
+	default:
+		return 0, ErrUnsupportedType
+	}
+}
+
+// mp3 layer III tables, indexed by the header bits
+var (
+	mp3Bitrates = map[bool][16]int{
+		true:  {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+		false: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	}
+	mp3SampleRates = map[byte][3]int{
+		3: {44100, 48000, 32000}, // MPEG 1
+		2: {22050, 24000, 16000}, // MPEG 2
+		0: {11025, 12000, 8000},  // MPEG 2.5
+	}
+)
+
+type mp3Frame struct {
+	length     int
+	samples    int
+	sampleRate int
+}
+
+// parseMP3Frame reads the layer III frame header, ok is false for anything else
+func parseMP3Frame(header []byte) (mp3Frame, bool) {
+	if len(header) < 4 || header[0] != 0xFF || header[1]&0xE0 != 0xE0 {
+		return mp3Frame{}, false
+	}
+
+	version := (header[1] >> 3) & 0x03
+	layer := (header[1] >> 1) & 0x03
+	bitrateIndex := header[2] >> 4
+	sampleRateIndex := (header[2] >> 2) & 0x03
+	padding := int((header[2] >> 1) & 0x01)
+
+	rates, ok := mp3SampleRates[version]
+	if !ok || layer != 1 || sampleRateIndex == 3 {
+		return mp3Frame{}, false
+	}
+	mpeg1 := version == 3
+	bitrate := mp3Bitrates[mpeg1][bitrateIndex] * 1000
+	if bitrate == 0 {
+		return mp3Frame{}, false
+	}
+	sampleRate := rates[sampleRateIndex]
+
+	frame := mp3Frame{sampleRate: sampleRate, samples: 576, length: 72*bitrate/sampleRate + padding}
+	if mpeg1 {
+		frame.samples = 1152
+		frame.length = 144*bitrate/sampleRate + padding
+	}
+	return frame, true
+}
+
+// skipID3 returns the offset of the first byte after the ID3v2 tag
+func skipID3(content []byte) int {
+	if len(content) < 10 || string(content[:3]) != "ID3" {
+		return 0
+	}
+	size := int(content[6]&0x7F)<<21 | int(content[7]&0x7F)<<14 | int(content[8]&0x7F)<<7 | int(content[9]&0x7F)
+	offset := 10 + size
+	if content[5]&0x10 != 0 {
+		offset += 10
+	}
+	return offset
+}
+
+// isMP3 requires two frames in a row, a single sync word appears in random data too
+func isMP3(content []byte) bool {
+	offset := skipID3(content)
+	if offset+4 > len(content) {
+		return false
+	}
+
+	frame, ok := parseMP3Frame(content[offset:])
+	if !ok {
+		return false
+	}
+	next := offset + frame.length
+	if next == len(content) {
+		return true
+	}
+	if next+4 > len(content) {
+		return false
+	}
+	_, ok = parseMP3Frame(content[next:])
+	return ok
+}
+
+func mp3Duration(content []byte) (time.Duration, error) {
+	var (
+		offset  = skipID3(content)
+		seconds float64
+		frames  int
+	)
+
+	for offset+4 <= len(content) {
+		frame, ok := parseMP3Frame(content[offset:])
+		if !ok {
+			// the ID3v1 tag or junk after the last frame
+			break
+		}
+		seconds += float64(frame.samples) / float64(frame.sampleRate)
+		offset += frame.length
+		frames++
+	}
+
+	if frames == 0 {
+		return 0, ErrInvalidFile
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func wavDuration(content []byte) (time.Duration, error) {
+	var (
+		byteRate uint32
+		offset   = 12
+	)
+
+	for offset+8 <= len(content) {
+		id := string(content[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(content[offset+4 : offset+8]))
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			if size < 16 || body+16 > len(content) {
+				return 0, ErrInvalidFile
+			}
+			byteRate = binary.LittleEndian.Uint32(content[body+8 : body+12])
+		case "data":
+			if byteRate == 0 {
+				return 0, ErrInvalidFile
+			}
+			// streamed files have no real data size
+			size = min(size, len(content)-body)
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second)), nil
+		}
+
+		// chunks are padded to an even size
+		offset = body + size + size%2
+	}
+
+	return 0, ErrInvalidFile
+}
+
+// oggDuration takes the granule position of the last page, it counts samples
+// at the vorbis sample rate or at 48 kHz for opus
+func oggDuration(content []byte) (time.Duration, error) {
+	var (
+		sampleRate uint32
+		preSkip    uint64
+		granule    uint64
+		first      = true
+		offset     = 0
+	)
+
+	for offset+27 <= len(content) {
+		if string(content[offset:offset+4]) != "OggS" {
+			return 0, ErrInvalidFile
+		}
+		segments := int(content[offset+26])
+		headerEnd := offset + 27 + segments
+		if headerEnd > len(content) {
+			return 0, ErrInvalidFile
+		}
+		bodySize := 0
+		for _, lacing := range content[offset+27 : headerEnd] {
+			bodySize += int(lacing)
+		}
+		if headerEnd+bodySize > len(content) {
+			return 0, ErrInvalidFile
+		}
+		body := content[headerEnd : headerEnd+bodySize]
+
+		if first {
+			switch {
+			case len(body) >= 16 && string(body[:7]) == "\x01vorbis":
+				sampleRate = binary.LittleEndian.Uint32(body[12:16])
+			case len(body) >= 12 && string(body[:8]) == "OpusHead":
+				sampleRate = 48000
+				preSkip = uint64(binary.LittleEndian.Uint16(body[10:12]))
+			default:
+				return 0, ErrUnsupportedType
+			}
+			first = false
+		}
+
+		// -1 means no packet ends on the page
+		if position := binary.LittleEndian.Uint64(content[offset+6 : offset+14]); position != ^uint64(0) {
+			granule = max(granule, position)
+		}
+		offset = headerEnd + bodySize
+	}
+
+	if sampleRate == 0 {
+		return 0, ErrInvalidFile
+	}
+	samples := granule - min(granule, preSkip)
+	return time.Duration(float64(samples) / float64(sampleRate) * float64(time.Second)), nil
+}
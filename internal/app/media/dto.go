@@ -0,0 +1,54 @@
+package media
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	KindImage = "image"
+	KindAudio = "audio"
+)
+
+const (
+	maxAssetBytes    = 3 << 20
+	maxImagePixels   = 25_000_000
+	maxAudioDuration = 2 * time.Minute
+
+	storagePrefix = "media"
+)
+
+type assetType struct {
+	kind      string
+	extension string
+}
+
+// allowedTypes are keyed by the sniffed content type, the declared one is not trusted
+var allowedTypes = map[string]assetType{
+	"image/jpeg": {kind: KindImage, extension: ".jpg"},
+	"image/png":  {kind: KindImage, extension: ".png"},
+	"image/webp": {kind: KindImage, extension: ".webp"},
+	"audio/mpeg": {kind: KindAudio, extension: ".mp3"},
+	"audio/ogg":  {kind: KindAudio, extension: ".ogg"},
+	"audio/wav":  {kind: KindAudio, extension: ".wav"},
+}
+
+type Asset struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	ContentType string    `json:"content_type"`
+	ObjectKey   string    `json:"-"`
+	Size        int64     `json:"size"`
+	Width       int       `json:"width,omitempty"`
+	Height      int       `json:"height,omitempty"`
+	DurationMs  int       `json:"duration_ms,omitempty"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	// URL is resolved on every read, presigned URLs expire
+	URL string `json:"url,omitempty"`
+}
+
+type UploadAssetRequest struct {
+	File      io.Reader
+	CreatedBy string
+}
@@ -0,0 +1,13 @@
+package media
+
+import "errors"
+
+var (
+	ErrAssetNotFound   = errors.New("media asset not found")
+	ErrUnsupportedType = errors.New("unsupported media type, allowed jpeg, png, webp, mp3, ogg and wav")
+	ErrFileTooLarge    = errors.New("media file is too large")
+	ErrTooManyPixels   = errors.New("image dimensions are too large")
+	ErrInvalidFile     = errors.New("media file is damaged")
+	ErrAudioTooLong    = errors.New("audio is too long")
+	ErrWrongKind       = errors.New("media asset has wrong kind")
+)
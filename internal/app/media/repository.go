@@ -0,0 +1,83 @@
+package media
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewMediaRepository(db *pgxpool.Pool) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const assetColumns = `id, kind, content_type, object_key, size_bytes, width, height, duration_ms, created_by, created_at`
+
+// createAsset returns the existing asset when the same file was uploaded before,
+// objects are content addressed, so one object has one asset
+func (r *repository) createAsset(ctx context.Context, asset Asset) (Asset, error) {
+	var (
+		query = `
+		INSERT INTO media_assets (kind, content_type, object_key, size_bytes, width, height, duration_ms, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (object_key) DO UPDATE SET object_key = EXCLUDED.object_key
+		RETURNING ` + assetColumns + `;
+		`
+	)
+
+	row := r.db.QueryRow(ctx, query, asset.Kind, asset.ContentType, asset.ObjectKey, asset.Size,
+		asset.Width, asset.Height, asset.DurationMs, asset.CreatedBy)
+	return scanAsset(row)
+}
+
+func (r *repository) getAsset(ctx context.Context, id string) (Asset, error) {
+	var (
+		query = `
+		SELECT ` + assetColumns + ` FROM media_assets WHERE id = $1;
+		`
+	)
+
+	return scanAsset(r.db.QueryRow(ctx, query, id))
+}
+
+func (r *repository) getAssetsByIDs(ctx context.Context, ids []string) ([]Asset, error) {
+	var (
+		query = `
+		SELECT ` + assetColumns + ` FROM media_assets WHERE id = ANY($1::uuid[]);
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assets []Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, rows.Err()
+}
+
+func scanAsset(row pgx.Row) (Asset, error) {
+	var asset Asset
+	err := row.Scan(&asset.ID, &asset.Kind, &asset.ContentType, &asset.ObjectKey, &asset.Size,
+		&asset.Width, &asset.Height, &asset.DurationMs, &asset.CreatedBy, &asset.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Asset{}, ErrAssetNotFound
+	}
+	return asset, err
+}
@@ -0,0 +1,213 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"slices"
+	"strings"
+	"uiren/internal/infrastracture/storage"
+	"uiren/pkg/logger"
+
+	"github.com/google/uuid"
+	_ "golang.org/x/image/webp"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package media
+
+type mediaRepository interface {
+	createAsset(ctx context.Context, asset Asset) (Asset, error)
+	getAsset(ctx context.Context, id string) (Asset, error)
+	getAssetsByIDs(ctx context.Context, ids []string) ([]Asset, error)
+}
+
+type objectStorage interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// MediaService keeps images and audio exercises are built from
+type MediaService struct {
+	repo    mediaRepository
+	storage objectStorage
+}
+
+func NewMediaService(repo mediaRepository, storage objectStorage) *MediaService {
+	return &MediaService{
+		repo:    repo,
+		storage: storage,
+	}
+}
+
+// UploadAsset validates the file by its content and stores it,
+// the same file uploaded twice returns the same asset
+func (s *MediaService) UploadAsset(ctx context.Context, req UploadAssetRequest) (Asset, error) {
+	logger.Info("MediaService.UploadAsset new request")
+
+	content, err := io.ReadAll(io.LimitReader(req.File, maxAssetBytes+1))
+	if err != nil {
+		logger.Error("MediaService.UploadAsset io.ReadAll: ", err)
+		return Asset{}, err
+	}
+	if len(content) > maxAssetBytes {
+		return Asset{}, ErrFileTooLarge
+	}
+
+	contentType := detectType(content)
+	fileType, ok := allowedTypes[contentType]
+	if !ok {
+		return Asset{}, ErrUnsupportedType
+	}
+
+	asset := Asset{
+		Kind:        fileType.kind,
+		ContentType: contentType,
+		ObjectKey:   storage.ContentKey(storagePrefix, content, fileType.extension),
+		Size:        int64(len(content)),
+		CreatedBy:   req.CreatedBy,
+	}
+
+	switch fileType.kind {
+	case KindImage:
+		config, _, err := image.DecodeConfig(bytes.NewReader(content))
+		if err != nil || config.Width <= 0 || config.Height <= 0 {
+			return Asset{}, ErrInvalidFile
+		}
+		if config.Width*config.Height > maxImagePixels {
+			return Asset{}, ErrTooManyPixels
+		}
+		asset.Width, asset.Height = config.Width, config.Height
+	case KindAudio:
+		duration, err := audioDuration(content, contentType)
+		if err != nil {
+			return Asset{}, err
+		}
+		if duration <= 0 {
+			return Asset{}, ErrInvalidFile
+		}
+		if duration > maxAudioDuration {
+			return Asset{}, ErrAudioTooLong
+		}
+		asset.DurationMs = int(duration.Milliseconds())
+	}
+
+	// the object is stored before the asset points to it
+	if err := s.storage.Put(ctx, asset.ObjectKey, content, contentType); err != nil {
+		logger.Error("MediaService.UploadAsset storage.Put: ", err)
+		return Asset{}, err
+	}
+
+	created, err := s.repo.createAsset(ctx, asset)
+	if err != nil {
+		logger.Error("MediaService.UploadAsset repo.createAsset: ", err)
+		return Asset{}, err
+	}
+
+	return s.withURL(ctx, created)
+}
+
+func (s *MediaService) GetAsset(ctx context.Context, id string) (Asset, error) {
+	logger.Info("MediaService.GetAsset new request")
+
+	if _, err := uuid.Parse(id); err != nil {
+		return Asset{}, ErrAssetNotFound
+	}
+
+	asset, err := s.repo.getAsset(ctx, id)
+	if err != nil {
+		logger.Error("MediaService.GetAsset repo.getAsset: ", err)
+		return Asset{}, err
+	}
+
+	return s.withURL(ctx, asset)
+}
+
+// GetAssets returns the assets in the order of ids, any missing id fails with ErrAssetNotFound
+func (s *MediaService) GetAssets(ctx context.Context, ids []string) ([]Asset, error) {
+	logger.Info("MediaService.GetAssets new request")
+
+	unique := uniqueIDs(ids)
+	if len(unique) == 0 {
+		return nil, nil
+	}
+
+	var missing []string
+	for _, id := range unique {
+		if _, err := uuid.Parse(id); err != nil {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAssetNotFound, strings.Join(missing, ", "))
+	}
+
+	assets, err := s.repo.getAssetsByIDs(ctx, unique)
+	if err != nil {
+		logger.Error("MediaService.GetAssets repo.getAssetsByIDs: ", err)
+		return nil, err
+	}
+
+	byID := make(map[string]Asset, len(assets))
+	for _, asset := range assets {
+		byID[asset.ID] = asset
+	}
+
+	result := make([]Asset, 0, len(unique))
+	for _, id := range unique {
+		asset, ok := byID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		if asset, err = s.withURL(ctx, asset); err != nil {
+			return nil, err
+		}
+		result = append(result, asset)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAssetNotFound, strings.Join(missing, ", "))
+	}
+
+	return result, nil
+}
+
+// ResolveURLs maps asset ids to the addresses clients download them from
+func (s *MediaService) ResolveURLs(ctx context.Context, ids []string) (map[string]string, error) {
+	logger.Info("MediaService.ResolveURLs new request")
+
+	assets, err := s.GetAssets(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make(map[string]string, len(assets))
+	for _, asset := range assets {
+		urls[asset.ID] = asset.URL
+	}
+	return urls, nil
+}
+
+func (s *MediaService) withURL(ctx context.Context, asset Asset) (Asset, error) {
+	url, err := s.storage.URL(ctx, asset.ObjectKey)
+	if err != nil {
+		logger.Error("MediaService.withURL storage.URL: ", err)
+		return Asset{}, err
+	}
+	asset.URL = url
+	return asset, nil
+}
+
+// uniqueIDs drops empty and repeated ids keeping the order
+func uniqueIDs(ids []string) []string {
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package media is a generated GoMock package.
+package media
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockmediaRepository is a mock of mediaRepository interface.
+type MockmediaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockmediaRepositoryMockRecorder
+}
+
+// MockmediaRepositoryMockRecorder is the mock recorder for MockmediaRepository.
+type MockmediaRepositoryMockRecorder struct {
+	mock *MockmediaRepository
+}
+
+// NewMockmediaRepository creates a new mock instance.
+func NewMockmediaRepository(ctrl *gomock.Controller) *MockmediaRepository {
+	mock := &MockmediaRepository{ctrl: ctrl}
+	mock.recorder = &MockmediaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmediaRepository) EXPECT() *MockmediaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// createAsset mocks base method.
+func (m *MockmediaRepository) createAsset(ctx context.Context, asset Asset) (Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createAsset", ctx, asset)
+	ret0, _ := ret[0].(Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createAsset indicates an expected call of createAsset.
+func (mr *MockmediaRepositoryMockRecorder) createAsset(ctx, asset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createAsset", reflect.TypeOf((*MockmediaRepository)(nil).createAsset), ctx, asset)
+}
+
+// getAsset mocks base method.
+func (m *MockmediaRepository) getAsset(ctx context.Context, id string) (Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getAsset", ctx, id)
+	ret0, _ := ret[0].(Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getAsset indicates an expected call of getAsset.
+func (mr *MockmediaRepositoryMockRecorder) getAsset(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getAsset", reflect.TypeOf((*MockmediaRepository)(nil).getAsset), ctx, id)
+}
+
+// getAssetsByIDs mocks base method.
+func (m *MockmediaRepository) getAssetsByIDs(ctx context.Context, ids []string) ([]Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getAssetsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getAssetsByIDs indicates an expected call of getAssetsByIDs.
+func (mr *MockmediaRepositoryMockRecorder) getAssetsByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getAssetsByIDs", reflect.TypeOf((*MockmediaRepository)(nil).getAssetsByIDs), ctx, ids)
+}
+
+// MockobjectStorage is a mock of objectStorage interface.
+type MockobjectStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockobjectStorageMockRecorder
+}
+
+// MockobjectStorageMockRecorder is the mock recorder for MockobjectStorage.
+type MockobjectStorageMockRecorder struct {
+	mock *MockobjectStorage
+}
+
+// NewMockobjectStorage creates a new mock instance.
+func NewMockobjectStorage(ctrl *gomock.Controller) *MockobjectStorage {
+	mock := &MockobjectStorage{ctrl: ctrl}
+	mock.recorder = &MockobjectStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockobjectStorage) EXPECT() *MockobjectStorageMockRecorder {
+	return m.recorder
+}
+
+// Put mocks base method.
+func (m *MockobjectStorage) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, content, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockobjectStorageMockRecorder) Put(ctx, key, content, contentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockobjectStorage)(nil).Put), ctx, key, content, contentType)
+}
+
+// URL mocks base method.
+func (m *MockobjectStorage) URL(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockobjectStorageMockRecorder) URL(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockobjectStorage)(nil).URL), ctx, key)
+}
@@ -0,0 +1,196 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+	"uiren/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+// wavFile returns 16 bit mono 8 kHz silence
+func wavFile(duration time.Duration) []byte {
+	const byteRate = 16000
+	data := make([]byte, int(duration.Seconds()*byteRate))
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+len(data)))
+	b.WriteString("WAVEfmt ")
+	binary.Write(&b, binary.LittleEndian, []uint32{16})
+	binary.Write(&b, binary.LittleEndian, []uint16{1, 1})
+	binary.Write(&b, binary.LittleEndian, []uint32{8000, byteRate})
+	binary.Write(&b, binary.LittleEndian, []uint16{2, 16})
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+// mp3File returns MPEG 1 layer III frames of 128 kbps at 44.1 kHz, 1152 samples each
+func mp3File(frames int) []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+
+	b := bytes.NewBufferString("ID3\x04\x00\x00\x00\x00\x00\x02tg")
+	for i := 0; i < frames; i++ {
+		b.Write(frame)
+	}
+	b.WriteString("TAG")
+	return b.Bytes()
+}
+
+func oggPage(granule uint64, body []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("OggS\x00\x00")
+	binary.Write(&b, binary.LittleEndian, granule)
+	b.Write(make([]byte, 12))
+	b.WriteByte(1)
+	b.WriteByte(byte(len(body)))
+	b.Write(body)
+	return b.Bytes()
+}
+
+// opusFile returns the opus head and one page ending at the duration
+func opusFile(duration time.Duration) []byte {
+	head := []byte("OpusHead\x01\x01\x38\x01\x80\xBB\x00\x00\x00\x00\x00")
+	return append(oggPage(0, head), oggPage(uint64(duration.Seconds()*48000)+312, []byte{0})...)
+}
+
+func pngFile(w, h int) []byte {
+	var b bytes.Buffer
+	png.Encode(&b, image.NewNRGBA(image.Rect(0, 0, w, h)))
+	return b.Bytes()
+}
+
+func Test_MediaService_UploadAsset(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockmediaRepository(ctrl)
+		storage = NewMockobjectStorage(ctrl)
+		service = NewMediaService(repo, storage)
+	)
+
+	for name, test := range map[string]struct {
+		content     []byte
+		contentType string
+		expected    Asset
+	}{
+		"png":  {content: pngFile(30, 20), contentType: "image/png", expected: Asset{Kind: KindImage, Width: 30, Height: 20}},
+		"wav":  {content: wavFile(3 * time.Second), contentType: "audio/wav", expected: Asset{Kind: KindAudio, DurationMs: 3000}},
+		"mp3":  {content: mp3File(100), contentType: "audio/mpeg", expected: Asset{Kind: KindAudio, DurationMs: 2612}},
+		"opus": {content: opusFile(5 * time.Second), contentType: "audio/ogg", expected: Asset{Kind: KindAudio, DurationMs: 5000}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			expected := test.expected
+			expected.ContentType = test.contentType
+			expected.Size = int64(len(test.content))
+			expected.CreatedBy = "admin"
+
+			storage.EXPECT().Put(ctx, gomock.Any(), test.content, test.contentType).DoAndReturn(
+				func(_ context.Context, key string, _ []byte, _ string) error {
+					expected.ObjectKey = key
+					return nil
+				})
+			repo.EXPECT().createAsset(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, asset Asset) (Asset, error) {
+				assert.Equal(t, expected, asset)
+				asset.ID = "asset-" + name
+				return asset, nil
+			})
+			storage.EXPECT().URL(ctx, gomock.Any()).Return("https://cdn.uiren.kz/"+name, nil)
+
+			asset, err := service.UploadAsset(ctx, UploadAssetRequest{File: bytes.NewReader(test.content), CreatedBy: "admin"})
+			require.NoError(t, err)
+			assert.Equal(t, "asset-"+name, asset.ID)
+			assert.Equal(t, "https://cdn.uiren.kz/"+name, asset.URL)
+		})
+	}
+}
+
+func Test_MediaService_UploadAsset_invalid(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		service = NewMediaService(NewMockmediaRepository(ctrl), NewMockobjectStorage(ctrl))
+	)
+
+	for name, test := range map[string]struct {
+		content []byte
+		err     error
+	}{
+		"text":        {content: []byte("hello"), err: ErrUnsupportedType},
+		"svg":         {content: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), err: ErrUnsupportedType},
+		"too large":   {content: make([]byte, maxAssetBytes+1), err: ErrFileTooLarge},
+		"broken png":  {content: pngFile(10, 10)[:20], err: ErrInvalidFile},
+		"long audio":  {content: wavFile(121 * time.Second), err: ErrAudioTooLong},
+		"empty audio": {content: wavFile(0), err: ErrInvalidFile},
+		"theora":      {content: oggPage(0, []byte("\x80theora")), err: ErrUnsupportedType},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.UploadAsset(ctx, UploadAssetRequest{File: bytes.NewReader(test.content)})
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func Test_MediaService_GetAssets(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockmediaRepository(ctrl)
+		storage = NewMockobjectStorage(ctrl)
+		service = NewMediaService(repo, storage)
+		image   = Asset{ID: "0b6c1c52-7a43-4bd2-9d0c-3b3f1d5c8a11", Kind: KindImage, ObjectKey: "media/a.png"}
+		audio   = Asset{ID: "8f14e45f-ceea-467a-9575-8e2c6c3b1e20", Kind: KindAudio, ObjectKey: "media/b.mp3"}
+	)
+
+	t.Run("resolve urls", func(t *testing.T) {
+		repo.EXPECT().getAssetsByIDs(ctx, []string{image.ID, audio.ID}).Return([]Asset{audio, image}, nil)
+		storage.EXPECT().URL(ctx, "media/a.png").Return("https://cdn.uiren.kz/media/a.png", nil)
+		storage.EXPECT().URL(ctx, "media/b.mp3").Return("https://cdn.uiren.kz/media/b.mp3", nil)
+
+		urls, err := service.ResolveURLs(ctx, []string{image.ID, "", audio.ID, image.ID})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			image.ID: "https://cdn.uiren.kz/media/a.png",
+			audio.ID: "https://cdn.uiren.kz/media/b.mp3",
+		}, urls)
+	})
+
+	t.Run("missing asset", func(t *testing.T) {
+		missing := "3c59dc04-8f0c-4d1a-b8a3-0a1f2d3e4b5c"
+		repo.EXPECT().getAssetsByIDs(ctx, []string{image.ID, missing}).Return([]Asset{image}, nil)
+		storage.EXPECT().URL(ctx, "media/a.png").Return("https://cdn.uiren.kz/media/a.png", nil)
+
+		_, err := service.GetAssets(ctx, []string{image.ID, missing})
+		assert.True(t, errors.Is(err, ErrAssetNotFound))
+		assert.Contains(t, err.Error(), missing)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, err := service.GetAssets(ctx, []string{"image.png"})
+		assert.True(t, errors.Is(err, ErrAssetNotFound))
+	})
+
+	t.Run("no ids", func(t *testing.T) {
+		assets, err := service.GetAssets(ctx, []string{""})
+		assert.NoError(t, err)
+		assert.Empty(t, assets)
+	})
+}
@@ -14,6 +14,7 @@ type Module struct {
 	UnlockReq   UnlockRequirements `bson:"unlock_requirements" json:"unlock_requirements"`
 	Reward      Reward             `bson:"reward" json:"reward"`
 	Lessons     []string           `bson:"lessons" json:"lessons"`
+	Status      string             `bson:"status" json:"status"`
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 	DeletedAt   *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
 }
@@ -27,6 +28,7 @@ type ModuleWithLessons struct {
 	UnlockReq   UnlockRequirements  `json:"unlock_requirements"`
 	Reward      Reward              `json:"reward"`
 	Lessons     []lessons.LessonDTO `json:"lessons"`
+	Status      string              `json:"status"`
 	CreatedAt   time.Time           `json:"created_at"`
 	DeletedAt   time.Time           `json:"deleted_at"`
 }
@@ -45,6 +47,7 @@ func (module Module) toDTO(lessons []lessons.LessonDTO) ModuleWithLessons {
 		UnlockReq:   module.UnlockReq,
 		Reward:      module.Reward,
 		Lessons:     lessons,
+		Status:      module.Status,
 		CreatedAt:   module.CreatedAt,
 		DeletedAt:   moduleDeletedAt,
 	}
@@ -71,6 +74,7 @@ type CreateModuleDTO struct {
 	UnlockReq   UnlockRequirements `bson:"unlock_requirements" json:"unlock_requirements"`
 	Reward      Reward             `bson:"reward" json:"reward"`
 	Lessons     []string           `bson:"lessons"`
+	Status      string             `bson:"status" json:"-"`
 	CreatedAt   time.Time          `bson:"created_at"`
 	DeletedAt   *time.Time         `bson:"deleted_at"`
 }
@@ -83,3 +87,18 @@ type UpdateModuleDTO struct {
 	UnlockReq   *UnlockRequirements `bson:"unlock_requirements,omitempty" json:"unlock_requirements,omitempty"`
 	Reward      *Reward             `bson:"reward,omitempty" json:"reward,omitempty"`
 }
+
+type ReorderLessonsDTO struct {
+	Lessons []string `json:"lessons"`
+}
+
+// SortFields lists fields the modules list can be sorted by, the first one is the default
+var SortFields = []string{"created_at", "code", "title"}
+
+// ListFilter narrows the modules list, empty fields are not applied
+type ListFilter struct {
+	Difficulty  string
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
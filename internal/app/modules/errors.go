@@ -9,4 +9,6 @@ var (
 	ErrInvalidCode        = errors.New("invalid code")
 	ErrLessonAlreadyInSet = errors.New("lesson already in list")
 	ErrLessonNotInList    = errors.New("lesson is not in list")
+	ErrNotPermutation     = errors.New("lessons list must contain exactly the current lessons")
+	ErrListChanged        = errors.New("lessons list was changed by another request")
 )
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"time"
+	"uiren/internal/app/publication"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -87,10 +90,9 @@ func (r *modulesRepository) deleteModule(ctx context.Context, code string) error
 	return nil
 }
 
-func (r *modulesRepository) updateModule(ctx context.Context, code string, dto UpdateModuleDTO) error {
+func (r *modulesRepository) updateModule(ctx context.Context, code string, dto UpdateModuleDTO) (Module, error) {
 	var (
-		collection = r.db.Collection(modulesCollection)
-		filter     = bson.M{
+		filter = bson.M{
 			"code":       code,
 			"deleted_at": nil,
 		}
@@ -117,70 +119,185 @@ func (r *modulesRepository) updateModule(ctx context.Context, code string, dto U
 	}
 
 	if len(update) == 0 {
-		return ErrNoFieldsToUpdate
+		return Module{}, ErrNoFieldsToUpdate
 	}
 
 	update = bson.M{
 		"$set": update,
 	}
-	res, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
+	module, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Module{}, ErrNotFound
 	}
 
-	if res.MatchedCount == 0 {
-		return ErrNotFound
+	return module, err
+}
+
+func (r *modulesRepository) restoreModule(ctx context.Context, code string, module Module) (Module, error) {
+	var (
+		filter = bson.M{
+			"code":       code,
+			"deleted_at": nil,
+		}
+		update = bson.M{"$set": bson.M{
+			"title":               module.Title,
+			"description":         module.Description,
+			"goal":                module.Goal,
+			"difficulty":          module.Difficulty,
+			"unlock_requirements": module.UnlockReq,
+			"reward":              module.Reward,
+			"lessons":             module.Lessons,
+		}}
+	)
+
+	restored, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Module{}, ErrNotFound
 	}
 
-	return nil
+	return restored, err
+}
+
+// setModuleStatus changes status only if nobody changed it since it was read
+func (r *modulesRepository) setModuleStatus(ctx context.Context, code, from, to string) (Module, error) {
+	var (
+		filter = bson.M{
+			"code":       code,
+			"deleted_at": nil,
+			"status":     from,
+		}
+		update = bson.M{"$set": bson.M{"status": to}}
+	)
+
+	module, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Module{}, publication.ErrStatusChanged
+	}
+
+	return module, err
 }
 
-func (r *modulesRepository) addLessonToList(ctx context.Context, code, lessonCode string) error {
+func (r *modulesRepository) getModuleCodesByLesson(ctx context.Context, lessonCode string) ([]string, error) {
 	var (
 		collection = r.db.Collection(modulesCollection)
-		filter     = bson.M{"code": code, "deleted_at": nil}
-		update     = bson.M{"$addToSet": bson.M{
-			"lessons": lessonCode,
-		}}
+		filter     = bson.M{
+			"lessons":    lessonCode,
+			"deleted_at": nil,
+		}
+		opts  = options.Find().SetProjection(bson.M{"code": 1, "_id": 0})
+		codes = make([]string, 0)
 	)
 
-	res, err := collection.UpdateOne(ctx, filter, update)
+	cur, err := collection.Find(ctx, filter, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer cur.Close(ctx)
 
-	if res.MatchedCount == 0 {
-		return ErrNotFound
+	for cur.Next(ctx) {
+		var module Module
+
+		if err = cur.Decode(&module); err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, module.Code)
 	}
-	if res.ModifiedCount == 0 {
-		return ErrLessonAlreadyInSet
+
+	return codes, cur.Err()
+}
+
+func (r *modulesRepository) unlinkLesson(ctx context.Context, code, lessonCode string) (Module, error) {
+	var (
+		filter = bson.M{
+			"code":       code,
+			"lessons":    lessonCode,
+			"deleted_at": nil,
+		}
+		update = bson.M{"$pull": bson.M{"lessons": lessonCode}}
+	)
+
+	module, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Module{}, ErrLessonNotInList
 	}
 
-	return nil
+	return module, err
 }
 
-func (r *modulesRepository) deleteLessonFromList(ctx context.Context, code, lessonCode string) error {
+func (r *modulesRepository) addLessonToList(ctx context.Context, code, lessonCode string) (Module, error) {
 	var (
-		collection = r.db.Collection(modulesCollection)
-		filter     = bson.M{"code": code, "deleted_at": nil}
-		update     = bson.M{"$pull": bson.M{
+		filter = bson.M{"code": code, "deleted_at": nil, "lessons": bson.M{"$ne": lessonCode}}
+		update = bson.M{"$push": bson.M{
 			"lessons": lessonCode,
 		}}
 	)
 
-	res, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return err
+	module, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// either there is no such module or the lesson is in the list already
+		if _, err := r.getModule(ctx, code); err != nil {
+			return Module{}, err
+		}
+		return Module{}, ErrLessonAlreadyInSet
 	}
 
-	if res.MatchedCount == 0 {
-		return ErrNotFound
+	return module, err
+}
+
+func (r *modulesRepository) deleteLessonFromList(ctx context.Context, code, lessonCode string) (Module, error) {
+	var (
+		filter = bson.M{"code": code, "deleted_at": nil, "lessons": lessonCode}
+		update = bson.M{"$pull": bson.M{
+			"lessons": lessonCode,
+		}}
+	)
+
+	module, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// either there is no such module or the lesson is not in the list
+		if _, err := r.getModule(ctx, code); err != nil {
+			return Module{}, err
+		}
+		return Module{}, ErrLessonNotInList
 	}
-	if res.ModifiedCount == 0 {
-		return ErrLessonNotInList
+
+	return module, err
+}
+
+// reorderLessons writes the new order only if the list still equals the one that was validated
+func (r *modulesRepository) reorderLessons(ctx context.Context, code string, current, lessons []string) (Module, error) {
+	var (
+		filter = bson.M{
+			"code":       code,
+			"deleted_at": nil,
+			"lessons":    current,
+		}
+		update = bson.M{"$set": bson.M{"lessons": lessons}}
+	)
+
+	module, err := r.findAndUpdate(ctx, filter, update)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Module{}, ErrListChanged
 	}
 
-	return nil
+	return module, err
+}
+
+// findAndUpdate returns the module as the update left it, revisions are saved from it
+// so a write that lands right after this one doesn't get into this revision
+func (r *modulesRepository) findAndUpdate(ctx context.Context, filter, update bson.M) (Module, error) {
+	var (
+		collection = r.db.Collection(modulesCollection)
+		opts       = options.FindOneAndUpdate().SetReturnDocument(options.After)
+		response   Module
+	)
+
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&response); err != nil {
+		return Module{}, err
+	}
+
+	return response, nil
 }
 
 func (r *modulesRepository) getAllModules(ctx context.Context) ([]Module, error) {
@@ -208,3 +325,89 @@ func (r *modulesRepository) getAllModules(ctx context.Context) ([]Module, error)
 
 	return result, nil
 }
+
+func (r *modulesRepository) listModules(ctx context.Context, filter ListFilter, params pagination.Params) ([]Module, error) {
+	var (
+		collection = r.db.Collection(modulesCollection)
+		query      = bson.M{"deleted_at": nil}
+		opts       = options.Find().SetSort(pagination.MongoSort(params, params.SortBy, "code")).SetLimit(int64(params.Fetch()))
+		result     []Module
+	)
+
+	if filter.Difficulty != "" {
+		query["difficulty"] = filter.Difficulty
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if created := createdRange(filter); len(created) > 0 {
+		query["created_at"] = created
+	}
+
+	if params.After != nil {
+		var value interface{} = params.After.Value
+		if params.SortBy == "created_at" {
+			createdAt, err := params.After.TimeValue()
+			if err != nil {
+				return nil, err
+			}
+			value = createdAt
+		}
+		query = bson.M{"$and": bson.A{query, pagination.MongoAfter(params, params.SortBy, value, "code", params.After.Key)}}
+	}
+
+	cur, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var module Module
+
+		if err = cur.Decode(&module); err != nil {
+			return nil, err
+		}
+
+		result = append(result, module)
+	}
+
+	return result, cur.Err()
+}
+
+func createdRange(filter ListFilter) bson.M {
+	created := bson.M{}
+	if filter.CreatedFrom != nil {
+		created["$gte"] = *filter.CreatedFrom
+	}
+	if filter.CreatedTo != nil {
+		created["$lt"] = *filter.CreatedTo
+	}
+	return created
+}
+
+func (r *modulesRepository) getModulesByStatus(ctx context.Context, status string) ([]Module, error) {
+	var (
+		collection = r.db.Collection(modulesCollection)
+		filter     = bson.M{"deleted_at": nil, "status": status}
+		result     []Module
+	)
+
+	cur, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var module Module
+
+		if err = cur.Decode(&module); err != nil {
+			return nil, err
+		}
+
+		result = append(result, module)
+	}
+
+	return result, nil
+}
@@ -2,9 +2,15 @@ package modules
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
+	"uiren/internal/app/events"
 	"uiren/internal/app/lessons"
+	"uiren/internal/app/publication"
+	"uiren/internal/app/revisions"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -13,23 +19,42 @@ import (
 
 type repository interface {
 	createModule(ctx context.Context, dto CreateModuleDTO) (primitive.ObjectID, error)
-	updateModule(ctx context.Context, code string, dto UpdateModuleDTO) error
+	updateModule(ctx context.Context, code string, dto UpdateModuleDTO) (Module, error)
 	deleteModule(ctx context.Context, code string) error
 	getModule(ctx context.Context, code string) (Module, error)
-	addLessonToList(ctx context.Context, code, lessonCode string) error
-	deleteLessonFromList(ctx context.Context, code, lessonCode string) error
+	addLessonToList(ctx context.Context, code, lessonCode string) (Module, error)
+	deleteLessonFromList(ctx context.Context, code, lessonCode string) (Module, error)
+	reorderLessons(ctx context.Context, code string, current, lessons []string) (Module, error)
+	restoreModule(ctx context.Context, code string, module Module) (Module, error)
+	setModuleStatus(ctx context.Context, code, from, to string) (Module, error)
+	getModuleCodesByLesson(ctx context.Context, lessonCode string) ([]string, error)
+	unlinkLesson(ctx context.Context, code, lessonCode string) (Module, error)
 
 	getAllModules(ctx context.Context) ([]Module, error)
+	listModules(ctx context.Context, filter ListFilter, params pagination.Params) ([]Module, error)
+	getModulesByStatus(ctx context.Context, status string) ([]Module, error)
 }
 
 type lessonsService interface {
 	GetLessonsByCodes(ctx context.Context, codes []string) ([]lessons.LessonDTO, error)
 	LessonExists(ctx context.Context, code string) (bool, error)
+	ValidateLessonsPublished(ctx context.Context, codes []string) error
+}
+
+type publisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
+type revisionService interface {
+	SaveRevision(ctx context.Context, entityType, code string, document interface{}) (int, error)
+	GetRevision(ctx context.Context, entityType, code string, version int) (revisions.Revision, error)
 }
 
 type ModulesService struct {
-	repo           repository
-	lessonsService lessonsService
+	repo            repository
+	lessonsService  lessonsService
+	revisionService revisionService
+	publisher       publisher
 }
 
 func NewModulesService(repo repository, lessonsService lessonsService) *ModulesService {
@@ -39,6 +64,14 @@ func NewModulesService(repo repository, lessonsService lessonsService) *ModulesS
 	}
 }
 
+func (s *ModulesService) WithRevisionService(revisionService revisionService) {
+	s.revisionService = revisionService
+}
+
+func (s *ModulesService) WithPublisher(publisher publisher) {
+	s.publisher = publisher
+}
+
 func (s ModulesService) GetModule(ctx context.Context, code string) (ModuleWithLessons, error) {
 	logger.Info("ModulesService.GetModule new request")
 
@@ -61,12 +94,16 @@ func (s ModulesService) CreateModule(ctx context.Context, dto CreateModuleDTO) (
 	logger.Info("ModulesService.CreateModule new request")
 	dto.CreatedAt = time.Now()
 	dto.Lessons = make([]string, 0)
+	dto.Status = publication.StatusDraft
 
 	id, err := s.repo.createModule(ctx, dto)
 	if err != nil {
 		logger.Error("ModulesService.CreateModule repo.createModule: ", err)
 		return primitive.NilObjectID, err
 	}
+	// the created module is exactly the inserted document
+	s.saveRevision(ctx, Module(dto))
+	s.publish(ctx, dto.Code)
 
 	return id, nil
 }
@@ -79,16 +116,21 @@ func (s ModulesService) DeleteModule(ctx context.Context, code string) error {
 		return err
 	}
 
+	s.publish(ctx, code)
+
 	return nil
 }
 
 func (s ModulesService) UpdateModule(ctx context.Context, code string, dto UpdateModuleDTO) error {
 	logger.Info("ModulesService.UpdateModule new request")
 
-	if err := s.repo.updateModule(ctx, code, dto); err != nil {
+	module, err := s.repo.updateModule(ctx, code, dto)
+	if err != nil {
 		logger.Error("ModulesService.UpdateModule repo.updateModule: ", err)
 		return err
 	}
+	s.saveRevision(ctx, module)
+	s.publish(ctx, code)
 
 	return nil
 }
@@ -106,10 +148,27 @@ func (s ModulesService) AddLessonToList(ctx context.Context, code, lessonCode st
 		return lessons.ErrNotFound
 	}
 
-	if err := s.repo.addLessonToList(ctx, code, lessonCode); err != nil {
+	module, err := s.repo.getModule(ctx, code)
+	if err != nil {
+		logger.Error("ModulesService.AddLessonToList repo.getModule: ", err)
+		return err
+	}
+
+	// learners see every lesson of a published module, so it can't get a draft one
+	if module.Status == publication.StatusPublished {
+		if err := s.lessonsService.ValidateLessonsPublished(ctx, []string{lessonCode}); err != nil {
+			logger.Error("ModulesService.AddLessonToList lessonsService.ValidateLessonsPublished: ", err)
+			return err
+		}
+	}
+
+	module, err = s.repo.addLessonToList(ctx, code, lessonCode)
+	if err != nil {
 		logger.Error("ModulesService.AddLessonToList repo.addLesson: ", err)
 		return err
 	}
+	s.saveRevision(ctx, module)
+	s.publish(ctx, code)
 
 	return nil
 }
@@ -117,18 +176,118 @@ func (s ModulesService) AddLessonToList(ctx context.Context, code, lessonCode st
 func (s ModulesService) DeleteLessonFromList(ctx context.Context, code, lessonCode string) error {
 	logger.Info("ModulesService.DeleteLessonFromList new request")
 
-	if err := s.repo.deleteLessonFromList(ctx, code, lessonCode); err != nil {
+	module, err := s.repo.deleteLessonFromList(ctx, code, lessonCode)
+	if err != nil {
 		logger.Error("ModulesService.DeleteLessonFromList repo.deleteLessonFromList: ", err)
 		return err
 	}
+	s.saveRevision(ctx, module)
+	s.publish(ctx, code)
 
 	return nil
 }
 
-func (s ModulesService) GetModulesList(ctx context.Context) ([]Module, error) {
-	logger.Info("ModulesService.GetModules new request")
-	// todo: change to func with pagination
-	return s.repo.getAllModules(ctx)
+// ReorderLessons replaces the lessons list with the same codes in a new order
+func (s ModulesService) ReorderLessons(ctx context.Context, code string, lessonCodes []string) error {
+	logger.Info("ModulesService.ReorderLessons new request")
+
+	module, err := s.repo.getModule(ctx, code)
+	if err != nil {
+		logger.Error("ModulesService.ReorderLessons repo.getModule: ", err)
+		return err
+	}
+
+	if !isPermutation(module.Lessons, lessonCodes) {
+		return ErrNotPermutation
+	}
+
+	module, err = s.repo.reorderLessons(ctx, code, module.Lessons, lessonCodes)
+	if err != nil {
+		logger.Error("ModulesService.ReorderLessons repo.reorderLessons: ", err)
+		return err
+	}
+	s.saveRevision(ctx, module)
+	s.publish(ctx, code)
+
+	return nil
+}
+
+// ListModules returns one page of modules with their lessons
+func (s ModulesService) ListModules(ctx context.Context, filter ListFilter, params pagination.Params) (pagination.Page[ModuleWithLessons], error) {
+	logger.Info("ModulesService.ListModules new request")
+
+	modules, err := s.repo.listModules(ctx, filter, params)
+	if err != nil {
+		logger.Error("ModulesService.ListModules repo.listModules: ", err)
+		return pagination.Page[ModuleWithLessons]{}, err
+	}
+
+	page := pagination.NewPage(modules, params, moduleCursor)
+
+	result, err := s.withLessons(ctx, page.Items)
+	if err != nil {
+		logger.Error("ModulesService.ListModules s.withLessons: ", err)
+		return pagination.Page[ModuleWithLessons]{}, err
+	}
+
+	return pagination.Page[ModuleWithLessons]{Items: result, NextCursor: page.NextCursor}, nil
+}
+
+func moduleCursor(module Module, sortBy string) (string, string) {
+	switch sortBy {
+	case "title":
+		return module.Title, module.Code
+	case "code":
+		return module.Code, module.Code
+	default:
+		return pagination.FormatTime(module.CreatedAt), module.Code
+	}
+}
+
+func (s ModulesService) GetPublishedModulesList(ctx context.Context) ([]Module, error) {
+	logger.Info("ModulesService.GetPublishedModulesList new request")
+
+	modules, err := s.repo.getModulesByStatus(ctx, publication.StatusPublished)
+	if err != nil {
+		logger.Error("ModulesService.GetPublishedModulesList repo.getModulesByStatus: ", err)
+		return nil, err
+	}
+
+	return modules, nil
+}
+
+// SetModuleStatus moves module through the publication lifecycle,
+// publishing requires all referenced lessons and exercises to be published
+func (s ModulesService) SetModuleStatus(ctx context.Context, code, status string) error {
+	logger.Info("ModulesService.SetModuleStatus new request")
+
+	module, err := s.repo.getModule(ctx, code)
+	if err != nil {
+		logger.Error("ModulesService.SetModuleStatus repo.getModule: ", err)
+		return err
+	}
+
+	if err := publication.ValidateTransition(module.Status, status); err != nil {
+		logger.Error("ModulesService.SetModuleStatus publication.ValidateTransition: ", err)
+		return err
+	}
+
+	if status == publication.StatusPublished {
+		if err := s.lessonsService.ValidateLessonsPublished(ctx, module.Lessons); err != nil {
+			logger.Error("ModulesService.SetModuleStatus lessonsService.ValidateLessonsPublished: ", err)
+			return err
+		}
+	}
+
+	module, err = s.repo.setModuleStatus(ctx, code, module.Status, status)
+	if err != nil {
+		logger.Error("ModulesService.SetModuleStatus repo.setModuleStatus: ", err)
+		return err
+	}
+	s.saveRevision(ctx, module)
+	s.publish(ctx, code)
+
+	return nil
 }
 
 func (s ModulesService) GetAllModulesWithLessons(ctx context.Context) ([]ModuleWithLessons, error) {
@@ -140,15 +299,220 @@ func (s ModulesService) GetAllModulesWithLessons(ctx context.Context) ([]ModuleW
 		return nil, err
 	}
 
-	var result []ModuleWithLessons
+	if len(modules) == 0 {
+		return nil, nil
+	}
+
+	result, err := s.withLessons(ctx, modules)
+	if err != nil {
+		logger.Error("ModulesService.GetModulesWithLessons s.withLessons: ", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// withLessons loads lessons of all modules at once, so the number of queries doesn't depend on modules count
+func (s ModulesService) withLessons(ctx context.Context, modules []Module) ([]ModuleWithLessons, error) {
+	var codes []string
+	seen := make(map[string]struct{})
 	for _, module := range modules {
-		lessons, err := s.lessonsService.GetLessonsByCodes(ctx, module.Lessons)
+		for _, code := range module.Lessons {
+			if _, ok := seen[code]; !ok {
+				seen[code] = struct{}{}
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	lessonsByCode := make(map[string]lessons.LessonDTO, len(codes))
+	if len(codes) > 0 {
+		lessonList, err := s.lessonsService.GetLessonsByCodes(ctx, codes)
 		if err != nil {
-			logger.Error("ModulesService.GetModulesWithLessons lessonsService.GetLessonsByCodes: ", err)
 			return nil, err
 		}
-		result = append(result, module.toDTO(lessons))
+		for _, lesson := range lessonList {
+			lessonsByCode[lesson.Code] = lesson
+		}
+	}
+
+	result := make([]ModuleWithLessons, 0, len(modules))
+	for _, module := range modules {
+		moduleLessons := make([]lessons.LessonDTO, 0, len(module.Lessons))
+		for _, code := range module.Lessons {
+			if lesson, ok := lessonsByCode[code]; ok {
+				moduleLessons = append(moduleLessons, lesson)
+			}
+		}
+		result = append(result, module.toDTO(moduleLessons))
 	}
 
 	return result, nil
 }
+
+func (s ModulesService) GetModulesReferencingLesson(ctx context.Context, lessonCode string) ([]string, error) {
+	logger.Info("ModulesService.GetModulesReferencingLesson new request")
+
+	codes, err := s.repo.getModuleCodesByLesson(ctx, lessonCode)
+	if err != nil {
+		logger.Error("ModulesService.GetModulesReferencingLesson repo.getModuleCodesByLesson: ", err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// UnlinkLesson removes lesson from the lists of all modules
+func (s ModulesService) UnlinkLesson(ctx context.Context, lessonCode string) error {
+	logger.Info("ModulesService.UnlinkLesson new request")
+
+	codes, err := s.repo.getModuleCodesByLesson(ctx, lessonCode)
+	if err != nil {
+		logger.Error("ModulesService.UnlinkLesson repo.getModuleCodesByLesson: ", err)
+		return err
+	}
+
+	// modules are unlinked one by one, so the revision of each has the list its own update left
+	for _, code := range codes {
+		module, err := s.repo.unlinkLesson(ctx, code, lessonCode)
+		switch err {
+		case nil:
+		case ErrLessonNotInList:
+			// unlinked by another request since the codes were read
+			continue
+		default:
+			logger.Error("ModulesService.UnlinkLesson repo.unlinkLesson: ", err)
+			return err
+		}
+		s.saveRevision(ctx, module)
+		s.publish(ctx, code)
+	}
+
+	return nil
+}
+
+// GetModuleLessonCodes returns lesson lists of all modules by module code, as stored
+func (s ModulesService) GetModuleLessonCodes(ctx context.Context) (map[string][]string, error) {
+	logger.Info("ModulesService.GetModuleLessonCodes new request")
+
+	modules, err := s.repo.getAllModules(ctx)
+	if err != nil {
+		logger.Error("ModulesService.GetModuleLessonCodes repo.getAllModules: ", err)
+		return nil, err
+	}
+
+	result := make(map[string][]string, len(modules))
+	for _, module := range modules {
+		result[module.Code] = module.Lessons
+	}
+
+	return result, nil
+}
+
+// RollbackModule restores module content from the given revision, the rollback itself is saved as a new revision
+func (s ModulesService) RollbackModule(ctx context.Context, code string, version int) error {
+	logger.Info("ModulesService.RollbackModule new request")
+
+	if s.revisionService == nil {
+		return revisions.ErrHistoryNotAvailable
+	}
+
+	revision, err := s.revisionService.GetRevision(ctx, revisions.EntityModule, code, version)
+	if err != nil {
+		logger.Error("ModulesService.RollbackModule revisionService.GetRevision: ", err)
+		return err
+	}
+
+	var module Module
+	if err := revision.Decode(&module); err != nil {
+		logger.Error("ModulesService.RollbackModule revision.Decode: ", err)
+		return err
+	}
+	if module.Lessons == nil {
+		module.Lessons = make([]string, 0)
+	}
+
+	current, err := s.repo.getModule(ctx, code)
+	if err != nil {
+		logger.Error("ModulesService.RollbackModule repo.getModule: ", err)
+		return err
+	}
+
+	// the revision may list lessons deleted or unpublished since then, status is not rolled back
+	if err := s.validateLessons(ctx, current.Status, module.Lessons); err != nil {
+		logger.Error("ModulesService.RollbackModule validateLessons: ", err)
+		return err
+	}
+
+	restored, err := s.repo.restoreModule(ctx, code, module)
+	if err != nil {
+		logger.Error("ModulesService.RollbackModule repo.restoreModule: ", err)
+		return err
+	}
+	s.saveRevision(ctx, restored)
+	s.publish(ctx, code)
+
+	return nil
+}
+
+// validateLessons checks that the lessons exist and, when the module is published, that they are published too
+func (s ModulesService) validateLessons(ctx context.Context, status string, codes []string) error {
+	var missing []string
+	for _, code := range codes {
+		exists, err := s.lessonsService.LessonExists(ctx, code)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", lessons.ErrNotFound, strings.Join(missing, ", "))
+	}
+
+	if status == publication.StatusPublished {
+		return s.lessonsService.ValidateLessonsPublished(ctx, codes)
+	}
+
+	return nil
+}
+
+// saveRevision snapshots the module as the write left it, history errors must not fail the write itself
+func (s ModulesService) saveRevision(ctx context.Context, module Module) {
+	if s.revisionService == nil {
+		return
+	}
+
+	if _, err := s.revisionService.SaveRevision(ctx, revisions.EntityModule, module.Code, module); err != nil {
+		logger.Error("ModulesService.saveRevision revisionService.SaveRevision: ", err)
+	}
+}
+
+func isPermutation(current, desired []string) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+
+	counts := make(map[string]int, len(current))
+	for _, code := range current {
+		counts[code]++
+	}
+	for _, code := range desired {
+		if counts[code] == 0 {
+			return false
+		}
+		counts[code]--
+	}
+
+	return true
+}
+
+// publish notifies subscribers that the module changed
+func (s ModulesService) publish(ctx context.Context, code string) {
+	if s.publisher == nil {
+		return
+	}
+
+	s.publisher.Publish(ctx, events.Event{Type: events.ModuleChanged, Code: code})
+}
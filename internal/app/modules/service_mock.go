@@ -7,7 +7,10 @@ package modules
 import (
 	context "context"
 	reflect "reflect"
+	events "uiren/internal/app/events"
 	lessons "uiren/internal/app/lessons"
+	revisions "uiren/internal/app/revisions"
+	pagination "uiren/pkg/pagination"
 
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
@@ -37,11 +40,12 @@ func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
 }
 
 // addLessonToList mocks base method.
-func (m *Mockrepository) addLessonToList(ctx context.Context, code, lessonCode string) error {
+func (m *Mockrepository) addLessonToList(ctx context.Context, code, lessonCode string) (Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "addLessonToList", ctx, code, lessonCode)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // addLessonToList indicates an expected call of addLessonToList.
@@ -66,11 +70,12 @@ func (mr *MockrepositoryMockRecorder) createModule(ctx, dto interface{}) *gomock
 }
 
 // deleteLessonFromList mocks base method.
-func (m *Mockrepository) deleteLessonFromList(ctx context.Context, code, lessonCode string) error {
+func (m *Mockrepository) deleteLessonFromList(ctx context.Context, code, lessonCode string) (Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "deleteLessonFromList", ctx, code, lessonCode)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // deleteLessonFromList indicates an expected call of deleteLessonFromList.
@@ -123,12 +128,118 @@ func (mr *MockrepositoryMockRecorder) getModule(ctx, code interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getModule", reflect.TypeOf((*Mockrepository)(nil).getModule), ctx, code)
 }
 
+// getModuleCodesByLesson mocks base method.
+func (m *Mockrepository) getModuleCodesByLesson(ctx context.Context, lessonCode string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getModuleCodesByLesson", ctx, lessonCode)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getModuleCodesByLesson indicates an expected call of getModuleCodesByLesson.
+func (mr *MockrepositoryMockRecorder) getModuleCodesByLesson(ctx, lessonCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getModuleCodesByLesson", reflect.TypeOf((*Mockrepository)(nil).getModuleCodesByLesson), ctx, lessonCode)
+}
+
+// getModulesByStatus mocks base method.
+func (m *Mockrepository) getModulesByStatus(ctx context.Context, status string) ([]Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getModulesByStatus", ctx, status)
+	ret0, _ := ret[0].([]Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getModulesByStatus indicates an expected call of getModulesByStatus.
+func (mr *MockrepositoryMockRecorder) getModulesByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getModulesByStatus", reflect.TypeOf((*Mockrepository)(nil).getModulesByStatus), ctx, status)
+}
+
+// listModules mocks base method.
+func (m *Mockrepository) listModules(ctx context.Context, filter ListFilter, params pagination.Params) ([]Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "listModules", ctx, filter, params)
+	ret0, _ := ret[0].([]Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// listModules indicates an expected call of listModules.
+func (mr *MockrepositoryMockRecorder) listModules(ctx, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "listModules", reflect.TypeOf((*Mockrepository)(nil).listModules), ctx, filter, params)
+}
+
+// reorderLessons mocks base method.
+func (m *Mockrepository) reorderLessons(ctx context.Context, code string, current, lessons []string) (Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "reorderLessons", ctx, code, current, lessons)
+	ret0, _ := ret[0].(Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// reorderLessons indicates an expected call of reorderLessons.
+func (mr *MockrepositoryMockRecorder) reorderLessons(ctx, code, current, lessons interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "reorderLessons", reflect.TypeOf((*Mockrepository)(nil).reorderLessons), ctx, code, current, lessons)
+}
+
+// restoreModule mocks base method.
+func (m *Mockrepository) restoreModule(ctx context.Context, code string, module Module) (Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "restoreModule", ctx, code, module)
+	ret0, _ := ret[0].(Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// restoreModule indicates an expected call of restoreModule.
+func (mr *MockrepositoryMockRecorder) restoreModule(ctx, code, module interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "restoreModule", reflect.TypeOf((*Mockrepository)(nil).restoreModule), ctx, code, module)
+}
+
+// setModuleStatus mocks base method.
+func (m *Mockrepository) setModuleStatus(ctx context.Context, code, from, to string) (Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "setModuleStatus", ctx, code, from, to)
+	ret0, _ := ret[0].(Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// setModuleStatus indicates an expected call of setModuleStatus.
+func (mr *MockrepositoryMockRecorder) setModuleStatus(ctx, code, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "setModuleStatus", reflect.TypeOf((*Mockrepository)(nil).setModuleStatus), ctx, code, from, to)
+}
+
+// unlinkLesson mocks base method.
+func (m *Mockrepository) unlinkLesson(ctx context.Context, code, lessonCode string) (Module, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "unlinkLesson", ctx, code, lessonCode)
+	ret0, _ := ret[0].(Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// unlinkLesson indicates an expected call of unlinkLesson.
+func (mr *MockrepositoryMockRecorder) unlinkLesson(ctx, code, lessonCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "unlinkLesson", reflect.TypeOf((*Mockrepository)(nil).unlinkLesson), ctx, code, lessonCode)
+}
+
 // updateModule mocks base method.
-func (m *Mockrepository) updateModule(ctx context.Context, code string, dto UpdateModuleDTO) error {
+func (m *Mockrepository) updateModule(ctx context.Context, code string, dto UpdateModuleDTO) (Module, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "updateModule", ctx, code, dto)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(Module)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // updateModule indicates an expected call of updateModule.
@@ -189,3 +300,105 @@ func (mr *MocklessonsServiceMockRecorder) LessonExists(ctx, code interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LessonExists", reflect.TypeOf((*MocklessonsService)(nil).LessonExists), ctx, code)
 }
+
+// ValidateLessonsPublished mocks base method.
+func (m *MocklessonsService) ValidateLessonsPublished(ctx context.Context, codes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateLessonsPublished", ctx, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateLessonsPublished indicates an expected call of ValidateLessonsPublished.
+func (mr *MocklessonsServiceMockRecorder) ValidateLessonsPublished(ctx, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateLessonsPublished", reflect.TypeOf((*MocklessonsService)(nil).ValidateLessonsPublished), ctx, codes)
+}
+
+// Mockpublisher is a mock of publisher interface.
+type Mockpublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockpublisherMockRecorder
+}
+
+// MockpublisherMockRecorder is the mock recorder for Mockpublisher.
+type MockpublisherMockRecorder struct {
+	mock *Mockpublisher
+}
+
+// NewMockpublisher creates a new mock instance.
+func NewMockpublisher(ctrl *gomock.Controller) *Mockpublisher {
+	mock := &Mockpublisher{ctrl: ctrl}
+	mock.recorder = &MockpublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockpublisher) EXPECT() *MockpublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *Mockpublisher) Publish(ctx context.Context, event events.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", ctx, event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockpublisherMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*Mockpublisher)(nil).Publish), ctx, event)
+}
+
+// MockrevisionService is a mock of revisionService interface.
+type MockrevisionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockrevisionServiceMockRecorder
+}
+
+// MockrevisionServiceMockRecorder is the mock recorder for MockrevisionService.
+type MockrevisionServiceMockRecorder struct {
+	mock *MockrevisionService
+}
+
+// NewMockrevisionService creates a new mock instance.
+func NewMockrevisionService(ctrl *gomock.Controller) *MockrevisionService {
+	mock := &MockrevisionService{ctrl: ctrl}
+	mock.recorder = &MockrevisionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrevisionService) EXPECT() *MockrevisionServiceMockRecorder {
+	return m.recorder
+}
+
+// GetRevision mocks base method.
+func (m *MockrevisionService) GetRevision(ctx context.Context, entityType, code string, version int) (revisions.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, entityType, code, version)
+	ret0, _ := ret[0].(revisions.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockrevisionServiceMockRecorder) GetRevision(ctx, entityType, code, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockrevisionService)(nil).GetRevision), ctx, entityType, code, version)
+}
+
+// SaveRevision mocks base method.
+func (m *MockrevisionService) SaveRevision(ctx context.Context, entityType, code string, document interface{}) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRevision", ctx, entityType, code, document)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRevision indicates an expected call of SaveRevision.
+func (mr *MockrevisionServiceMockRecorder) SaveRevision(ctx, entityType, code, document interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevision", reflect.TypeOf((*MockrevisionService)(nil).SaveRevision), ctx, entityType, code, document)
+}
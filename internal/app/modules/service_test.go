@@ -5,11 +5,16 @@ import (
 	"errors"
 	"testing"
 	"time"
+	"uiren/internal/app/events"
 	"uiren/internal/app/lessons"
+	"uiren/internal/app/publication"
+	"uiren/internal/app/revisions"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -110,7 +115,7 @@ func Test_ModulesService_UpdateModule_success(t *testing.T) {
 		dto      = UpdateModuleDTO{Title: &newTitle}
 	)
 
-	repo.EXPECT().updateModule(ctx, code, dto).Return(nil)
+	repo.EXPECT().updateModule(ctx, code, dto).Return(Module{}, nil)
 
 	err := srv.UpdateModule(ctx, code, dto)
 	assert.NoError(t, err)
@@ -130,7 +135,7 @@ func Test_ModulesService_UpdateModule_fail(t *testing.T) {
 		dto   = UpdateModuleDTO{Title: &title}
 	)
 
-	repo.EXPECT().updateModule(ctx, code, dto).Return(ErrNoFieldsToUpdate)
+	repo.EXPECT().updateModule(ctx, code, dto).Return(Module{}, ErrNoFieldsToUpdate)
 
 	err := srv.UpdateModule(ctx, code, dto)
 	assert.Error(t, err)
@@ -151,7 +156,8 @@ func Test_ModulesService_AddLessonToList_success(t *testing.T) {
 	)
 
 	lessonsService.EXPECT().LessonExists(ctx, lessonCode).Return(true, nil)
-	repo.EXPECT().addLessonToList(ctx, moduleCode, lessonCode).Return(nil)
+	repo.EXPECT().getModule(ctx, moduleCode).Return(Module{Code: moduleCode, Status: publication.StatusDraft}, nil)
+	repo.EXPECT().addLessonToList(ctx, moduleCode, lessonCode).Return(Module{}, nil)
 
 	err := srv.AddLessonToList(ctx, moduleCode, lessonCode)
 	assert.NoError(t, err)
@@ -171,8 +177,9 @@ func Test_ModulesService_AddLessonToList_fail(t *testing.T) {
 	)
 
 	lessonsService.EXPECT().LessonExists(ctx, lessonCode).Return(true, nil)
+	repo.EXPECT().getModule(ctx, moduleCode).Return(Module{Code: moduleCode, Status: publication.StatusDraft}, nil)
 
-	repo.EXPECT().addLessonToList(ctx, moduleCode, lessonCode).Return(ErrLessonAlreadyInSet)
+	repo.EXPECT().addLessonToList(ctx, moduleCode, lessonCode).Return(Module{}, ErrLessonAlreadyInSet)
 
 	err := srv.AddLessonToList(ctx, moduleCode, lessonCode)
 	assert.Error(t, err)
@@ -207,6 +214,49 @@ func Test_ModulesService_AddLessonToList_LessonExists(t *testing.T) {
 	})
 }
 
+func Test_ModulesService_AddLessonToList_published(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		repo           = NewMockrepository(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+
+		srv        = NewModulesService(repo, lessonsService)
+		moduleCode = "module1"
+		lessonCode = "lesson1"
+		published  = Module{Code: moduleCode, Status: publication.StatusPublished}
+	)
+
+	t.Run("published lesson", func(t *testing.T) {
+		lessonsService.EXPECT().LessonExists(ctx, lessonCode).Return(true, nil)
+		repo.EXPECT().getModule(ctx, moduleCode).Return(published, nil)
+		lessonsService.EXPECT().ValidateLessonsPublished(ctx, []string{lessonCode}).Return(nil)
+		repo.EXPECT().addLessonToList(ctx, moduleCode, lessonCode).Return(published, nil)
+
+		err := srv.AddLessonToList(ctx, moduleCode, lessonCode)
+		assert.NoError(t, err)
+	})
+
+	t.Run("draft lesson", func(t *testing.T) {
+		lessonsService.EXPECT().LessonExists(ctx, lessonCode).Return(true, nil)
+		repo.EXPECT().getModule(ctx, moduleCode).Return(published, nil)
+		lessonsService.EXPECT().ValidateLessonsPublished(ctx, []string{lessonCode}).
+			Return(publication.NotPublishedError("lessons", []string{lessonCode}))
+
+		err := srv.AddLessonToList(ctx, moduleCode, lessonCode)
+		assert.ErrorIs(t, err, publication.ErrNotPublished)
+	})
+
+	t.Run("module not found", func(t *testing.T) {
+		lessonsService.EXPECT().LessonExists(ctx, lessonCode).Return(true, nil)
+		repo.EXPECT().getModule(ctx, moduleCode).Return(Module{}, ErrNotFound)
+
+		err := srv.AddLessonToList(ctx, moduleCode, lessonCode)
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
 func Test_ModulesService_DeleteLessonFromList_success(t *testing.T) {
 	t.Parallel()
 	var (
@@ -220,7 +270,7 @@ func Test_ModulesService_DeleteLessonFromList_success(t *testing.T) {
 		lessonCode = "lesson1"
 	)
 
-	repo.EXPECT().deleteLessonFromList(ctx, moduleCode, lessonCode).Return(nil)
+	repo.EXPECT().deleteLessonFromList(ctx, moduleCode, lessonCode).Return(Module{}, nil)
 
 	err := srv.DeleteLessonFromList(ctx, moduleCode, lessonCode)
 	assert.NoError(t, err)
@@ -239,13 +289,64 @@ func Test_ModulesService_DeleteLessonFromList_fail(t *testing.T) {
 		lessonCode = "lesson1"
 	)
 
-	repo.EXPECT().deleteLessonFromList(ctx, moduleCode, lessonCode).Return(ErrLessonNotInList)
+	repo.EXPECT().deleteLessonFromList(ctx, moduleCode, lessonCode).Return(Module{}, ErrLessonNotInList)
 
 	err := srv.DeleteLessonFromList(ctx, moduleCode, lessonCode)
 	assert.Error(t, err)
 	assert.Equal(t, err, ErrLessonNotInList)
 }
 
+func Test_ModulesService_ReorderLessons(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		repo           = NewMockrepository(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+		srv            = NewModulesService(repo, lessonsService)
+		moduleCode     = "module1"
+		current        = []string{"lesson1", "lesson2", "lesson3"}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		reordered := []string{"lesson3", "lesson1", "lesson2"}
+		repo.EXPECT().getModule(ctx, moduleCode).Return(Module{Code: moduleCode, Lessons: current}, nil)
+		repo.EXPECT().reorderLessons(ctx, moduleCode, current, reordered).Return(Module{}, nil)
+
+		err := srv.ReorderLessons(ctx, moduleCode, reordered)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not a permutation", func(t *testing.T) {
+		for _, lessons := range [][]string{
+			{"lesson1", "lesson2"},
+			{"lesson1", "lesson2", "lesson4"},
+			{"lesson1", "lesson1", "lesson2"},
+		} {
+			repo.EXPECT().getModule(ctx, moduleCode).Return(Module{Code: moduleCode, Lessons: current}, nil)
+
+			err := srv.ReorderLessons(ctx, moduleCode, lessons)
+			assert.Equal(t, ErrNotPermutation, err)
+		}
+	})
+
+	t.Run("list changed", func(t *testing.T) {
+		reordered := []string{"lesson2", "lesson1", "lesson3"}
+		repo.EXPECT().getModule(ctx, moduleCode).Return(Module{Code: moduleCode, Lessons: current}, nil)
+		repo.EXPECT().reorderLessons(ctx, moduleCode, current, reordered).Return(Module{}, ErrListChanged)
+
+		err := srv.ReorderLessons(ctx, moduleCode, reordered)
+		assert.Equal(t, ErrListChanged, err)
+	})
+
+	t.Run("module not found", func(t *testing.T) {
+		repo.EXPECT().getModule(ctx, moduleCode).Return(Module{}, ErrNotFound)
+
+		err := srv.ReorderLessons(ctx, moduleCode, current)
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
 func Test_ModulesService_GetModule_success(t *testing.T) {
 	t.Parallel()
 	var (
@@ -330,6 +431,7 @@ func Test_ModulesService_GetAllModulesWithLessons(t *testing.T) {
 			{
 				Code:        "module_001",
 				Title:       "Sample Module fdsfasdfdsa",
+				Lessons:     []string{"lesson1", "lesson2"},
 				Description: "A mock module for testing.",
 				Goal:        "Understand basic grammar.",
 				Difficulty:  "Easy",
@@ -348,6 +450,7 @@ func Test_ModulesService_GetAllModulesWithLessons(t *testing.T) {
 			{
 				Code:        "module_002",
 				Title:       "Sample Module ",
+				Lessons:     []string{"lesson2", "lesson3", "deleted"},
 				Description: "A mock module for testing.",
 				Goal:        "Understand basic grammar.",
 				Difficulty:  "Easy",
@@ -363,19 +466,22 @@ func Test_ModulesService_GetAllModulesWithLessons(t *testing.T) {
 				DeletedAt: nil,
 			},
 		}
-		lessons = []lessons.LessonDTO{
-			{Code: "some_lesson"},
+		lessonCodes = []string{"lesson1", "lesson2", "lesson3", "deleted"}
+		lessonList  = []lessons.LessonDTO{
+			{Code: "lesson1"},
+			{Code: "lesson2"},
+			{Code: "lesson3"},
 		}
 		errRepo = errors.New("repo err")
 	)
 
 	t.Run("success", func(t *testing.T) {
-		var expectedResult []ModuleWithLessons
-		repo.EXPECT().getAllModules(ctx).Return(modules, nil)
-		for _, module := range modules {
-			lessonSrv.EXPECT().GetLessonsByCodes(ctx, module.Lessons).Return(lessons, nil)
-			expectedResult = append(expectedResult, module.toDTO(lessons))
+		expectedResult := []ModuleWithLessons{
+			modules[0].toDTO(lessonList[:2]),
+			modules[1].toDTO(lessonList[1:]),
 		}
+		repo.EXPECT().getAllModules(ctx).Return(modules, nil)
+		lessonSrv.EXPECT().GetLessonsByCodes(ctx, lessonCodes).Return(lessonList, nil).Times(1)
 
 		result, err := srv.GetAllModulesWithLessons(ctx)
 		assert.NoError(t, err)
@@ -391,9 +497,329 @@ func Test_ModulesService_GetAllModulesWithLessons(t *testing.T) {
 
 	t.Run("repo failed#2", func(t *testing.T) {
 		repo.EXPECT().getAllModules(ctx).Return(modules, nil)
-		lessonSrv.EXPECT().GetLessonsByCodes(ctx, modules[0].Lessons).Return(nil, errRepo)
+		lessonSrv.EXPECT().GetLessonsByCodes(ctx, lessonCodes).Return(nil, errRepo)
 		result, err := srv.GetAllModulesWithLessons(ctx)
 		assert.Equal(t, err, errRepo)
 		assert.Nil(t, result)
 	})
 }
+
+func Test_ModulesService_UpdateModule_saveRevision(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		repo           = NewMockrepository(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+		revisionSrv    = NewMockrevisionService(ctrl)
+
+		srv      = NewModulesService(repo, lessonsService)
+		code     = "module1"
+		newTitle = "new title"
+		dto      = UpdateModuleDTO{Title: &newTitle}
+		module   = Module{Code: code, Title: newTitle, Lessons: []string{}}
+	)
+	srv.WithRevisionService(revisionSrv)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().updateModule(ctx, code, dto).Return(module, nil)
+		revisionSrv.EXPECT().SaveRevision(ctx, revisions.EntityModule, code, module).Return(1, nil)
+
+		err := srv.UpdateModule(ctx, code, dto)
+		assert.NoError(t, err)
+	})
+
+	t.Run("history fail does not fail update", func(t *testing.T) {
+		repo.EXPECT().updateModule(ctx, code, dto).Return(module, nil)
+		revisionSrv.EXPECT().SaveRevision(ctx, revisions.EntityModule, code, module).Return(0, errors.New("mongo down"))
+
+		err := srv.UpdateModule(ctx, code, dto)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_ModulesService_publish(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		repo           = NewMockrepository(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+		publisher      = NewMockpublisher(ctrl)
+
+		srv      = NewModulesService(repo, lessonsService)
+		code     = "module1"
+		newTitle = "new title"
+		dto      = UpdateModuleDTO{Title: &newTitle}
+	)
+	srv.WithPublisher(publisher)
+
+	t.Run("update", func(t *testing.T) {
+		repo.EXPECT().updateModule(ctx, code, dto).Return(Module{}, nil)
+		publisher.EXPECT().Publish(ctx, events.Event{Type: events.ModuleChanged, Code: code})
+
+		err := srv.UpdateModule(ctx, code, dto)
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo.EXPECT().deleteModule(ctx, code).Return(nil)
+		publisher.EXPECT().Publish(ctx, events.Event{Type: events.ModuleChanged, Code: code})
+
+		err := srv.DeleteModule(ctx, code)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed write is not published", func(t *testing.T) {
+		repo.EXPECT().updateModule(ctx, code, dto).Return(Module{}, ErrNotFound)
+
+		err := srv.UpdateModule(ctx, code, dto)
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func Test_ModulesService_RollbackModule(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		repo           = NewMockrepository(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+		revisionSrv    = NewMockrevisionService(ctrl)
+
+		srv      = NewModulesService(repo, lessonsService)
+		code     = "module1"
+		revision = revisions.Revision{
+			EntityType: revisions.EntityModule,
+			Code:       code,
+			Version:    1,
+			Snapshot: bson.M{
+				"code":    code,
+				"title":   "old title",
+				"lessons": bson.A{"lesson1"},
+			},
+		}
+		restored = Module{Code: code, Title: "old title", Lessons: []string{"lesson1"}}
+	)
+
+	t.Run("history not configured", func(t *testing.T) {
+		err := srv.RollbackModule(ctx, code, 1)
+		assert.Equal(t, revisions.ErrHistoryNotAvailable, err)
+	})
+
+	srv.WithRevisionService(revisionSrv)
+
+	t.Run("success", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityModule, code, 1).Return(revision, nil)
+		repo.EXPECT().getModule(ctx, code).Return(Module{Code: code, Status: publication.StatusDraft}, nil)
+		lessonsService.EXPECT().LessonExists(ctx, "lesson1").Return(true, nil)
+		repo.EXPECT().restoreModule(ctx, code, restored).Return(restored, nil)
+		revisionSrv.EXPECT().SaveRevision(ctx, revisions.EntityModule, code, restored).Return(3, nil)
+
+		err := srv.RollbackModule(ctx, code, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityModule, code, 7).Return(revisions.Revision{}, revisions.ErrRevisionNotFound)
+
+		err := srv.RollbackModule(ctx, code, 7)
+		assert.Equal(t, revisions.ErrRevisionNotFound, err)
+	})
+
+	t.Run("module not found", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityModule, code, 1).Return(revision, nil)
+		repo.EXPECT().getModule(ctx, code).Return(Module{}, ErrNotFound)
+
+		err := srv.RollbackModule(ctx, code, 1)
+		assert.Equal(t, ErrNotFound, err)
+	})
+
+	t.Run("lesson was deleted", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityModule, code, 1).Return(revision, nil)
+		repo.EXPECT().getModule(ctx, code).Return(Module{Code: code, Status: publication.StatusDraft}, nil)
+		lessonsService.EXPECT().LessonExists(ctx, "lesson1").Return(false, nil)
+
+		err := srv.RollbackModule(ctx, code, 1)
+		assert.ErrorIs(t, err, lessons.ErrNotFound)
+		assert.Contains(t, err.Error(), "lesson1")
+	})
+
+	t.Run("published module gets a draft lesson", func(t *testing.T) {
+		revisionSrv.EXPECT().GetRevision(ctx, revisions.EntityModule, code, 1).Return(revision, nil)
+		repo.EXPECT().getModule(ctx, code).Return(Module{Code: code, Status: publication.StatusPublished}, nil)
+		lessonsService.EXPECT().LessonExists(ctx, "lesson1").Return(true, nil)
+		lessonsService.EXPECT().ValidateLessonsPublished(ctx, []string{"lesson1"}).
+			Return(publication.NotPublishedError("lessons", []string{"lesson1"}))
+
+		err := srv.RollbackModule(ctx, code, 1)
+		assert.ErrorIs(t, err, publication.ErrNotPublished)
+	})
+}
+
+func Test_ModulesService_SetModuleStatus(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		repo           = NewMockrepository(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+
+		srv    = NewModulesService(repo, lessonsService)
+		code   = "module1"
+		module = Module{
+			Code:    code,
+			Lessons: []string{"lesson1", "lesson2"},
+			Status:  publication.StatusInReview,
+		}
+		errNotPublished = publication.NotPublishedError("lessons", []string{"lesson2"})
+	)
+
+	t.Run("publish success", func(t *testing.T) {
+		repo.EXPECT().getModule(ctx, code).Return(module, nil)
+		lessonsService.EXPECT().ValidateLessonsPublished(ctx, module.Lessons).Return(nil)
+		repo.EXPECT().setModuleStatus(ctx, code, publication.StatusInReview, publication.StatusPublished).Return(Module{}, nil)
+
+		err := srv.SetModuleStatus(ctx, code, publication.StatusPublished)
+		assert.NoError(t, err)
+	})
+
+	t.Run("publish with unpublished lessons", func(t *testing.T) {
+		repo.EXPECT().getModule(ctx, code).Return(module, nil)
+		lessonsService.EXPECT().ValidateLessonsPublished(ctx, module.Lessons).Return(errNotPublished)
+
+		err := srv.SetModuleStatus(ctx, code, publication.StatusPublished)
+		assert.True(t, errors.Is(err, publication.ErrNotPublished))
+	})
+
+	t.Run("back to draft skips validation", func(t *testing.T) {
+		repo.EXPECT().getModule(ctx, code).Return(module, nil)
+		repo.EXPECT().setModuleStatus(ctx, code, publication.StatusInReview, publication.StatusDraft).Return(Module{}, nil)
+
+		err := srv.SetModuleStatus(ctx, code, publication.StatusDraft)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		repo.EXPECT().getModule(ctx, code).Return(module, nil)
+
+		err := srv.SetModuleStatus(ctx, code, publication.StatusArchived)
+		assert.True(t, errors.Is(err, publication.ErrInvalidTransition))
+	})
+
+	t.Run("status changed concurrently", func(t *testing.T) {
+		repo.EXPECT().getModule(ctx, code).Return(module, nil)
+		repo.EXPECT().setModuleStatus(ctx, code, publication.StatusInReview, publication.StatusDraft).Return(Module{}, publication.ErrStatusChanged)
+
+		err := srv.SetModuleStatus(ctx, code, publication.StatusDraft)
+		assert.Equal(t, publication.ErrStatusChanged, err)
+	})
+}
+
+func Test_ModulesService_GetModulesReferencingLesson(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		repo           = NewMockrepository(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+		srv            = NewModulesService(repo, lessonsService)
+		errRepo        = errors.New("repo error")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().getModuleCodesByLesson(ctx, "lesson1").Return([]string{"module1", "module2"}, nil)
+
+		codes, err := srv.GetModulesReferencingLesson(ctx, "lesson1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"module1", "module2"}, codes)
+	})
+
+	t.Run("repo fail", func(t *testing.T) {
+		repo.EXPECT().getModuleCodesByLesson(ctx, "lesson1").Return(nil, errRepo)
+
+		_, err := srv.GetModulesReferencingLesson(ctx, "lesson1")
+		assert.Equal(t, errRepo, err)
+	})
+}
+
+func Test_ModulesService_UnlinkLesson(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx            = context.TODO()
+		ctrl           = gomock.NewController(t)
+		repo           = NewMockrepository(ctrl)
+		lessonsService = NewMocklessonsService(ctrl)
+		revisionSrv    = NewMockrevisionService(ctrl)
+		srv            = NewModulesService(repo, lessonsService)
+		unlinked       = Module{Code: "module1", Lessons: []string{"lesson2"}}
+	)
+	srv.WithRevisionService(revisionSrv)
+
+	t.Run("revision of every unlinked module", func(t *testing.T) {
+		repo.EXPECT().getModuleCodesByLesson(ctx, "lesson1").Return([]string{"module1", "module2"}, nil)
+		repo.EXPECT().unlinkLesson(ctx, "module1", "lesson1").Return(unlinked, nil)
+		repo.EXPECT().unlinkLesson(ctx, "module2", "lesson1").Return(Module{}, ErrLessonNotInList)
+		revisionSrv.EXPECT().SaveRevision(ctx, revisions.EntityModule, "module1", unlinked).Return(2, nil)
+
+		err := srv.UnlinkLesson(ctx, "lesson1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("repo fail", func(t *testing.T) {
+		errRepo := errors.New("repo error")
+		repo.EXPECT().getModuleCodesByLesson(ctx, "lesson1").Return([]string{"module1"}, nil)
+		repo.EXPECT().unlinkLesson(ctx, "module1", "lesson1").Return(Module{}, errRepo)
+
+		err := srv.UnlinkLesson(ctx, "lesson1")
+		assert.Equal(t, errRepo, err)
+	})
+}
+
+func Test_ModulesService_ListModules(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx       = context.TODO()
+		ctrl      = gomock.NewController(t)
+		repo      = NewMockrepository(ctrl)
+		lessonSrv = NewMocklessonsService(ctrl)
+		srv       = NewModulesService(repo, lessonSrv)
+		filter    = ListFilter{Difficulty: "easy"}
+		params    = pagination.Params{Limit: 1, SortBy: "title", Order: pagination.OrderAsc}
+		modules   = []Module{
+			{Code: "module1", Title: "A", Lessons: []string{"lesson1"}},
+			{Code: "module2", Title: "B"},
+		}
+		lessonList = []lessons.LessonDTO{{Code: "lesson1"}}
+		errRepo    = errors.New("repo err")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().listModules(ctx, filter, params).Return(modules, nil)
+		lessonSrv.EXPECT().GetLessonsByCodes(ctx, []string{"lesson1"}).Return(lessonList, nil)
+
+		page, err := srv.ListModules(ctx, filter, params)
+		assert.NoError(t, err)
+		assert.Equal(t, []ModuleWithLessons{modules[0].toDTO(lessonList)}, page.Items)
+
+		cursor, err := pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, pagination.Cursor{SortBy: "title", Order: pagination.OrderAsc, Value: "A", Key: "module1"}, cursor)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		repo.EXPECT().listModules(ctx, filter, params).Return(modules[1:], nil)
+
+		page, err := srv.ListModules(ctx, filter, params)
+		assert.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("repo failed", func(t *testing.T) {
+		repo.EXPECT().listModules(ctx, filter, params).Return(nil, errRepo)
+
+		_, err := srv.ListModules(ctx, filter, params)
+		assert.Equal(t, errRepo, err)
+	})
+}
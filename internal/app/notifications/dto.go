@@ -0,0 +1,41 @@
+package notifications
+
+import "time"
+
+const (
+	TypeFriendRequest        = "friend_request"
+	TypeFriendAccepted       = "friend_accepted"
+	TypeBadgeEarned          = "badge_earned"
+	TypeAchievementLevelUp   = "achievement_level_up"
+	TypeLeaderboardOvertaken = "leaderboard_overtaken"
+)
+
+const (
+	// brokerChannel is the redis channel notifications are fanned out through
+	brokerChannel = "notifications"
+	// defaultOvertakenLimit is how many overtaken users are notified at most for one XP gain
+	defaultOvertakenLimit = 10
+	// clientBuffer is how many notifications wait for a slow client before they are dropped,
+	// dropped notifications stay unread and are loaded with the list
+	clientBuffer = 16
+)
+
+// SortFields notifications are always shown from the newest
+var SortFields = []string{"created_at"}
+
+// Notification is addressed to Username. Actor is the other user of friendship and
+// leaderboard notifications, Code and Value are the badge or the achievement and its level.
+type Notification struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Type      string    `json:"type"`
+	Actor     string    `json:"actor,omitempty"`
+	Code      string    `json:"code,omitempty"`
+	Value     int       `json:"value,omitempty"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListFilter struct {
+	UnreadOnly bool
+}
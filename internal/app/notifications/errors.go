@@ -0,0 +1,7 @@
+package notifications
+
+import "errors"
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
@@ -0,0 +1,71 @@
+package notifications
+
+import "sync"
+
+// Hub keeps streams of the clients connected to this instance,
+// one user may have several connections
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[string]map[chan Notification]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[string]map[chan Notification]struct{}),
+	}
+}
+
+// Subscribe returns the user's stream and the function which closes it
+func (h *Hub) Subscribe(username string) (<-chan Notification, func()) {
+	stream := make(chan Notification, clientBuffer)
+
+	h.mu.Lock()
+	if h.clients[username] == nil {
+		h.clients[username] = make(map[chan Notification]struct{})
+	}
+	h.clients[username][stream] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return stream, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			if _, ok := h.clients[username][stream]; !ok {
+				return
+			}
+			delete(h.clients[username], stream)
+			if len(h.clients[username]) == 0 {
+				delete(h.clients, username)
+			}
+			close(stream)
+		})
+	}
+}
+
+// Deliver sends the notification to every stream of the user without waiting for slow clients
+func (h *Hub) Deliver(notification Notification) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for stream := range h.clients[notification.Username] {
+		select {
+		case stream <- notification:
+		default:
+		}
+	}
+}
+
+// Close ends all streams, it is called on shutdown
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for username, streams := range h.clients {
+		for stream := range streams {
+			close(stream)
+		}
+		delete(h.clients, username)
+	}
+}
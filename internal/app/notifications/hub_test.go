@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Hub_Deliver(t *testing.T) {
+	t.Parallel()
+	var (
+		hub           = NewHub()
+		first, stop1  = hub.Subscribe("seab")
+		second, stop2 = hub.Subscribe("seab")
+		other, stop3  = hub.Subscribe("asan2")
+		notification  = Notification{ID: 1, Username: "seab", Type: TypeFriendRequest, Actor: "asan2"}
+	)
+	defer stop1()
+	defer stop2()
+	defer stop3()
+
+	hub.Deliver(notification)
+
+	assert.Equal(t, <-first, notification)
+	assert.Equal(t, <-second, notification)
+	assert.Len(t, other, 0)
+}
+
+func Test_Hub_slow_client_does_not_block(t *testing.T) {
+	t.Parallel()
+	var (
+		hub          = NewHub()
+		stream, stop = hub.Subscribe("seab")
+	)
+	defer stop()
+
+	for i := 0; i < clientBuffer*2; i++ {
+		hub.Deliver(Notification{ID: int64(i), Username: "seab"})
+	}
+
+	assert.Len(t, stream, clientBuffer)
+}
+
+func Test_Hub_unsubscribe_and_close(t *testing.T) {
+	t.Parallel()
+	var (
+		hub           = NewHub()
+		first, stop1  = hub.Subscribe("seab")
+		second, stop2 = hub.Subscribe("seab")
+	)
+
+	stop1()
+	_, ok := <-first
+	assert.False(t, ok)
+
+	// unsubscribing twice or after close does nothing
+	stop1()
+	hub.Close()
+	stop2()
+
+	_, ok = <-second
+	assert.False(t, ok)
+	assert.Empty(t, hub.clients)
+}
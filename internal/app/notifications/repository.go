@@ -0,0 +1,227 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"uiren/pkg/pagination"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const notificationColumns = `id, username, type, actor, code, value, is_read, created_at`
+
+func (r *repository) createNotification(ctx context.Context, notification Notification) (Notification, error) {
+	var (
+		query = `
+		INSERT INTO notifications (username, type, actor, code, value)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + notificationColumns + `;
+		`
+	)
+
+	row := r.db.QueryRow(ctx, query, notification.Username, notification.Type, notification.Actor, notification.Code, notification.Value)
+	return scanNotification(row)
+}
+
+// createUserNotification creates the notification for the user found by id,
+// progress events know only the id of the user
+func (r *repository) createUserNotification(ctx context.Context, userID string, notification Notification) (Notification, error) {
+	var (
+		query = `
+		INSERT INTO notifications (username, type, actor, code, value)
+		SELECT username, $2, $3, $4, $5 FROM users WHERE id = $1
+		RETURNING ` + notificationColumns + `;
+		`
+	)
+
+	row := r.db.QueryRow(ctx, query, userID, notification.Type, notification.Actor, notification.Code, notification.Value)
+	created, err := scanNotification(row)
+	if errors.Is(err, ErrNotificationNotFound) {
+		return Notification{}, nil
+	}
+	return created, err
+}
+
+// createOvertakenNotifications notifies users who had at least as much XP as the user
+// before the gain and have less now, users blocked in any direction are skipped
+func (r *repository) createOvertakenNotifications(ctx context.Context, userID string, gainedXP, limit int) ([]Notification, error) {
+	var (
+		query = `
+		WITH mover AS (
+			SELECT u.id, u.username, p.xp FROM users_progress p
+			JOIN users u ON u.id = p.user_id
+			WHERE p.user_id = $1
+		),
+		overtaken AS (
+			SELECT u.username, m.username AS actor FROM users_progress p
+			JOIN users u ON u.id = p.user_id
+			CROSS JOIN mover m
+			WHERE p.user_id <> m.id
+				AND u.deleted_at IS NULL
+				AND p.xp >= m.xp - $2
+				AND p.xp < m.xp
+				AND NOT EXISTS (
+					SELECT 1 FROM user_blocks b
+					WHERE (b.blocker_username = u.username AND b.blocked_username = m.username)
+						OR (b.blocker_username = m.username AND b.blocked_username = u.username)
+				)
+			ORDER BY p.xp DESC
+			LIMIT $3
+		)
+		INSERT INTO notifications (username, type, actor)
+		SELECT username, '` + TypeLeaderboardOvertaken + `', actor FROM overtaken
+		RETURNING ` + notificationColumns + `;
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, userID, gainedXP, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var created []Notification
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, notification)
+	}
+
+	return created, rows.Err()
+}
+
+func (r *repository) getNotifications(ctx context.Context, username string, filter ListFilter, params pagination.Params) ([]Notification, error) {
+	var (
+		conditions = "username = $1"
+		args       = []interface{}{username}
+	)
+
+	if filter.UnreadOnly {
+		conditions += " AND is_read = false"
+	}
+
+	if params.After != nil {
+		createdAt, err := params.After.TimeValue()
+		if err != nil {
+			return nil, err
+		}
+		id, err := strconv.ParseInt(params.After.Key, 10, 64)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+
+		condition, afterArgs := pagination.PostgresAfter(params, "created_at", createdAt, "id", id, len(args)+1)
+		args = append(args, afterArgs...)
+		conditions += " AND " + condition
+	}
+
+	args = append(args, params.Fetch())
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM notifications
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d;
+		`, notificationColumns, conditions, pagination.PostgresOrderBy(params, "created_at", "id"), len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []Notification
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *repository) countUnread(ctx context.Context, username string) (int, error) {
+	var (
+		query = `
+		SELECT COUNT(*) FROM notifications WHERE username = $1 AND is_read = false;
+		`
+		count int
+	)
+
+	if err := r.db.QueryRow(ctx, query, username).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *repository) markRead(ctx context.Context, username string, id int64) error {
+	var (
+		query = `
+		UPDATE notifications SET is_read = true WHERE id = $1 AND username = $2;
+		`
+	)
+
+	commandTag, err := r.db.Exec(ctx, query, id, username)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) markAllRead(ctx context.Context, username string) (int64, error) {
+	var (
+		query = `
+		UPDATE notifications SET is_read = true WHERE username = $1 AND is_read = false;
+		`
+	)
+
+	commandTag, err := r.db.Exec(ctx, query, username)
+	if err != nil {
+		return 0, err
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
+func scanNotification(row pgx.Row) (Notification, error) {
+	var notification Notification
+	if err := row.Scan(
+		&notification.ID,
+		&notification.Username,
+		&notification.Type,
+		&notification.Actor,
+		&notification.Code,
+		&notification.Value,
+		&notification.IsRead,
+		&notification.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Notification{}, ErrNotificationNotFound
+		}
+		return Notification{}, err
+	}
+
+	return notification, nil
+}
@@ -0,0 +1,202 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"uiren/internal/app/events"
+	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package notifications
+
+type notificationRepository interface {
+	createNotification(ctx context.Context, notification Notification) (Notification, error)
+	createUserNotification(ctx context.Context, userID string, notification Notification) (Notification, error)
+	createOvertakenNotifications(ctx context.Context, userID string, gainedXP, limit int) ([]Notification, error)
+
+	getNotifications(ctx context.Context, username string, filter ListFilter, params pagination.Params) ([]Notification, error)
+	countUnread(ctx context.Context, username string) (int, error)
+	markRead(ctx context.Context, username string, id int64) error
+	markAllRead(ctx context.Context, username string) (int64, error)
+}
+
+// broker fans notifications out to every instance, so the user gets them
+// whichever instance the stream is connected to
+type broker interface {
+	Publish(ctx context.Context, channel string, message interface{}) error
+	Subscribe(ctx context.Context, channel string) <-chan string
+}
+
+type NotificationService struct {
+	notificationRepository notificationRepository
+	hub                    *Hub
+	broker                 broker
+	overtakenLimit         int
+}
+
+func NewNotificationService(notificationRepository notificationRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepository: notificationRepository,
+		hub:                    NewHub(),
+		overtakenLimit:         defaultOvertakenLimit,
+	}
+}
+
+// WithBroker enables delivery across instances, without it notifications
+// are pushed only to streams of this instance
+func (s *NotificationService) WithBroker(broker broker) {
+	s.broker = broker
+}
+
+func (s *NotificationService) SetOvertakenLimit(limit int) {
+	s.overtakenLimit = limit
+}
+
+// Run delivers notifications from the broker to the connected streams until ctx is done
+func (s *NotificationService) Run(ctx context.Context) {
+	if s.broker == nil {
+		return
+	}
+
+	for message := range s.broker.Subscribe(ctx, brokerChannel) {
+		var notification Notification
+		if err := json.Unmarshal([]byte(message), &notification); err != nil {
+			logger.Error("NotificationService.Run json.Unmarshal: ", err)
+			continue
+		}
+		s.hub.Deliver(notification)
+	}
+}
+
+// Close ends the connected streams
+func (s *NotificationService) Close() {
+	s.hub.Close()
+}
+
+// HandleEvent saves notifications about friendship and progress events and pushes them
+// to the connected streams, it is subscribed to the events bus
+func (s *NotificationService) HandleEvent(ctx context.Context, event events.Event) {
+	var (
+		created []Notification
+		err     error
+	)
+
+	switch event.Type {
+	case events.FriendRequestReceived:
+		created, err = s.create(ctx, Notification{Username: event.Username, Type: TypeFriendRequest, Actor: event.Code})
+	case events.FriendRequestAccepted:
+		created, err = s.create(ctx, Notification{Username: event.Username, Type: TypeFriendAccepted, Actor: event.Code})
+	case events.BadgeEarned:
+		created, err = s.createForUser(ctx, event.UserID, Notification{Type: TypeBadgeEarned, Code: event.Code})
+	case events.AchievementLevelUp:
+		created, err = s.createForUser(ctx, event.UserID, Notification{Type: TypeAchievementLevelUp, Code: event.Code, Value: event.Value})
+	case events.XPGained:
+		created, err = s.notificationRepository.createOvertakenNotifications(ctx, event.UserID, event.Value, s.overtakenLimit)
+	default:
+		return
+	}
+	if err != nil {
+		logger.Error("NotificationService.HandleEvent "+event.Type+": ", err)
+		return
+	}
+
+	for _, notification := range created {
+		s.push(ctx, notification)
+	}
+}
+
+func (s *NotificationService) create(ctx context.Context, notification Notification) ([]Notification, error) {
+	created, err := s.notificationRepository.createNotification(ctx, notification)
+	if err != nil {
+		return nil, err
+	}
+	return []Notification{created}, nil
+}
+
+func (s *NotificationService) createForUser(ctx context.Context, userID string, notification Notification) ([]Notification, error) {
+	created, err := s.notificationRepository.createUserNotification(ctx, userID, notification)
+	if err != nil {
+		return nil, err
+	}
+	// the user is deleted
+	if created.ID == 0 {
+		return nil, nil
+	}
+	return []Notification{created}, nil
+}
+
+// push sends the notification through the broker, when the broker fails
+// the notification is delivered at least to this instance
+func (s *NotificationService) push(ctx context.Context, notification Notification) {
+	if s.broker == nil {
+		s.hub.Deliver(notification)
+		return
+	}
+
+	message, err := json.Marshal(notification)
+	if err != nil {
+		logger.Error("NotificationService.push json.Marshal: ", err)
+		return
+	}
+
+	if err := s.broker.Publish(ctx, brokerChannel, message); err != nil {
+		logger.Error("NotificationService.push broker.Publish: ", err)
+		s.hub.Deliver(notification)
+	}
+}
+
+// Subscribe returns the stream of the user's new notifications and the function which closes it
+func (s *NotificationService) Subscribe(username string) (<-chan Notification, func()) {
+	return s.hub.Subscribe(username)
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, username string, filter ListFilter, params pagination.Params) (pagination.Page[Notification], error) {
+	logger.Info("NotificationService.GetNotifications new request")
+
+	notifications, err := s.notificationRepository.getNotifications(ctx, username, filter, params)
+	if err != nil {
+		logger.Error("NotificationService.GetNotifications notificationRepository.getNotifications: ", err)
+		return pagination.Page[Notification]{}, err
+	}
+
+	return pagination.NewPage(notifications, params, notificationCursor), nil
+}
+
+func (s *NotificationService) CountUnread(ctx context.Context, username string) (int, error) {
+	logger.Info("NotificationService.CountUnread new request")
+
+	count, err := s.notificationRepository.countUnread(ctx, username)
+	if err != nil {
+		logger.Error("NotificationService.CountUnread notificationRepository.countUnread: ", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, username string, id int64) error {
+	logger.Info("NotificationService.MarkRead new request")
+
+	if err := s.notificationRepository.markRead(ctx, username, id); err != nil {
+		logger.Error("NotificationService.MarkRead notificationRepository.markRead: ", err)
+		return err
+	}
+	return nil
+}
+
+// MarkAllRead returns the number of notifications marked as read
+func (s *NotificationService) MarkAllRead(ctx context.Context, username string) (int64, error) {
+	logger.Info("NotificationService.MarkAllRead new request")
+
+	updated, err := s.notificationRepository.markAllRead(ctx, username)
+	if err != nil {
+		logger.Error("NotificationService.MarkAllRead notificationRepository.markAllRead: ", err)
+		return 0, err
+	}
+	return updated, nil
+}
+
+func notificationCursor(notification Notification, _ string) (string, string) {
+	return pagination.FormatTime(notification.CreatedAt), strconv.FormatInt(notification.ID, 10)
+}
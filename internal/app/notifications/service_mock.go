@@ -0,0 +1,191 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package notifications is a generated GoMock package.
+package notifications
+
+import (
+	context "context"
+	reflect "reflect"
+	pagination "uiren/pkg/pagination"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MocknotificationRepository is a mock of notificationRepository interface.
+type MocknotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MocknotificationRepositoryMockRecorder
+}
+
+// MocknotificationRepositoryMockRecorder is the mock recorder for MocknotificationRepository.
+type MocknotificationRepositoryMockRecorder struct {
+	mock *MocknotificationRepository
+}
+
+// NewMocknotificationRepository creates a new mock instance.
+func NewMocknotificationRepository(ctrl *gomock.Controller) *MocknotificationRepository {
+	mock := &MocknotificationRepository{ctrl: ctrl}
+	mock.recorder = &MocknotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocknotificationRepository) EXPECT() *MocknotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// countUnread mocks base method.
+func (m *MocknotificationRepository) countUnread(ctx context.Context, username string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "countUnread", ctx, username)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// countUnread indicates an expected call of countUnread.
+func (mr *MocknotificationRepositoryMockRecorder) countUnread(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "countUnread", reflect.TypeOf((*MocknotificationRepository)(nil).countUnread), ctx, username)
+}
+
+// createNotification mocks base method.
+func (m *MocknotificationRepository) createNotification(ctx context.Context, notification Notification) (Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createNotification", ctx, notification)
+	ret0, _ := ret[0].(Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createNotification indicates an expected call of createNotification.
+func (mr *MocknotificationRepositoryMockRecorder) createNotification(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createNotification", reflect.TypeOf((*MocknotificationRepository)(nil).createNotification), ctx, notification)
+}
+
+// createOvertakenNotifications mocks base method.
+func (m *MocknotificationRepository) createOvertakenNotifications(ctx context.Context, userID string, gainedXP, limit int) ([]Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createOvertakenNotifications", ctx, userID, gainedXP, limit)
+	ret0, _ := ret[0].([]Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createOvertakenNotifications indicates an expected call of createOvertakenNotifications.
+func (mr *MocknotificationRepositoryMockRecorder) createOvertakenNotifications(ctx, userID, gainedXP, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createOvertakenNotifications", reflect.TypeOf((*MocknotificationRepository)(nil).createOvertakenNotifications), ctx, userID, gainedXP, limit)
+}
+
+// createUserNotification mocks base method.
+func (m *MocknotificationRepository) createUserNotification(ctx context.Context, userID string, notification Notification) (Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createUserNotification", ctx, userID, notification)
+	ret0, _ := ret[0].(Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createUserNotification indicates an expected call of createUserNotification.
+func (mr *MocknotificationRepositoryMockRecorder) createUserNotification(ctx, userID, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createUserNotification", reflect.TypeOf((*MocknotificationRepository)(nil).createUserNotification), ctx, userID, notification)
+}
+
+// getNotifications mocks base method.
+func (m *MocknotificationRepository) getNotifications(ctx context.Context, username string, filter ListFilter, params pagination.Params) ([]Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getNotifications", ctx, username, filter, params)
+	ret0, _ := ret[0].([]Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getNotifications indicates an expected call of getNotifications.
+func (mr *MocknotificationRepositoryMockRecorder) getNotifications(ctx, username, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getNotifications", reflect.TypeOf((*MocknotificationRepository)(nil).getNotifications), ctx, username, filter, params)
+}
+
+// markAllRead mocks base method.
+func (m *MocknotificationRepository) markAllRead(ctx context.Context, username string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "markAllRead", ctx, username)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// markAllRead indicates an expected call of markAllRead.
+func (mr *MocknotificationRepositoryMockRecorder) markAllRead(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "markAllRead", reflect.TypeOf((*MocknotificationRepository)(nil).markAllRead), ctx, username)
+}
+
+// markRead mocks base method.
+func (m *MocknotificationRepository) markRead(ctx context.Context, username string, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "markRead", ctx, username, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// markRead indicates an expected call of markRead.
+func (mr *MocknotificationRepositoryMockRecorder) markRead(ctx, username, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "markRead", reflect.TypeOf((*MocknotificationRepository)(nil).markRead), ctx, username, id)
+}
+
+// Mockbroker is a mock of broker interface.
+type Mockbroker struct {
+	ctrl     *gomock.Controller
+	recorder *MockbrokerMockRecorder
+}
+
+// MockbrokerMockRecorder is the mock recorder for Mockbroker.
+type MockbrokerMockRecorder struct {
+	mock *Mockbroker
+}
+
+// NewMockbroker creates a new mock instance.
+func NewMockbroker(ctrl *gomock.Controller) *Mockbroker {
+	mock := &Mockbroker{ctrl: ctrl}
+	mock.recorder = &MockbrokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockbroker) EXPECT() *MockbrokerMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *Mockbroker) Publish(ctx context.Context, channel string, message interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, channel, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockbrokerMockRecorder) Publish(ctx, channel, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*Mockbroker)(nil).Publish), ctx, channel, message)
+}
+
+// Subscribe mocks base method.
+func (m *Mockbroker) Subscribe(ctx context.Context, channel string) <-chan string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, channel)
+	ret0, _ := ret[0].(<-chan string)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockbrokerMockRecorder) Subscribe(ctx, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*Mockbroker)(nil).Subscribe), ctx, channel)
+}
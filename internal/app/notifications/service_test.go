@@ -0,0 +1,207 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"uiren/internal/app/events"
+	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+func Test_NotificationService_HandleEvent(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx          = context.TODO()
+		ctrl         = gomock.NewController(t)
+		repo         = NewMocknotificationRepository(ctrl)
+		service      = NewNotificationService(repo)
+		stream, stop = service.Subscribe("seab")
+	)
+	defer stop()
+
+	t.Run("friend request", func(t *testing.T) {
+		created := Notification{ID: 1, Username: "seab", Type: TypeFriendRequest, Actor: "asan2"}
+		repo.EXPECT().createNotification(ctx, Notification{Username: "seab", Type: TypeFriendRequest, Actor: "asan2"}).Return(created, nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.FriendRequestReceived, Username: "seab", Code: "asan2"})
+		assert.Equal(t, <-stream, created)
+	})
+
+	t.Run("friend request accepted", func(t *testing.T) {
+		created := Notification{ID: 2, Username: "seab", Type: TypeFriendAccepted, Actor: "asan2"}
+		repo.EXPECT().createNotification(ctx, Notification{Username: "seab", Type: TypeFriendAccepted, Actor: "asan2"}).Return(created, nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.FriendRequestAccepted, Username: "seab", Code: "asan2"})
+		assert.Equal(t, <-stream, created)
+	})
+
+	t.Run("achievement level up", func(t *testing.T) {
+		created := Notification{ID: 3, Username: "seab", Type: TypeAchievementLevelUp, Code: "Lessons Completed", Value: 2}
+		repo.EXPECT().createUserNotification(ctx, "user-1", Notification{Type: TypeAchievementLevelUp, Code: "Lessons Completed", Value: 2}).Return(created, nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.AchievementLevelUp, UserID: "user-1", Code: "Lessons Completed", Value: 2})
+		assert.Equal(t, <-stream, created)
+	})
+
+	t.Run("badge of deleted user", func(t *testing.T) {
+		repo.EXPECT().createUserNotification(ctx, "user-2", Notification{Type: TypeBadgeEarned, Code: "badge1"}).Return(Notification{}, nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.BadgeEarned, UserID: "user-2", Code: "badge1"})
+		assert.Len(t, stream, 0)
+	})
+
+	t.Run("overtaken users", func(t *testing.T) {
+		created := []Notification{
+			{ID: 4, Username: "seab", Type: TypeLeaderboardOvertaken, Actor: "asan2"},
+			{ID: 5, Username: "nur", Type: TypeLeaderboardOvertaken, Actor: "asan2"},
+		}
+		repo.EXPECT().createOvertakenNotifications(ctx, "user-3", 100, defaultOvertakenLimit).Return(created, nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.XPGained, UserID: "user-3", Value: 100})
+		assert.Equal(t, <-stream, created[0])
+		assert.Len(t, stream, 0)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo.EXPECT().createNotification(ctx, gomock.Any()).Return(Notification{}, errors.New("db error"))
+
+		service.HandleEvent(ctx, events.Event{Type: events.FriendRequestReceived, Username: "seab", Code: "asan2"})
+		assert.Len(t, stream, 0)
+	})
+
+	t.Run("other events ignored", func(t *testing.T) {
+		service.HandleEvent(ctx, events.Event{Type: events.LessonCompleted, UserID: "user-1", Code: "lesson-1"})
+		service.HandleEvent(ctx, events.Event{Type: events.ModuleChanged, Code: "module-1"})
+	})
+}
+
+func Test_NotificationService_broker(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx          = context.TODO()
+		ctrl         = gomock.NewController(t)
+		repo         = NewMocknotificationRepository(ctrl)
+		broker       = NewMockbroker(ctrl)
+		service      = NewNotificationService(repo)
+		stream, stop = service.Subscribe("seab")
+		created      = Notification{ID: 1, Username: "seab", Type: TypeFriendRequest, Actor: "asan2"}
+		message, _   = json.Marshal(created)
+	)
+	defer stop()
+	service.WithBroker(broker)
+
+	t.Run("pushed through the broker", func(t *testing.T) {
+		repo.EXPECT().createNotification(ctx, gomock.Any()).Return(created, nil)
+		broker.EXPECT().Publish(ctx, brokerChannel, message).Return(nil)
+
+		service.HandleEvent(ctx, events.Event{Type: events.FriendRequestReceived, Username: "seab", Code: "asan2"})
+		// delivered by Run when the message comes back from the broker
+		assert.Len(t, stream, 0)
+	})
+
+	t.Run("broker error delivers locally", func(t *testing.T) {
+		repo.EXPECT().createNotification(ctx, gomock.Any()).Return(created, nil)
+		broker.EXPECT().Publish(ctx, brokerChannel, message).Return(errors.New("redis down"))
+
+		service.HandleEvent(ctx, events.Event{Type: events.FriendRequestReceived, Username: "seab", Code: "asan2"})
+		assert.Equal(t, <-stream, created)
+	})
+
+	t.Run("run delivers broker messages", func(t *testing.T) {
+		messages := make(chan string, 2)
+		messages <- "not a json"
+		messages <- string(message)
+		close(messages)
+		broker.EXPECT().Subscribe(ctx, brokerChannel).Return(messages)
+
+		service.Run(ctx)
+		assert.Equal(t, <-stream, created)
+	})
+}
+
+func Test_NotificationService_GetNotifications(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx           = context.TODO()
+		ctrl          = gomock.NewController(t)
+		repo          = NewMocknotificationRepository(ctrl)
+		service       = NewNotificationService(repo)
+		params        = pagination.Params{Limit: 1, SortBy: "created_at", Order: pagination.OrderDesc}
+		filter        = ListFilter{UnreadOnly: true}
+		now           = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		notifications = []Notification{
+			{ID: 2, Username: "seab", Type: TypeBadgeEarned, Code: "badge1", CreatedAt: now},
+			{ID: 1, Username: "seab", Type: TypeFriendRequest, Actor: "asan2", CreatedAt: now.Add(-time.Hour)},
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().getNotifications(ctx, "seab", filter, params).Return(notifications, nil)
+
+		page, err := service.GetNotifications(ctx, "seab", filter, params)
+		assert.NoError(t, err)
+		assert.Equal(t, page.Items, notifications[:1])
+
+		cursor, err := pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, cursor.Key, "2")
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		errRepo := errors.New("db error")
+		repo.EXPECT().getNotifications(ctx, "seab", filter, params).Return(nil, errRepo)
+
+		_, err := service.GetNotifications(ctx, "seab", filter, params)
+		assert.Equal(t, err, errRepo)
+	})
+}
+
+func Test_NotificationService_MarkRead(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMocknotificationRepository(ctrl)
+		service = NewNotificationService(repo)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().markRead(ctx, "seab", int64(1)).Return(nil)
+
+		err := service.MarkRead(ctx, "seab", 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().markRead(ctx, "seab", int64(2)).Return(ErrNotificationNotFound)
+
+		err := service.MarkRead(ctx, "seab", 2)
+		assert.Equal(t, err, ErrNotificationNotFound)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		repo.EXPECT().markAllRead(ctx, "seab").Return(int64(3), nil)
+
+		updated, err := service.MarkAllRead(ctx, "seab")
+		assert.NoError(t, err)
+		assert.Equal(t, updated, int64(3))
+	})
+
+	t.Run("count unread", func(t *testing.T) {
+		repo.EXPECT().countUnread(ctx, "seab").Return(2, nil)
+
+		count, err := service.CountUnread(ctx, "seab")
+		assert.NoError(t, err)
+		assert.Equal(t, count, 2)
+	})
+}
@@ -43,8 +43,17 @@ type UpdateUserProgressRequest struct {
 	XP                   int                   `json:"xp"`
 	NewBadges            []string              `json:"new_badges"`
 	AchievementsProgress []AchievementProgress `json:"achievements_progress"`
+	// CurrentModule is the module the user is passing now, kept as is when empty
+	CurrentModule string `json:"current_module,omitempty"`
+	// CompletedLesson, CompletedModule and Streak are optional and only go to the friends feed
+	CompletedLesson string `json:"completed_lesson,omitempty"`
+	CompletedModule string `json:"completed_module,omitempty"`
+	Streak          int    `json:"streak,omitempty"`
 }
 
+// streakMilestones are streak lengths in days shown in the friends feed
+var streakMilestones = []int{3, 7, 14, 30, 50, 100, 200, 365}
+
 type AddBadgesRequest struct {
 	UserID string   `json:"user_id"`
 	Badges []string `json:"badges"`
@@ -2,7 +2,9 @@ package progress
 
 import (
 	"context"
+	"slices"
 	"uiren/internal/app/achievements"
+	"uiren/internal/app/events"
 	"uiren/pkg/logger"
 )
 
@@ -25,17 +27,24 @@ type progressUpdaterRepo interface {
 
 	addBadges(ctx context.Context, tx transaction, req AddBadgesRequest) error
 	addXP(ctx context.Context, tx transaction, req AddXPRequest) error
+	recordSessionXP(ctx context.Context, tx transaction, sessionID string, req AddXPRequest) (bool, error)
 	updateAchievementProgress(ctx context.Context, tx transaction, req UpdateAchievementProgressRequest) error
+	setCurrentModule(ctx context.Context, tx transaction, userID, moduleCode string) error
 }
 
 type achievementService interface {
 	GetAchievement(ctx context.Context, id int) (achievements.AchievementDTO, error)
 }
 
+type publisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
 type ProgressService struct {
 	receiverRepo progressReceiverRepo
 	updaterRepo  progressUpdaterRepo
 	achService   achievementService
+	publisher    publisher
 }
 
 func NewProgressService(receiverRepo progressReceiverRepo, updaterRepo progressUpdaterRepo, achService achievementService) *ProgressService {
@@ -46,6 +55,10 @@ func NewProgressService(receiverRepo progressReceiverRepo, updaterRepo progressU
 	}
 }
 
+func (s *ProgressService) WithPublisher(publisher publisher) {
+	s.publisher = publisher
+}
+
 func (s *ProgressService) GetBadges(ctx context.Context, user_id string) ([]string, error) {
 	logger.Info("ProgressService.GetBadges new request")
 	badges, err := s.receiverRepo.getUserBadges(ctx, user_id)
@@ -111,15 +124,27 @@ func (s *ProgressService) UpdateUserProgress(ctx context.Context, req UpdateUser
 		return err
 	}
 
+	if req.CurrentModule != "" {
+		if err := s.updaterRepo.setCurrentModule(ctx, tx, req.UserID, req.CurrentModule); err != nil {
+			logger.Error("ProgressService.UpdateUserProgress setCurrentModule: ", err)
+			return err
+		}
+	}
+
+	var levelUps []events.Event
 	if req.AchievementsProgress != nil {
 		for _, achievement := range req.AchievementsProgress {
-			if err := s.updateAchievementProgress(ctx, tx, UpdateAchievementProgressRequest{
+			levelUp, err := s.updateAchievementProgress(ctx, tx, UpdateAchievementProgressRequest{
 				UserID:   req.UserID,
 				Progress: achievement,
-			}); err != nil {
+			})
+			if err != nil {
 				logger.Error("ProgressService.UpdateUserProgress updateUserAchievementProgress: ", err)
 				return err
 			}
+			if levelUp != nil {
+				levelUps = append(levelUps, *levelUp)
+			}
 		}
 	}
 
@@ -129,10 +154,86 @@ func (s *ProgressService) UpdateUserProgress(ctx context.Context, req UpdateUser
 	}
 	commited = true
 
+	s.publishProgress(ctx, req, levelUps)
+
+	return nil
+}
+
+// CompleteLesson adds XP earned in the lesson session, it is published like progress reported by the client.
+// The session is recorded with the XP, so a retry for the same session adds nothing
+func (s *ProgressService) CompleteLesson(ctx context.Context, sessionID, userID, lessonCode string, xp int) error {
+	logger.Info("ProgressService.CompleteLesson new request")
+
+	tx, err := s.updaterRepo.beginTransaction(ctx)
+	if err != nil {
+		logger.Error("ProgressService.CompleteLesson beginTransaction: ", err)
+		return err
+	}
+
+	commited := false
+	defer func() {
+		if !commited {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	req := AddXPRequest{
+		UserID: userID,
+		XP:     xp,
+	}
+	recorded, err := s.updaterRepo.recordSessionXP(ctx, tx, sessionID, req)
+	if err != nil {
+		logger.Error("ProgressService.CompleteLesson recordSessionXP: ", err)
+		return err
+	}
+	if !recorded {
+		return nil
+	}
+
+	if err := s.updaterRepo.addXP(ctx, tx, req); err != nil {
+		logger.Error("ProgressService.CompleteLesson addXP: ", err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("ProgressService.CompleteLesson commit: ", err)
+		return err
+	}
+	commited = true
+
+	s.publishProgress(ctx, UpdateUserProgressRequest{UserID: userID, XP: xp, CompletedLesson: lessonCode}, nil)
+
 	return nil
 }
 
-func (s *ProgressService) updateAchievementProgress(ctx context.Context, tx transaction, req UpdateAchievementProgressRequest) error {
+// publishProgress notifies subscribers about the committed progress
+func (s *ProgressService) publishProgress(ctx context.Context, req UpdateUserProgressRequest, levelUps []events.Event) {
+	if s.publisher == nil {
+		return
+	}
+
+	if req.CompletedLesson != "" {
+		s.publisher.Publish(ctx, events.Event{Type: events.LessonCompleted, Code: req.CompletedLesson, UserID: req.UserID})
+	}
+	if req.CompletedModule != "" {
+		s.publisher.Publish(ctx, events.Event{Type: events.ModuleFinished, Code: req.CompletedModule, UserID: req.UserID})
+	}
+	for _, badge := range req.NewBadges {
+		s.publisher.Publish(ctx, events.Event{Type: events.BadgeEarned, Code: badge, UserID: req.UserID})
+	}
+	for _, levelUp := range levelUps {
+		s.publisher.Publish(ctx, levelUp)
+	}
+	if slices.Contains(streakMilestones, req.Streak) {
+		s.publisher.Publish(ctx, events.Event{Type: events.StreakMilestone, UserID: req.UserID, Value: req.Streak})
+	}
+	if req.XP > 0 {
+		s.publisher.Publish(ctx, events.Event{Type: events.XPGained, UserID: req.UserID, Value: req.XP})
+	}
+}
+
+// updateAchievementProgress returns the level up event when the user reached a new level threshold
+func (s *ProgressService) updateAchievementProgress(ctx context.Context, tx transaction, req UpdateAchievementProgressRequest) (*events.Event, error) {
 	req.Progress.NewLevel = 0
 	currentLevel, err := s.receiverRepo.getAchievementProgress(ctx, req.UserID, req.Progress.AchievementID)
 	if err == ErrAchievementProgressNotFound {
@@ -141,13 +242,13 @@ func (s *ProgressService) updateAchievementProgress(ctx context.Context, tx tran
 		}
 	} else if err != nil {
 		logger.Error("ProgressService.updateAchievementProgress getAchievementProgress: ", err)
-		return err
+		return nil, err
 	}
 
 	ach, err := s.achService.GetAchievement(ctx, req.Progress.AchievementID)
 	if err != nil {
 		logger.Error("ProgressService.updateAchievementProgress GetAchievement: ", err)
-		return err
+		return nil, err
 	}
 
 	newLevel := 0
@@ -166,10 +267,31 @@ func (s *ProgressService) updateAchievementProgress(ctx context.Context, tx tran
 
 	if err := s.updaterRepo.updateAchievementProgress(ctx, tx, req); err != nil {
 		logger.Error("ProgressService.updateAchievementProgress updateUserAchievementProgress: ", err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	reached := reachedLevel(ach.Levels, newProgress)
+	if reached <= reachedLevel(ach.Levels, currentLevel.Progress) {
+		return nil, nil
+	}
+
+	return &events.Event{
+		Type:   events.AchievementLevelUp,
+		Code:   ach.Name,
+		UserID: req.UserID,
+		Value:  reached,
+	}, nil
+}
+
+// reachedLevel returns the highest level whose threshold is reached, 0 when none is
+func reachedLevel(levels []achievements.AchievementLevel, progress int) int {
+	reached := 0
+	for _, level := range levels {
+		if level.Threshold <= progress {
+			reached = level.Level
+		}
+	}
+	return reached
 }
 
 func (s *ProgressService) RegisterNewBadge(ctx context.Context, req Badge) error {
@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 	achievements "uiren/internal/app/achievements"
+	events "uiren/internal/app/events"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -205,6 +206,35 @@ func (mr *MockprogressUpdaterRepoMockRecorder) insertBadge(ctx, req interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "insertBadge", reflect.TypeOf((*MockprogressUpdaterRepo)(nil).insertBadge), ctx, req)
 }
 
+// recordSessionXP mocks base method.
+func (m *MockprogressUpdaterRepo) recordSessionXP(ctx context.Context, tx transaction, sessionID string, req AddXPRequest) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "recordSessionXP", ctx, tx, sessionID, req)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// recordSessionXP indicates an expected call of recordSessionXP.
+func (mr *MockprogressUpdaterRepoMockRecorder) recordSessionXP(ctx, tx, sessionID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "recordSessionXP", reflect.TypeOf((*MockprogressUpdaterRepo)(nil).recordSessionXP), ctx, tx, sessionID, req)
+}
+
+// setCurrentModule mocks base method.
+func (m *MockprogressUpdaterRepo) setCurrentModule(ctx context.Context, tx transaction, userID, moduleCode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "setCurrentModule", ctx, tx, userID, moduleCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// setCurrentModule indicates an expected call of setCurrentModule.
+func (mr *MockprogressUpdaterRepoMockRecorder) setCurrentModule(ctx, tx, userID, moduleCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "setCurrentModule", reflect.TypeOf((*MockprogressUpdaterRepo)(nil).setCurrentModule), ctx, tx, userID, moduleCode)
+}
+
 // updateAchievementProgress mocks base method.
 func (m *MockprogressUpdaterRepo) updateAchievementProgress(ctx context.Context, tx transaction, req UpdateAchievementProgressRequest) error {
 	m.ctrl.T.Helper()
@@ -256,3 +286,38 @@ func (mr *MockachievementServiceMockRecorder) GetAchievement(ctx, id interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAchievement", reflect.TypeOf((*MockachievementService)(nil).GetAchievement), ctx, id)
 }
+
+// Mockpublisher is a mock of publisher interface.
+type Mockpublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockpublisherMockRecorder
+}
+
+// MockpublisherMockRecorder is the mock recorder for Mockpublisher.
+type MockpublisherMockRecorder struct {
+	mock *Mockpublisher
+}
+
+// NewMockpublisher creates a new mock instance.
+func NewMockpublisher(ctrl *gomock.Controller) *Mockpublisher {
+	mock := &Mockpublisher{ctrl: ctrl}
+	mock.recorder = &MockpublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockpublisher) EXPECT() *MockpublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *Mockpublisher) Publish(ctx context.Context, event events.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", ctx, event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockpublisherMockRecorder) Publish(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*Mockpublisher)(nil).Publish), ctx, event)
+}
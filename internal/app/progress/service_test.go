@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 	"uiren/internal/app/achievements"
+	"uiren/internal/app/events"
 	"uiren/pkg/logger"
 
 	gomock "github.com/golang/mock/gomock"
@@ -290,6 +291,32 @@ func Test_ProgressService_UpdateProgress_success_no_achievements(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func Test_ProgressService_UpdateProgress_current_module(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx        = context.TODO()
+		ctrl       = gomock.NewController(t)
+		updateRepo = NewMockprogressUpdaterRepo(ctrl)
+		service    = &ProgressService{updaterRepo: updateRepo}
+		tx         = NewMocktransaction(ctrl)
+		req        = UpdateUserProgressRequest{
+			UserID:        "user123",
+			NewBadges:     []string{"badge1"},
+			XP:            10,
+			CurrentModule: "basics",
+		}
+	)
+
+	updateRepo.EXPECT().beginTransaction(ctx).Return(tx, nil)
+	updateRepo.EXPECT().addBadges(ctx, tx, gomock.Any()).Return(nil)
+	updateRepo.EXPECT().addXP(ctx, tx, gomock.Any()).Return(nil)
+	updateRepo.EXPECT().setCurrentModule(ctx, tx, "user123", "basics").Return(nil)
+	tx.EXPECT().Commit(ctx).Return(nil)
+
+	err := service.UpdateUserProgress(ctx, req)
+	assert.NoError(t, err)
+}
+
 func Test_ProgressService_UpdateProgress_beginTransaction_beginTransaction_failed(t *testing.T) {
 	t.Parallel()
 	var (
@@ -575,8 +602,9 @@ func Test_ProgressService_updateAchievementProgress_level_calculation(t *testing
 		achSrv.EXPECT().GetAchievement(ctx, 1).Return(achievement, nil)
 		req.Progress.NewLevel = 4
 		updateRepo.EXPECT().updateAchievementProgress(ctx, tx, req)
-		err := service.updateAchievementProgress(ctx, tx, req)
+		levelUp, err := service.updateAchievementProgress(ctx, tx, req)
 		assert.NoError(t, err)
+		assert.Equal(t, levelUpLevel(levelUp), 3)
 	})
 
 	t.Run("#2 test", func(t *testing.T) {
@@ -613,8 +641,9 @@ func Test_ProgressService_updateAchievementProgress_level_calculation(t *testing
 		achSrv.EXPECT().GetAchievement(ctx, 1).Return(achievement, nil)
 		req.Progress.NewLevel = 1
 		updateRepo.EXPECT().updateAchievementProgress(ctx, tx, req)
-		err := service.updateAchievementProgress(ctx, tx, req)
+		levelUp, err := service.updateAchievementProgress(ctx, tx, req)
 		assert.NoError(t, err)
+		assert.Equal(t, levelUpLevel(levelUp), 0)
 	})
 
 	t.Run("#3 test", func(t *testing.T) {
@@ -651,11 +680,167 @@ func Test_ProgressService_updateAchievementProgress_level_calculation(t *testing
 		achSrv.EXPECT().GetAchievement(ctx, 1).Return(achievement, nil)
 		req.Progress.NewLevel = 4
 		updateRepo.EXPECT().updateAchievementProgress(ctx, tx, req)
-		err := service.updateAchievementProgress(ctx, tx, req)
+		levelUp, err := service.updateAchievementProgress(ctx, tx, req)
 		assert.NoError(t, err)
+		assert.Equal(t, levelUpLevel(levelUp), 4)
 	})
 }
 
+func levelUpLevel(levelUp *events.Event) int {
+	if levelUp == nil {
+		return 0
+	}
+	return levelUp.Value
+}
+
+func Test_ProgressService_UpdateProgress_publishes_events(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx        = context.TODO()
+		ctrl       = gomock.NewController(t)
+		updateRepo = NewMockprogressUpdaterRepo(ctrl)
+		selectRepo = NewMockprogressReceiverRepo(ctrl)
+		achSrv     = NewMockachievementService(ctrl)
+		pub        = NewMockpublisher(ctrl)
+		service    = &ProgressService{updaterRepo: updateRepo, receiverRepo: selectRepo, achService: achSrv, publisher: pub}
+		tx         = NewMocktransaction(ctrl)
+		req        = UpdateUserProgressRequest{
+			UserID:          "user123",
+			NewBadges:       []string{"badge1"},
+			XP:              10,
+			CompletedLesson: "lesson-1",
+			CompletedModule: "module-1",
+			Streak:          7,
+			AchievementsProgress: []AchievementProgress{
+				{AchievementID: 1, EarnedProgress: 30},
+			},
+		}
+		achievement = achievements.AchievementDTO{
+			ID:   1,
+			Name: "Lessons Completed",
+			Levels: []achievements.AchievementLevel{
+				{Level: 1, Threshold: 20},
+				{Level: 2, Threshold: 40},
+			},
+		}
+	)
+
+	updateRepo.EXPECT().beginTransaction(ctx).Return(tx, nil)
+	updateRepo.EXPECT().addBadges(ctx, tx, gomock.Any()).Return(nil)
+	updateRepo.EXPECT().addXP(ctx, tx, gomock.Any()).Return(nil)
+	selectRepo.EXPECT().getAchievementProgress(ctx, req.UserID, 1).Return(UserAchievement{Level: 1, Progress: 10}, nil)
+	achSrv.EXPECT().GetAchievement(ctx, 1).Return(achievement, nil)
+	updateRepo.EXPECT().updateAchievementProgress(ctx, tx, gomock.Any()).Return(nil)
+	tx.EXPECT().Commit(ctx).Return(nil)
+
+	gomock.InOrder(
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.LessonCompleted, Code: "lesson-1", UserID: req.UserID}),
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.ModuleFinished, Code: "module-1", UserID: req.UserID}),
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.BadgeEarned, Code: "badge1", UserID: req.UserID}),
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.AchievementLevelUp, Code: "Lessons Completed", UserID: req.UserID, Value: 2}),
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.StreakMilestone, UserID: req.UserID, Value: 7}),
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.XPGained, UserID: req.UserID, Value: 10}),
+	)
+
+	err := service.UpdateUserProgress(ctx, req)
+	assert.NoError(t, err)
+}
+
+func Test_ProgressService_UpdateProgress_commit_failed_no_events(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx        = context.TODO()
+		ctrl       = gomock.NewController(t)
+		updateRepo = NewMockprogressUpdaterRepo(ctrl)
+		pub        = NewMockpublisher(ctrl)
+		service    = &ProgressService{updaterRepo: updateRepo, publisher: pub}
+		tx         = NewMocktransaction(ctrl)
+		errCommit  = errors.New("commit failed")
+		req        = UpdateUserProgressRequest{
+			UserID:          "user123",
+			NewBadges:       []string{"badge1"},
+			CompletedLesson: "lesson-1",
+		}
+	)
+
+	updateRepo.EXPECT().beginTransaction(ctx).Return(tx, nil)
+	updateRepo.EXPECT().addBadges(ctx, tx, gomock.Any()).Return(nil)
+	updateRepo.EXPECT().addXP(ctx, tx, gomock.Any()).Return(nil)
+	tx.EXPECT().Commit(ctx).Return(errCommit)
+	tx.EXPECT().Rollback(ctx).Return(nil)
+	pub.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.UpdateUserProgress(ctx, req)
+	assert.Equal(t, err, errCommit)
+}
+
+func Test_ProgressService_CompleteLesson(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx        = context.TODO()
+		ctrl       = gomock.NewController(t)
+		updateRepo = NewMockprogressUpdaterRepo(ctrl)
+		pub        = NewMockpublisher(ctrl)
+		service    = &ProgressService{updaterRepo: updateRepo, publisher: pub}
+		tx         = NewMocktransaction(ctrl)
+	)
+
+	updateRepo.EXPECT().beginTransaction(ctx).Return(tx, nil)
+	updateRepo.EXPECT().recordSessionXP(ctx, tx, "session-1", AddXPRequest{UserID: "user123", XP: 25}).Return(true, nil)
+	updateRepo.EXPECT().addXP(ctx, tx, AddXPRequest{UserID: "user123", XP: 25}).Return(nil)
+	tx.EXPECT().Commit(ctx).Return(nil)
+	gomock.InOrder(
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.LessonCompleted, Code: "lesson-1", UserID: "user123"}),
+		pub.EXPECT().Publish(ctx, events.Event{Type: events.XPGained, UserID: "user123", Value: 25}),
+	)
+
+	err := service.CompleteLesson(ctx, "session-1", "user123", "lesson-1", 25)
+	assert.NoError(t, err)
+}
+
+func Test_ProgressService_CompleteLesson_addXP_failed(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx        = context.TODO()
+		ctrl       = gomock.NewController(t)
+		updateRepo = NewMockprogressUpdaterRepo(ctrl)
+		pub        = NewMockpublisher(ctrl)
+		service    = &ProgressService{updaterRepo: updateRepo, publisher: pub}
+		tx         = NewMocktransaction(ctrl)
+		errRepo    = errors.New("db error")
+	)
+
+	updateRepo.EXPECT().beginTransaction(ctx).Return(tx, nil)
+	updateRepo.EXPECT().recordSessionXP(ctx, tx, "session-1", gomock.Any()).Return(true, nil)
+	updateRepo.EXPECT().addXP(ctx, tx, gomock.Any()).Return(errRepo)
+	tx.EXPECT().Rollback(ctx).Return(nil)
+	pub.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.CompleteLesson(ctx, "session-1", "user123", "lesson-1", 25)
+	assert.Equal(t, errRepo, err)
+}
+
+func Test_ProgressService_CompleteLesson_alreadyRecorded(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx        = context.TODO()
+		ctrl       = gomock.NewController(t)
+		updateRepo = NewMockprogressUpdaterRepo(ctrl)
+		pub        = NewMockpublisher(ctrl)
+		service    = &ProgressService{updaterRepo: updateRepo, publisher: pub}
+		tx         = NewMocktransaction(ctrl)
+	)
+
+	updateRepo.EXPECT().beginTransaction(ctx).Return(tx, nil)
+	updateRepo.EXPECT().recordSessionXP(ctx, tx, "session-1", gomock.Any()).Return(false, nil)
+	updateRepo.EXPECT().addXP(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	tx.EXPECT().Rollback(ctx).Return(nil)
+	pub.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.CompleteLesson(ctx, "session-1", "user123", "lesson-1", 25)
+	assert.NoError(t, err)
+}
+
 func Test_ProgressService_GetXPLeaderboard_success(t *testing.T) {
 	t.Parallel()
 	var (
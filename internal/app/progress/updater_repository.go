@@ -76,6 +76,42 @@ func (r *progressUpdaterRepository) addXP(ctx context.Context, tx transaction, r
 	return nil
 }
 
+// recordSessionXP returns false when the XP of the session was added already
+func (r *progressUpdaterRepository) recordSessionXP(ctx context.Context, tx transaction, sessionID string, req AddXPRequest) (bool, error) {
+	var (
+		query = `
+		INSERT INTO
+			lesson_session_xp(session_id, user_id, xp)
+		VALUES
+			($1, $2, $3)
+		ON CONFLICT (session_id) DO NOTHING
+		`
+	)
+
+	res, err := tx.Exec(ctx, query, sessionID, req.UserID, req.XP)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func (r *progressUpdaterRepository) setCurrentModule(ctx context.Context, tx transaction, userID, moduleCode string) error {
+	var (
+		query = `
+		INSERT INTO 
+			users_progress(user_id, current_module)
+		VALUES
+			($1, $2)
+		ON CONFLICT ON CONSTRAINT unique_user_id
+		DO UPDATE 
+			SET current_module = EXCLUDED.current_module
+		`
+	)
+
+	_, err := tx.Exec(ctx, query, userID, moduleCode)
+	return err
+}
+
 func (r *progressUpdaterRepository) updateAchievementProgress(ctx context.Context, tx transaction, req UpdateAchievementProgressRequest) error {
 	var (
 		query = `
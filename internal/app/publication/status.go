@@ -0,0 +1,57 @@
+package publication
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid status")
+	ErrInvalidTransition = errors.New("status transition is not allowed")
+	ErrStatusChanged     = errors.New("status was changed by another request")
+	ErrNotPublished      = errors.New("referenced content is not published")
+)
+
+// transitions lists the statuses every status can be moved to
+var transitions = map[string][]string{
+	StatusDraft:     {StatusInReview},
+	StatusInReview:  {StatusDraft, StatusPublished},
+	StatusPublished: {StatusArchived},
+	StatusArchived:  {StatusDraft},
+}
+
+func IsValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+func ValidateTransition(from, to string) error {
+	if !IsValidStatus(to) {
+		return ErrInvalidStatus
+	}
+
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+}
+
+// NotPublishedError reports which referenced codes block publishing
+func NotPublishedError(entityType string, codes []string) error {
+	return fmt.Errorf("%w: %s %s", ErrNotPublished, entityType, strings.Join(codes, ", "))
+}
+
+type UpdateStatusDTO struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,36 @@
+package publication
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateTransition(t *testing.T) {
+	t.Parallel()
+
+	allowed := [][2]string{
+		{StatusDraft, StatusInReview},
+		{StatusInReview, StatusDraft},
+		{StatusInReview, StatusPublished},
+		{StatusPublished, StatusArchived},
+		{StatusArchived, StatusDraft},
+	}
+	for _, transition := range allowed {
+		assert.NoError(t, ValidateTransition(transition[0], transition[1]), transition)
+	}
+
+	forbidden := [][2]string{
+		{StatusDraft, StatusPublished},
+		{StatusPublished, StatusDraft},
+		{StatusArchived, StatusPublished},
+		{StatusDraft, StatusDraft},
+	}
+	for _, transition := range forbidden {
+		err := ValidateTransition(transition[0], transition[1])
+		assert.True(t, errors.Is(err, ErrInvalidTransition), transition)
+	}
+
+	assert.Equal(t, ErrInvalidStatus, ValidateTransition(StatusDraft, "deleted"))
+}
@@ -0,0 +1,44 @@
+package revisions
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	EntityModule   = "module"
+	EntityLesson   = "lesson"
+	EntityExercise = "exercise"
+)
+
+type Revision struct {
+	EntityType string    `bson:"entity_type" json:"entity_type"`
+	Code       string    `bson:"code" json:"code"`
+	Version    int       `bson:"version" json:"version"`
+	Snapshot   bson.M    `bson:"snapshot,omitempty" json:"snapshot,omitempty"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+}
+
+// Decode fills target (module, lesson or exercise document) with the revision snapshot
+func (r Revision) Decode(target interface{}) error {
+	raw, err := bson.Marshal(r.Snapshot)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, target)
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type RevisionDiff struct {
+	EntityType  string        `json:"entity_type"`
+	Code        string        `json:"code"`
+	FromVersion int           `json:"from_version"`
+	ToVersion   int           `json:"to_version"`
+	Changes     []FieldChange `json:"changes"`
+}
@@ -0,0 +1,11 @@
+package revisions
+
+import "errors"
+
+var (
+	ErrRevisionNotFound    = errors.New("revision not found")
+	ErrInvalidEntityType   = errors.New("invalid entity type")
+	ErrInvalidVersion      = errors.New("invalid revision version")
+	ErrVersionConflict     = errors.New("failed to allocate revision version")
+	ErrHistoryNotAvailable = errors.New("revision history is not configured")
+)
@@ -0,0 +1,111 @@
+package revisions
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	revisionsCollection = "revisions"
+	// concurrent writers may pick the same version, unique index (entity_type, code, version) rejects one of them
+	createRevisionAttempts = 3
+)
+
+type revisionRepository struct {
+	db *mongo.Database
+}
+
+func NewRevisionRepository(db *mongo.Database) *revisionRepository {
+	return &revisionRepository{
+		db: db,
+	}
+}
+
+func (r *revisionRepository) createRevision(ctx context.Context, revision Revision) (int, error) {
+	var (
+		collection = r.db.Collection(revisionsCollection)
+	)
+
+	for attempt := 0; attempt < createRevisionAttempts; attempt++ {
+		lastVersion, err := r.getLastVersion(ctx, revision.EntityType, revision.Code)
+		if err != nil {
+			return 0, err
+		}
+		revision.Version = lastVersion + 1
+
+		if _, err := collection.InsertOne(ctx, revision); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+			return 0, err
+		}
+
+		return revision.Version, nil
+	}
+
+	return 0, ErrVersionConflict
+}
+
+func (r *revisionRepository) getLastVersion(ctx context.Context, entityType, code string) (int, error) {
+	var (
+		collection = r.db.Collection(revisionsCollection)
+		filter     = bson.M{"entity_type": entityType, "code": code}
+		opts       = options.FindOne().
+				SetSort(bson.M{"version": -1}).
+				SetProjection(bson.M{"version": 1, "_id": 0})
+		response Revision
+	)
+
+	if err := collection.FindOne(ctx, filter, opts).Decode(&response); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return response.Version, nil
+}
+
+func (r *revisionRepository) getRevisions(ctx context.Context, entityType, code string) ([]Revision, error) {
+	var (
+		collection = r.db.Collection(revisionsCollection)
+		filter     = bson.M{"entity_type": entityType, "code": code}
+		opts       = options.Find().
+				SetSort(bson.M{"version": -1}).
+				SetProjection(bson.M{"snapshot": 0})
+		result []Revision
+	)
+
+	cur, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *revisionRepository) getRevision(ctx context.Context, entityType, code string, version int) (Revision, error) {
+	var (
+		collection = r.db.Collection(revisionsCollection)
+		filter     = bson.M{"entity_type": entityType, "code": code, "version": version}
+		response   Revision
+	)
+
+	if err := collection.FindOne(ctx, filter).Decode(&response); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return Revision{}, ErrRevisionNotFound
+		}
+		return Revision{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,169 @@
+package revisions
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+	"uiren/pkg/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package revisions
+
+type repository interface {
+	createRevision(ctx context.Context, revision Revision) (int, error)
+	getRevisions(ctx context.Context, entityType, code string) ([]Revision, error)
+	getRevision(ctx context.Context, entityType, code string, version int) (Revision, error)
+}
+
+type RevisionService struct {
+	repo repository
+}
+
+func NewRevisionService(repo repository) *RevisionService {
+	return &RevisionService{
+		repo: repo,
+	}
+}
+
+// SaveRevision stores document as the next revision of entity and returns its version
+func (s *RevisionService) SaveRevision(ctx context.Context, entityType, code string, document interface{}) (int, error) {
+	logger.Info("RevisionService.SaveRevision new request")
+
+	if !isValidEntityType(entityType) {
+		return 0, ErrInvalidEntityType
+	}
+
+	snapshot, err := toSnapshot(document)
+	if err != nil {
+		logger.Error("RevisionService.SaveRevision toSnapshot: ", err)
+		return 0, err
+	}
+
+	version, err := s.repo.createRevision(ctx, Revision{
+		EntityType: entityType,
+		Code:       code,
+		Snapshot:   snapshot,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		logger.Error("RevisionService.SaveRevision repo.createRevision: ", err)
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (s *RevisionService) GetRevisions(ctx context.Context, entityType, code string) ([]Revision, error) {
+	logger.Info("RevisionService.GetRevisions new request")
+
+	if !isValidEntityType(entityType) {
+		return nil, ErrInvalidEntityType
+	}
+
+	revisions, err := s.repo.getRevisions(ctx, entityType, code)
+	if err != nil {
+		logger.Error("RevisionService.GetRevisions repo.getRevisions: ", err)
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (s *RevisionService) GetRevision(ctx context.Context, entityType, code string, version int) (Revision, error) {
+	logger.Info("RevisionService.GetRevision new request")
+
+	if !isValidEntityType(entityType) {
+		return Revision{}, ErrInvalidEntityType
+	}
+	if version <= 0 {
+		return Revision{}, ErrInvalidVersion
+	}
+
+	revision, err := s.repo.getRevision(ctx, entityType, code, version)
+	if err != nil {
+		logger.Error("RevisionService.GetRevision repo.getRevision: ", err)
+		return Revision{}, err
+	}
+
+	return revision, nil
+}
+
+func (s *RevisionService) DiffRevisions(ctx context.Context, entityType, code string, fromVersion, toVersion int) (RevisionDiff, error) {
+	logger.Info("RevisionService.DiffRevisions new request")
+
+	from, err := s.GetRevision(ctx, entityType, code, fromVersion)
+	if err != nil {
+		logger.Error("RevisionService.DiffRevisions GetRevision(from): ", err)
+		return RevisionDiff{}, err
+	}
+
+	to, err := s.GetRevision(ctx, entityType, code, toVersion)
+	if err != nil {
+		logger.Error("RevisionService.DiffRevisions GetRevision(to): ", err)
+		return RevisionDiff{}, err
+	}
+
+	return RevisionDiff{
+		EntityType:  entityType,
+		Code:        code,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Changes:     diffSnapshots(from.Snapshot, to.Snapshot),
+	}, nil
+}
+
+func isValidEntityType(entityType string) bool {
+	switch entityType {
+	case EntityModule, EntityLesson, EntityExercise:
+		return true
+	}
+	return false
+}
+
+func toSnapshot(document interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot bson.M
+	if err := bson.Unmarshal(raw, &snapshot); err != nil {
+		return nil, err
+	}
+	delete(snapshot, "_id")
+
+	return snapshot, nil
+}
+
+func diffSnapshots(from, to bson.M) []FieldChange {
+	fields := make(map[string]struct{}, len(from)+len(to))
+	for field := range from {
+		fields[field] = struct{}{}
+	}
+	for field := range to {
+		fields[field] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(fields))
+	for field := range fields {
+		keys = append(keys, field)
+	}
+	sort.Strings(keys)
+
+	changes := make([]FieldChange, 0)
+	for _, field := range keys {
+		if reflect.DeepEqual(from[field], to[field]) {
+			continue
+		}
+		changes = append(changes, FieldChange{
+			Field: field,
+			From:  from[field],
+			To:    to[field],
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package revisions is a generated GoMock package.
+package revisions
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// createRevision mocks base method.
+func (m *Mockrepository) createRevision(ctx context.Context, revision Revision) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createRevision", ctx, revision)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createRevision indicates an expected call of createRevision.
+func (mr *MockrepositoryMockRecorder) createRevision(ctx, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createRevision", reflect.TypeOf((*Mockrepository)(nil).createRevision), ctx, revision)
+}
+
+// getRevision mocks base method.
+func (m *Mockrepository) getRevision(ctx context.Context, entityType, code string, version int) (Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getRevision", ctx, entityType, code, version)
+	ret0, _ := ret[0].(Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getRevision indicates an expected call of getRevision.
+func (mr *MockrepositoryMockRecorder) getRevision(ctx, entityType, code, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getRevision", reflect.TypeOf((*Mockrepository)(nil).getRevision), ctx, entityType, code, version)
+}
+
+// getRevisions mocks base method.
+func (m *Mockrepository) getRevisions(ctx context.Context, entityType, code string) ([]Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getRevisions", ctx, entityType, code)
+	ret0, _ := ret[0].([]Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getRevisions indicates an expected call of getRevisions.
+func (mr *MockrepositoryMockRecorder) getRevisions(ctx, entityType, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getRevisions", reflect.TypeOf((*Mockrepository)(nil).getRevisions), ctx, entityType, code)
+}
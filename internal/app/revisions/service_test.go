@@ -0,0 +1,132 @@
+package revisions
+
+import (
+	"context"
+	"testing"
+	"uiren/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+func Test_RevisionService_SaveRevision(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx  = context.TODO()
+		ctrl = gomock.NewController(t)
+		repo = NewMockrepository(ctrl)
+		srv  = NewRevisionService(repo)
+		code = "module1"
+		doc  = struct {
+			Code  string `bson:"code"`
+			Title string `bson:"title"`
+		}{Code: code, Title: "Module 1"}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().createRevision(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, revision Revision) (int, error) {
+			assert.Equal(t, EntityModule, revision.EntityType)
+			assert.Equal(t, code, revision.Code)
+			assert.Equal(t, bson.M{"code": code, "title": "Module 1"}, revision.Snapshot)
+			return 3, nil
+		})
+
+		version, err := srv.SaveRevision(ctx, EntityModule, code, doc)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, version)
+	})
+
+	t.Run("invalid entity type", func(t *testing.T) {
+		version, err := srv.SaveRevision(ctx, "course", code, doc)
+		assert.Equal(t, ErrInvalidEntityType, err)
+		assert.Equal(t, 0, version)
+	})
+
+	t.Run("repo fail", func(t *testing.T) {
+		repo.EXPECT().createRevision(ctx, gomock.Any()).Return(0, ErrVersionConflict)
+
+		version, err := srv.SaveRevision(ctx, EntityModule, code, doc)
+		assert.Equal(t, ErrVersionConflict, err)
+		assert.Equal(t, 0, version)
+	})
+}
+
+func Test_RevisionService_GetRevision(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx      = context.TODO()
+		ctrl     = gomock.NewController(t)
+		repo     = NewMockrepository(ctrl)
+		srv      = NewRevisionService(repo)
+		code     = "lesson1"
+		revision = Revision{EntityType: EntityLesson, Code: code, Version: 2}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().getRevision(ctx, EntityLesson, code, 2).Return(revision, nil)
+
+		result, err := srv.GetRevision(ctx, EntityLesson, code, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, revision, result)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := srv.GetRevision(ctx, EntityLesson, code, 0)
+		assert.Equal(t, ErrInvalidVersion, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().getRevision(ctx, EntityLesson, code, 5).Return(Revision{}, ErrRevisionNotFound)
+
+		_, err := srv.GetRevision(ctx, EntityLesson, code, 5)
+		assert.Equal(t, ErrRevisionNotFound, err)
+	})
+}
+
+func Test_RevisionService_DiffRevisions(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx  = context.TODO()
+		ctrl = gomock.NewController(t)
+		repo = NewMockrepository(ctrl)
+		srv  = NewRevisionService(repo)
+		code = "exercise1"
+		from = Revision{
+			EntityType: EntityExercise,
+			Code:       code,
+			Version:    1,
+			Snapshot:   bson.M{"code": code, "question": "old", "options": bson.A{"a", "b"}},
+		}
+		to = Revision{
+			EntityType: EntityExercise,
+			Code:       code,
+			Version:    2,
+			Snapshot:   bson.M{"code": code, "question": "new", "options": bson.A{"a", "b"}, "hint": "h"},
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().getRevision(ctx, EntityExercise, code, 1).Return(from, nil)
+		repo.EXPECT().getRevision(ctx, EntityExercise, code, 2).Return(to, nil)
+
+		diff, err := srv.DiffRevisions(ctx, EntityExercise, code, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []FieldChange{
+			{Field: "hint", From: nil, To: "h"},
+			{Field: "question", From: "old", To: "new"},
+		}, diff.Changes)
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		repo.EXPECT().getRevision(ctx, EntityExercise, code, 1).Return(from, nil)
+		repo.EXPECT().getRevision(ctx, EntityExercise, code, 9).Return(Revision{}, ErrRevisionNotFound)
+
+		_, err := srv.DiffRevisions(ctx, EntityExercise, code, 1, 9)
+		assert.Equal(t, ErrRevisionNotFound, err)
+	})
+}
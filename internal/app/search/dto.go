@@ -0,0 +1,38 @@
+package search
+
+const (
+	// IndexLanguage is the default_language of the text indexes in migration/mongo_indexes.js,
+	// queries are stemmed the same way as the indexed content or stemmed words never match
+	IndexLanguage = "russian"
+
+	DefaultLimit = 10
+	MaxLimit     = 50
+
+	maxQueryLength = 200
+)
+
+// Query is a search request, learners search only published content
+type Query struct {
+	Text          string
+	Limit         int
+	PublishedOnly bool
+}
+
+// Hit is a found module, lesson or exercise.
+// Lessons and Modules are the parents the hit belongs to.
+type Hit struct {
+	Code    string   `json:"code"`
+	Title   string   `json:"title"`
+	Snippet string   `json:"snippet,omitempty"`
+	Score   float64  `json:"score"`
+	Lessons []string `json:"lessons,omitempty"`
+	Modules []string `json:"modules,omitempty"`
+}
+
+// Results are ranked by text score inside every group
+type Results struct {
+	Query     string `json:"query"`
+	Modules   []Hit  `json:"modules"`
+	Lessons   []Hit  `json:"lessons"`
+	Exercises []Hit  `json:"exercises"`
+}
@@ -0,0 +1,9 @@
+package search
+
+import "errors"
+
+var (
+	ErrEmptyQuery   = errors.New("search query is empty")
+	ErrQueryTooLong = errors.New("search query is too long")
+	ErrInvalidLimit = errors.New("invalid limit")
+)
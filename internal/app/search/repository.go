@@ -0,0 +1,204 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"uiren/internal/app/publication"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	modulesCollection   = "modules"
+	lessonsCollection   = "lessons"
+	exercisesCollection = "exercises"
+)
+
+type searchRepository struct {
+	db *mongo.Database
+}
+
+func NewSearchRepository(db *mongo.Database) *searchRepository {
+	return &searchRepository{
+		db: db,
+	}
+}
+
+type moduleDocument struct {
+	Code        string  `bson:"code"`
+	Title       string  `bson:"title"`
+	Description string  `bson:"description"`
+	Score       float64 `bson:"score"`
+}
+
+type lessonDocument struct {
+	Code  string  `bson:"code"`
+	Title string  `bson:"title"`
+	Score float64 `bson:"score"`
+}
+
+type exerciseDocument struct {
+	Code     string   `bson:"code"`
+	Question string   `bson:"question"`
+	Hints    []string `bson:"hints"`
+	Score    float64  `bson:"score"`
+}
+
+func (r *searchRepository) searchModules(ctx context.Context, query Query) ([]Hit, error) {
+	var (
+		collection = r.db.Collection(modulesCollection)
+		documents  []moduleDocument
+	)
+
+	if err := r.find(ctx, collection, query, bson.M{"code": 1, "title": 1, "description": 1}, &documents); err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(documents))
+	for _, document := range documents {
+		hits = append(hits, Hit{
+			Code:    document.Code,
+			Title:   document.Title,
+			Snippet: document.Description,
+			Score:   document.Score,
+		})
+	}
+
+	return hits, nil
+}
+
+func (r *searchRepository) searchLessons(ctx context.Context, query Query) ([]Hit, error) {
+	var (
+		collection = r.db.Collection(lessonsCollection)
+		documents  []lessonDocument
+	)
+
+	if err := r.find(ctx, collection, query, bson.M{"code": 1, "title": 1}, &documents); err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(documents))
+	for _, document := range documents {
+		hits = append(hits, Hit{
+			Code:  document.Code,
+			Title: document.Title,
+			Score: document.Score,
+		})
+	}
+
+	return hits, nil
+}
+
+func (r *searchRepository) searchExercises(ctx context.Context, query Query) ([]Hit, error) {
+	var (
+		collection = r.db.Collection(exercisesCollection)
+		documents  []exerciseDocument
+	)
+
+	if err := r.find(ctx, collection, query, bson.M{"code": 1, "question": 1, "hints": 1}, &documents); err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(documents))
+	for _, document := range documents {
+		hits = append(hits, Hit{
+			Code:    document.Code,
+			Title:   document.Question,
+			Snippet: strings.Join(document.Hints, ", "),
+			Score:   document.Score,
+		})
+	}
+
+	return hits, nil
+}
+
+// find runs the text search over the collection text index, best matches go first
+func (r *searchRepository) find(ctx context.Context, collection *mongo.Collection, query Query, projection bson.M, result interface{}) error {
+	var (
+		filter = textFilter(query)
+		score  = bson.M{"$meta": "textScore"}
+	)
+
+	projection["score"] = score
+
+	opts := options.Find().
+		SetProjection(projection).
+		SetSort(bson.D{{Key: "score", Value: score}}).
+		SetLimit(int64(query.Limit))
+
+	cur, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	return cur.All(ctx, result)
+}
+
+// textFilter searches in the language of the index, every document is stemmed as IndexLanguage
+func textFilter(query Query) bson.M {
+	filter := bson.M{
+		"$text":      bson.M{"$search": query.Text, "$language": IndexLanguage},
+		"deleted_at": nil,
+	}
+	if query.PublishedOnly {
+		filter["status"] = publication.StatusPublished
+	}
+	return filter
+}
+
+// getModuleCodesByLessons returns codes of modules containing every lesson
+func (r *searchRepository) getModuleCodesByLessons(ctx context.Context, lessonCodes []string, publishedOnly bool) (map[string][]string, error) {
+	return r.getParents(ctx, r.db.Collection(modulesCollection), "lessons", lessonCodes, publishedOnly)
+}
+
+// getLessonCodesByExercises returns codes of lessons containing every exercise
+func (r *searchRepository) getLessonCodesByExercises(ctx context.Context, exerciseCodes []string, publishedOnly bool) (map[string][]string, error) {
+	return r.getParents(ctx, r.db.Collection(lessonsCollection), "exercises", exerciseCodes, publishedOnly)
+}
+
+func (r *searchRepository) getParents(ctx context.Context, collection *mongo.Collection, listField string, codes []string, publishedOnly bool) (map[string][]string, error) {
+	var (
+		filter = bson.M{
+			listField:    bson.M{"$in": codes},
+			"deleted_at": nil,
+		}
+		opts    = options.Find().SetProjection(bson.M{"code": 1, listField: 1, "_id": 0})
+		parents = make(map[string][]string, len(codes))
+	)
+
+	if publishedOnly {
+		filter["status"] = publication.StatusPublished
+	}
+
+	cur, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	wanted := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		wanted[code] = struct{}{}
+	}
+
+	for cur.Next(ctx) {
+		var parent bson.M
+		if err := cur.Decode(&parent); err != nil {
+			return nil, err
+		}
+
+		parentCode, _ := parent["code"].(string)
+		children, _ := parent[listField].(bson.A)
+		for _, child := range children {
+			code, _ := child.(string)
+			if _, ok := wanted[code]; ok {
+				parents[code] = append(parents[code], parentCode)
+			}
+		}
+	}
+
+	return parents, cur.Err()
+}
@@ -0,0 +1,27 @@
+package search
+
+import (
+	"testing"
+	"uiren/internal/app/publication"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_textFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("queries are stemmed in the index language", func(t *testing.T) {
+		filter := textFilter(Query{Text: "greetings"})
+		assert.Equal(t, bson.M{
+			"$text":      bson.M{"$search": "greetings", "$language": IndexLanguage},
+			"deleted_at": nil,
+		}, filter)
+		assert.Equal(t, "russian", IndexLanguage)
+	})
+
+	t.Run("published only", func(t *testing.T) {
+		filter := textFilter(Query{Text: "сәлем", PublishedOnly: true})
+		assert.Equal(t, publication.StatusPublished, filter["status"])
+	})
+}
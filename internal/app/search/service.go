@@ -0,0 +1,142 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"uiren/pkg/logger"
+	"unicode/utf8"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package search
+
+type repository interface {
+	searchModules(ctx context.Context, query Query) ([]Hit, error)
+	searchLessons(ctx context.Context, query Query) ([]Hit, error)
+	searchExercises(ctx context.Context, query Query) ([]Hit, error)
+	getModuleCodesByLessons(ctx context.Context, lessonCodes []string, publishedOnly bool) (map[string][]string, error)
+	getLessonCodesByExercises(ctx context.Context, exerciseCodes []string, publishedOnly bool) (map[string][]string, error)
+}
+
+type SearchService struct {
+	repo repository
+}
+
+func NewSearchService(repo repository) *SearchService {
+	return &SearchService{
+		repo: repo,
+	}
+}
+
+// Search finds modules, lessons and exercises matching the query text.
+// Lessons and exercises are linked back to the modules they are used in.
+func (s *SearchService) Search(ctx context.Context, query Query) (Results, error) {
+	logger.Info("SearchService.Search new request")
+
+	query, err := normalizeQuery(query)
+	if err != nil {
+		return Results{}, err
+	}
+
+	modules, err := s.repo.searchModules(ctx, query)
+	if err != nil {
+		logger.Error("SearchService.Search repo.searchModules: ", err)
+		return Results{}, err
+	}
+
+	lessons, err := s.repo.searchLessons(ctx, query)
+	if err != nil {
+		logger.Error("SearchService.Search repo.searchLessons: ", err)
+		return Results{}, err
+	}
+
+	exercises, err := s.repo.searchExercises(ctx, query)
+	if err != nil {
+		logger.Error("SearchService.Search repo.searchExercises: ", err)
+		return Results{}, err
+	}
+
+	if err := s.linkParents(ctx, lessons, exercises, query.PublishedOnly); err != nil {
+		logger.Error("SearchService.Search s.linkParents: ", err)
+		return Results{}, err
+	}
+
+	return Results{
+		Query:     query.Text,
+		Modules:   modules,
+		Lessons:   lessons,
+		Exercises: exercises,
+	}, nil
+}
+
+// linkParents fills lessons of found exercises and modules of found lessons and exercises
+func (s *SearchService) linkParents(ctx context.Context, lessons, exercises []Hit, publishedOnly bool) error {
+	var lessonCodes []string
+	for _, lesson := range lessons {
+		lessonCodes = append(lessonCodes, lesson.Code)
+	}
+
+	if len(exercises) > 0 {
+		exerciseCodes := make([]string, 0, len(exercises))
+		for _, exercise := range exercises {
+			exerciseCodes = append(exerciseCodes, exercise.Code)
+		}
+
+		exerciseLessons, err := s.repo.getLessonCodesByExercises(ctx, exerciseCodes, publishedOnly)
+		if err != nil {
+			return err
+		}
+
+		for i := range exercises {
+			exercises[i].Lessons = exerciseLessons[exercises[i].Code]
+			for _, code := range exercises[i].Lessons {
+				if !slices.Contains(lessonCodes, code) {
+					lessonCodes = append(lessonCodes, code)
+				}
+			}
+		}
+	}
+
+	if len(lessonCodes) == 0 {
+		return nil
+	}
+
+	lessonModules, err := s.repo.getModuleCodesByLessons(ctx, lessonCodes, publishedOnly)
+	if err != nil {
+		return err
+	}
+
+	for i := range lessons {
+		lessons[i].Modules = lessonModules[lessons[i].Code]
+	}
+	for i := range exercises {
+		for _, lesson := range exercises[i].Lessons {
+			for _, module := range lessonModules[lesson] {
+				if !slices.Contains(exercises[i].Modules, module) {
+					exercises[i].Modules = append(exercises[i].Modules, module)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func normalizeQuery(query Query) (Query, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return Query{}, ErrEmptyQuery
+	}
+	if utf8.RuneCountInString(query.Text) > maxQueryLength {
+		return Query{}, ErrQueryTooLong
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultLimit
+	}
+	if query.Limit < 0 || query.Limit > MaxLimit {
+		return Query{}, ErrInvalidLimit
+	}
+
+	return query, nil
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package search is a generated GoMock package.
+package search
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// getLessonCodesByExercises mocks base method.
+func (m *Mockrepository) getLessonCodesByExercises(ctx context.Context, exerciseCodes []string, publishedOnly bool) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getLessonCodesByExercises", ctx, exerciseCodes, publishedOnly)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getLessonCodesByExercises indicates an expected call of getLessonCodesByExercises.
+func (mr *MockrepositoryMockRecorder) getLessonCodesByExercises(ctx, exerciseCodes, publishedOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getLessonCodesByExercises", reflect.TypeOf((*Mockrepository)(nil).getLessonCodesByExercises), ctx, exerciseCodes, publishedOnly)
+}
+
+// getModuleCodesByLessons mocks base method.
+func (m *Mockrepository) getModuleCodesByLessons(ctx context.Context, lessonCodes []string, publishedOnly bool) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getModuleCodesByLessons", ctx, lessonCodes, publishedOnly)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getModuleCodesByLessons indicates an expected call of getModuleCodesByLessons.
+func (mr *MockrepositoryMockRecorder) getModuleCodesByLessons(ctx, lessonCodes, publishedOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getModuleCodesByLessons", reflect.TypeOf((*Mockrepository)(nil).getModuleCodesByLessons), ctx, lessonCodes, publishedOnly)
+}
+
+// searchExercises mocks base method.
+func (m *Mockrepository) searchExercises(ctx context.Context, query Query) ([]Hit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "searchExercises", ctx, query)
+	ret0, _ := ret[0].([]Hit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// searchExercises indicates an expected call of searchExercises.
+func (mr *MockrepositoryMockRecorder) searchExercises(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "searchExercises", reflect.TypeOf((*Mockrepository)(nil).searchExercises), ctx, query)
+}
+
+// searchLessons mocks base method.
+func (m *Mockrepository) searchLessons(ctx context.Context, query Query) ([]Hit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "searchLessons", ctx, query)
+	ret0, _ := ret[0].([]Hit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// searchLessons indicates an expected call of searchLessons.
+func (mr *MockrepositoryMockRecorder) searchLessons(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "searchLessons", reflect.TypeOf((*Mockrepository)(nil).searchLessons), ctx, query)
+}
+
+// searchModules mocks base method.
+func (m *Mockrepository) searchModules(ctx context.Context, query Query) ([]Hit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "searchModules", ctx, query)
+	ret0, _ := ret[0].([]Hit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// searchModules indicates an expected call of searchModules.
+func (mr *MockrepositoryMockRecorder) searchModules(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "searchModules", reflect.TypeOf((*Mockrepository)(nil).searchModules), ctx, query)
+}
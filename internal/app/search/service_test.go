@@ -0,0 +1,82 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"uiren/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+func Test_SearchService_Search(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx   = context.TODO()
+		ctrl  = gomock.NewController(t)
+		repo  = NewMockrepository(ctrl)
+		srv   = NewSearchService(repo)
+		query = Query{Text: "сәлем", Limit: DefaultLimit, PublishedOnly: true}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().searchModules(ctx, query).Return([]Hit{{Code: "basics", Score: 1.5}}, nil)
+		repo.EXPECT().searchLessons(ctx, query).Return([]Hit{{Code: "greetings", Score: 2}}, nil)
+		repo.EXPECT().searchExercises(ctx, query).Return([]Hit{{Code: "ex1", Score: 3}, {Code: "ex2", Score: 1}}, nil)
+		repo.EXPECT().getLessonCodesByExercises(ctx, []string{"ex1", "ex2"}, true).Return(map[string][]string{
+			"ex1": {"greetings", "review"},
+		}, nil)
+		repo.EXPECT().getModuleCodesByLessons(ctx, []string{"greetings", "review"}, true).Return(map[string][]string{
+			"greetings": {"basics"},
+			"review":    {"basics", "exam"},
+		}, nil)
+
+		results, err := srv.Search(ctx, Query{Text: "  сәлем ", PublishedOnly: true})
+		assert.NoError(t, err)
+		assert.Equal(t, Results{
+			Query:   "сәлем",
+			Modules: []Hit{{Code: "basics", Score: 1.5}},
+			Lessons: []Hit{{Code: "greetings", Score: 2, Modules: []string{"basics"}}},
+			Exercises: []Hit{
+				{Code: "ex1", Score: 3, Lessons: []string{"greetings", "review"}, Modules: []string{"basics", "exam"}},
+				{Code: "ex2", Score: 1},
+			},
+		}, results)
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		repo.EXPECT().searchModules(ctx, query).Return(nil, nil)
+		repo.EXPECT().searchLessons(ctx, query).Return(nil, nil)
+		repo.EXPECT().searchExercises(ctx, query).Return(nil, nil)
+
+		results, err := srv.Search(ctx, Query{Text: "сәлем", PublishedOnly: true})
+		assert.NoError(t, err)
+		assert.Equal(t, Results{Query: "сәлем"}, results)
+	})
+
+	t.Run("repo failed", func(t *testing.T) {
+		errRepo := errors.New("repo err")
+		repo.EXPECT().searchModules(ctx, query).Return(nil, nil)
+		repo.EXPECT().searchLessons(ctx, query).Return(nil, errRepo)
+
+		_, err := srv.Search(ctx, Query{Text: "сәлем", PublishedOnly: true})
+		assert.Equal(t, errRepo, err)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := srv.Search(ctx, Query{Text: "   "})
+		assert.Equal(t, ErrEmptyQuery, err)
+
+		_, err = srv.Search(ctx, Query{Text: strings.Repeat("ә", maxQueryLength+1)})
+		assert.Equal(t, ErrQueryTooLong, err)
+
+		_, err = srv.Search(ctx, Query{Text: "hello", Limit: MaxLimit + 1})
+		assert.Equal(t, ErrInvalidLimit, err)
+	})
+}
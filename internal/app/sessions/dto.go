@@ -0,0 +1,77 @@
+package sessions
+
+import (
+	"time"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/hearts"
+)
+
+const (
+	StatusActive    = "active"
+	StatusFinished  = "finished"
+	StatusAbandoned = "abandoned"
+)
+
+// Session is one pass of a lesson. Exercises are the codes of the lesson at the start,
+// so editing the lesson doesn't shift the position of started sessions
+type Session struct {
+	ID         string   `json:"id"`
+	UserID     string   `json:"-"`
+	LessonCode string   `json:"lesson_code"`
+	Exercises  []string `json:"exercises"`
+	// Practice doesn't cost hearts and earns them back, it is allowed without hearts
+	Practice bool `json:"practice"`
+	// Position is the index of the exercise to answer, it equals len(Exercises) when all are answered
+	Position int `json:"position"`
+	// HintsUsed counts hints taken on the current exercise
+	HintsUsed    int    `json:"hints_used"`
+	HeartsLost   int    `json:"hearts_lost"`
+	HeartsEarned int    `json:"hearts_earned"`
+	Status       string `json:"status"`
+	// ExerciseStartedAt is when the current exercise was shown, it is reset on resume
+	// so the time the app was closed doesn't count
+	ExerciseStartedAt time.Time  `json:"-"`
+	StartedAt         time.Time  `json:"started_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+	Summary           *Summary   `json:"summary,omitempty"`
+}
+
+// SessionState is the session with the exercise to answer
+type SessionState struct {
+	Session
+	Total int `json:"total"`
+	// Current has only the hints taken so far, it is empty when all exercises are answered
+	Current        *exercises.LearnerExercise `json:"current,omitempty"`
+	HintsAvailable int                        `json:"hints_available"`
+}
+
+type SessionAnswer struct {
+	Position     int       `json:"position"`
+	ExerciseCode string    `json:"exercise_code"`
+	Correct      bool      `json:"correct"`
+	Score        float64   `json:"score"`
+	HintsUsed    int       `json:"hints_used"`
+	TimeSpentMs  int64     `json:"time_spent_ms"`
+	AnsweredAt   time.Time `json:"answered_at"`
+}
+
+type AnswerResult struct {
+	exercises.GradeResult
+	Session SessionState `json:"session"`
+	// Hearts are the user's hearts after the answer when they are known
+	Hearts *hearts.Hearts `json:"hearts,omitempty"`
+}
+
+type Summary struct {
+	Correct int `json:"correct"`
+	Total   int `json:"total"`
+	// Accuracy is the average score, partially correct answers count by their score
+	Accuracy     float64         `json:"accuracy"`
+	XP           int             `json:"xp"`
+	TimeSpentMs  int64           `json:"time_spent_ms"`
+	HintsUsed    int             `json:"hints_used"`
+	HeartsLost   int             `json:"hearts_lost"`
+	HeartsEarned int             `json:"hearts_earned"`
+	Answers      []SessionAnswer `json:"answers"`
+}
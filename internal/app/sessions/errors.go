@@ -0,0 +1,15 @@
+package sessions
+
+import "errors"
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionNotActive    = errors.New("session is finished or abandoned")
+	ErrSessionChanged      = errors.New("session was changed on another device")
+	ErrSessionCompleted    = errors.New("all exercises of the session are answered")
+	ErrSessionNotCompleted = errors.New("not all exercises of the session are answered")
+	ErrNoMoreHints         = errors.New("no more hints")
+	ErrEmptyLesson         = errors.New("lesson has no exercises")
+	ErrNoHearts            = errors.New("no hearts left, practice to earn them back")
+	ErrSessionOutdated     = errors.New("exercise of the session was removed from the lesson, start the lesson again")
+)
@@ -0,0 +1,204 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepository(db *pgxpool.Pool) *repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const sessionColumns = `id, user_id, lesson_code, exercises, practice, position, hints_used, hearts_lost, hearts_earned,
+	status, exercise_started_at, started_at, updated_at, finished_at, summary`
+
+// createSession fails with ErrSessionChanged when another device started a session at the same time,
+// a user has one active session
+func (r *repository) createSession(ctx context.Context, session Session) (string, error) {
+	var (
+		query = `
+		INSERT INTO lesson_sessions (user_id, lesson_code, exercises, practice, status, exercise_started_at, started_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id;
+		`
+		id string
+	)
+
+	err := r.db.QueryRow(ctx, query, session.UserID, session.LessonCode, session.Exercises, session.Practice, session.Status,
+		session.ExerciseStartedAt, session.StartedAt, session.UpdatedAt).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return "", ErrSessionChanged
+		}
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (r *repository) getSession(ctx context.Context, id string) (Session, error) {
+	var (
+		query = `
+		SELECT ` + sessionColumns + ` FROM lesson_sessions WHERE id = $1;
+		`
+	)
+
+	return scanSession(r.db.QueryRow(ctx, query, id))
+}
+
+func (r *repository) getActiveSession(ctx context.Context, userID string) (Session, error) {
+	var (
+		query = `
+		SELECT ` + sessionColumns + ` FROM lesson_sessions WHERE user_id = $1 AND status = 'active';
+		`
+	)
+
+	return scanSession(r.db.QueryRow(ctx, query, userID))
+}
+
+func (r *repository) abandonSession(ctx context.Context, id string, now time.Time) error {
+	var (
+		query = `
+		UPDATE lesson_sessions SET status = 'abandoned', updated_at = $2
+		WHERE id = $1 AND status = 'active';
+		`
+	)
+
+	_, err := r.db.Exec(ctx, query, id, now)
+	return err
+}
+
+// restartExercise, useHint and recordAnswer change the session only at the position it was read at,
+// so two devices can't answer the same exercise
+func (r *repository) restartExercise(ctx context.Context, id string, position int, now time.Time) error {
+	var (
+		query = `
+		UPDATE lesson_sessions SET exercise_started_at = $3, updated_at = $3
+		WHERE id = $1 AND status = 'active' AND position = $2;
+		`
+	)
+
+	res, err := r.db.Exec(ctx, query, id, position, now)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrSessionChanged
+	}
+	return nil
+}
+
+func (r *repository) useHint(ctx context.Context, id string, position, hintsUsed int, now time.Time) error {
+	var (
+		query = `
+		UPDATE lesson_sessions SET hints_used = hints_used + 1, updated_at = $4
+		WHERE id = $1 AND status = 'active' AND position = $2 AND hints_used = $3;
+		`
+	)
+
+	res, err := r.db.Exec(ctx, query, id, position, hintsUsed, now)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrSessionChanged
+	}
+	return nil
+}
+
+// recordAnswer moves the session to the next exercise and saves the answer in one statement
+func (r *repository) recordAnswer(ctx context.Context, id string, answer SessionAnswer, heartsLost, heartsEarned int) error {
+	var (
+		query = `
+		WITH moved AS (
+			UPDATE lesson_sessions
+			SET position = position + 1, hints_used = 0, hearts_lost = hearts_lost + $8, hearts_earned = hearts_earned + $10,
+				exercise_started_at = $7, updated_at = $7
+			WHERE id = $1 AND status = 'active' AND position = $2
+			RETURNING id
+		)
+		INSERT INTO lesson_session_answers (session_id, position, exercise_code, correct, score, hints_used, time_spent_ms, answered_at)
+		SELECT id, $2, $3, $4, $5, $6, $9, $7 FROM moved;
+		`
+	)
+
+	res, err := r.db.Exec(ctx, query, id, answer.Position, answer.ExerciseCode, answer.Correct, answer.Score,
+		answer.HintsUsed, answer.AnsweredAt, heartsLost, answer.TimeSpentMs, heartsEarned)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrSessionChanged
+	}
+	return nil
+}
+
+func (r *repository) getAnswers(ctx context.Context, id string) ([]SessionAnswer, error) {
+	var (
+		query = `
+		SELECT position, exercise_code, correct, score, hints_used, time_spent_ms, answered_at
+		FROM lesson_session_answers
+		WHERE session_id = $1
+		ORDER BY position;
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []SessionAnswer
+	for rows.Next() {
+		var answer SessionAnswer
+		if err := rows.Scan(&answer.Position, &answer.ExerciseCode, &answer.Correct, &answer.Score,
+			&answer.HintsUsed, &answer.TimeSpentMs, &answer.AnsweredAt); err != nil {
+			return nil, err
+		}
+		answers = append(answers, answer)
+	}
+
+	return answers, rows.Err()
+}
+
+func (r *repository) finishSession(ctx context.Context, id string, summary Summary, now time.Time) error {
+	var (
+		query = `
+		UPDATE lesson_sessions SET status = 'finished', summary = $2, finished_at = $3, updated_at = $3
+		WHERE id = $1 AND status = 'active';
+		`
+	)
+
+	res, err := r.db.Exec(ctx, query, id, summary, now)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrSessionChanged
+	}
+	return nil
+}
+
+func scanSession(row pgx.Row) (Session, error) {
+	var session Session
+	err := row.Scan(&session.ID, &session.UserID, &session.LessonCode, &session.Exercises, &session.Practice, &session.Position,
+		&session.HintsUsed, &session.HeartsLost, &session.HeartsEarned, &session.Status, &session.ExerciseStartedAt,
+		&session.StartedAt, &session.UpdatedAt, &session.FinishedAt, &session.Summary)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Session{}, ErrSessionNotFound
+	}
+	return session, err
+}
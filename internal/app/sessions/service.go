@@ -0,0 +1,508 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/hearts"
+	"uiren/internal/app/lessons"
+	"uiren/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+//go:generate mockgen -source service.go -destination service_mock.go -package sessions
+
+type sessionRepository interface {
+	createSession(ctx context.Context, session Session) (string, error)
+	getSession(ctx context.Context, id string) (Session, error)
+	getActiveSession(ctx context.Context, userID string) (Session, error)
+	abandonSession(ctx context.Context, id string, now time.Time) error
+	restartExercise(ctx context.Context, id string, position int, now time.Time) error
+	useHint(ctx context.Context, id string, position, hintsUsed int, now time.Time) error
+	recordAnswer(ctx context.Context, id string, answer SessionAnswer, heartsLost, heartsEarned int) error
+	getAnswers(ctx context.Context, id string) ([]SessionAnswer, error)
+	finishSession(ctx context.Context, id string, summary Summary, now time.Time) error
+}
+
+// contentService gives the published content, data package implements it
+type contentService interface {
+	GetPublicLesson(ctx context.Context, code string) (lessons.LessonDTO, error)
+	GetPublicExercise(ctx context.Context, code string) (exercises.Exercise, error)
+}
+
+type exerciseService interface {
+	GradeAnswer(ctx context.Context, code string, answer exercises.Answer) (exercises.GradeResult, error)
+	ToLearner(exercise exercises.Exercise) (exercises.LearnerExercise, error)
+	ScoreXP(score float64) int
+}
+
+type progressService interface {
+	CompleteLesson(ctx context.Context, sessionID, userID, lessonCode string, xp int) error
+}
+
+type heartsService interface {
+	GetHearts(ctx context.Context, userID string) (hearts.Hearts, error)
+	LoseHeart(ctx context.Context, userID string) (hearts.Hearts, error)
+	EarnHeart(ctx context.Context, userID string) (hearts.Hearts, error)
+}
+
+// SessionService keeps the learner's pass of a lesson on the server,
+// so an unfinished lesson can be resumed on any device
+type SessionService struct {
+	repo            sessionRepository
+	contentService  contentService
+	exerciseService exerciseService
+	progressService progressService
+	heartsService   heartsService
+	now             func() time.Time
+}
+
+func NewSessionService(repo sessionRepository, contentService contentService, exerciseService exerciseService) *SessionService {
+	return &SessionService{
+		repo:            repo,
+		contentService:  contentService,
+		exerciseService: exerciseService,
+		// timestamp columns keep the wall clock, so times are saved in UTC
+		now: func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithProgressService adds XP of finished sessions to the user progress
+func (s *SessionService) WithProgressService(progressService progressService) {
+	s.progressService = progressService
+}
+
+// WithHeartsService makes mistakes in lessons cost hearts, without hearts only practice can be started
+func (s *SessionService) WithHeartsService(heartsService heartsService) {
+	s.heartsService = heartsService
+}
+
+// StartSession starts the lesson or resumes its unfinished session,
+// the unfinished session of another lesson is abandoned
+func (s *SessionService) StartSession(ctx context.Context, userID, lessonCode string) (SessionState, error) {
+	logger.Info("SessionService.StartSession new request")
+
+	state, err := s.start(ctx, userID, lessonCode, false)
+	if err != nil {
+		logger.Error("SessionService.StartSession start: ", err)
+		return SessionState{}, err
+	}
+	return state, nil
+}
+
+// StartPractice starts the lesson as practice, it doesn't cost hearts and correct answers earn them back
+func (s *SessionService) StartPractice(ctx context.Context, userID, lessonCode string) (SessionState, error) {
+	logger.Info("SessionService.StartPractice new request")
+
+	state, err := s.start(ctx, userID, lessonCode, true)
+	if err != nil {
+		logger.Error("SessionService.StartPractice start: ", err)
+		return SessionState{}, err
+	}
+	return state, nil
+}
+
+func (s *SessionService) start(ctx context.Context, userID, lessonCode string, practice bool) (SessionState, error) {
+	lesson, err := s.contentService.GetPublicLesson(ctx, lessonCode)
+	if err != nil {
+		return SessionState{}, err
+	}
+	if len(lesson.Exercises) == 0 {
+		return SessionState{}, ErrEmptyLesson
+	}
+
+	active, err := s.repo.getActiveSession(ctx, userID)
+	found := err == nil
+	if err != nil && err != ErrSessionNotFound {
+		return SessionState{}, err
+	}
+	if found && active.LessonCode == lessonCode && active.Practice == practice {
+		state, err := s.resume(ctx, active)
+		if err != ErrSessionOutdated {
+			return state, err
+		}
+		// the outdated session is abandoned already, the lesson starts again as it is now
+		found = false
+	}
+
+	// the unfinished session is kept when the new lesson can't be started
+	if !practice {
+		if _, err := s.checkHearts(ctx, userID); err != nil {
+			return SessionState{}, err
+		}
+	}
+
+	if found {
+		if err := s.repo.abandonSession(ctx, active.ID, s.now()); err != nil {
+			return SessionState{}, err
+		}
+	}
+
+	now := s.now()
+	session := Session{
+		UserID:            userID,
+		LessonCode:        lessonCode,
+		Exercises:         make([]string, 0, len(lesson.Exercises)),
+		Practice:          practice,
+		Status:            StatusActive,
+		ExerciseStartedAt: now,
+		StartedAt:         now,
+		UpdatedAt:         now,
+	}
+	for _, exercise := range lesson.Exercises {
+		session.Exercises = append(session.Exercises, exercise.Code)
+	}
+
+	session.ID, err = s.repo.createSession(ctx, session)
+	if err != nil {
+		return SessionState{}, err
+	}
+
+	return s.state(ctx, session)
+}
+
+// GetActiveSession returns the unfinished session of the user to offer resuming it
+func (s *SessionService) GetActiveSession(ctx context.Context, userID string) (SessionState, error) {
+	logger.Info("SessionService.GetActiveSession new request")
+
+	session, err := s.repo.getActiveSession(ctx, userID)
+	if err != nil {
+		logger.Error("SessionService.GetActiveSession repo.getActiveSession: ", err)
+		return SessionState{}, err
+	}
+
+	return s.state(ctx, session)
+}
+
+func (s *SessionService) GetSession(ctx context.Context, userID, id string) (SessionState, error) {
+	logger.Info("SessionService.GetSession new request")
+
+	session, err := s.getSession(ctx, userID, id)
+	if err != nil {
+		logger.Error("SessionService.GetSession getSession: ", err)
+		return SessionState{}, err
+	}
+
+	return s.state(ctx, session)
+}
+
+// ResumeSession continues the session on this device, time of the current exercise starts again
+func (s *SessionService) ResumeSession(ctx context.Context, userID, id string) (SessionState, error) {
+	logger.Info("SessionService.ResumeSession new request")
+
+	session, err := s.getActiveSessionByID(ctx, userID, id)
+	if err != nil {
+		logger.Error("SessionService.ResumeSession getActiveSessionByID: ", err)
+		return SessionState{}, err
+	}
+
+	return s.resume(ctx, session)
+}
+
+// TakeHint shows the next hint of the current exercise
+func (s *SessionService) TakeHint(ctx context.Context, userID, id string) (SessionState, error) {
+	logger.Info("SessionService.TakeHint new request")
+
+	session, err := s.getActiveSessionByID(ctx, userID, id)
+	if err != nil {
+		logger.Error("SessionService.TakeHint getActiveSessionByID: ", err)
+		return SessionState{}, err
+	}
+	if session.Position >= len(session.Exercises) {
+		return SessionState{}, ErrSessionCompleted
+	}
+
+	exercise, err := s.contentService.GetPublicExercise(ctx, session.Exercises[session.Position])
+	if errors.Is(err, exercises.ErrNotFound) {
+		return SessionState{}, s.outdate(ctx, session)
+	}
+	if err != nil {
+		logger.Error("SessionService.TakeHint contentService.GetPublicExercise: ", err)
+		return SessionState{}, err
+	}
+	if session.HintsUsed >= len(exercise.Hints) {
+		return SessionState{}, ErrNoMoreHints
+	}
+
+	now := s.now()
+	if err := s.repo.useHint(ctx, session.ID, session.Position, session.HintsUsed, now); err != nil {
+		logger.Error("SessionService.TakeHint repo.useHint: ", err)
+		return SessionState{}, err
+	}
+	session.HintsUsed++
+	session.UpdatedAt = now
+
+	return s.state(ctx, session)
+}
+
+// SubmitAnswer grades the answer to the current exercise and moves to the next one,
+// an incorrect answer in a lesson costs a heart and a correct answer in practice earns one
+func (s *SessionService) SubmitAnswer(ctx context.Context, userID, id string, answer exercises.Answer) (AnswerResult, error) {
+	logger.Info("SessionService.SubmitAnswer new request")
+
+	session, err := s.getActiveSessionByID(ctx, userID, id)
+	if err != nil {
+		logger.Error("SessionService.SubmitAnswer getActiveSessionByID: ", err)
+		return AnswerResult{}, err
+	}
+	if session.Position >= len(session.Exercises) {
+		return AnswerResult{}, ErrSessionCompleted
+	}
+
+	var current *hearts.Hearts
+	if !session.Practice {
+		current, err = s.checkHearts(ctx, userID)
+		if err != nil {
+			logger.Error("SessionService.SubmitAnswer checkHearts: ", err)
+			return AnswerResult{}, err
+		}
+	}
+
+	code := session.Exercises[session.Position]
+	result, err := s.exerciseService.GradeAnswer(ctx, code, answer)
+	if errors.Is(err, exercises.ErrNotFound) {
+		return AnswerResult{}, s.outdate(ctx, session)
+	}
+	if err != nil {
+		logger.Error("SessionService.SubmitAnswer exerciseService.GradeAnswer: ", err)
+		return AnswerResult{}, err
+	}
+
+	now := s.now()
+	record := SessionAnswer{
+		Position:     session.Position,
+		ExerciseCode: code,
+		Correct:      result.Correct,
+		Score:        result.Score,
+		HintsUsed:    session.HintsUsed,
+		TimeSpentMs:  now.Sub(session.ExerciseStartedAt).Milliseconds(),
+		AnsweredAt:   now,
+	}
+	heartsLost, heartsEarned := 0, 0
+	switch {
+	case !session.Practice && !result.Correct:
+		heartsLost = 1
+	case session.Practice && result.Correct:
+		heartsEarned = 1
+	}
+
+	if err := s.repo.recordAnswer(ctx, session.ID, record, heartsLost, heartsEarned); err != nil {
+		logger.Error("SessionService.SubmitAnswer repo.recordAnswer: ", err)
+		return AnswerResult{}, err
+	}
+	session.Position++
+	session.HintsUsed = 0
+	session.HeartsLost += heartsLost
+	session.HeartsEarned += heartsEarned
+	session.ExerciseStartedAt = now
+	session.UpdatedAt = now
+
+	state, err := s.state(ctx, session)
+	if err != nil {
+		logger.Error("SessionService.SubmitAnswer state: ", err)
+		return AnswerResult{}, err
+	}
+
+	// the answer is saved already, so a failed hearts change doesn't fail it
+	if s.heartsService != nil && heartsLost+heartsEarned > 0 {
+		change := s.heartsService.LoseHeart
+		if heartsEarned > 0 {
+			change = s.heartsService.EarnHeart
+		}
+		changed, err := change(ctx, userID)
+		if err != nil {
+			logger.Error("SessionService.SubmitAnswer heartsService change: ", err)
+			current = nil
+		} else {
+			current = &changed
+		}
+	}
+
+	return AnswerResult{GradeResult: result, Session: state, Hearts: current}, nil
+}
+
+// FinishSession summarizes the session with every exercise answered and adds its XP to the progress.
+// The XP is added once per session, finishing a finished session retries adding it and returns its summary
+func (s *SessionService) FinishSession(ctx context.Context, userID, id string) (Summary, error) {
+	logger.Info("SessionService.FinishSession new request")
+
+	session, err := s.getSession(ctx, userID, id)
+	if err != nil {
+		logger.Error("SessionService.FinishSession getSession: ", err)
+		return Summary{}, err
+	}
+
+	var summary Summary
+	switch {
+	case session.Status == StatusFinished && session.Summary != nil:
+		summary = *session.Summary
+	case session.Status != StatusActive:
+		return Summary{}, ErrSessionNotActive
+	default:
+		if session.Position < len(session.Exercises) {
+			return Summary{}, ErrSessionNotCompleted
+		}
+
+		answers, err := s.repo.getAnswers(ctx, session.ID)
+		if err != nil {
+			logger.Error("SessionService.FinishSession repo.getAnswers: ", err)
+			return Summary{}, err
+		}
+		summary = s.summarize(session, answers)
+
+		err = s.repo.finishSession(ctx, session.ID, summary, s.now())
+		if err == ErrSessionChanged {
+			// another request finished the session first, its summary is the one that counts
+			summary, err = s.finishedSummary(ctx, session.ID)
+		}
+		if err != nil {
+			logger.Error("SessionService.FinishSession repo.finishSession: ", err)
+			return Summary{}, err
+		}
+	}
+
+	if s.progressService != nil {
+		// practice adds XP without completing the lesson again
+		lessonCode := session.LessonCode
+		if session.Practice {
+			lessonCode = ""
+		}
+		if err := s.progressService.CompleteLesson(ctx, session.ID, userID, lessonCode, summary.XP); err != nil {
+			logger.Error("SessionService.FinishSession progressService.CompleteLesson: ", err)
+			return Summary{}, err
+		}
+	}
+
+	return summary, nil
+}
+
+// finishedSummary returns the summary saved by the request which finished the session,
+// ErrSessionChanged means the session was abandoned instead
+func (s *SessionService) finishedSummary(ctx context.Context, id string) (Summary, error) {
+	session, err := s.repo.getSession(ctx, id)
+	if err != nil {
+		return Summary{}, err
+	}
+	if session.Status != StatusFinished || session.Summary == nil {
+		return Summary{}, ErrSessionChanged
+	}
+	return *session.Summary, nil
+}
+
+func (s *SessionService) summarize(session Session, answers []SessionAnswer) Summary {
+	summary := Summary{
+		Total:        len(session.Exercises),
+		HeartsLost:   session.HeartsLost,
+		HeartsEarned: session.HeartsEarned,
+		Answers:      answers,
+	}
+
+	var score float64
+	for _, answer := range answers {
+		if answer.Correct {
+			summary.Correct++
+		}
+		score += answer.Score
+		summary.TimeSpentMs += answer.TimeSpentMs
+		summary.HintsUsed += answer.HintsUsed
+	}
+
+	if summary.Total > 0 {
+		summary.Accuracy = score / float64(summary.Total)
+	}
+	summary.XP = s.exerciseService.ScoreXP(score)
+	return summary
+}
+
+// checkHearts returns ErrNoHearts when the user can't make mistakes in lessons,
+// hearts are nil without the hearts service
+func (s *SessionService) checkHearts(ctx context.Context, userID string) (*hearts.Hearts, error) {
+	if s.heartsService == nil {
+		return nil, nil
+	}
+
+	current, err := s.heartsService.GetHearts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Current == 0 {
+		return nil, ErrNoHearts
+	}
+	return &current, nil
+}
+
+func (s *SessionService) resume(ctx context.Context, session Session) (SessionState, error) {
+	now := s.now()
+	if err := s.repo.restartExercise(ctx, session.ID, session.Position, now); err != nil {
+		logger.Error("SessionService.resume repo.restartExercise: ", err)
+		return SessionState{}, err
+	}
+	session.ExerciseStartedAt = now
+	session.UpdatedAt = now
+
+	return s.state(ctx, session)
+}
+
+// getSession hides sessions of other users
+func (s *SessionService) getSession(ctx context.Context, userID, id string) (Session, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return Session{}, ErrSessionNotFound
+	}
+
+	session, err := s.repo.getSession(ctx, id)
+	if err != nil {
+		return Session{}, err
+	}
+	if session.UserID != userID {
+		return Session{}, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (s *SessionService) getActiveSessionByID(ctx context.Context, userID, id string) (Session, error) {
+	session, err := s.getSession(ctx, userID, id)
+	if err != nil {
+		return Session{}, err
+	}
+	if session.Status != StatusActive {
+		return Session{}, ErrSessionNotActive
+	}
+	return session, nil
+}
+
+// state adds the current exercise in the learner view with the hints taken so far
+func (s *SessionService) state(ctx context.Context, session Session) (SessionState, error) {
+	state := SessionState{Session: session, Total: len(session.Exercises)}
+	if session.Status != StatusActive || session.Position >= len(session.Exercises) {
+		return state, nil
+	}
+
+	exercise, err := s.contentService.GetPublicExercise(ctx, session.Exercises[session.Position])
+	if errors.Is(err, exercises.ErrNotFound) {
+		return SessionState{}, s.outdate(ctx, session)
+	}
+	if err != nil {
+		return SessionState{}, err
+	}
+	learner, err := s.exerciseService.ToLearner(exercise)
+	if err != nil {
+		return SessionState{}, err
+	}
+
+	state.HintsAvailable = len(learner.Hints)
+	learner.Hints = learner.Hints[:min(session.HintsUsed, len(learner.Hints))]
+	state.Current = &learner
+	return state, nil
+}
+
+// outdate abandons the session whose current exercise was unpublished or deleted,
+// it can't be continued and starting the lesson again takes its exercises as they are now
+func (s *SessionService) outdate(ctx context.Context, session Session) error {
+	if err := s.repo.abandonSession(ctx, session.ID, s.now()); err != nil {
+		logger.Error("SessionService.outdate repo.abandonSession: ", err)
+		return err
+	}
+	return ErrSessionOutdated
+}
@@ -0,0 +1,394 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+
+// Package sessions is a generated GoMock package.
+package sessions
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	exercises "uiren/internal/app/exercises"
+	hearts "uiren/internal/app/hearts"
+	lessons "uiren/internal/app/lessons"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MocksessionRepository is a mock of sessionRepository interface.
+type MocksessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MocksessionRepositoryMockRecorder
+}
+
+// MocksessionRepositoryMockRecorder is the mock recorder for MocksessionRepository.
+type MocksessionRepositoryMockRecorder struct {
+	mock *MocksessionRepository
+}
+
+// NewMocksessionRepository creates a new mock instance.
+func NewMocksessionRepository(ctrl *gomock.Controller) *MocksessionRepository {
+	mock := &MocksessionRepository{ctrl: ctrl}
+	mock.recorder = &MocksessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksessionRepository) EXPECT() *MocksessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// abandonSession mocks base method.
+func (m *MocksessionRepository) abandonSession(ctx context.Context, id string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "abandonSession", ctx, id, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// abandonSession indicates an expected call of abandonSession.
+func (mr *MocksessionRepositoryMockRecorder) abandonSession(ctx, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "abandonSession", reflect.TypeOf((*MocksessionRepository)(nil).abandonSession), ctx, id, now)
+}
+
+// createSession mocks base method.
+func (m *MocksessionRepository) createSession(ctx context.Context, session Session) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createSession", ctx, session)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createSession indicates an expected call of createSession.
+func (mr *MocksessionRepositoryMockRecorder) createSession(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createSession", reflect.TypeOf((*MocksessionRepository)(nil).createSession), ctx, session)
+}
+
+// finishSession mocks base method.
+func (m *MocksessionRepository) finishSession(ctx context.Context, id string, summary Summary, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "finishSession", ctx, id, summary, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// finishSession indicates an expected call of finishSession.
+func (mr *MocksessionRepositoryMockRecorder) finishSession(ctx, id, summary, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "finishSession", reflect.TypeOf((*MocksessionRepository)(nil).finishSession), ctx, id, summary, now)
+}
+
+// getActiveSession mocks base method.
+func (m *MocksessionRepository) getActiveSession(ctx context.Context, userID string) (Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getActiveSession", ctx, userID)
+	ret0, _ := ret[0].(Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getActiveSession indicates an expected call of getActiveSession.
+func (mr *MocksessionRepositoryMockRecorder) getActiveSession(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getActiveSession", reflect.TypeOf((*MocksessionRepository)(nil).getActiveSession), ctx, userID)
+}
+
+// getAnswers mocks base method.
+func (m *MocksessionRepository) getAnswers(ctx context.Context, id string) ([]SessionAnswer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getAnswers", ctx, id)
+	ret0, _ := ret[0].([]SessionAnswer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getAnswers indicates an expected call of getAnswers.
+func (mr *MocksessionRepositoryMockRecorder) getAnswers(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getAnswers", reflect.TypeOf((*MocksessionRepository)(nil).getAnswers), ctx, id)
+}
+
+// getSession mocks base method.
+func (m *MocksessionRepository) getSession(ctx context.Context, id string) (Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getSession", ctx, id)
+	ret0, _ := ret[0].(Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// getSession indicates an expected call of getSession.
+func (mr *MocksessionRepositoryMockRecorder) getSession(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getSession", reflect.TypeOf((*MocksessionRepository)(nil).getSession), ctx, id)
+}
+
+// recordAnswer mocks base method.
+func (m *MocksessionRepository) recordAnswer(ctx context.Context, id string, answer SessionAnswer, heartsLost, heartsEarned int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "recordAnswer", ctx, id, answer, heartsLost, heartsEarned)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// recordAnswer indicates an expected call of recordAnswer.
+func (mr *MocksessionRepositoryMockRecorder) recordAnswer(ctx, id, answer, heartsLost, heartsEarned interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "recordAnswer", reflect.TypeOf((*MocksessionRepository)(nil).recordAnswer), ctx, id, answer, heartsLost, heartsEarned)
+}
+
+// restartExercise mocks base method.
+func (m *MocksessionRepository) restartExercise(ctx context.Context, id string, position int, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "restartExercise", ctx, id, position, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// restartExercise indicates an expected call of restartExercise.
+func (mr *MocksessionRepositoryMockRecorder) restartExercise(ctx, id, position, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "restartExercise", reflect.TypeOf((*MocksessionRepository)(nil).restartExercise), ctx, id, position, now)
+}
+
+// useHint mocks base method.
+func (m *MocksessionRepository) useHint(ctx context.Context, id string, position, hintsUsed int, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "useHint", ctx, id, position, hintsUsed, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// useHint indicates an expected call of useHint.
+func (mr *MocksessionRepositoryMockRecorder) useHint(ctx, id, position, hintsUsed, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "useHint", reflect.TypeOf((*MocksessionRepository)(nil).useHint), ctx, id, position, hintsUsed, now)
+}
+
+// MockcontentService is a mock of contentService interface.
+type MockcontentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockcontentServiceMockRecorder
+}
+
+// MockcontentServiceMockRecorder is the mock recorder for MockcontentService.
+type MockcontentServiceMockRecorder struct {
+	mock *MockcontentService
+}
+
+// NewMockcontentService creates a new mock instance.
+func NewMockcontentService(ctrl *gomock.Controller) *MockcontentService {
+	mock := &MockcontentService{ctrl: ctrl}
+	mock.recorder = &MockcontentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcontentService) EXPECT() *MockcontentServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPublicExercise mocks base method.
+func (m *MockcontentService) GetPublicExercise(ctx context.Context, code string) (exercises.Exercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicExercise", ctx, code)
+	ret0, _ := ret[0].(exercises.Exercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicExercise indicates an expected call of GetPublicExercise.
+func (mr *MockcontentServiceMockRecorder) GetPublicExercise(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicExercise", reflect.TypeOf((*MockcontentService)(nil).GetPublicExercise), ctx, code)
+}
+
+// GetPublicLesson mocks base method.
+func (m *MockcontentService) GetPublicLesson(ctx context.Context, code string) (lessons.LessonDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicLesson", ctx, code)
+	ret0, _ := ret[0].(lessons.LessonDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicLesson indicates an expected call of GetPublicLesson.
+func (mr *MockcontentServiceMockRecorder) GetPublicLesson(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicLesson", reflect.TypeOf((*MockcontentService)(nil).GetPublicLesson), ctx, code)
+}
+
+// MockexerciseService is a mock of exerciseService interface.
+type MockexerciseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockexerciseServiceMockRecorder
+}
+
+// MockexerciseServiceMockRecorder is the mock recorder for MockexerciseService.
+type MockexerciseServiceMockRecorder struct {
+	mock *MockexerciseService
+}
+
+// NewMockexerciseService creates a new mock instance.
+func NewMockexerciseService(ctrl *gomock.Controller) *MockexerciseService {
+	mock := &MockexerciseService{ctrl: ctrl}
+	mock.recorder = &MockexerciseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockexerciseService) EXPECT() *MockexerciseServiceMockRecorder {
+	return m.recorder
+}
+
+// GradeAnswer mocks base method.
+func (m *MockexerciseService) GradeAnswer(ctx context.Context, code string, answer exercises.Answer) (exercises.GradeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GradeAnswer", ctx, code, answer)
+	ret0, _ := ret[0].(exercises.GradeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GradeAnswer indicates an expected call of GradeAnswer.
+func (mr *MockexerciseServiceMockRecorder) GradeAnswer(ctx, code, answer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GradeAnswer", reflect.TypeOf((*MockexerciseService)(nil).GradeAnswer), ctx, code, answer)
+}
+
+// ScoreXP mocks base method.
+func (m *MockexerciseService) ScoreXP(score float64) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScoreXP", score)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// ScoreXP indicates an expected call of ScoreXP.
+func (mr *MockexerciseServiceMockRecorder) ScoreXP(score interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScoreXP", reflect.TypeOf((*MockexerciseService)(nil).ScoreXP), score)
+}
+
+// ToLearner mocks base method.
+func (m *MockexerciseService) ToLearner(exercise exercises.Exercise) (exercises.LearnerExercise, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToLearner", exercise)
+	ret0, _ := ret[0].(exercises.LearnerExercise)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ToLearner indicates an expected call of ToLearner.
+func (mr *MockexerciseServiceMockRecorder) ToLearner(exercise interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToLearner", reflect.TypeOf((*MockexerciseService)(nil).ToLearner), exercise)
+}
+
+// MockprogressService is a mock of progressService interface.
+type MockprogressService struct {
+	ctrl     *gomock.Controller
+	recorder *MockprogressServiceMockRecorder
+}
+
+// MockprogressServiceMockRecorder is the mock recorder for MockprogressService.
+type MockprogressServiceMockRecorder struct {
+	mock *MockprogressService
+}
+
+// NewMockprogressService creates a new mock instance.
+func NewMockprogressService(ctrl *gomock.Controller) *MockprogressService {
+	mock := &MockprogressService{ctrl: ctrl}
+	mock.recorder = &MockprogressServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockprogressService) EXPECT() *MockprogressServiceMockRecorder {
+	return m.recorder
+}
+
+// CompleteLesson mocks base method.
+func (m *MockprogressService) CompleteLesson(ctx context.Context, sessionID, userID, lessonCode string, xp int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLesson", ctx, sessionID, userID, lessonCode, xp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteLesson indicates an expected call of CompleteLesson.
+func (mr *MockprogressServiceMockRecorder) CompleteLesson(ctx, sessionID, userID, lessonCode, xp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLesson", reflect.TypeOf((*MockprogressService)(nil).CompleteLesson), ctx, sessionID, userID, lessonCode, xp)
+}
+
+// MockheartsService is a mock of heartsService interface.
+type MockheartsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockheartsServiceMockRecorder
+}
+
+// MockheartsServiceMockRecorder is the mock recorder for MockheartsService.
+type MockheartsServiceMockRecorder struct {
+	mock *MockheartsService
+}
+
+// NewMockheartsService creates a new mock instance.
+func NewMockheartsService(ctrl *gomock.Controller) *MockheartsService {
+	mock := &MockheartsService{ctrl: ctrl}
+	mock.recorder = &MockheartsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockheartsService) EXPECT() *MockheartsServiceMockRecorder {
+	return m.recorder
+}
+
+// EarnHeart mocks base method.
+func (m *MockheartsService) EarnHeart(ctx context.Context, userID string) (hearts.Hearts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EarnHeart", ctx, userID)
+	ret0, _ := ret[0].(hearts.Hearts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EarnHeart indicates an expected call of EarnHeart.
+func (mr *MockheartsServiceMockRecorder) EarnHeart(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EarnHeart", reflect.TypeOf((*MockheartsService)(nil).EarnHeart), ctx, userID)
+}
+
+// GetHearts mocks base method.
+func (m *MockheartsService) GetHearts(ctx context.Context, userID string) (hearts.Hearts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHearts", ctx, userID)
+	ret0, _ := ret[0].(hearts.Hearts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHearts indicates an expected call of GetHearts.
+func (mr *MockheartsServiceMockRecorder) GetHearts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHearts", reflect.TypeOf((*MockheartsService)(nil).GetHearts), ctx, userID)
+}
+
+// LoseHeart mocks base method.
+func (m *MockheartsService) LoseHeart(ctx context.Context, userID string) (hearts.Hearts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoseHeart", ctx, userID)
+	ret0, _ := ret[0].(hearts.Hearts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoseHeart indicates an expected call of LoseHeart.
+func (mr *MockheartsServiceMockRecorder) LoseHeart(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoseHeart", reflect.TypeOf((*MockheartsService)(nil).LoseHeart), ctx, userID)
+}
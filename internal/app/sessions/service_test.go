@@ -0,0 +1,623 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"uiren/internal/app/exercises"
+	"uiren/internal/app/hearts"
+	"uiren/internal/app/lessons"
+	"uiren/pkg/logger"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logger.InitLogger("info")
+}
+
+const (
+	sessionID = "9b2f5c1e-3a4d-4f6b-8c7d-1e2f3a4b5c6d"
+	userID    = "user-1"
+)
+
+type testService struct {
+	srv       *SessionService
+	repo      *MocksessionRepository
+	content   *MockcontentService
+	exercises *MockexerciseService
+	progress  *MockprogressService
+	hearts    *MockheartsService
+	now       time.Time
+}
+
+func newTestService(t *testing.T) testService {
+	ctrl := gomock.NewController(t)
+	ts := testService{
+		repo:      NewMocksessionRepository(ctrl),
+		content:   NewMockcontentService(ctrl),
+		exercises: NewMockexerciseService(ctrl),
+		progress:  NewMockprogressService(ctrl),
+		hearts:    NewMockheartsService(ctrl),
+		now:       time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+	}
+	ts.srv = NewSessionService(ts.repo, ts.content, ts.exercises)
+	ts.srv.WithProgressService(ts.progress)
+	ts.srv.now = func() time.Time { return ts.now }
+	return ts
+}
+
+// withHearts makes mistakes in lessons cost hearts
+func (ts testService) withHearts() testService {
+	ts.srv.WithHeartsService(ts.hearts)
+	return ts
+}
+
+// expectCurrent expects the learner view of the exercise to be built
+func (ts testService) expectCurrent(ctx context.Context, exercise exercises.Exercise) {
+	ts.content.EXPECT().GetPublicExercise(ctx, exercise.Code).Return(exercise, nil)
+	ts.exercises.EXPECT().ToLearner(exercise).Return(exercises.LearnerExercise{Code: exercise.Code, Hints: exercise.Hints}, nil)
+}
+
+func activeSession(position int) Session {
+	return Session{
+		ID:                sessionID,
+		UserID:            userID,
+		LessonCode:        "lesson-1",
+		Exercises:         []string{"ex-1", "ex-2"},
+		Position:          position,
+		Status:            StatusActive,
+		ExerciseStartedAt: time.Date(2026, 10, 19, 11, 59, 30, 0, time.UTC),
+	}
+}
+
+func Test_SessionService_StartSession(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx    = context.TODO()
+		lesson = lessons.LessonDTO{Code: "lesson-1", Exercises: []exercises.Exercise{{Code: "ex-1"}, {Code: "ex-2"}}}
+	)
+
+	t.Run("new session", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-1").Return(lesson, nil)
+		ts.repo.EXPECT().getActiveSession(ctx, userID).Return(Session{}, ErrSessionNotFound)
+		ts.repo.EXPECT().createSession(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, session Session) (string, error) {
+			assert.Equal(t, []string{"ex-1", "ex-2"}, session.Exercises)
+			assert.Equal(t, StatusActive, session.Status)
+			assert.Equal(t, ts.now, session.ExerciseStartedAt)
+			return sessionID, nil
+		})
+		ts.expectCurrent(ctx, exercises.Exercise{Code: "ex-1", Hints: []string{"first", "second"}})
+
+		state, err := ts.srv.StartSession(ctx, userID, "lesson-1")
+		assert.NoError(t, err)
+		assert.Equal(t, sessionID, state.ID)
+		assert.Equal(t, 2, state.Total)
+		assert.Equal(t, "ex-1", state.Current.Code)
+		assert.Empty(t, state.Current.Hints)
+		assert.Equal(t, 2, state.HintsAvailable)
+	})
+
+	t.Run("resumes the session of the same lesson", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-1").Return(lesson, nil)
+		ts.repo.EXPECT().getActiveSession(ctx, userID).Return(activeSession(1), nil)
+		ts.repo.EXPECT().restartExercise(ctx, sessionID, 1, ts.now).Return(nil)
+		ts.expectCurrent(ctx, exercises.Exercise{Code: "ex-2"})
+
+		state, err := ts.srv.StartSession(ctx, userID, "lesson-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, state.Position)
+		assert.Equal(t, "ex-2", state.Current.Code)
+	})
+
+	t.Run("starts again when the resumed exercise was removed", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-1").Return(lesson, nil)
+		ts.repo.EXPECT().getActiveSession(ctx, userID).Return(activeSession(1), nil)
+		ts.repo.EXPECT().restartExercise(ctx, sessionID, 1, ts.now).Return(nil)
+		ts.content.EXPECT().GetPublicExercise(ctx, "ex-2").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		ts.repo.EXPECT().abandonSession(ctx, sessionID, ts.now).Return(nil)
+		ts.repo.EXPECT().createSession(ctx, gomock.Any()).Return("new-id", nil)
+		ts.expectCurrent(ctx, exercises.Exercise{Code: "ex-1"})
+
+		state, err := ts.srv.StartSession(ctx, userID, "lesson-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "new-id", state.ID)
+		assert.Equal(t, 0, state.Position)
+	})
+
+	t.Run("abandons the session of another lesson", func(t *testing.T) {
+		ts := newTestService(t)
+		other := activeSession(1)
+		other.LessonCode = "lesson-0"
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-1").Return(lesson, nil)
+		ts.repo.EXPECT().getActiveSession(ctx, userID).Return(other, nil)
+		ts.repo.EXPECT().abandonSession(ctx, sessionID, ts.now).Return(nil)
+		ts.repo.EXPECT().createSession(ctx, gomock.Any()).Return("new-id", nil)
+		ts.expectCurrent(ctx, exercises.Exercise{Code: "ex-1"})
+
+		state, err := ts.srv.StartSession(ctx, userID, "lesson-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "new-id", state.ID)
+		assert.Equal(t, 0, state.Position)
+	})
+
+	t.Run("no hearts keeps the unfinished session", func(t *testing.T) {
+		ts := newTestService(t).withHearts()
+		other := activeSession(1)
+		other.LessonCode = "lesson-0"
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-1").Return(lesson, nil)
+		ts.repo.EXPECT().getActiveSession(ctx, userID).Return(other, nil)
+		ts.hearts.EXPECT().GetHearts(ctx, userID).Return(hearts.Hearts{Current: 0, Max: 5}, nil)
+		ts.repo.EXPECT().abandonSession(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := ts.srv.StartSession(ctx, userID, "lesson-1")
+		assert.Equal(t, ErrNoHearts, err)
+	})
+
+	t.Run("empty lesson", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-2").Return(lessons.LessonDTO{Code: "lesson-2"}, nil)
+
+		_, err := ts.srv.StartSession(ctx, userID, "lesson-2")
+		assert.Equal(t, ErrEmptyLesson, err)
+	})
+
+	t.Run("lesson not found", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-3").Return(lessons.LessonDTO{}, lessons.ErrNotFound)
+
+		_, err := ts.srv.StartSession(ctx, userID, "lesson-3")
+		assert.Equal(t, lessons.ErrNotFound, err)
+	})
+}
+
+func Test_SessionService_StartPractice(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx    = context.TODO()
+		lesson = lessons.LessonDTO{Code: "lesson-1", Exercises: []exercises.Exercise{{Code: "ex-1"}, {Code: "ex-2"}}}
+	)
+
+	t.Run("started without hearts", func(t *testing.T) {
+		ts := newTestService(t).withHearts()
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-1").Return(lesson, nil)
+		ts.repo.EXPECT().getActiveSession(ctx, userID).Return(Session{}, ErrSessionNotFound)
+		ts.repo.EXPECT().createSession(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, session Session) (string, error) {
+			assert.True(t, session.Practice)
+			return sessionID, nil
+		})
+		ts.expectCurrent(ctx, exercises.Exercise{Code: "ex-1"})
+
+		state, err := ts.srv.StartPractice(ctx, userID, "lesson-1")
+		assert.NoError(t, err)
+		assert.True(t, state.Practice)
+	})
+
+	t.Run("lesson session of the same lesson is abandoned", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.content.EXPECT().GetPublicLesson(ctx, "lesson-1").Return(lesson, nil)
+		ts.repo.EXPECT().getActiveSession(ctx, userID).Return(activeSession(1), nil)
+		ts.repo.EXPECT().abandonSession(ctx, sessionID, ts.now).Return(nil)
+		ts.repo.EXPECT().createSession(ctx, gomock.Any()).Return("new-id", nil)
+		ts.expectCurrent(ctx, exercises.Exercise{Code: "ex-1"})
+
+		state, err := ts.srv.StartPractice(ctx, userID, "lesson-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "new-id", state.ID)
+	})
+}
+
+func Test_SessionService_GetSession(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx = context.TODO()
+	)
+
+	t.Run("session of another user", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(0)
+		session.UserID = "user-2"
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+
+		_, err := ts.srv.GetSession(ctx, userID, sessionID)
+		assert.Equal(t, ErrSessionNotFound, err)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ts := newTestService(t)
+
+		_, err := ts.srv.GetSession(ctx, userID, "not-a-uuid")
+		assert.Equal(t, ErrSessionNotFound, err)
+	})
+
+	t.Run("current exercise was unpublished", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(0), nil)
+		ts.content.EXPECT().GetPublicExercise(ctx, "ex-1").Return(exercises.Exercise{}, exercises.ErrNotFound)
+		ts.repo.EXPECT().abandonSession(ctx, sessionID, ts.now).Return(nil)
+
+		_, err := ts.srv.GetSession(ctx, userID, sessionID)
+		assert.Equal(t, ErrSessionOutdated, err)
+	})
+
+	t.Run("finished session has no current exercise", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(2)
+		session.Status = StatusFinished
+		session.Summary = &Summary{XP: 15}
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+
+		state, err := ts.srv.GetSession(ctx, userID, sessionID)
+		assert.NoError(t, err)
+		assert.Nil(t, state.Current)
+		assert.Equal(t, 15, state.Summary.XP)
+	})
+}
+
+func Test_SessionService_ResumeSession(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx = context.TODO()
+	)
+
+	t.Run("restarts the exercise time", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(1)
+		session.HintsUsed = 1
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+		ts.repo.EXPECT().restartExercise(ctx, sessionID, 1, ts.now).Return(nil)
+		ts.expectCurrent(ctx, exercises.Exercise{Code: "ex-2", Hints: []string{"first", "second"}})
+
+		state, err := ts.srv.ResumeSession(ctx, userID, sessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, ts.now, state.ExerciseStartedAt)
+		assert.Equal(t, []string{"first"}, state.Current.Hints)
+	})
+
+	t.Run("answered on another device", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(1), nil)
+		ts.repo.EXPECT().restartExercise(ctx, sessionID, 1, ts.now).Return(ErrSessionChanged)
+
+		_, err := ts.srv.ResumeSession(ctx, userID, sessionID)
+		assert.Equal(t, ErrSessionChanged, err)
+	})
+
+	t.Run("abandoned session", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(1)
+		session.Status = StatusAbandoned
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+
+		_, err := ts.srv.ResumeSession(ctx, userID, sessionID)
+		assert.Equal(t, ErrSessionNotActive, err)
+	})
+}
+
+func Test_SessionService_TakeHint(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx      = context.TODO()
+		exercise = exercises.Exercise{Code: "ex-1", Hints: []string{"first", "second"}}
+	)
+
+	t.Run("next hint", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(0)
+		session.HintsUsed = 1
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+		ts.content.EXPECT().GetPublicExercise(ctx, "ex-1").Return(exercise, nil)
+		ts.repo.EXPECT().useHint(ctx, sessionID, 0, 1, ts.now).Return(nil)
+		ts.expectCurrent(ctx, exercise)
+
+		state, err := ts.srv.TakeHint(ctx, userID, sessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, state.HintsUsed)
+		assert.Equal(t, []string{"first", "second"}, state.Current.Hints)
+	})
+
+	t.Run("no more hints", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(0)
+		session.HintsUsed = 2
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+		ts.content.EXPECT().GetPublicExercise(ctx, "ex-1").Return(exercise, nil)
+
+		_, err := ts.srv.TakeHint(ctx, userID, sessionID)
+		assert.Equal(t, ErrNoMoreHints, err)
+	})
+}
+
+func Test_SessionService_SubmitAnswer(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx    = context.TODO()
+		answer = exercises.Answer{Text: "Рақмет"}
+	)
+
+	t.Run("correct answer moves to the next exercise", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(0)
+		session.HintsUsed = 1
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-1", answer).Return(exercises.GradeResult{Correct: true, Score: 1}, nil)
+		ts.repo.EXPECT().recordAnswer(ctx, sessionID, SessionAnswer{
+			Position:     0,
+			ExerciseCode: "ex-1",
+			Correct:      true,
+			Score:        1,
+			HintsUsed:    1,
+			TimeSpentMs:  30000,
+			AnsweredAt:   ts.now,
+		}, 0, 0).Return(nil)
+		ts.expectCurrent(ctx, exercises.Exercise{Code: "ex-2"})
+
+		result, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.NoError(t, err)
+		assert.True(t, result.Correct)
+		assert.Equal(t, 1, result.Session.Position)
+		assert.Equal(t, 0, result.Session.HintsUsed)
+		assert.Equal(t, "ex-2", result.Session.Current.Code)
+	})
+
+	t.Run("incorrect last answer costs a heart", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(1), nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-2", answer).Return(exercises.GradeResult{Score: 0.5}, nil)
+		ts.repo.EXPECT().recordAnswer(ctx, sessionID, gomock.Any(), 1, 0).Return(nil)
+
+		result, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.NoError(t, err)
+		assert.False(t, result.Correct)
+		assert.Equal(t, 1, result.Session.HeartsLost)
+		assert.Equal(t, 2, result.Session.Position)
+		assert.Nil(t, result.Session.Current)
+	})
+
+	t.Run("answered on another device", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(0), nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-1", answer).Return(exercises.GradeResult{Correct: true, Score: 1}, nil)
+		ts.repo.EXPECT().recordAnswer(ctx, sessionID, gomock.Any(), 0, 0).Return(ErrSessionChanged)
+
+		_, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.Equal(t, ErrSessionChanged, err)
+	})
+
+	t.Run("exercise was removed", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(0), nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-1", answer).Return(exercises.GradeResult{}, exercises.ErrNotFound)
+		ts.repo.EXPECT().abandonSession(ctx, sessionID, ts.now).Return(nil)
+		ts.repo.EXPECT().recordAnswer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.Equal(t, ErrSessionOutdated, err)
+	})
+
+	t.Run("all exercises answered", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(2), nil)
+
+		_, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.Equal(t, ErrSessionCompleted, err)
+	})
+
+	t.Run("incorrect answer loses a heart", func(t *testing.T) {
+		ts := newTestService(t).withHearts()
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(1), nil)
+		ts.hearts.EXPECT().GetHearts(ctx, userID).Return(hearts.Hearts{Current: 1, Max: 5}, nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-2", answer).Return(exercises.GradeResult{}, nil)
+		gomock.InOrder(
+			ts.repo.EXPECT().recordAnswer(ctx, sessionID, gomock.Any(), 1, 0).Return(nil),
+			ts.hearts.EXPECT().LoseHeart(ctx, userID).Return(hearts.Hearts{Current: 0, Max: 5}, nil),
+		)
+
+		result, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.NoError(t, err)
+		assert.Equal(t, &hearts.Hearts{Current: 0, Max: 5}, result.Hearts)
+	})
+
+	t.Run("correct answer keeps hearts", func(t *testing.T) {
+		ts := newTestService(t).withHearts()
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(1), nil)
+		ts.hearts.EXPECT().GetHearts(ctx, userID).Return(hearts.Hearts{Current: 3, Max: 5}, nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-2", answer).Return(exercises.GradeResult{Correct: true, Score: 1}, nil)
+		ts.repo.EXPECT().recordAnswer(ctx, sessionID, gomock.Any(), 0, 0).Return(nil)
+
+		result, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.NoError(t, err)
+		assert.Equal(t, &hearts.Hearts{Current: 3, Max: 5}, result.Hearts)
+	})
+
+	t.Run("no hearts left", func(t *testing.T) {
+		ts := newTestService(t).withHearts()
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(1), nil)
+		ts.hearts.EXPECT().GetHearts(ctx, userID).Return(hearts.Hearts{Current: 0, Max: 5}, nil)
+
+		_, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.Equal(t, ErrNoHearts, err)
+	})
+
+	t.Run("correct answer in practice earns a heart", func(t *testing.T) {
+		ts := newTestService(t).withHearts()
+		session := activeSession(1)
+		session.Practice = true
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-2", answer).Return(exercises.GradeResult{Correct: true, Score: 1}, nil)
+		ts.repo.EXPECT().recordAnswer(ctx, sessionID, gomock.Any(), 0, 1).Return(nil)
+		ts.hearts.EXPECT().EarnHeart(ctx, userID).Return(hearts.Hearts{Current: 1, Max: 5}, nil)
+
+		result, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Session.HeartsEarned)
+		assert.Equal(t, &hearts.Hearts{Current: 1, Max: 5}, result.Hearts)
+	})
+
+	t.Run("incorrect answer in practice is free", func(t *testing.T) {
+		ts := newTestService(t).withHearts()
+		session := activeSession(1)
+		session.Practice = true
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-2", answer).Return(exercises.GradeResult{}, nil)
+		ts.repo.EXPECT().recordAnswer(ctx, sessionID, gomock.Any(), 0, 0).Return(nil)
+
+		result, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Session.HeartsLost)
+		assert.Nil(t, result.Hearts)
+	})
+
+	t.Run("failed hearts change keeps the answer", func(t *testing.T) {
+		ts := newTestService(t).withHearts()
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(1), nil)
+		ts.hearts.EXPECT().GetHearts(ctx, userID).Return(hearts.Hearts{Current: 2, Max: 5}, nil)
+		ts.exercises.EXPECT().GradeAnswer(ctx, "ex-2", answer).Return(exercises.GradeResult{}, nil)
+		ts.repo.EXPECT().recordAnswer(ctx, sessionID, gomock.Any(), 1, 0).Return(nil)
+		ts.hearts.EXPECT().LoseHeart(ctx, userID).Return(hearts.Hearts{}, errors.New("db error"))
+
+		result, err := ts.srv.SubmitAnswer(ctx, userID, sessionID, answer)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Session.Position)
+		assert.Nil(t, result.Hearts)
+	})
+}
+
+func Test_SessionService_FinishSession(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		answers = []SessionAnswer{
+			{Position: 0, ExerciseCode: "ex-1", Correct: true, Score: 1, HintsUsed: 1, TimeSpentMs: 12000},
+			{Position: 1, ExerciseCode: "ex-2", Score: 0.5, TimeSpentMs: 30000},
+		}
+	)
+
+	t.Run("summary", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(2)
+		session.HeartsLost = 1
+		expected := Summary{
+			Correct:     1,
+			Total:       2,
+			Accuracy:    0.75,
+			XP:          15,
+			TimeSpentMs: 42000,
+			HintsUsed:   1,
+			HeartsLost:  1,
+			Answers:     answers,
+		}
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+		ts.repo.EXPECT().getAnswers(ctx, sessionID).Return(answers, nil)
+		ts.exercises.EXPECT().ScoreXP(1.5).Return(15)
+		gomock.InOrder(
+			ts.repo.EXPECT().finishSession(ctx, sessionID, expected, ts.now).Return(nil),
+			ts.progress.EXPECT().CompleteLesson(ctx, sessionID, userID, "lesson-1", 15).Return(nil),
+		)
+
+		summary, err := ts.srv.FinishSession(ctx, userID, sessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, summary)
+	})
+
+	t.Run("finished on another device", func(t *testing.T) {
+		ts := newTestService(t)
+		finished := activeSession(2)
+		finished.Status = StatusFinished
+		finished.Summary = &Summary{Correct: 1, Total: 2, XP: 15}
+		gomock.InOrder(
+			ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(2), nil),
+			ts.repo.EXPECT().getSession(ctx, sessionID).Return(finished, nil),
+		)
+		ts.repo.EXPECT().getAnswers(ctx, sessionID).Return(answers, nil)
+		ts.exercises.EXPECT().ScoreXP(1.5).Return(15)
+		ts.repo.EXPECT().finishSession(ctx, sessionID, gomock.Any(), ts.now).Return(ErrSessionChanged)
+		ts.progress.EXPECT().CompleteLesson(ctx, sessionID, userID, "lesson-1", 15).Return(nil)
+
+		summary, err := ts.srv.FinishSession(ctx, userID, sessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, *finished.Summary, summary)
+	})
+
+	t.Run("abandoned on another device", func(t *testing.T) {
+		ts := newTestService(t)
+		abandoned := activeSession(2)
+		abandoned.Status = StatusAbandoned
+		gomock.InOrder(
+			ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(2), nil),
+			ts.repo.EXPECT().getSession(ctx, sessionID).Return(abandoned, nil),
+		)
+		ts.repo.EXPECT().getAnswers(ctx, sessionID).Return(answers, nil)
+		ts.exercises.EXPECT().ScoreXP(1.5).Return(15)
+		ts.repo.EXPECT().finishSession(ctx, sessionID, gomock.Any(), ts.now).Return(ErrSessionChanged)
+		ts.progress.EXPECT().CompleteLesson(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := ts.srv.FinishSession(ctx, userID, sessionID)
+		assert.Equal(t, ErrSessionChanged, err)
+	})
+
+	t.Run("not all exercises answered", func(t *testing.T) {
+		ts := newTestService(t)
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(1), nil)
+
+		_, err := ts.srv.FinishSession(ctx, userID, sessionID)
+		assert.Equal(t, ErrSessionNotCompleted, err)
+	})
+
+	t.Run("practice adds XP only", func(t *testing.T) {
+		ts := newTestService(t)
+		session := activeSession(2)
+		session.Practice = true
+		session.HeartsEarned = 1
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(session, nil)
+		ts.repo.EXPECT().getAnswers(ctx, sessionID).Return(answers, nil)
+		ts.exercises.EXPECT().ScoreXP(1.5).Return(15)
+		ts.repo.EXPECT().finishSession(ctx, sessionID, gomock.Any(), ts.now).Return(nil)
+		ts.progress.EXPECT().CompleteLesson(ctx, sessionID, userID, "", 15).Return(nil)
+
+		summary, err := ts.srv.FinishSession(ctx, userID, sessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, summary.HeartsEarned)
+	})
+
+	t.Run("progress failed", func(t *testing.T) {
+		ts := newTestService(t)
+		errProgress := errors.New("db error")
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(activeSession(2), nil)
+		ts.repo.EXPECT().getAnswers(ctx, sessionID).Return(answers, nil)
+		ts.exercises.EXPECT().ScoreXP(1.5).Return(15)
+		ts.repo.EXPECT().finishSession(ctx, sessionID, gomock.Any(), ts.now).Return(nil)
+		ts.progress.EXPECT().CompleteLesson(ctx, sessionID, userID, "lesson-1", 15).Return(errProgress)
+
+		_, err := ts.srv.FinishSession(ctx, userID, sessionID)
+		assert.Equal(t, errProgress, err)
+	})
+
+	t.Run("finishing again retries the XP", func(t *testing.T) {
+		ts := newTestService(t)
+		finished := activeSession(2)
+		finished.Status = StatusFinished
+		finished.Summary = &Summary{Correct: 1, Total: 2, XP: 15}
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(finished, nil)
+		ts.progress.EXPECT().CompleteLesson(ctx, sessionID, userID, "lesson-1", 15).Return(nil)
+
+		summary, err := ts.srv.FinishSession(ctx, userID, sessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, *finished.Summary, summary)
+	})
+
+	t.Run("abandoned", func(t *testing.T) {
+		ts := newTestService(t)
+		abandoned := activeSession(2)
+		abandoned.Status = StatusAbandoned
+		ts.repo.EXPECT().getSession(ctx, sessionID).Return(abandoned, nil)
+
+		_, err := ts.srv.FinishSession(ctx, userID, sessionID)
+		assert.Equal(t, ErrSessionNotActive, err)
+	})
+}
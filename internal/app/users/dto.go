@@ -3,6 +3,7 @@ package users
 import (
 	"database/sql"
 	"time"
+	"uiren/internal/app/hearts"
 	"uiren/internal/app/progress"
 
 	"github.com/google/uuid"
@@ -12,6 +13,8 @@ type UserProgress struct {
 	Badges       []string                   `json:"badges"`
 	XP           int                        `json:"xp"`
 	Achievements []progress.UserAchievement `json:"achievements"`
+	// Hearts are set by the data service when hearts are on
+	Hearts *hearts.Hearts `json:"hearts,omitempty"`
 }
 
 type user struct {
@@ -72,3 +75,35 @@ type CreateUserDTO struct {
 type UpdateUserDTO struct {
 	ID, Firstname, Lastname, Phone, PhoneRegion string
 }
+
+// SortFields lists fields the users list can be sorted by, the first one is the default
+var SortFields = []string{"created_at", "username"}
+
+// ListFilter narrows the users list, nil fields are not applied
+type ListFilter struct {
+	IsActive    *bool
+	IsAdmin     *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+const (
+	DefaultSearchLimit = 10
+	MaxSearchLimit     = 50
+
+	maxSearchQueryLength = 50
+)
+
+// SearchUsersDTO finds users for Requester, who is never in the result
+type SearchUsersDTO struct {
+	Query     string
+	Requester string
+	Limit     int
+}
+
+// PublicUser is what other users can see in search results
+type PublicUser struct {
+	Username  string `json:"username"`
+	Firstname string `json:"first_name"`
+	Lastname  string `json:"last_name"`
+}
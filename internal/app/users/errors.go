@@ -9,4 +9,6 @@ var (
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUsernameExists    = errors.New("username already exists")
 	ErrEmailExists       = errors.New("email already exists")
+	ErrEmptySearchQuery  = errors.New("search query is empty")
+	ErrInvalidLimit      = errors.New("invalid limit")
 )
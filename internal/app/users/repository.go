@@ -2,8 +2,12 @@ package users
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+	"uiren/pkg/pagination"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -336,3 +340,185 @@ func (r *userRepository) getAllUsers(ctx context.Context) ([]UserDTO, error) {
 
 	return users, nil
 }
+
+func (r *userRepository) listUsers(ctx context.Context, filter ListFilter, params pagination.Params) ([]UserDTO, error) {
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []interface{}
+	)
+
+	if filter.IsActive != nil {
+		args = append(args, *filter.IsActive)
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", len(args)))
+	}
+	if filter.IsAdmin != nil {
+		args = append(args, *filter.IsAdmin)
+		conditions = append(conditions, fmt.Sprintf("is_admin = $%d", len(args)))
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	if params.After != nil {
+		var value interface{} = params.After.Value
+		if params.SortBy == "created_at" {
+			createdAt, err := params.After.TimeValue()
+			if err != nil {
+				return nil, err
+			}
+			value = createdAt
+		}
+		condition, afterArgs := pagination.PostgresAfter(params, params.SortBy, value, "id", params.After.Key, len(args)+1)
+		args = append(args, afterArgs...)
+		conditions = append(conditions, condition)
+	}
+
+	args = append(args, params.Fetch())
+	query := fmt.Sprintf(`
+		SELECT 
+			id,
+			username,
+			email,
+			password,
+			first_name,
+			last_name,
+			phone,
+			is_active,
+			is_admin,
+			created_at,
+			updated_at
+		FROM 
+			users
+		WHERE 
+			%s
+		ORDER BY 
+			%s
+		LIMIT $%d;
+		`, strings.Join(conditions, " AND "), pagination.PostgresOrderBy(params, params.SortBy, "id"), len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []UserDTO
+	for rows.Next() {
+		var user user
+		if err := rows.Scan(
+			&user.id,
+			&user.username,
+			&user.email,
+			&user.password,
+			&user.firstname,
+			&user.lastname,
+			&user.phone,
+			&user.isActive,
+			&user.isAdmin,
+			&user.createdAt,
+			&user.updatedAt,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, user.ToDTO())
+	}
+
+	return users, rows.Err()
+}
+
+func (r *userRepository) searchUsers(ctx context.Context, dto SearchUsersDTO) ([]PublicUser, error) {
+	var (
+		query = `
+		SELECT
+			username,
+			first_name,
+			last_name
+		FROM
+			users
+		WHERE
+			deleted_at IS NULL
+			AND is_active = true
+			AND is_searchable = true
+			AND username <> $2
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_username = $2 AND b.blocked_username = users.username)
+					OR (b.blocker_username = users.username AND b.blocked_username = $2)
+			)
+			AND (
+				username ILIKE $3
+				OR first_name ILIKE $3
+				OR last_name ILIKE $3
+				OR username % $1
+				OR first_name % $1
+				OR last_name % $1
+			)
+		ORDER BY
+			username ILIKE $3 DESC,
+			GREATEST(
+				similarity(username, $1),
+				similarity(COALESCE(first_name, ''), $1),
+				similarity(COALESCE(last_name, ''), $1)
+			) DESC,
+			username
+		LIMIT $4;
+		`
+		prefix = likeEscaper.Replace(dto.Query) + "%"
+		result []PublicUser
+	)
+
+	rows, err := r.db.Query(ctx, query, dto.Query, dto.Requester, prefix, dto.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			user      PublicUser
+			firstname sql.NullString
+			lastname  sql.NullString
+		)
+		if err := rows.Scan(&user.Username, &firstname, &lastname); err != nil {
+			return nil, err
+		}
+		user.Firstname = firstname.String
+		user.Lastname = lastname.String
+		result = append(result, user)
+	}
+
+	return result, rows.Err()
+}
+
+// likeEscaper escapes LIKE wildcards, so the query is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *userRepository) setSearchable(ctx context.Context, id string, searchable bool) error {
+	var (
+		query = `
+		UPDATE 
+			users 
+		SET 
+			is_searchable = $1,
+			updated_at = $2
+		WHERE 
+			id = $3
+			AND deleted_at IS NULL;
+		`
+	)
+
+	commandTag, err := r.db.Exec(ctx, query, searchable, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
@@ -3,9 +3,12 @@ package users
 import (
 	"context"
 	"errors"
+	"strings"
 	"uiren/internal/app/progress"
 	"uiren/internal/infrastracture/hasher"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
+	"unicode/utf8"
 )
 
 //go:generate mockgen -source service.go -destination service_mock.go -package users
@@ -18,7 +21,10 @@ type repository interface {
 	enableUser(ctx context.Context, username string) error
 	checkUserExists(ctx context.Context, username string) error
 	getAllUsers(ctx context.Context) ([]UserDTO, error)
+	listUsers(ctx context.Context, filter ListFilter, params pagination.Params) ([]UserDTO, error)
 	getUserByID(ctx context.Context, id string) (UserDTO, error)
+	searchUsers(ctx context.Context, dto SearchUsersDTO) ([]PublicUser, error)
+	setSearchable(ctx context.Context, id string, searchable bool) error
 }
 
 // temp
@@ -174,6 +180,31 @@ func (s *UserService) GetAllUsers(ctx context.Context) ([]UserDTO, error) {
 	return users, nil
 }
 
+// ListUsers returns one page of users, passwords are hidden
+func (s *UserService) ListUsers(ctx context.Context, filter ListFilter, params pagination.Params) (pagination.Page[UserDTO], error) {
+	logger.Info("UserService.ListUsers new request")
+
+	users, err := s.repo.listUsers(ctx, filter, params)
+	if err != nil {
+		logger.Error("UserService.ListUsers repo.listUsers: ", err)
+		return pagination.Page[UserDTO]{}, err
+	}
+
+	for i := range users {
+		users[i].normalize()
+		users[i].Password = "..."
+	}
+
+	return pagination.NewPage(users, params, userCursor), nil
+}
+
+func userCursor(user UserDTO, sortBy string) (string, string) {
+	if sortBy == "username" {
+		return user.Username, user.ID
+	}
+	return pagination.FormatTime(user.CreatedAt), user.ID
+}
+
 func (s *UserService) GetUserByID(ctx context.Context, id string) (UserDTO, error) {
 	logger.Info("UserService.GetUserByID new request")
 	user, err := s.repo.getUserByID(ctx, id)
@@ -184,3 +215,47 @@ func (s *UserService) GetUserByID(ctx context.Context, id string) (UserDTO, erro
 	user.normalize()
 	return user, nil
 }
+
+// SearchUsers finds users by username prefix or by similar username and names.
+// Users who disabled search are not shown.
+func (s *UserService) SearchUsers(ctx context.Context, dto SearchUsersDTO) ([]PublicUser, error) {
+	logger.Info("UserService.SearchUsers new request")
+
+	dto.Query = strings.TrimSpace(dto.Query)
+	if dto.Query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if utf8.RuneCountInString(dto.Query) > maxSearchQueryLength {
+		dto.Query = string([]rune(dto.Query)[:maxSearchQueryLength])
+	}
+
+	if dto.Limit == 0 {
+		dto.Limit = DefaultSearchLimit
+	}
+	if dto.Limit < 0 || dto.Limit > MaxSearchLimit {
+		return nil, ErrInvalidLimit
+	}
+
+	users, err := s.repo.searchUsers(ctx, dto)
+	if err != nil {
+		logger.Error("UserService.SearchUsers repo.searchUsers: ", err)
+		return nil, err
+	}
+
+	if users == nil {
+		users = make([]PublicUser, 0)
+	}
+	return users, nil
+}
+
+// SetSearchable shows or hides the user in user search and friend suggestions
+func (s *UserService) SetSearchable(ctx context.Context, id string, searchable bool) error {
+	logger.Info("UserService.SetSearchable new request")
+
+	if err := s.repo.setSearchable(ctx, id, searchable); err != nil {
+		logger.Error("UserService.SetSearchable repo.setSearchable: ", err)
+		return err
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 	progress "uiren/internal/app/progress"
+	pagination "uiren/pkg/pagination"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -138,6 +139,50 @@ func (mr *MockrepositoryMockRecorder) getUserByUsername(ctx, username interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getUserByUsername", reflect.TypeOf((*Mockrepository)(nil).getUserByUsername), ctx, username)
 }
 
+// listUsers mocks base method.
+func (m *Mockrepository) listUsers(ctx context.Context, filter ListFilter, params pagination.Params) ([]UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "listUsers", ctx, filter, params)
+	ret0, _ := ret[0].([]UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// listUsers indicates an expected call of listUsers.
+func (mr *MockrepositoryMockRecorder) listUsers(ctx, filter, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "listUsers", reflect.TypeOf((*Mockrepository)(nil).listUsers), ctx, filter, params)
+}
+
+// searchUsers mocks base method.
+func (m *Mockrepository) searchUsers(ctx context.Context, dto SearchUsersDTO) ([]PublicUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "searchUsers", ctx, dto)
+	ret0, _ := ret[0].([]PublicUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// searchUsers indicates an expected call of searchUsers.
+func (mr *MockrepositoryMockRecorder) searchUsers(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "searchUsers", reflect.TypeOf((*Mockrepository)(nil).searchUsers), ctx, dto)
+}
+
+// setSearchable mocks base method.
+func (m *Mockrepository) setSearchable(ctx context.Context, id string, searchable bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "setSearchable", ctx, id, searchable)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// setSearchable indicates an expected call of setSearchable.
+func (mr *MockrepositoryMockRecorder) setSearchable(ctx, id, searchable interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "setSearchable", reflect.TypeOf((*Mockrepository)(nil).setSearchable), ctx, id, searchable)
+}
+
 // updateUser mocks base method.
 func (m *Mockrepository) updateUser(ctx context.Context, dto UpdateUserDTO) (UserDTO, error) {
 	m.ctrl.T.Helper()
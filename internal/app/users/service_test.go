@@ -8,6 +8,7 @@ import (
 	"uiren/internal/app/progress"
 	"uiren/internal/infrastracture/hasher"
 	"uiren/pkg/logger"
+	"uiren/pkg/pagination"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -590,3 +591,100 @@ func Test_UserService_GetAllUsers(t *testing.T) {
 		assert.Nil(t, users)
 	})
 }
+
+func Test_UserService_ListUsers(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx      = context.TODO()
+		ctrl     = gomock.NewController(t)
+		repo     = NewMockrepository(ctrl)
+		service  = &UserService{repo: repo}
+		isActive = true
+		filter   = ListFilter{IsActive: &isActive}
+		params   = pagination.Params{Limit: 1, SortBy: "username", Order: pagination.OrderAsc}
+		users    = []UserDTO{
+			{ID: "1", Username: "asmith", Email: " asmith@example.com", Password: "hashed"},
+			{ID: "2", Username: "jdoe", Password: "hashed"},
+		}
+		repoErr = errors.New("repo error")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().listUsers(ctx, filter, params).Return(users, nil)
+
+		page, err := service.ListUsers(ctx, filter, params)
+		assert.NoError(t, err)
+		assert.Equal(t, []UserDTO{{ID: "1", Username: "asmith", Email: "asmith@example.com", Password: "..."}}, page.Items)
+
+		cursor, err := pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, pagination.Cursor{SortBy: "username", Order: pagination.OrderAsc, Value: "asmith", Key: "1"}, cursor)
+	})
+
+	t.Run("repo failed", func(t *testing.T) {
+		repo.EXPECT().listUsers(ctx, filter, params).Return(nil, repoErr)
+
+		_, err := service.ListUsers(ctx, filter, params)
+		assert.Equal(t, repoErr, err)
+	})
+}
+
+func Test_UserService_SearchUsers(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockrepository(ctrl)
+		service = &UserService{repo: repo}
+		found   = []PublicUser{{Username: "seab", Firstname: "Seab"}}
+		repoErr = errors.New("repo error")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().searchUsers(ctx, SearchUsersDTO{Query: "sea", Requester: "jdoe", Limit: DefaultSearchLimit}).Return(found, nil)
+
+		result, err := service.SearchUsers(ctx, SearchUsersDTO{Query: " sea ", Requester: "jdoe"})
+		assert.NoError(t, err)
+		assert.Equal(t, found, result)
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		repo.EXPECT().searchUsers(ctx, gomock.Any()).Return(nil, nil)
+
+		result, err := service.SearchUsers(ctx, SearchUsersDTO{Query: "zzz", Requester: "jdoe"})
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+	})
+
+	t.Run("repo failed", func(t *testing.T) {
+		repo.EXPECT().searchUsers(ctx, gomock.Any()).Return(nil, repoErr)
+
+		_, err := service.SearchUsers(ctx, SearchUsersDTO{Query: "sea", Requester: "jdoe"})
+		assert.Equal(t, repoErr, err)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err := service.SearchUsers(ctx, SearchUsersDTO{Query: "  ", Requester: "jdoe"})
+		assert.Equal(t, ErrEmptySearchQuery, err)
+
+		_, err = service.SearchUsers(ctx, SearchUsersDTO{Query: "sea", Requester: "jdoe", Limit: MaxSearchLimit + 1})
+		assert.Equal(t, ErrInvalidLimit, err)
+	})
+}
+
+func Test_UserService_SetSearchable(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx     = context.TODO()
+		ctrl    = gomock.NewController(t)
+		repo    = NewMockrepository(ctrl)
+		service = &UserService{repo: repo}
+	)
+
+	repo.EXPECT().setSearchable(ctx, "1", false).Return(nil)
+	assert.NoError(t, service.SetSearchable(ctx, "1", false))
+
+	repo.EXPECT().setSearchable(ctx, "2", true).Return(ErrUserNotFound)
+	assert.Equal(t, ErrUserNotFound, service.SetSearchable(ctx, "2", true))
+}
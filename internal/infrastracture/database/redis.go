@@ -42,6 +42,41 @@ func (r *RedisDB) Delete(ctx context.Context, key string) error {
 	return r.Client.Del(ctx, key).Err()
 }
 
+func (r *RedisDB) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.Client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe returns messages of the channel until ctx is done,
+// the connection is restored by the client when it breaks
+func (r *RedisDB) Subscribe(ctx context.Context, channel string) <-chan string {
+	pubsub := r.Client.Subscribe(ctx, channel)
+	messages := make(chan string)
+
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+
+		incoming := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-incoming:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- message.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return messages
+}
+
 func GetRedisDatabase(ctx context.Context, config RedisConfig) (*RedisDB, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:     config.Address,
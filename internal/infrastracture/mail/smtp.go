@@ -0,0 +1,134 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+
+	"github.com/jordan-wright/email"
+)
+
+const (
+	defaultSMTPTimeout = 30 * time.Second
+)
+
+var (
+	ErrNoRecipients = errors.New("no recipients")
+)
+
+// Message is one rendered email
+type Message struct {
+	To      []string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+type SMTPConfig struct {
+	Address     string // host:port
+	Username    string // empty username disables auth
+	Password    string
+	FromName    string
+	FromAddress string
+	Timeout     time.Duration
+}
+
+// SMTPMailer sends messages through one SMTP server,
+// STARTTLS is used whenever the server supports it
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultSMTPTimeout
+	}
+
+	return &SMTPMailer{
+		config: config,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if len(message.To) == 0 {
+		return ErrNoRecipients
+	}
+
+	e := email.NewEmail()
+	e.From = (&netmail.Address{Name: m.config.FromName, Address: m.config.FromAddress}).String()
+	e.To = message.To
+	e.Subject = message.Subject
+	e.HTML = []byte(message.HTML)
+	e.Text = []byte(message.Text)
+
+	raw, err := e.Bytes()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.config.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// the smtp client has no context, the deadline stops a hanging server
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(m.config.Address)
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if m.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.config.FromAddress); err != nil {
+		return err
+	}
+	for _, to := range message.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("rcpt %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
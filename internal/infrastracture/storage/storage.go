@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrInvalidKey     = errors.New("invalid object key")
+)
+
+// Storage keeps objects by key, FileSystem for a single instance and S3 for everything else
+type Storage interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients download the object from
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// ContentKey returns the content addressed key of the object, the same content always gets
+// the same key, so objects are immutable and can be cached forever
+func ContentKey(prefix string, content []byte, extension string) string {
+	sum := sha256.Sum256(content)
+	return prefix + "/" + hex.EncodeToString(sum[:]) + extension
+}
+
+// validKey rejects keys escaping the storage root
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
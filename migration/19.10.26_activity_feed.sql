@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS activities (
+    id bigserial PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type varchar(50) NOT NULL,
+    code varchar(255) NOT NULL DEFAULT '',
+    value integer NOT NULL DEFAULT 0,
+    created_at timestamp NOT NULL DEFAULT now()
+);
+
+-- the same progress reported again during the day is recorded once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_activities_daily_unique ON activities (user_id, type, code, value, (created_at::date));
+CREATE INDEX IF NOT EXISTS idx_activities_user_created ON activities (user_id, created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS activity_reactions (
+    activity_id bigint NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+    username varchar(50) NOT NULL REFERENCES users(username) ON DELETE CASCADE ON UPDATE CASCADE,
+    reaction varchar(20) NOT NULL CHECK (reaction IN ('congratulate', 'like', 'fire')),
+    created_at timestamp NOT NULL DEFAULT now(),
+    PRIMARY KEY (activity_id, username, reaction)
+);
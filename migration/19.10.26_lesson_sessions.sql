@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS lesson_sessions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lesson_code varchar(255) NOT NULL,
+    -- exercise codes of the lesson when the session started
+    exercises text[] NOT NULL,
+    position integer NOT NULL DEFAULT 0,
+    -- hints taken on the current exercise
+    hints_used integer NOT NULL DEFAULT 0,
+    hearts_lost integer NOT NULL DEFAULT 0,
+    status varchar(16) NOT NULL CHECK (status IN ('active', 'finished', 'abandoned')),
+    exercise_started_at timestamp NOT NULL,
+    started_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp NOT NULL DEFAULT now(),
+    finished_at timestamp,
+    summary jsonb
+);
+
+-- a user has one unfinished session, it is resumed on any device
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lesson_sessions_active ON lesson_sessions (user_id) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS lesson_session_answers (
+    session_id uuid NOT NULL REFERENCES lesson_sessions(id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    exercise_code varchar(255) NOT NULL,
+    correct boolean NOT NULL,
+    score double precision NOT NULL,
+    hints_used integer NOT NULL DEFAULT 0,
+    time_spent_ms bigint NOT NULL,
+    answered_at timestamp NOT NULL DEFAULT now(),
+    PRIMARY KEY (session_id, position)
+);
+
+-- XP of a finished session, it is inserted in the transaction that adds the XP,
+-- so finishing the session again retries a failed grant and never adds the XP twice
+CREATE TABLE IF NOT EXISTS lesson_session_xp (
+    session_id uuid PRIMARY KEY REFERENCES lesson_sessions(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    xp integer NOT NULL,
+    granted_at timestamp NOT NULL DEFAULT now()
+);
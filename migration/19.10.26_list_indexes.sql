@@ -0,0 +1,4 @@
+-- admin lists are paginated by the sort column and id
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx ON users (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS users_username_id_idx ON users (username, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS achievements_created_at_id_idx ON achievements (created_at, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS media_assets (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind varchar(16) NOT NULL CHECK (kind IN ('image', 'audio')),
+    content_type varchar(64) NOT NULL,
+    -- content addressed key of the object storage, media/<sha256>.<ext>
+    object_key varchar(255) NOT NULL UNIQUE,
+    size_bytes bigint NOT NULL,
+    width integer NOT NULL DEFAULT 0,
+    height integer NOT NULL DEFAULT 0,
+    duration_ms integer NOT NULL DEFAULT 0,
+    created_by varchar(255) NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL DEFAULT now()
+);
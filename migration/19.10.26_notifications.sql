@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id bigserial PRIMARY KEY,
+    username varchar(50) NOT NULL REFERENCES users(username) ON DELETE CASCADE ON UPDATE CASCADE,
+    type varchar(50) NOT NULL,
+    actor varchar(50) NOT NULL DEFAULT '',
+    code varchar(255) NOT NULL DEFAULT '',
+    value integer NOT NULL DEFAULT 0,
+    is_read boolean NOT NULL DEFAULT false,
+    created_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_username_created ON notifications (username, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (username) WHERE is_read = false;
+
+-- overtaken users are found by the XP range
+CREATE INDEX IF NOT EXISTS idx_users_progress_xp ON users_progress (xp);
@@ -0,0 +1,18 @@
+-- Avatars uploaded before this table are files in ./storage/avatars, avatar-<id>.png
+-- or avatar-<id>-<size>.png. Without a row here the user would get the identicon, so a legacy file
+-- is converted into user_avatars when the avatar is first requested. To convert them all at once
+-- and remove the files, run after this migration:
+--   main -config=<name> avatars backfill
+-- Files the backfill reports as failed stay in place and are converted on request.
+
+CREATE TABLE IF NOT EXISTS user_avatars (
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    size integer NOT NULL,
+    -- content addressed key of the object storage, avatars/<sha256>.png
+    object_key varchar(255) NOT NULL,
+    updated_at timestamp NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, size)
+);
+
+-- an object is deleted only when no avatar references it
+CREATE INDEX IF NOT EXISTS idx_user_avatars_object_key ON user_avatars (object_key);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_username varchar(50) NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    blocked_username varchar(50) NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+    created_at timestamp NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocker_username, blocked_username)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks (blocked_username);
+
+-- declined request can be sent again only after the cooldown
+ALTER TABLE friendships ADD COLUMN IF NOT EXISTS updated_at timestamp NOT NULL DEFAULT now();
@@ -0,0 +1,10 @@
+-- a user without a row has full hearts, hearts refill one by one from refilled_at
+CREATE TABLE IF NOT EXISTS user_hearts (
+    user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    hearts integer NOT NULL CHECK (hearts >= 0),
+    refilled_at timestamp NOT NULL
+);
+
+-- practice sessions don't cost hearts and earn them back
+ALTER TABLE lesson_sessions ADD COLUMN IF NOT EXISTS practice boolean NOT NULL DEFAULT false;
+ALTER TABLE lesson_sessions ADD COLUMN IF NOT EXISTS hearts_earned integer NOT NULL DEFAULT 0;
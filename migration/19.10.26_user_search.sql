@@ -0,0 +1,12 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- users can hide themselves from user search and friend suggestions
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_searchable boolean NOT NULL DEFAULT true;
+
+CREATE INDEX IF NOT EXISTS users_username_trgm_idx ON users USING gin (username gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_first_name_trgm_idx ON users USING gin (first_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_last_name_trgm_idx ON users USING gin (last_name gin_trgm_ops);
+
+-- module the user is passing now, used for friend suggestions
+ALTER TABLE users_progress ADD COLUMN IF NOT EXISTS current_module varchar(50);
+CREATE INDEX IF NOT EXISTS users_progress_current_module_idx ON users_progress (current_module);
@@ -0,0 +1,10 @@
+// mongosh "$MONGO_URI" migration/mongo_content_status.js
+// content created before the publication workflow is already live, mark it as published
+
+["modules", "lessons", "exercises"].forEach((name) => {
+  db.getCollection(name).updateMany(
+    { status: { $exists: false } },
+    { $set: { status: "published" } }
+  );
+  db.getCollection(name).createIndex({ status: 1 }, { name: name + "_status" });
+});
@@ -0,0 +1,33 @@
+// mongosh "$MONGO_URI" migration/mongo_indexes.js
+
+db.revisions.createIndex(
+  { entity_type: 1, code: 1, version: -1 },
+  { unique: true, name: "revisions_entity_code_version" }
+);
+
+// admin lists are paginated by the sort field and code
+for (const collection of ["modules", "lessons", "exercises"]) {
+  db[collection].createIndex(
+    { created_at: -1, code: -1 },
+    { name: `${collection}_created_at_code` }
+  );
+}
+db.modules.createIndex({ title: 1, code: 1 }, { name: "modules_title_code" });
+db.lessons.createIndex({ title: 1, code: 1 }, { name: "lessons_title_code" });
+
+// content search, one text index per collection.
+// language_override points to a field that is never set, so every document is stemmed as russian,
+// queries are stemmed as russian too (search.IndexLanguage), other query languages would never match the index keys
+const textIndexOptions = { default_language: "russian", language_override: "search_language" };
+db.modules.createIndex(
+  { title: "text", description: "text" },
+  { ...textIndexOptions, name: "modules_text", weights: { title: 10, description: 2 } }
+);
+db.lessons.createIndex(
+  { title: "text" },
+  { ...textIndexOptions, name: "lessons_text" }
+);
+db.exercises.createIndex(
+  { question: "text", hints: "text" },
+  { ...textIndexOptions, name: "exercises_text", weights: { question: 5, hints: 1 } }
+);
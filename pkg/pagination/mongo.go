@@ -0,0 +1,35 @@
+package pagination
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MongoSort orders by the sort field and then by the unique key field
+func MongoSort(params Params, field, keyField string) bson.D {
+	direction := 1
+	if params.Desc() {
+		direction = -1
+	}
+
+	if field == keyField {
+		return bson.D{{Key: field, Value: direction}}
+	}
+	return bson.D{{Key: field, Value: direction}, {Key: keyField, Value: direction}}
+}
+
+// MongoAfter returns the filter for documents after the cursor,
+// value and key must be converted to the types stored in the collection
+func MongoAfter(params Params, field string, value interface{}, keyField string, key interface{}) bson.M {
+	op := "$gt"
+	if params.Desc() {
+		op = "$lt"
+	}
+
+	if field == keyField {
+		return bson.M{keyField: bson.M{op: key}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.M{field: value, keyField: bson.M{op: key}},
+	}}
+}
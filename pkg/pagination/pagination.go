@@ -0,0 +1,155 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrInvalidSort   = errors.New("invalid sort field")
+	ErrInvalidOrder  = errors.New("invalid sort order")
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// Params describes one page of a keyset paginated list.
+// After is the last item of the previous page, nil for the first page.
+type Params struct {
+	Limit  int
+	SortBy string
+	Order  string
+	After  *Cursor
+}
+
+// Cursor points at the last item of the page: Value is the value of the sort field,
+// Key is the unique field used to order items with equal values
+type Cursor struct {
+	SortBy string `json:"s"`
+	Order  string `json:"o"`
+	Value  string `json:"v"`
+	Key    string `json:"k"`
+}
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// NewParams validates raw request values, the first of sortFields is the default sort.
+// Cursor must be issued for the same sort, otherwise it points to the wrong place.
+func NewParams(limit int, cursor, sortBy, order string, sortFields []string) (Params, error) {
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit < 0 || limit > MaxLimit {
+		return Params{}, ErrInvalidLimit
+	}
+
+	if sortBy == "" && len(sortFields) > 0 {
+		sortBy = sortFields[0]
+	}
+	if !slices.Contains(sortFields, sortBy) {
+		return Params{}, ErrInvalidSort
+	}
+
+	if order == "" {
+		order = OrderDesc
+	}
+	if order != OrderAsc && order != OrderDesc {
+		return Params{}, ErrInvalidOrder
+	}
+
+	params := Params{
+		Limit:  limit,
+		SortBy: sortBy,
+		Order:  order,
+	}
+
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return Params{}, err
+		}
+		if after.SortBy != sortBy || after.Order != order {
+			return Params{}, ErrInvalidCursor
+		}
+		params.After = &after
+	}
+
+	return params, nil
+}
+
+// Fetch is the number of items repository should load, one extra item shows that there is a next page
+func (p Params) Fetch() int {
+	return p.Limit + 1
+}
+
+func (p Params) Desc() bool {
+	return p.Order == OrderDesc
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// TimeValue parses the cursor value of a time sort field
+func (c Cursor) TimeValue() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+func DecodeCursor(cursor string) (Cursor, error) {
+	var c Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// FormatTime formats time sort values, so they can be parsed back by Cursor.TimeValue
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// NewPage cuts the extra item loaded by the repository and builds the cursor from the last item.
+// cursorOf returns the value of the sort field and the unique key of the item.
+func NewPage[T any](items []T, params Params, cursorOf func(item T, sortBy string) (value, key string)) Page[T] {
+	page := Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = make([]T, 0)
+	}
+
+	if len(items) > params.Limit {
+		page.Items = items[:params.Limit]
+		value, key := cursorOf(page.Items[params.Limit-1], params.SortBy)
+		page.NextCursor = Cursor{
+			SortBy: params.SortBy,
+			Order:  params.Order,
+			Value:  value,
+			Key:    key,
+		}.Encode()
+	}
+
+	return page
+}
@@ -0,0 +1,110 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var sortFields = []string{"created_at", "code"}
+
+func Test_NewParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		params, err := NewParams(0, "", "", "", sortFields)
+		assert.NoError(t, err)
+		assert.Equal(t, Params{Limit: DefaultLimit, SortBy: "created_at", Order: OrderDesc}, params)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		_, err := NewParams(MaxLimit+1, "", "", "", sortFields)
+		assert.Equal(t, ErrInvalidLimit, err)
+
+		_, err = NewParams(10, "", "password", "", sortFields)
+		assert.Equal(t, ErrInvalidSort, err)
+
+		_, err = NewParams(10, "", "code", "up", sortFields)
+		assert.Equal(t, ErrInvalidOrder, err)
+
+		_, err = NewParams(10, "not a cursor", "code", "", sortFields)
+		assert.Equal(t, ErrInvalidCursor, err)
+	})
+
+	t.Run("cursor of another sort", func(t *testing.T) {
+		cursor := Cursor{SortBy: "code", Order: OrderAsc, Value: "a", Key: "a"}.Encode()
+
+		_, err := NewParams(10, cursor, "code", OrderDesc, sortFields)
+		assert.Equal(t, ErrInvalidCursor, err)
+
+		params, err := NewParams(10, cursor, "code", OrderAsc, sortFields)
+		assert.NoError(t, err)
+		assert.Equal(t, "a", params.After.Key)
+	})
+}
+
+func Test_NewPage(t *testing.T) {
+	t.Parallel()
+	var (
+		params   = Params{Limit: 2, SortBy: "code", Order: OrderAsc}
+		cursorOf = func(item string, sortBy string) (string, string) { return item, item }
+	)
+
+	page := NewPage([]string{"a", "b", "c"}, params, cursorOf)
+	assert.Equal(t, []string{"a", "b"}, page.Items)
+
+	cursor, err := DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, Cursor{SortBy: "code", Order: OrderAsc, Value: "b", Key: "b"}, cursor)
+
+	last := NewPage([]string{"c"}, params, cursorOf)
+	assert.Equal(t, []string{"c"}, last.Items)
+	assert.Empty(t, last.NextCursor)
+
+	empty := NewPage[string](nil, params, cursorOf)
+	assert.NotNil(t, empty.Items)
+}
+
+func Test_TimeValue(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 5, 17, 10, 0, 0, 123456789, time.UTC)
+
+	value, err := Cursor{Value: FormatTime(now)}.TimeValue()
+	assert.NoError(t, err)
+	assert.True(t, now.Equal(value))
+
+	_, err = Cursor{Value: "yesterday"}.TimeValue()
+	assert.Equal(t, ErrInvalidCursor, err)
+}
+
+func Test_Mongo(t *testing.T) {
+	t.Parallel()
+	params := Params{Limit: 10, SortBy: "created_at", Order: OrderDesc}
+
+	assert.Equal(t, bson.D{{Key: "created_at", Value: -1}, {Key: "code", Value: -1}}, MongoSort(params, "created_at", "code"))
+	assert.Equal(t, bson.M{"$or": bson.A{
+		bson.M{"created_at": bson.M{"$lt": 1}},
+		bson.M{"created_at": 1, "code": bson.M{"$lt": "a"}},
+	}}, MongoAfter(params, "created_at", 1, "code", "a"))
+
+	params.Order = OrderAsc
+	assert.Equal(t, bson.M{"code": bson.M{"$gt": "a"}}, MongoAfter(params, "code", "a", "code", "a"))
+}
+
+func Test_Postgres(t *testing.T) {
+	t.Parallel()
+	params := Params{Limit: 10, SortBy: "created_at", Order: OrderAsc}
+
+	assert.Equal(t, "created_at ASC, id ASC", PostgresOrderBy(params, "created_at", "id"))
+
+	condition, args := PostgresAfter(params, "created_at", "t", "id", "k", 3)
+	assert.Equal(t, "(created_at, id) > ($3, $4)", condition)
+	assert.Equal(t, []interface{}{"t", "k"}, args)
+
+	params.Order = OrderDesc
+	condition, args = PostgresAfter(params, "id", "k", "id", "k", 1)
+	assert.Equal(t, "id < $1", condition)
+	assert.Equal(t, []interface{}{"k"}, args)
+}
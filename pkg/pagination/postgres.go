@@ -0,0 +1,31 @@
+package pagination
+
+import "fmt"
+
+// PostgresOrderBy orders by the sort column and then by the unique key column.
+// Columns must come from a whitelist, they are put into the query as is.
+func PostgresOrderBy(params Params, column, keyColumn string) string {
+	direction := "ASC"
+	if params.Desc() {
+		direction = "DESC"
+	}
+
+	if column == keyColumn {
+		return fmt.Sprintf("%s %s", column, direction)
+	}
+	return fmt.Sprintf("%s %s, %s %s", column, direction, keyColumn, direction)
+}
+
+// PostgresAfter returns the condition for rows after the cursor and its arguments,
+// placeholders are numbered from argIndex
+func PostgresAfter(params Params, column string, value interface{}, keyColumn string, key interface{}, argIndex int) (string, []interface{}) {
+	op := ">"
+	if params.Desc() {
+		op = "<"
+	}
+
+	if column == keyColumn {
+		return fmt.Sprintf("%s %s $%d", keyColumn, op, argIndex), []interface{}{key}
+	}
+	return fmt.Sprintf("(%s, %s) %s ($%d, $%d)", column, keyColumn, op, argIndex, argIndex+1), []interface{}{value, key}
+}